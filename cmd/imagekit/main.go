@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -19,6 +20,10 @@ func main() {
 	// Execute CLI command
 	if err := cli.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		// A failed compare --threshold check is not an error running the tool
+		if errors.Is(err, cli.ErrCompareFailed) {
+			os.Exit(2)
+		}
 		os.Exit(1)
 	}
 	
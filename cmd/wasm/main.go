@@ -7,7 +7,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"image"
-	"image/jpeg"
+	_ "image/jpeg"
 	"image/png"
 	"strings"
 	"syscall/js"
@@ -238,6 +238,11 @@ func processImageData(base64Data string, processor func(image.Image, string) (im
 	// Clean up base64 string (remove any whitespace)
 	base64Data = strings.TrimSpace(base64Data)
 	
+	// Reject oversized input before allocating the decoded bytes
+	if err := transform.DefaultLimits.CheckSize(int64(base64.StdEncoding.DecodedLen(len(base64Data)))); err != nil {
+		return "", err
+	}
+	
 	// Decode base64
 	imageBytes, err := base64.StdEncoding.DecodeString(base64Data)
 	if err != nil {
@@ -252,6 +257,11 @@ func processImageData(base64Data string, processor func(image.Image, string) (im
 		}
 	}
 
+	// Check the header before decoding so decompression bombs fail fast
+	if err := transform.DefaultLimits.Check(imageBytes); err != nil {
+		return "", err
+	}
+
 	// Decode image
 	reader := bytes.NewReader(imageBytes)
 	img, format, err := image.Decode(reader)
@@ -269,7 +279,8 @@ func processImageData(base64Data string, processor func(image.Image, string) (im
 	var buf bytes.Buffer
 	switch format {
 	case "jpeg":
-		err = jpeg.Encode(&buf, processedImg, &jpeg.Options{Quality: 95})
+		// SaveImage flattens transparency so it doesn't turn black in JPEG
+		err = transform.SaveImage(&buf, processedImg, transform.FormatJPEG, 95)
 	case "png":
 		err = png.Encode(&buf, processedImg)
 	default:
@@ -1,8 +1,14 @@
 package batch
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
 	"path/filepath"
 	"strings"
+	
+	"github.com/allieus/imagekit/pkg/transform"
 )
 
 // GenerateOutputPath generates the output file path by adding "_converted" suffix
@@ -17,6 +23,36 @@ func GenerateOutputPath(inputPath string) string {
 	return filepath.Join(dir, name+"_converted"+ext)
 }
 
+// GenerateOutputPathWithFormat generates the output file path like GenerateOutputPath,
+// replacing the extension when the output format differs from the input extension
+// Example: ("image.png", "jpeg") -> "image_converted.jpg"
+// Example: ("photo.jpeg", "jpeg") -> "photo_converted.jpeg"
+func GenerateOutputPathWithFormat(inputPath string, format transform.ImageFormat) string {
+	outputPath := GenerateOutputPath(inputPath)
+	if format == "" {
+		return outputPath
+	}
+	
+	ext := filepath.Ext(outputPath)
+	if format.MatchesExtension(ext) {
+		return outputPath
+	}
+	return strings.TrimSuffix(outputPath, ext) + format.Extension()
+}
+
+// IsImageFile checks if a file has the extension of an image format that can be both
+// read and written back, as commands that save in the input format need
+func IsImageFile(path string) bool {
+	format, ok := transform.FormatFromPath(path)
+	return ok && transform.IsEncodable(format)
+}
+
+// IsDecodableFile checks if a file has the extension of a readable image format,
+// including read-only formats such as TIFF
+func IsDecodableFile(path string) bool {
+	return transform.IsSupportedExtension(filepath.Ext(path))
+}
+
 // IsConvertedFile checks if a file already has the "_converted" suffix
 func IsConvertedFile(path string) bool {
 	base := filepath.Base(path)
@@ -24,4 +60,158 @@ func IsConvertedFile(path string) bool {
 	name := strings.TrimSuffix(base, ext)
 	
 	return strings.HasSuffix(name, "_converted")
+}
+
+// NumberedOutputPath numbers an output path when a command writes several files
+// Example: ("sheet.png", 2, 12) -> "sheet-02.png"
+// Example: ("sheet.png", 1, 1) -> "sheet.png"
+func NumberedOutputPath(outputPath string, n, total int) string {
+	if total <= 1 {
+		return outputPath
+	}
+	ext := filepath.Ext(outputPath)
+	width := len(fmt.Sprint(total))
+	return fmt.Sprintf("%s-%0*d%s", strings.TrimSuffix(outputPath, ext), width, n, ext)
+}
+
+// GridOutputPath names one tile of a split image by its row and column, counting
+// from 1 and padding the numbers when there are ten or more
+// Example: ("poster.jpg", 1, 2, 2, 3) -> "poster_r1c2.jpg"
+// Example: ("poster.jpg", 3, 10, 4, 12) -> "poster_r3c10.jpg"
+func GridOutputPath(outputPath string, row, column, rows, columns int) string {
+	ext := filepath.Ext(outputPath)
+	return fmt.Sprintf("%s_r%0*dc%0*d%s", strings.TrimSuffix(outputPath, ext),
+		len(fmt.Sprint(rows)), row, len(fmt.Sprint(columns)), column, ext)
+}
+
+// FindImageFiles expands glob patterns and plain paths into readable image files,
+// skipping converted files and duplicates while keeping the order of the patterns.
+// A "**" element matches any number of directories, as in "assets/**/*.jpg".
+func FindImageFiles(patterns []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches, err := globFiles(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob pattern: %w", err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files matching pattern: %s", pattern)
+		}
+		for _, match := range matches {
+			if seen[match] || IsConvertedFile(match) || !IsDecodableFile(match) {
+				continue
+			}
+			seen[match] = true
+			files = append(files, match)
+		}
+	}
+	
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no valid image files found to process")
+	}
+	return files, nil
+}
+
+// ExcludeFiles removes paths from a file list, comparing cleaned absolute paths, so a
+// command whose output matches its input patterns doesn't read its previous output
+func ExcludeFiles(files []string, exclude ...string) []string {
+	excluded := make(map[string]bool)
+	for _, path := range exclude {
+		excluded[absPath(path)] = true
+	}
+	var kept []string
+	for _, file := range files {
+		if !excluded[absPath(file)] {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// ExcludeNumberedOutputs removes a multi-page output and its numbered pages, such as
+// "sheet.png" and "sheet-02.png", from a file list
+func ExcludeNumberedOutputs(files []string, outputPath string) []string {
+	ext := filepath.Ext(outputPath)
+	prefix := absPath(strings.TrimSuffix(outputPath, ext)) + "-"
+	var kept []string
+	for _, file := range ExcludeFiles(files, outputPath) {
+		path := absPath(file)
+		number := strings.TrimSuffix(strings.TrimPrefix(path, prefix), ext)
+		if strings.HasPrefix(path, prefix) && strings.HasSuffix(path, ext) && isDigits(number) {
+			continue
+		}
+		kept = append(kept, file)
+	}
+	return kept
+}
+
+// isDigits reports whether s is a non-empty string of ASCII digits
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// absPath returns the cleaned absolute form of a path, or the cleaned path itself when
+// the working directory is unknown
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// globFiles expands a glob pattern. The part before the first "**" is a directory that
+// is walked recursively, and the part after it must match the end of each file path.
+func globFiles(pattern string) ([]string, error) {
+	root, rest, found := strings.Cut(filepath.ToSlash(pattern), "**")
+	if !found {
+		return filepath.Glob(pattern)
+	}
+	root = strings.TrimSuffix(root, "/")
+	if root == "" {
+		root = "."
+	}
+	rest = strings.TrimPrefix(rest, "/")
+	if rest == "" {
+		rest = "*"
+	}
+	if _, err := path.Match(rest, ""); err != nil {
+		return nil, err
+	}
+	
+	var matches []string
+	err := filepath.WalkDir(filepath.FromSlash(root), func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(filepath.FromSlash(root), file)
+		if err != nil {
+			return err
+		}
+		// Try the pattern against the path below every directory level, so "**"
+		// also matches no directory at all
+		for suffix := filepath.ToSlash(rel); ; {
+			if ok, _ := path.Match(rest, suffix); ok {
+				matches = append(matches, file)
+				break
+			}
+			var more bool
+			if _, suffix, more = strings.Cut(suffix, "/"); !more {
+				break
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return matches, err
 }
\ No newline at end of file
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	
 	"github.com/allieus/imagekit/pkg/transform"
 )
@@ -43,9 +42,8 @@ func (p *Processor) ProcessFiles(pattern string, options ProcessOptions, progres
 			continue
 		}
 		
-		// Check if it's a supported image format
-		ext := strings.ToLower(filepath.Ext(match))
-		if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+		// Check if it's a supported image format; read-only formats need --format
+		if !IsDecodableFile(match) || options.Format == "" && !IsImageFile(match) {
 			continue
 		}
 		
@@ -64,7 +62,7 @@ func (p *Processor) ProcessFiles(pattern string, options ProcessOptions, progres
 	}
 	
 	for i, inputPath := range filesToProcess {
-		outputPath := GenerateOutputPath(inputPath)
+		outputPath := GenerateOutputPathWithFormat(inputPath, options.Format)
 		
 		// Process single file
 		err := p.processSingleFile(inputPath, outputPath, options)
@@ -79,6 +77,12 @@ func (p *Processor) ProcessFiles(pattern string, options ProcessOptions, progres
 			}
 		} else {
 			result.SuccessCount++
+			if inputInfo, err := os.Stat(inputPath); err == nil {
+				result.InputBytes += inputInfo.Size()
+			}
+			if outputInfo, err := os.Stat(outputPath); err == nil {
+				result.OutputBytes += outputInfo.Size()
+			}
 			if progressCallback != nil {
 				progressCallback(i+1, result.TotalFiles, filepath.Base(inputPath), true)
 			}
@@ -100,81 +104,38 @@ func (p *Processor) processSingleFile(inputPath, outputPath string, options Proc
 		return fmt.Errorf("input file does not exist: %s", inputPath)
 	}
 	
-	// Process based on options
-	if options.ResizeOptions != nil {
-		// Open input file for resize
-		inputFile, err := os.Open(inputPath)
-		if err != nil {
-			return fmt.Errorf("failed to open input file: %w", err)
-		}
-		defer func() { _ = inputFile.Close() }()
-		
-		// Create output file
-		outputFile, err := os.Create(outputPath)
-		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
-		}
-		defer func() { _ = outputFile.Close() }()
-		
-		// Resize operation
-		if err := p.transformer.Resize(inputFile, outputFile, *options.ResizeOptions); err != nil {
-			_ = os.Remove(outputPath) // Clean up on failure
-			return fmt.Errorf("resize failed: %w", err)
-		}
-		
-		// If DPI is also specified, process it
-		if options.DPI > 0 {
-			// Close files first
-			_ = inputFile.Close()
-			_ = outputFile.Close()
-			
-			// Use the resized output as input for DPI change
-			tempFile, err := os.Open(outputPath)
-			if err != nil {
-				return fmt.Errorf("failed to open temp file: %w", err)
-			}
-			defer func() { _ = tempFile.Close() }()
-			
-			tempOutputPath := outputPath + ".tmp"
-			tempOutput, err := os.Create(tempOutputPath)
-			if err != nil {
-				return fmt.Errorf("failed to create temp output: %w", err)
-			}
-			defer func() { _ = tempOutput.Close() }()
-			
-			if err := p.transformer.SetDPI(tempFile, tempOutput, options.DPI); err != nil {
-				_ = os.Remove(tempOutputPath)
-				return fmt.Errorf("DPI setting failed: %w", err)
-			}
-			
-			// Replace original with DPI-adjusted version
-			_ = tempFile.Close()
-			_ = tempOutput.Close()
-			if err := os.Rename(tempOutputPath, outputPath); err != nil {
-				return fmt.Errorf("failed to replace file: %w", err)
-			}
-		}
-	} else if options.DPI > 0 {
-		// DPI only operation
-		inputFile, err := os.Open(inputPath)
-		if err != nil {
-			return fmt.Errorf("failed to open input file: %w", err)
-		}
-		defer func() { _ = inputFile.Close() }()
-		
-		outputFile, err := os.Create(outputPath)
-		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
-		}
-		defer func() { _ = outputFile.Close() }()
-		
-		if err := p.transformer.SetDPI(inputFile, outputFile, options.DPI); err != nil {
-			_ = os.Remove(outputPath) // Clean up on failure
-			return fmt.Errorf("DPI setting failed: %w", err)
-		}
-	} else {
+	if !options.HasConversion() {
 		return fmt.Errorf("no conversion options specified")
 	}
 	
+	// Open input file
+	inputFile, err := os.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to open input file: %w", err)
+	}
+	defer func() { _ = inputFile.Close() }()
+	
+	// Create output file
+	outputFile, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer func() { _ = outputFile.Close() }()
+	
+	// Resize, format and DPI are applied in a single pass
+	convertOptions := transform.ConvertOptions{
+		Resize:     options.ResizeOptions,
+		Format:     options.Format,
+		Quality:    options.Quality,
+		Resolution: options.resolution(),
+		PNG:        options.PNG,
+		JPEG:       options.JPEG,
+	}
+	if err := p.transformer.Convert(inputFile, outputFile, convertOptions); err != nil {
+		_ = outputFile.Close()
+		_ = os.Remove(outputPath) // Clean up on failure
+		return fmt.Errorf("conversion failed: %w", err)
+	}
+	
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,188 @@
+package batch
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/transform"
+)
+
+// ResponsiveOptions contains options for generating responsive image sets
+type ResponsiveOptions struct {
+	Widths    []int                   // Target widths; wider than the source are skipped
+	Formats   []transform.ImageFormat // Output formats; the last one is the <img> fallback
+	OutputDir string
+	Quality   int
+	BaseURL   string // Prefix for the file names in srcset URLs
+	Sizes     string // sizes attribute (empty = 100vw)
+}
+
+// ResponsiveVariant is one generated file
+type ResponsiveVariant struct {
+	Path   string                `json:"path"`
+	URL    string                `json:"url"`
+	Format transform.ImageFormat `json:"format"`
+	Width  int                   `json:"width"`
+	Height int                   `json:"height"`
+	Bytes  int64                 `json:"bytes"`
+}
+
+// ResponsiveImage is the set of variants made from one source image
+type ResponsiveImage struct {
+	Source   string              `json:"source"`
+	Width    int                 `json:"width"`
+	Height   int                 `json:"height"`
+	Variants []ResponsiveVariant `json:"variants"`
+	HTML     string              `json:"html"`
+}
+
+// ResponsiveWidths returns the requested widths that do not enlarge the source, in
+// increasing order. When every width is larger, the source width is used.
+func ResponsiveWidths(widths []int, sourceWidth int) []int {
+	var result []int
+	seen := make(map[int]bool)
+	for _, width := range widths {
+		if width > 0 && width <= sourceWidth && !seen[width] {
+			seen[width] = true
+			result = append(result, width)
+		}
+	}
+	if len(result) == 0 {
+		return []int{sourceWidth}
+	}
+	sort.Ints(result)
+	return result
+}
+
+// ResponsiveFileName names a variant predictably
+// Example: ("photos/beach.jpg", 640, "webp") -> "beach-640w.webp"
+func ResponsiveFileName(inputPath string, width int, format transform.ImageFormat) string {
+	base := filepath.Base(inputPath)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	return fmt.Sprintf("%s-%dw%s", name, width, format.Extension())
+}
+
+// ProcessResponsive converts every file into each width and format in the output
+// directory. Files whose variants would overwrite an earlier file's are reported as
+// failures.
+func (p *Processor) ProcessResponsive(files []string, options ResponsiveOptions, progressCallback func(current int, total int, fileName string, success bool)) ([]ResponsiveImage, *BatchResult, error) {
+	if len(options.Widths) == 0 || len(options.Formats) == 0 {
+		return nil, nil, fmt.Errorf("at least one width and one format are required")
+	}
+	if err := os.MkdirAll(options.OutputDir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	
+	result := &BatchResult{TotalFiles: len(files), FailedFiles: []FailedFile{}}
+	var images []ResponsiveImage
+	written := make(map[string]string)
+	for i, inputPath := range files {
+		set, err := p.processResponsiveFile(inputPath, options, written)
+		if err != nil {
+			result.FailedFiles = append(result.FailedFiles, FailedFile{Path: inputPath, Error: err})
+		} else {
+			result.SuccessCount++
+			if info, err := os.Stat(inputPath); err == nil {
+				result.InputBytes += info.Size()
+			}
+			for _, variant := range set.Variants {
+				result.OutputBytes += variant.Bytes
+			}
+			images = append(images, set)
+		}
+		if progressCallback != nil {
+			progressCallback(i+1, result.TotalFiles, filepath.Base(inputPath), err == nil)
+		}
+	}
+	return images, result, nil
+}
+
+// processResponsiveFile writes the variants of one source image
+func (p *Processor) processResponsiveFile(inputPath string, options ResponsiveOptions, written map[string]string) (ResponsiveImage, error) {
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return ResponsiveImage{}, fmt.Errorf("failed to open input file: %w", err)
+	}
+	info, err := transform.ReadImageInfo(file)
+	_ = file.Close()
+	if err != nil {
+		return ResponsiveImage{}, err
+	}
+	
+	set := ResponsiveImage{Source: inputPath, Width: info.Width, Height: info.Height}
+	widths := ResponsiveWidths(options.Widths, info.Width)
+	for _, format := range options.Formats {
+		for _, width := range widths {
+			name := ResponsiveFileName(inputPath, width, format)
+			if other, ok := written[name]; ok {
+				return ResponsiveImage{}, fmt.Errorf("%s was already written for %s", name, other)
+			}
+			written[name] = inputPath
+			
+			resize := &transform.ResizeOptions{WidthDim: transform.DimensionValue{Value: width}, Mode: transform.ResizeFit, Quality: options.Quality}
+			outputPath := filepath.Join(options.OutputDir, name)
+			err := p.processSingleFile(inputPath, outputPath, ProcessOptions{ResizeOptions: resize, Format: format, Quality: options.Quality})
+			if err != nil {
+				return ResponsiveImage{}, err
+			}
+			
+			variant := ResponsiveVariant{Path: outputPath, URL: options.BaseURL + name, Format: format}
+			variant.Width, variant.Height = transform.CalculateDimensions(info.Width, info.Height, *resize)
+			if stat, err := os.Stat(outputPath); err == nil {
+				variant.Bytes = stat.Size()
+			}
+			set.Variants = append(set.Variants, variant)
+		}
+	}
+	set.HTML = ResponsiveHTML(set, options.Sizes)
+	return set, nil
+}
+
+// ResponsiveHTML returns a <picture> element with a <source> per extra format and an
+// <img> using the last format's variants, so with the default JPEG-only set it holds
+// just the <img>
+func ResponsiveHTML(set ResponsiveImage, sizes string) string {
+	if sizes == "" {
+		sizes = "100vw"
+	}
+	var formats []transform.ImageFormat
+	byFormat := make(map[transform.ImageFormat][]ResponsiveVariant)
+	for _, variant := range set.Variants {
+		if _, ok := byFormat[variant.Format]; !ok {
+			formats = append(formats, variant.Format)
+		}
+		byFormat[variant.Format] = append(byFormat[variant.Format], variant)
+	}
+	if len(formats) == 0 {
+		return ""
+	}
+	
+	srcset := func(variants []ResponsiveVariant) string {
+		entries := make([]string, len(variants))
+		for i, variant := range variants {
+			entries[i] = fmt.Sprintf("%s %dw", variant.URL, variant.Width)
+		}
+		return html.EscapeString(strings.Join(entries, ", "))
+	}
+	
+	var b strings.Builder
+	b.WriteString("<picture>\n")
+	for _, format := range formats[:len(formats)-1] {
+		fmt.Fprintf(&b, "  <source type=\"%s\" srcset=\"%s\" sizes=\"%s\">\n", mimeType(format), srcset(byFormat[format]), html.EscapeString(sizes))
+	}
+	fallback := byFormat[formats[len(formats)-1]]
+	largest := fallback[len(fallback)-1]
+	fmt.Fprintf(&b, "  <img src=\"%s\" srcset=\"%s\" sizes=\"%s\" width=\"%d\" height=\"%d\" alt=\"\" loading=\"lazy\" decoding=\"async\">\n",
+		html.EscapeString(largest.URL), srcset(fallback), html.EscapeString(sizes), largest.Width, largest.Height)
+	b.WriteString("</picture>")
+	return b.String()
+}
+
+// mimeType returns the media type of an image format
+func mimeType(format transform.ImageFormat) string {
+	return "image/" + string(format)
+}
\ No newline at end of file
@@ -0,0 +1,82 @@
+package batch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Sort orders for commands that combine several files
+const (
+	SortName = "name" // Natural order, so "img2.jpg" comes before "img10.jpg"
+	SortTime = "time" // Oldest modification time first
+	SortNone = "none" // As given on the command line
+)
+
+// SortFiles orders files in place
+func SortFiles(files []string, order string) error {
+	switch strings.ToLower(order) {
+	case SortName, "":
+		sort.SliceStable(files, func(i, j int) bool {
+			return naturalLess(filepath.ToSlash(files[i]), filepath.ToSlash(files[j]))
+		})
+	case SortTime:
+		times := make(map[string]int64, len(files))
+		for _, file := range files {
+			info, err := os.Stat(file)
+			if err != nil {
+				return err
+			}
+			times[file] = info.ModTime().UnixNano()
+		}
+		sort.SliceStable(files, func(i, j int) bool { return times[files[i]] < times[files[j]] })
+	case SortNone:
+	default:
+		return fmt.Errorf("unsupported sort order: %s (use name, time or none)", order)
+	}
+	return nil
+}
+
+// naturalLess compares strings case-insensitively, treating runs of digits as numbers
+func naturalLess(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			na, ra := cutDigits(a)
+			nb, rb := cutDigits(b)
+			// Compare by value: without leading zeros a longer number is larger
+			ta, tb := strings.TrimLeft(na, "0"), strings.TrimLeft(nb, "0")
+			if len(ta) != len(tb) {
+				return len(ta) < len(tb)
+			}
+			if ta != tb {
+				return ta < tb
+			}
+			if na != nb {
+				return len(na) < len(nb)
+			}
+			a, b = ra, rb
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+// cutDigits splits a string after its leading digits
+func cutDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
\ No newline at end of file
@@ -7,6 +7,8 @@ type BatchResult struct {
 	TotalFiles   int
 	SuccessCount int
 	FailedFiles  []FailedFile
+	InputBytes   int64 // Total size of successfully processed input files
+	OutputBytes  int64 // Total size of the files they produced
 }
 
 // FailedFile represents a file that failed to process
@@ -18,7 +20,27 @@ type FailedFile struct {
 // ProcessOptions contains options for batch processing
 type ProcessOptions struct {
 	ResizeOptions *transform.ResizeOptions
-	DPI           int
+	Resolution    transform.Resolution  // Target resolution (zero = unchanged)
+	Format        transform.ImageFormat // Output format (empty = same as input)
+	Quality       int                   // JPEG quality used when not resizing
+	PNG           transform.PNGOptions  // PNG size optimization
+	JPEG          transform.JPEGOptions // JPEG encoder options
+	
+	// Deprecated: Use Resolution. DPI sets a square resolution when Resolution is zero.
+	DPI int
+}
+
+// HasConversion reports whether any conversion was requested
+func (o ProcessOptions) HasConversion() bool {
+	return o.ResizeOptions != nil || !o.resolution().IsZero() || o.Format != "" || o.PNG.Enabled() || o.JPEG.Enabled()
+}
+
+// resolution returns the target resolution, falling back to the deprecated DPI field
+func (o ProcessOptions) resolution() transform.Resolution {
+	if o.Resolution.IsZero() && o.DPI > 0 {
+		return transform.SquareDPI(o.DPI)
+	}
+	return o.Resolution
 }
 
 // HasErrors returns true if there were any failures
@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/batch"
+	"github.com/allieus/imagekit/pkg/transform"
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bleedSize      string
+	bleedFill      string
+	bleedColor     string
+	bleedCropMarks bool
+	bleedSafeArea  string
+	bleedDPI       string
+)
+
+var bleedCmd = &cobra.Command{
+	Use:   "bleed [input-pattern or file] [output-file (optional)]",
+	Short: "인쇄용 재단 여백(도련) 추가",
+	Long: `인쇄 재단 오차에 대비해 이미지 바깥에 재단 여백(도련)을 추가합니다.
+여백은 이미지 해상도(DPI) 기준의 실제 길이로 계산됩니다.
+
+예제:
+  # 3mm 도련 추가 (가장자리를 거울처럼 반사)
+  imagekit bleed design.png print.png
+
+  # 가장자리 픽셀을 늘리거나 단색으로 채우기
+  imagekit bleed --size=3mm --fill=stretch design.jpg print.jpg
+  imagekit bleed --size=5mm --fill=color --color=#000000 design.jpg print.jpg
+
+  # 재단선과 안전 영역 가이드 표시 (교정용)
+  imagekit bleed --crop-marks --safe-area=5mm design.png proof.png
+
+  # 해상도 정보가 없는 이미지는 --dpi로 지정
+  imagekit bleed --dpi=300 "cards/*.png"
+
+결과 파일에는 계산에 사용한 해상도가 기록되어 재단 후 크기가 유지됩니다.
+재단선은 도련 바깥 5mm 흰 여백에 그려집니다.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runBleed,
+}
+
+func init() {
+	bleedCmd.Flags().StringVar(&bleedSize, "size", "3mm", "각 변에 추가할 도련 (인쇄 크기 또는 픽셀: 3mm, 0.125in, 36)")
+	bleedCmd.Flags().StringVar(&bleedFill, "fill", "mirror", "도련 채우기 방식 (mirror, stretch, color)")
+	bleedCmd.Flags().StringVar(&bleedColor, "color", "#ffffff", "--fill=color일 때 채울 색")
+	bleedCmd.Flags().BoolVar(&bleedCropMarks, "crop-marks", false, "도련 바깥에 재단선 표시")
+	bleedCmd.Flags().StringVar(&bleedSafeArea, "safe-area", "", "재단선 안쪽 안전 영역 가이드 간격 (예: 5mm)")
+	bleedCmd.Flags().StringVar(&bleedDPI, "dpi", "", "길이 계산에 사용할 해상도 (기본값: 이미지 해상도)")
+}
+
+func runBleed(cmd *cobra.Command, args []string) error {
+	inputPattern := args[0]
+	
+	// Parse bleed options
+	options, err := parseBleedOptions()
+	if err != nil {
+		return err
+	}
+	
+	// Create transformer
+	transformer := transform.NewTransformer()
+	transformer.SetLogger(verboseLogf)
+	
+	// Check if it's a glob pattern or contains wildcards
+	hasGlob := strings.Contains(inputPattern, "*") || strings.Contains(inputPattern, "?") || strings.Contains(inputPattern, "[")
+	
+	// Single file mode with explicit output
+	if len(args) == 2 && !hasGlob {
+		return processSingleBleedFile(transformer, inputPattern, args[1], options)
+	}
+	
+	// Check if it's a single file without glob patterns
+	if !hasGlob {
+		// Single file mode with auto-generated output name
+		if _, err := os.Stat(inputPattern); err == nil {
+			outputPath := batch.GenerateOutputPath(inputPattern)
+			return processSingleBleedFile(transformer, inputPattern, outputPath, options)
+		}
+		return fmt.Errorf("파일을 찾을 수 없습니다: %s", inputPattern)
+	}
+	
+	// Batch mode
+	return processBatchBleed(transformer, inputPattern, options)
+}
+
+func parseBleedOptions() (transform.BleedOptions, error) {
+	var options transform.BleedOptions
+	var err error
+	
+	if options.Size, err = transform.ParseDimension(bleedSize); err != nil {
+		return options, fmt.Errorf("잘못된 size 값: %w", err)
+	}
+	if options.SafeArea, err = transform.ParseDimension(bleedSafeArea); err != nil {
+		return options, fmt.Errorf("잘못된 safe-area 값: %w", err)
+	}
+	if options.Fill, err = transform.ParseBleedFill(bleedFill); err != nil {
+		return options, fmt.Errorf("잘못된 fill 값: %w", err)
+	}
+	if options.Color, err = transform.ParseHexColor(bleedColor); err != nil {
+		return options, fmt.Errorf("잘못된 color 값: %w", err)
+	}
+	if bleedDPI != "" {
+		if options.Resolution, err = transform.ParseResolution(bleedDPI); err != nil {
+			return options, fmt.Errorf("잘못된 dpi 값: %w", err)
+		}
+	}
+	options.CropMarks = bleedCropMarks
+	
+	if err := transform.ValidateBleedOptions(options); err != nil {
+		return options, fmt.Errorf("도련 옵션 파싱 실패: %w", err)
+	}
+	return options, nil
+}
+
+func processSingleBleedFile(transformer *transform.Transformer, inputPath, outputPath string, options transform.BleedOptions) error {
+	// Show progress
+	bar := progressbar.Default(-1, "도련 추가 중...")
+	
+	// Open input file
+	inputFile, err := os.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
+	}
+	defer func() { _ = inputFile.Close() }()
+	
+	// Add bleed, writing the output only after the whole image was processed
+	output := &bytes.Buffer{}
+	if err := transformer.Bleed(inputFile, output, options); err != nil {
+		return fmt.Errorf("도련 추가 실패: %w", err)
+	}
+	if err := os.WriteFile(outputPath, output.Bytes(), 0644); err != nil {
+		return fmt.Errorf("출력 파일을 생성할 수 없습니다: %w", err)
+	}
+	
+	_ = bar.Finish()
+	fmt.Printf("✅ 도련 추가 완료: %s\n", outputPath)
+	
+	return nil
+}
+
+func processBatchBleed(transformer *transform.Transformer, pattern string, options transform.BleedOptions) error {
+	// Find matching files
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return fmt.Errorf("잘못된 glob 패턴: %w", err)
+	}
+	
+	if len(matches) == 0 {
+		return fmt.Errorf("패턴과 일치하는 파일이 없습니다: %s", pattern)
+	}
+	
+	// Filter valid image files
+	var filesToProcess []string
+	for _, match := range matches {
+		if batch.IsConvertedFile(match) || !batch.IsImageFile(match) {
+			continue
+		}
+		filesToProcess = append(filesToProcess, match)
+	}
+	
+	if len(filesToProcess) == 0 {
+		return fmt.Errorf("처리할 유효한 이미지 파일이 없습니다")
+	}
+	
+	// Process files
+	fmt.Println("Adding bleed...")
+	successCount := 0
+	var failedFiles []string
+	
+	for i, inputPath := range filesToProcess {
+		outputPath := batch.GenerateOutputPath(inputPath)
+		
+		err := processSingleBleedFile(transformer, inputPath, outputPath, options)
+		
+		status := "✅"
+		if err != nil {
+			status = "❌"
+			failedFiles = append(failedFiles, inputPath)
+		} else {
+			successCount++
+		}
+		
+		fmt.Printf("[%d/%d] %s → %s %s\n", i+1, len(filesToProcess),
+			filepath.Base(inputPath), filepath.Base(outputPath), status)
+		
+		if err != nil {
+			fmt.Printf("  에러: %v\n", err)
+		}
+	}
+	
+	// Show summary
+	fmt.Printf("\n완료: %d/%d 성공", successCount, len(filesToProcess))
+	if len(failedFiles) > 0 {
+		fmt.Printf(", %d 실패\n", len(failedFiles))
+		fmt.Println("\n실패한 파일:")
+		for _, path := range failedFiles {
+			fmt.Printf("  - %s\n", path)
+		}
+	} else {
+		fmt.Println()
+	}
+	
+	return nil
+}
\ No newline at end of file
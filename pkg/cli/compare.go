@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/transform"
+	"github.com/spf13/cobra"
+)
+
+// ErrCompareFailed is returned when compare --threshold fails, so main can exit with
+// status 2 instead of the status 1 used for other errors
+var ErrCompareFailed = errors.New("비교 기준을 통과하지 못했습니다")
+
+var (
+	compareResize    bool
+	compareTolerance int
+	compareDiff      string
+	compareDiffColor string
+	compareMetric    string
+	compareThreshold float64
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare [original] [modified]",
+	Short: "두 이미지를 비교해 PSNR, SSIM, 차이 측정",
+	Long: `변환 전후의 두 이미지를 비교해 화질 손실을 수치로 보여 줍니다.
+
+  PSNR   신호 대 잡음비 (dB), 높을수록 원본에 가깝고 동일하면 ∞ (40 이상이면 눈으로 구분하기 어려움)
+  SSIM   구조적 유사도 (0-1), 1이면 동일
+  최대 차이   RGB 채널 값 차이의 최댓값 (0-255)
+  다른 픽셀   채널 차이가 --tolerance보다 큰 픽셀의 비율
+
+예제:
+  # 품질 80으로 변환한 결과 확인
+  imagekit compare original.jpg converted.jpg
+
+  # 차이 나는 픽셀을 빨간색으로 표시한 이미지 저장
+  imagekit compare original.png converted.png --diff diff.png --tolerance 3
+
+  # 크기가 다르면 두 번째 이미지를 원본 크기로 맞춰 비교
+  imagekit compare original.jpg thumbnail.jpg --resize
+
+  # CI 회귀 검사: SSIM이 0.98보다 낮으면 종료 코드 2
+  imagekit compare expected.png actual.png --metric ssim --threshold 0.98
+
+--threshold를 지정하면 ssim, psnr은 기준보다 낮을 때, diff(다른 픽셀 %)와 delta(최대 차이)는 기준보다 높을 때 실패합니다.
+기준을 통과하지 못하면 종료 코드 2, 파일을 읽을 수 없는 등 다른 오류는 종료 코드 1로 끝납니다.
+투명한 이미지는 흰 배경에 합성해 비교하므로 PNG와 그 JPEG 변환본도 비교할 수 있습니다.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCompare,
+}
+
+func init() {
+	compareCmd.Flags().BoolVar(&compareResize, "resize", false, "크기가 다르면 두 번째 이미지를 원본 크기로 맞춤 (기본값: 실패)")
+	compareCmd.Flags().IntVar(&compareTolerance, "tolerance", 0, "같은 것으로 볼 채널 차이 (0-255)")
+	compareCmd.Flags().StringVar(&compareDiff, "diff", "", "차이 나는 픽셀을 강조한 이미지 파일")
+	compareCmd.Flags().StringVar(&compareDiffColor, "diff-color", "#ff0000", "--diff 강조 색")
+	compareCmd.Flags().StringVar(&compareMetric, "metric", "ssim", "--threshold로 검사할 지표 (ssim, psnr, diff, delta)")
+	compareCmd.Flags().Float64Var(&compareThreshold, "threshold", 0, "지표가 기준을 넘지 못하면 실패 (종료 코드 2)")
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	originalPath, modifiedPath := args[0], args[1]
+	
+	options := transform.CompareOptions{Resize: compareResize, Tolerance: compareTolerance, Diff: compareDiff != ""}
+	if compareTolerance < 0 || compareTolerance > 255 {
+		return fmt.Errorf("tolerance는 0에서 255 사이여야 합니다: %d", compareTolerance)
+	}
+	metric := strings.ToLower(strings.TrimSpace(compareMetric))
+	switch metric {
+	case "ssim", "psnr", "diff", "delta":
+	default:
+		return fmt.Errorf("잘못된 metric 값: %s (ssim, psnr, diff, delta)", compareMetric)
+	}
+	var diffFormat transform.ImageFormat
+	if compareDiff != "" {
+		var ok bool
+		if diffFormat, ok = transform.FormatFromPath(compareDiff); !ok || transform.ValidateOutputFormat(diffFormat) != nil {
+			return fmt.Errorf("지원하지 않는 diff 파일 형식입니다: %s", compareDiff)
+		}
+		color, err := transform.ParseHexColor(compareDiffColor)
+		if err != nil {
+			return fmt.Errorf("잘못된 diff-color 값: %w", err)
+		}
+		options.DiffColor = color
+	}
+	
+	// Open input files
+	original, err := os.Open(originalPath)
+	if err != nil {
+		return fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
+	}
+	defer func() { _ = original.Close() }()
+	modified, err := os.Open(modifiedPath)
+	if err != nil {
+		return fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
+	}
+	defer func() { _ = modified.Close() }()
+	
+	// Create transformer
+	transformer := transform.NewTransformer()
+	transformer.SetLogger(verboseLogf)
+	
+	result, err := transformer.Compare(original, modified, options)
+	if err != nil {
+		return fmt.Errorf("비교 실패: %w", err)
+	}
+	
+	fmt.Println("📊 이미지 비교")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("📁 원본: %s\n", originalPath)
+	if result.Resized {
+		fmt.Printf("📁 비교: %s (원본 크기로 조정)\n", modifiedPath)
+	} else {
+		fmt.Printf("📁 비교: %s\n", modifiedPath)
+	}
+	fmt.Printf("📏 크기: %d x %d 픽셀\n", result.Width, result.Height)
+	fmt.Printf("📈 PSNR: %s\n", formatPSNR(result.PSNR))
+	fmt.Printf("🧩 SSIM: %.4f\n", result.SSIM)
+	fmt.Printf("🔺 최대 차이: %d\n", result.MaxDelta)
+	fmt.Printf("🎯 다른 픽셀: %.2f%% (%d개, 허용 차이 %d)\n", result.DiffPercent, result.DiffPixels, compareTolerance)
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	
+	if result.Diff != nil {
+		if err := saveCompareDiff(result, diffFormat); err != nil {
+			return err
+		}
+	}
+	
+	if cmd.Flags().Changed("threshold") {
+		// A failed check is a result, not a usage mistake: print only the one-line
+		// error from main, which exits with status 2
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		return checkCompareThreshold(result, metric)
+	}
+	return nil
+}
+
+// formatPSNR formats a PSNR value, which is infinite for identical images
+func formatPSNR(psnr float64) string {
+	if math.IsInf(psnr, 1) {
+		return "∞ (동일)"
+	}
+	return fmt.Sprintf("%.2f dB", psnr)
+}
+
+// saveCompareDiff writes the diff image to the --diff file
+func saveCompareDiff(result transform.CompareResult, format transform.ImageFormat) error {
+	outputFile, err := os.Create(compareDiff)
+	if err != nil {
+		return fmt.Errorf("diff 파일을 생성할 수 없습니다: %w", err)
+	}
+	defer func() { _ = outputFile.Close() }()
+	
+	if err := transform.SaveImage(outputFile, result.Diff, format, 95); err != nil {
+		return fmt.Errorf("diff 저장 실패: %w", err)
+	}
+	fmt.Printf("✅ diff 저장: %s\n", compareDiff)
+	return nil
+}
+
+// checkCompareThreshold fails when the chosen metric is worse than --threshold
+func checkCompareThreshold(result transform.CompareResult, metric string) error {
+	var value string
+	var passed bool
+	switch metric {
+	case "ssim":
+		value, passed = fmt.Sprintf("%.4f", result.SSIM), result.SSIM >= compareThreshold
+	case "psnr":
+		value, passed = formatPSNR(result.PSNR), result.PSNR >= compareThreshold
+	case "diff":
+		value, passed = fmt.Sprintf("%.2f%%", result.DiffPercent), result.DiffPercent <= compareThreshold
+	default:
+		value, passed = fmt.Sprint(result.MaxDelta), float64(result.MaxDelta) <= compareThreshold
+	}
+	
+	if !passed {
+		return fmt.Errorf("%w: %s %s, 기준 %g", ErrCompareFailed, strings.ToUpper(metric), value, compareThreshold)
+	}
+	fmt.Printf("✅ %s %s: 기준 %g 통과\n", strings.ToUpper(metric), value, compareThreshold)
+	return nil
+}
@@ -13,49 +13,90 @@ import (
 )
 
 var (
-	width   string
-	height  string
-	dpi     int
-	mode    string
-	quality int
+	width        string
+	height       string
+	dpi          string
+	mode         string
+	quality      int
+	background   string
+	outputFormat string
+	pngOptimize  bool
+	colors       int
+	subsampling  string
+	progressive  bool
+	maxOutPixels string
 )
 
 var convertCmd = &cobra.Command{
 	Use:   "convert [input-pattern or file] [output-file (optional)]",
-	Short: "이미지 변환 (크기, DPI)",
+	Short: "이미지 변환 (크기, DPI, 형식)",
 	Long: `단일 파일 또는 glob 패턴으로 여러 이미지를 변환합니다.
 	
 예제:
   # 단일 파일 변환
   imagekit convert --width=1920 --height=1080 input.jpg output.jpg
   imagekit convert --dpi=96 input.png output.png
+  imagekit convert --dpi=300x150 input.png output.png   # 가로/세로 해상도가 다른 경우
+  imagekit convert --dpi=118dpcm input.jpg output.jpg   # 센티미터당 도트 수
+  imagekit convert --width=10cm --dpi=300 input.jpg output.jpg  # 300 DPI에서 폭 10cm (1181 픽셀)
+  imagekit convert --width=50% input.jpg output.jpg  # 원본의 절반 크기
+  imagekit convert input.png output.webp             # 확장자로 형식 변환
   
   # 여러 파일 변환 (glob 패턴)
   imagekit convert --width=1920 "*.jpg"              # 모든 jpg 파일
   imagekit convert --dpi=96 "photos/*.png"           # photos 디렉토리의 png 파일들
-  imagekit convert --width=800 --height=600 "*.{jpg,png}"  # jpg와 png 파일들`,
+  imagekit convert --width=800 --height=600 "*.{jpg,png}"  # jpg와 png 파일들
+  imagekit convert --format=jpeg "*.png"             # 모든 png 파일을 jpg로 변환
+  
+  # 축소/확대만 하기
+  imagekit convert --width="<=1920" "*.jpg"          # 1920보다 큰 이미지만 축소
+  imagekit convert --width=">=800" "*.jpg"           # 800보다 작은 이미지만 확대
+  imagekit convert --max-output-pixels=12MP "*.jpg"  # 1200만 화소를 넘으면 비율 유지하며 축소
+  
+  # PNG 용량 최적화
+  imagekit convert --png-optimize input.png output.png
+  imagekit convert --png-optimize --colors=256 "*.png"  # 256색 팔레트로 양자화
+  
+  # JPEG 인코딩 옵션
+  imagekit convert --subsampling=444 design.png design.jpg   # 색 번짐 없는 JPEG
+  imagekit convert --progressive "*.jpg"                     # 프로그레시브 JPEG`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runConvert,
 }
 
 func init() {
-	convertCmd.Flags().StringVar(&width, "width", "", "목표 너비 (픽셀 또는 배수: 1920, 2x, x2, 0.5x)")
-	convertCmd.Flags().StringVar(&height, "height", "", "목표 높이 (픽셀 또는 배수: 1080, 2x, x2, 0.5x)")
-	convertCmd.Flags().IntVar(&dpi, "dpi", 0, "목표 DPI (72, 96, 150, 300)")
+	convertCmd.Flags().StringVar(&width, "width", "", "목표 너비 (픽셀, 배수, 비율 또는 인쇄 크기: 1920, 2x, 0.5x, 50%, 10cm, 210mm, 4in; <=1920 축소만, >=800 확대만)")
+	convertCmd.Flags().StringVar(&height, "height", "", "목표 높이 (픽셀, 배수, 비율 또는 인쇄 크기: 1080, 2x, 0.5x, 50%, 10cm, 297mm, 4in; <=1080 축소만, >=600 확대만)")
+	convertCmd.Flags().StringVar(&dpi, "dpi", "", "목표 해상도 (72, 300, 가로x세로: 300x150, 센티미터당: 118dpcm)")
+	convertCmd.Flags().StringVar(&maxOutPixels, "max-output-pixels", "", "출력 이미지 최대 픽셀 수, 넘으면 비율 유지하며 축소 (예: 12MP)")
 	convertCmd.Flags().StringVar(&mode, "mode", "fit", "리사이징 모드 (fit, fill, exact)")
-	convertCmd.Flags().IntVar(&quality, "quality", 95, "JPEG 품질 (1-100)")
+	convertCmd.Flags().IntVar(&quality, "quality", 95, "JPEG 품질 (1-100, 무손실로 저장되는 WebP에는 적용되지 않음)")
+	convertCmd.Flags().StringVar(&background, "background", "#ffffff", "투명 영역을 합성할 배경색 (JPEG 등 알파 채널이 없는 형식)")
+	convertCmd.Flags().StringVar(&outputFormat, "format", "", "출력 형식 (jpeg, png, webp, gif; 기본값: 출력 파일 확장자 또는 원본 형식)")
+	convertCmd.Flags().BoolVar(&pngOptimize, "png-optimize", false, "PNG 용량 최적화 (색상 형식, 비트 깊이, 필터, 압축 수준 탐색)")
+	convertCmd.Flags().IntVar(&colors, "colors", 0, "PNG 팔레트 색상 수로 양자화 (2-256, 디더링 적용, 손실 압축)")
+	convertCmd.Flags().StringVar(&subsampling, "subsampling", "", "JPEG 크로마 서브샘플링 (444, 422, 420; 기본값: 420)")
+	convertCmd.Flags().BoolVar(&progressive, "progressive", false, "프로그레시브 JPEG로 저장")
 }
 
 func runConvert(cmd *cobra.Command, args []string) error {
 	inputPattern := args[0]
 	
-	// Check if conversion options are specified
-	if width == "" && height == "" && dpi <= 0 {
-		return fmt.Errorf("변환 옵션을 지정해주세요 (--width, --height, 또는 --dpi)")
+	// Parse conversion options
+	options, err := buildConvertOptions()
+	if err != nil {
+		return err
 	}
 	
 	// Create transformer
 	transformer := transform.NewTransformer()
+	bgColor, err := transform.ParseHexColor(background)
+	if err != nil {
+		return fmt.Errorf("잘못된 background 값: %w", err)
+	}
+	transformer.SetBackground(bgColor)
+	transformer.SetLogger(verboseLogf)
+	processor := batch.NewProcessor(transformer)
 	
 	// Check if it's a glob pattern or contains wildcards
 	hasGlob := strings.Contains(inputPattern, "*") || strings.Contains(inputPattern, "?") || strings.Contains(inputPattern, "[")
@@ -63,49 +104,33 @@ func runConvert(cmd *cobra.Command, args []string) error {
 	// Single file mode with explicit output
 	if len(args) == 2 && !hasGlob {
 		outputPath := args[1]
-		return processSingleFile(transformer, inputPattern, outputPath)
+		if err := inferOutputFormat(&options, inputPattern, outputPath); err != nil {
+			return err
+		}
+		if err := validateConvertOptions(options); err != nil {
+			return err
+		}
+		if format, ok := transform.FormatFromPath(outputPath); ok {
+			warnWebPQuality(cmd, format)
+		}
+		return processSingleFile(processor, inputPattern, outputPath, options)
 	}
 	
+	if err := validateConvertOptions(options); err != nil {
+		return err
+	}
+	warnWebPQuality(cmd, options.Format)
+	
 	// Check if it's a single file without glob patterns
 	if !hasGlob {
 		// Single file mode with auto-generated output name
 		if _, err := os.Stat(inputPattern); err == nil {
-			outputPath := batch.GenerateOutputPath(inputPattern)
-			return processSingleFile(transformer, inputPattern, outputPath)
+			outputPath := batch.GenerateOutputPathWithFormat(inputPattern, options.Format)
+			return processSingleFile(processor, inputPattern, outputPath, options)
 		}
 		return fmt.Errorf("파일을 찾을 수 없습니다: %s", inputPattern)
 	}
 	
-	// Batch mode
-	processor := batch.NewProcessor(transformer)
-	
-	// Parse dimensions
-	widthDim, err := transform.ParseDimension(width)
-	if err != nil {
-		return fmt.Errorf("잘못된 width 값: %w", err)
-	}
-	heightDim, err := transform.ParseDimension(height)
-	if err != nil {
-		return fmt.Errorf("잘못된 height 값: %w", err)
-	}
-	
-	// Prepare options
-	var resizeOptions *transform.ResizeOptions
-	if !widthDim.IsZero() || !heightDim.IsZero() {
-		resizeMode := getResizeMode(mode)
-		resizeOptions = &transform.ResizeOptions{
-			WidthDim:  widthDim,
-			HeightDim: heightDim,
-			Mode:      resizeMode,
-			Quality:   quality,
-		}
-	}
-	
-	options := batch.ProcessOptions{
-		ResizeOptions: resizeOptions,
-		DPI:           dpi,
-	}
-	
 	// Progress callback
 	fmt.Println("Converting images...")
 	progressCallback := func(current, total int, fileName string, success bool) {
@@ -113,8 +138,8 @@ func runConvert(cmd *cobra.Command, args []string) error {
 		if !success {
 			status = "❌"
 		}
-		fmt.Printf("[%d/%d] %s → %s %s\n", current, total, fileName, 
-			strings.TrimSuffix(fileName, filepath.Ext(fileName))+"_converted"+filepath.Ext(fileName), status)
+		fmt.Printf("[%d/%d] %s → %s %s\n", current, total, fileName,
+			batch.GenerateOutputPathWithFormat(fileName, options.Format), status)
 	}
 	
 	// Process files
@@ -134,112 +159,153 @@ func runConvert(cmd *cobra.Command, args []string) error {
 	} else {
 		fmt.Println()
 	}
+	if options.PNG.Enabled() && result.SuccessCount > 0 {
+		printBytesSaved(result.InputBytes, result.OutputBytes)
+	}
 	
 	return nil
 }
 
-// processSingleFile handles single file conversion
-func processSingleFile(transformer *transform.Transformer, inputPath, outputPath string) error {
-	// Show progress
-	bar := progressbar.Default(-1, "이미지 변환 중...")
+// buildConvertOptions builds batch options from the command line flags
+func buildConvertOptions() (batch.ProcessOptions, error) {
+	options := batch.ProcessOptions{
+		Quality: quality,
+	}
+	
+	// Parse resolution
+	if dpi != "" {
+		resolution, err := transform.ParseResolution(dpi)
+		if err != nil {
+			return options, fmt.Errorf("잘못된 dpi 값: %w", err)
+		}
+		options.Resolution = resolution
+	}
 	
 	// Parse dimensions
 	widthDim, err := transform.ParseDimension(width)
 	if err != nil {
-		return fmt.Errorf("잘못된 width 값: %w", err)
+		return options, fmt.Errorf("잘못된 width 값: %w", err)
 	}
 	heightDim, err := transform.ParseDimension(height)
 	if err != nil {
-		return fmt.Errorf("잘못된 height 값: %w", err)
+		return options, fmt.Errorf("잘못된 height 값: %w", err)
 	}
-	
-	// Process based on flags
-	if !widthDim.IsZero() || !heightDim.IsZero() {
-		// Open input file for resize
-		inputFile, err := os.Open(inputPath)
-		if err != nil {
-			return fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
-		}
-		defer func() { _ = inputFile.Close() }()
-		
-		// Create output file
-		outputFile, err := os.Create(outputPath)
-		if err != nil {
-			return fmt.Errorf("출력 파일을 생성할 수 없습니다: %w", err)
+	var pixelCap int64
+	if maxOutPixels != "" {
+		if pixelCap, err = transform.ParsePixelCount(maxOutPixels); err != nil {
+			return options, fmt.Errorf("잘못된 max-output-pixels 값: %w", err)
 		}
-		defer func() { _ = outputFile.Close() }()
-		
-		// Resize operation
-		resizeMode := getResizeMode(mode)
-		options := transform.ResizeOptions{
+	}
+	if !widthDim.IsZero() || !heightDim.IsZero() || pixelCap > 0 {
+		options.ResizeOptions = &transform.ResizeOptions{
 			WidthDim:  widthDim,
 			HeightDim: heightDim,
-			Mode:      resizeMode,
+			Mode:      getResizeMode(mode),
 			Quality:   quality,
+			MaxPixels: pixelCap,
 		}
-		
-		bar.Describe("크기 변환 중...")
-		if err := transformer.Resize(inputFile, outputFile, options); err != nil {
-			return fmt.Errorf("크기 변환 실패: %w", err)
-		}
-		
-		// If DPI is also specified, we need to process it separately
-		if dpi > 0 {
-			// Re-open files for DPI processing
-			_ = inputFile.Close()
-			_ = outputFile.Close()
-			
-			// Use the resized output as input for DPI change
-			tempFile, err := os.Open(outputPath)
-			if err != nil {
-				return fmt.Errorf("임시 파일을 열 수 없습니다: %w", err)
-			}
-			defer func() { _ = tempFile.Close() }()
-			
-			outputFile2, err := os.Create(outputPath + ".tmp")
-			if err != nil {
-				return fmt.Errorf("임시 출력 파일을 생성할 수 없습니다: %w", err)
-			}
-			defer func() { _ = outputFile2.Close() }()
-			
-			bar.Describe("DPI 설정 중...")
-			if err := transformer.SetDPI(tempFile, outputFile2, dpi); err != nil {
-				return fmt.Errorf("DPI 설정 실패: %w", err)
-			}
-			
-			// Replace original with DPI-adjusted version
-			if err := os.Rename(outputPath+".tmp", outputPath); err != nil {
-				return fmt.Errorf("failed to rename temporary file: %w", err)
-			}
-		}
-	} else if dpi > 0 {
-		// Open input file for DPI
-		inputFile, err := os.Open(inputPath)
+	}
+	
+	// Parse output format
+	if outputFormat != "" {
+		format, err := transform.ParseImageFormat(outputFormat)
 		if err != nil {
-			return fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
+			return options, fmt.Errorf("잘못된 format 값: %w", err)
 		}
-		defer func() { _ = inputFile.Close() }()
-		
-		// Create output file
-		outputFile, err := os.Create(outputPath)
+		if err := transform.ValidateOutputFormat(format); err != nil {
+			return options, fmt.Errorf("지원하지 않는 출력 형식: %w", err)
+		}
+		options.Format = format
+	}
+	
+	// Parse PNG optimization options
+	if colors != 0 && (colors < 2 || colors > 256) {
+		return options, fmt.Errorf("잘못된 colors 값: 2에서 256 사이여야 합니다 (%d)", colors)
+	}
+	options.PNG = transform.PNGOptions{Optimize: pngOptimize, Colors: colors}
+	
+	// Parse JPEG encoder options
+	options.JPEG.Progressive = progressive
+	if subsampling != "" {
+		mode, err := transform.ParseChromaSubsampling(subsampling)
 		if err != nil {
-			return fmt.Errorf("출력 파일을 생성할 수 없습니다: %w", err)
+			return options, fmt.Errorf("잘못된 subsampling 값: %w", err)
 		}
-		defer func() { _ = outputFile.Close() }()
-		
-		// DPI only operation
-		bar.Describe("DPI 설정 중...")
-		if err := transformer.SetDPI(inputFile, outputFile, dpi); err != nil {
-			return fmt.Errorf("DPI 설정 실패: %w", err)
+		options.JPEG.Subsampling = mode
+	}
+	
+	return options, nil
+}
+
+// inferOutputFormat picks the output format from the output file extension
+// when --format is not given, and rejects extensions that contradict --format
+func inferOutputFormat(options *batch.ProcessOptions, inputPath, outputPath string) error {
+	ext := filepath.Ext(outputPath)
+	extFormat, ok := transform.FormatFromPath(outputPath)
+	
+	if options.Format != "" {
+		if ok && extFormat != options.Format {
+			return fmt.Errorf("출력 파일 확장자(%s)가 --format(%s)과 일치하지 않습니다", ext, options.Format)
 		}
+		return nil
+	}
+	
+	// Only an actual format change counts as a conversion option
+	if inputFormat, _ := transform.FormatFromPath(inputPath); ok && extFormat != inputFormat {
+		options.Format = extFormat
+	}
+	return nil
+}
+
+// warnWebPQuality warns that --quality has no effect on WebP output, which is lossless
+func warnWebPQuality(cmd *cobra.Command, format transform.ImageFormat) {
+	if format == transform.FormatWebP && cmd.Flags().Changed("quality") {
+		fmt.Println("⚠️  WebP는 무손실로 저장되므로 --quality는 적용되지 않습니다")
+	}
+}
+
+// validateConvertOptions checks that at least one conversion was requested
+func validateConvertOptions(options batch.ProcessOptions) error {
+	if !options.HasConversion() {
+		return fmt.Errorf("변환 옵션을 지정해주세요 (--width, --height, --dpi, --format, --png-optimize, --progressive 또는 --subsampling)")
+	}
+	return nil
+}
+
+// processSingleFile handles single file conversion
+func processSingleFile(processor *batch.Processor, inputPath, outputPath string, options batch.ProcessOptions) error {
+	// Show progress
+	bar := progressbar.Default(-1, "이미지 변환 중...")
+	
+	if err := processor.ProcessSingleFile(inputPath, outputPath, options); err != nil {
+		return fmt.Errorf("변환 실패: %w", err)
 	}
 	
 	_ = bar.Finish()
 	fmt.Printf("✅ 변환 완료: %s\n", outputPath)
 	
+	if options.PNG.Enabled() {
+		inputInfo, inErr := os.Stat(inputPath)
+		outputInfo, outErr := os.Stat(outputPath)
+		if inErr == nil && outErr == nil {
+			printBytesSaved(inputInfo.Size(), outputInfo.Size())
+		}
+	}
+	
 	return nil
 }
 
+// printBytesSaved reports the size change of optimized output
+func printBytesSaved(inputSize, outputSize int64) {
+	saved := inputSize - outputSize
+	percent := 0.0
+	if inputSize > 0 {
+		percent = float64(saved) / float64(inputSize) * 100
+	}
+	fmt.Printf("📦 용량: %s → %s (%s 절감, %.1f%%)\n",
+		formatFileSize(inputSize), formatFileSize(outputSize), formatFileSize(saved), percent)
+}
+
 func getResizeMode(mode string) transform.ResizeMode {
 	switch strings.ToLower(mode) {
 	case "fill":
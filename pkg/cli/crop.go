@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -17,6 +18,7 @@ var (
 	cropBottom string
 	cropLeft   string
 	cropRight  string
+	cropLossless bool
 )
 
 var cropCmd = &cobra.Command{
@@ -34,7 +36,13 @@ var cropCmd = &cobra.Command{
   imagekit crop --top=15% "photos/*.png"                      # photos 디렉토리의 png 파일들 상단 15% 제거
   
   # 모든 가장자리 크롭
-  imagekit crop --top=20 --bottom=20 --left=20 --right=20 input.jpg output.jpg`,
+  imagekit crop --top=20 --bottom=20 --left=20 --right=20 input.jpg output.jpg
+  
+  # JPEG 무손실 크롭 (좌측/상단 경계를 블록 단위로 맞춤)
+  imagekit crop --top=100 --left=50 --lossless photo.jpg cropped.jpg
+
+JPEG는 좌측/상단 경계가 8 또는 16픽셀 블록에 맞으면 재인코딩 없이 잘라냅니다.
+--lossless를 지정하면 경계를 블록에 맞게 조정해 항상 무손실로 처리합니다.`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runCrop,
 }
@@ -44,6 +52,7 @@ func init() {
 	cropCmd.Flags().StringVar(&cropBottom, "bottom", "", "하단에서 제거할 영역 (픽셀 또는 %)")
 	cropCmd.Flags().StringVar(&cropLeft, "left", "", "좌측에서 제거할 영역 (픽셀 또는 %)")
 	cropCmd.Flags().StringVar(&cropRight, "right", "", "우측에서 제거할 영역 (픽셀 또는 %)")
+	cropCmd.Flags().BoolVar(&cropLossless, "lossless", false, "JPEG 크롭 경계를 블록에 맞춰 재인코딩 없이 처리")
 }
 
 func runCrop(cmd *cobra.Command, args []string) error {
@@ -62,6 +71,7 @@ func runCrop(cmd *cobra.Command, args []string) error {
 	
 	// Create transformer
 	transformer := transform.NewTransformer()
+	transformer.SetLogger(verboseLogf)
 	
 	// Check if it's a glob pattern or contains wildcards
 	hasGlob := strings.Contains(inputPattern, "*") || strings.Contains(inputPattern, "?") || strings.Contains(inputPattern, "[")
@@ -87,7 +97,7 @@ func runCrop(cmd *cobra.Command, args []string) error {
 }
 
 func parseCropOptions() (transform.EdgeCropOptions, error) {
-	options := transform.EdgeCropOptions{}
+	options := transform.EdgeCropOptions{Lossless: cropLossless}
 	
 	if cropTop != "" {
 		top, err := transform.ParseCropValue(cropTop)
@@ -135,17 +145,15 @@ func processSingleCropFile(transformer *transform.Transformer, inputPath, output
 	}
 	defer func() { _ = inputFile.Close() }()
 	
-	// Create output file
-	outputFile, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("출력 파일을 생성할 수 없습니다: %w", err)
-	}
-	defer func() { _ = outputFile.Close() }()
-	
-	// Perform crop
-	if err := transformer.CropEdges(inputFile, outputFile, options); err != nil {
+	// Write the output only after the whole image was processed, so a failure
+	// doesn't leave an empty file behind
+	output := &bytes.Buffer{}
+	if err := transformer.CropEdges(inputFile, output, options); err != nil {
 		return fmt.Errorf("크롭 실패: %w", err)
 	}
+	if err := os.WriteFile(outputPath, output.Bytes(), 0644); err != nil {
+		return fmt.Errorf("출력 파일을 생성할 수 없습니다: %w", err)
+	}
 	
 	_ = bar.Finish()
 	fmt.Printf("✅ 크롭 완료: %s\n", outputPath)
@@ -173,8 +181,7 @@ func processBatchCrop(transformer *transform.Transformer, pattern string, option
 		}
 		
 		// Check if it's a supported image format
-		ext := strings.ToLower(filepath.Ext(match))
-		if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+		if !batch.IsImageFile(match) {
 			continue
 		}
 		
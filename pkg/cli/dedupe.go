@@ -0,0 +1,297 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/batch"
+	"github.com/allieus/imagekit/pkg/imagehash"
+	"github.com/allieus/imagekit/pkg/transform"
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dedupeHash     string
+	dedupeDistance int
+	dedupeJSON     string
+	dedupeMoveTo   string
+	dedupeDryRun   bool
+	dedupeSort     string
+)
+
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe [input-pattern or files...]",
+	Short: "지각 해시로 중복·유사 이미지 찾기",
+	Long: `크기나 압축만 다르게 저장된 같은 사진을 지각 해시(perceptual hash)로 찾아 묶습니다.
+그룹 안의 모든 이미지 쌍은 해시가 다른 비트 수(해밍 거리)가 --distance 이하입니다.
+A와 B, B와 C가 비슷해도 A와 C가 멀면 C는 A와 같은 그룹이 되지 않습니다.
+
+  ahash  8x8 축소 이미지의 평균 밝기 비교, 가장 빠름
+  dhash  9x8 축소 이미지의 좌우 밝기 변화 비교
+  phash  32x32 축소 이미지의 저주파 DCT 성분 비교, 크기 변경·재압축·색 보정에 가장 강함
+
+예제:
+  # 하위 폴더까지 중복 찾기
+  imagekit dedupe "assets/**/*.jpg"
+
+  # 더 엄격하게 비교하고 결과를 JSON으로 저장
+  imagekit dedupe "assets/**/*.jpg" "assets/**/*.png" --distance 2 --json duplicates.json
+
+  # 그룹마다 해상도가 가장 큰 파일만 남기고 나머지는 duplicates 폴더로 이동 (먼저 --dry-run으로 확인)
+  imagekit dedupe "assets/**/*" --move-to duplicates --dry-run
+  imagekit dedupe "assets/**/*" --move-to duplicates
+
+"**"는 여러 단계의 하위 폴더와 일치합니다.
+ahash와 dhash는 하늘처럼 밋밋한 영역이 많은 사진에서 값이 쉽게 흔들리므로 --distance를 10 정도로 늘려 사용하세요.
+남길 파일은 픽셀 수가 가장 많은 파일이며, 같으면 용량이 큰 파일, 그래도 같으면 먼저 나온 파일입니다.
+이동할 폴더에 같은 이름이 있으면 이름 뒤에 -2, -3 등을 붙입니다.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDedupe,
+}
+
+func init() {
+	dedupeCmd.Flags().StringVar(&dedupeHash, "hash", "phash", "지각 해시 종류 (ahash, dhash, phash)")
+	dedupeCmd.Flags().IntVar(&dedupeDistance, "distance", 5, "같은 그룹으로 볼 최대 해밍 거리 (0-64, 0 = 해시가 같을 때만)")
+	dedupeCmd.Flags().StringVar(&dedupeJSON, "json", "", "결과를 저장할 JSON 파일")
+	dedupeCmd.Flags().StringVar(&dedupeMoveTo, "move-to", "", "그룹마다 해상도가 가장 큰 파일만 남기고 나머지를 옮길 폴더")
+	dedupeCmd.Flags().BoolVar(&dedupeDryRun, "dry-run", false, "--move-to로 옮길 파일을 표시만 하고 옮기지 않음")
+	dedupeCmd.Flags().StringVar(&dedupeSort, "sort", "name", "이미지 순서 (name, time, none = 입력 순서)")
+}
+
+// dedupeFile is one image of a duplicate group
+type dedupeFile struct {
+	Path     string `json:"path"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	Size     int64  `json:"size"`
+	Hash     string `json:"hash"`
+	Distance int    `json:"distance"` // Distance to the kept file
+	Keep     bool   `json:"keep"`
+}
+
+// dedupeReport is the JSON report of the dedupe command
+type dedupeReport struct {
+	Hash        transform.HashAlgorithm `json:"hash"`
+	MaxDistance int                     `json:"maxDistance"`
+	Images      int                     `json:"images"`
+	Groups      [][]dedupeFile          `json:"groups"`
+}
+
+func runDedupe(cmd *cobra.Command, args []string) error {
+	algorithm, err := transform.ParseHashAlgorithm(dedupeHash)
+	if err != nil {
+		return fmt.Errorf("잘못된 hash 값: %w", err)
+	}
+	if dedupeDistance < 0 || dedupeDistance > 64 {
+		return fmt.Errorf("distance는 0에서 64 사이여야 합니다: %d", dedupeDistance)
+	}
+	if dedupeDryRun && dedupeMoveTo == "" {
+		return fmt.Errorf("--dry-run은 --move-to와 함께 사용하세요")
+	}
+	files, err := batch.FindImageFiles(args)
+	if err != nil {
+		return err
+	}
+	if err := batch.SortFiles(files, dedupeSort); err != nil {
+		return fmt.Errorf("잘못된 sort 값: %w", err)
+	}
+	
+	// Create transformer
+	transformer := transform.NewTransformer()
+	transformer.SetLogger(verboseLogf)
+	
+	// Hash every image, keeping the ones that could be read
+	var images []dedupeFile
+	var hashes []imagehash.Hash
+	var failures []string
+	bar := progressbar.Default(int64(len(files)), "해시 계산 중...")
+	for _, path := range files {
+		entry, hash, err := hashImageFile(transformer, path, algorithm)
+		_ = bar.Add(1)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s ❌ %v", path, err))
+			continue
+		}
+		images = append(images, entry)
+		hashes = append(hashes, hash)
+	}
+	_ = bar.Finish()
+	for _, failure := range failures {
+		fmt.Println(failure)
+	}
+	
+	report := dedupeReport{Hash: algorithm, MaxDistance: dedupeDistance, Images: len(images), Groups: [][]dedupeFile{}}
+	duplicates := 0
+	for _, members := range imagehash.Group(hashes, dedupeDistance) {
+		group := make([]dedupeFile, len(members))
+		keep := 0
+		for i, index := range members {
+			group[i] = images[index]
+			if betterCopy(group[i], group[keep]) {
+				keep = i
+			}
+		}
+		group[keep].Keep = true
+		for i, index := range members {
+			group[i].Distance = imagehash.Distance(hashes[index], hashes[members[keep]])
+		}
+		report.Groups = append(report.Groups, group)
+		duplicates += len(group) - 1
+	}
+	
+	if dedupeJSON != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(dedupeJSON, append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("JSON 파일을 저장할 수 없습니다: %w", err)
+		}
+	}
+	printDedupeReport(report, duplicates)
+	if dedupeJSON != "" {
+		fmt.Printf("✅ JSON 저장: %s\n", dedupeJSON)
+	}
+	
+	if dedupeMoveTo != "" && duplicates > 0 {
+		if err := moveDuplicates(report.Groups); err != nil {
+			return err
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d/%d 파일 실패", len(failures), len(files))
+	}
+	return nil
+}
+
+// hashImageFile opens one file and computes its perceptual hash
+func hashImageFile(transformer *transform.Transformer, path string, algorithm transform.HashAlgorithm) (dedupeFile, imagehash.Hash, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return dedupeFile{}, 0, fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	
+	info, err := file.Stat()
+	if err != nil {
+		return dedupeFile{}, 0, fmt.Errorf("파일 정보 가져오기 실패: %w", err)
+	}
+	result, err := transformer.Hash(file, algorithm)
+	if err != nil {
+		return dedupeFile{}, 0, err
+	}
+	return dedupeFile{Path: path, Width: result.Width, Height: result.Height, Size: info.Size(), Hash: result.Hash.String()}, result.Hash, nil
+}
+
+// betterCopy reports whether a should be kept over b: more pixels first, then the
+// larger file
+func betterCopy(a, b dedupeFile) bool {
+	pixelsA, pixelsB := a.Width*a.Height, b.Width*b.Height
+	if pixelsA != pixelsB {
+		return pixelsA > pixelsB
+	}
+	return a.Size > b.Size
+}
+
+// printDedupeReport prints the duplicate groups as a table
+func printDedupeReport(report dedupeReport, duplicates int) {
+	if len(report.Groups) == 0 {
+		fmt.Printf("✅ 중복 없음 (이미지 %d개, %s, 거리 %d 이하)\n", report.Images, report.Hash, report.MaxDistance)
+		return
+	}
+	
+	fmt.Printf("🔍 중복 그룹 %d개, 중복 파일 %d개 (이미지 %d개, %s, 거리 %d 이하)\n", len(report.Groups), duplicates, report.Images, report.Hash, report.MaxDistance)
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	for i, group := range report.Groups {
+		fmt.Printf("그룹 %d\n", i+1)
+		for _, file := range group {
+			if file.Keep {
+				fmt.Printf("  ✅ %s  %dx%d  %s\n", file.Path, file.Width, file.Height, formatFileSize(file.Size))
+			} else {
+				fmt.Printf("  ♻️  %s  %dx%d  %s  (거리 %d)\n", file.Path, file.Width, file.Height, formatFileSize(file.Size), file.Distance)
+			}
+		}
+	}
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+}
+
+// moveDuplicates moves every file that is not kept to the --move-to folder
+func moveDuplicates(groups [][]dedupeFile) error {
+	if !dedupeDryRun {
+		if err := os.MkdirAll(dedupeMoveTo, 0755); err != nil {
+			return fmt.Errorf("이동할 폴더를 만들 수 없습니다: %w", err)
+		}
+	}
+	
+	moved := 0
+	taken := make(map[string]bool)
+	for _, group := range groups {
+		for _, file := range group {
+			if file.Keep {
+				continue
+			}
+			target := uniqueMovePath(file.Path, taken)
+			taken[target] = true
+			if dedupeDryRun {
+				fmt.Printf("📦 %s → %s (dry-run)\n", file.Path, target)
+				continue
+			}
+			if err := moveFile(file.Path, target); err != nil {
+				return fmt.Errorf("파일을 옮길 수 없습니다: %s: %w", file.Path, err)
+			}
+			fmt.Printf("📦 %s → %s\n", file.Path, target)
+			moved++
+		}
+	}
+	if !dedupeDryRun {
+		fmt.Printf("✅ %d개 파일 이동: %s\n", moved, dedupeMoveTo)
+	}
+	return nil
+}
+
+// uniqueMovePath returns a path in the --move-to folder for a file, numbering the name
+// when it is already used
+func uniqueMovePath(path string, taken map[string]bool) string {
+	ext := filepath.Ext(path)
+	name := strings.TrimSuffix(filepath.Base(path), ext)
+	target := filepath.Join(dedupeMoveTo, name+ext)
+	for n := 2; ; n++ {
+		if _, err := os.Stat(target); os.IsNotExist(err) && !taken[target] {
+			return target
+		}
+		target = filepath.Join(dedupeMoveTo, fmt.Sprintf("%s-%d%s", name, n, ext))
+	}
+}
+
+// moveFile renames a file, copying it when the target is on another file system
+func moveFile(source, target string) error {
+	if err := os.Rename(source, target); err == nil {
+		return nil
+	}
+	
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(target)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		_ = os.Remove(target)
+		return err
+	}
+	_ = in.Close()
+	return os.Remove(source)
+}
\ No newline at end of file
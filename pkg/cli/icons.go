@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	
+	"github.com/allieus/imagekit/pkg/transform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	iconsOut             string
+	iconsBackground      string
+	iconsAppleBackground string
+	iconsPadding         float64
+	iconsICNS            bool
+	iconsBaseURL         string
+)
+
+var iconsCmd = &cobra.Command{
+	Use:   "icons [input-file]",
+	Short: "앱 아이콘과 파비콘 세트 생성 (PNG, ICO, ICNS)",
+	Long: `로고 이미지 하나로 PWA, Android, Apple 아이콘과 favicon.ico를 만들고 manifest.json의 icons 항목을 저장합니다.
+정사각형이 아닌 이미지는 투명한 정사각형 가운데에 놓이며, 512x512 이상의 원본을 권장합니다.
+
+예제:
+  # icons/ 폴더에 전체 아이콘 세트 생성
+  imagekit icons logo.png --out icons/
+
+  # macOS용 icon.icns도 생성
+  imagekit icons --icns logo.png --out icons/
+
+  # 마스커블 아이콘의 안전 영역을 위해 10% 여백, 흰 배경
+  imagekit icons --padding 10 --background=#ffffff logo.png --out web/static/icons/
+
+생성 파일: icon-16x16.png … icon-512x512.png, apple-touch-icon.png (180x180), favicon.ico (16, 32, 48), manifest.json
+iOS는 투명 부분을 검게 표시하므로 apple-touch-icon.png는 항상 배경색으로 채웁니다 (--apple-background).`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIcons,
+}
+
+func init() {
+	iconsCmd.Flags().StringVar(&iconsOut, "out", "icons", "출력 폴더")
+	iconsCmd.Flags().StringVar(&iconsBackground, "background", "", "모든 아이콘의 배경 색 (기본값: 투명 유지)")
+	iconsCmd.Flags().StringVar(&iconsAppleBackground, "apple-background", "#ffffff", "apple-touch-icon.png의 배경 색 (--background가 없을 때)")
+	iconsCmd.Flags().Float64Var(&iconsPadding, "padding", 0, "가장자리 여백 (%, 0-40)")
+	iconsCmd.Flags().BoolVar(&iconsICNS, "icns", false, "macOS용 icon.icns도 생성")
+	iconsCmd.Flags().StringVar(&iconsBaseURL, "base-url", "/static/icons/", "manifest.json의 src 앞에 붙일 경로")
+}
+
+func runIcons(cmd *cobra.Command, args []string) error {
+	inputPath := args[0]
+	
+	options, err := parseIconOptions()
+	if err != nil {
+		return err
+	}
+	
+	inputFile, err := os.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
+	}
+	defer func() { _ = inputFile.Close() }()
+	
+	// Create transformer
+	transformer := transform.NewTransformer()
+	transformer.SetLogger(verboseLogf)
+	
+	files, err := transformer.Icons(inputFile, options)
+	if err != nil {
+		return fmt.Errorf("아이콘 생성 실패: %w", err)
+	}
+	
+	if err := os.MkdirAll(iconsOut, 0755); err != nil {
+		return fmt.Errorf("출력 폴더를 만들 수 없습니다: %w", err)
+	}
+	for _, file := range files {
+		path := filepath.Join(iconsOut, file.Name)
+		if err := os.WriteFile(path, file.Data, 0644); err != nil {
+			return fmt.Errorf("출력 파일을 저장할 수 없습니다: %w", err)
+		}
+		fmt.Printf("✅ %s (%s)\n", path, formatFileSize(int64(len(file.Data))))
+	}
+	
+	manifest, err := transform.IconManifest(files, iconsBaseURL)
+	if err != nil {
+		return err
+	}
+	manifestPath := filepath.Join(iconsOut, "manifest.json")
+	if err := os.WriteFile(manifestPath, append(manifest, '\n'), 0644); err != nil {
+		return fmt.Errorf("JSON 파일을 저장할 수 없습니다: %w", err)
+	}
+	fmt.Printf("✅ %s (web/manifest.json의 icons 항목)\n", manifestPath)
+	
+	return nil
+}
+
+// parseIconOptions builds icon options from the command line flags
+func parseIconOptions() (transform.IconOptions, error) {
+	options := transform.IconOptions{Padding: iconsPadding / 100, ICNS: iconsICNS}
+	if iconsBackground != "" {
+		background, err := transform.ParseHexColor(iconsBackground)
+		if err != nil {
+			return options, fmt.Errorf("잘못된 background 값: %w", err)
+		}
+		options.Background = background
+	}
+	appleBackground, err := transform.ParseHexColor(iconsAppleBackground)
+	if err != nil {
+		return options, fmt.Errorf("잘못된 apple-background 값: %w", err)
+	}
+	options.AppleBackground = appleBackground
+	if err := transform.ValidateIconOptions(options); err != nil {
+		return options, fmt.Errorf("padding은 0에서 40 사이여야 합니다: %g", iconsPadding)
+	}
+	return options, nil
+}
\ No newline at end of file
@@ -11,12 +11,23 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	infoPalette bool
+	infoColors  int
+)
+
 var infoCmd = &cobra.Command{
 	Use:   "info [image]",
 	Short: "이미지 정보 표시",
-	Long:  `이미지의 크기, 형식, DPI 등의 정보를 표시합니다.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runInfo,
+	Long: `이미지의 크기, 형식, DPI 등의 정보를 표시합니다.
+--palette를 지정하면 주요 색상과 비율도 표시합니다 (자세한 출력은 palette 명령어 참고).`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInfo,
+}
+
+func init() {
+	infoCmd.Flags().BoolVar(&infoPalette, "palette", false, "주요 색상 팔레트 표시")
+	infoCmd.Flags().IntVar(&infoColors, "colors", 6, "--palette 색상 수 (1-64)")
 }
 
 func runInfo(cmd *cobra.Command, args []string) error {
@@ -44,10 +55,11 @@ func runInfo(cmd *cobra.Command, args []string) error {
 	// Get image info
 	info := transform.GetImageInfo(img, format)
 	
-	// Try to get DPI information
-	dpi, _ := transform.GetImageDPI(bytes.NewReader(buf.Bytes()), format)
-	if dpi > 0 {
-		info.DPI = dpi
+	// Try to get resolution information
+	if resolution, err := transform.GetImageResolution(bytes.NewReader(buf.Bytes()), format); err == nil {
+		info.Resolution = resolution
+		x, _ := resolution.DPI()
+		info.DPI = int(x + 0.5)
 	}
 	
 	// Get file info
@@ -62,9 +74,18 @@ func runInfo(cmd *cobra.Command, args []string) error {
 	fmt.Printf("📁 파일명: %s\n", imagePath)
 	fmt.Printf("📏 크기: %d x %d 픽셀\n", info.Width, info.Height)
 	fmt.Printf("🎨 형식: %s\n", strings.ToUpper(string(info.Format)))
-	fmt.Printf("📐 DPI: %d\n", info.DPI)
+	fmt.Printf("📐 해상도: %s\n", info.Resolution)
+	printWidth, printHeight := info.Resolution.PrintSize(info.Width, info.Height)
+	fmt.Printf("🖨️  인쇄 크기: %.2f x %.2f cm (%.2f x %.2f in)\n", printWidth*2.54, printHeight*2.54, printWidth, printHeight)
 	fmt.Printf("💾 파일 크기: %s\n", formatFileSize(fileInfo.Size()))
 	fmt.Printf("📅 수정 시간: %s\n", fileInfo.ModTime().Format("2006-01-02 15:04:05"))
+	if infoPalette {
+		colors, err := transform.ExtractPalette(img, transform.PaletteOptions{Colors: infoColors})
+		if err != nil {
+			return fmt.Errorf("팔레트 추출 실패: %w", err)
+		}
+		fmt.Printf("🌈 팔레트: %s\n", paletteSummary(colors))
+	}
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	
 	return nil
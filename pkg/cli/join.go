@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	
+	"github.com/allieus/imagekit/pkg/batch"
+	"github.com/allieus/imagekit/pkg/transform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	joinDirection   string
+	joinColumns     int
+	joinGap         string
+	joinAlign       string
+	joinScale       string
+	joinBackground  string
+	joinDPI         string
+	joinFormat      string
+	joinQuality     int
+	joinPNGOptimize bool
+	joinColors      int
+	joinSubsampling string
+	joinProgressive bool
+	joinSort        string
+)
+
+var joinCmd = &cobra.Command{
+	Use:   "join [input-files...] [output-file]",
+	Short: "여러 이미지를 가로, 세로 또는 격자로 이어 붙이기",
+	Long: `여러 이미지를 한 장으로 이어 붙입니다. 전후 비교 이미지나 세로로 이어 붙인 스크린샷을 만들 때 사용합니다.
+이미지는 입력한 순서대로 배치됩니다.
+
+예제:
+  # 전후 비교를 나란히, 10픽셀 간격
+  imagekit join --gap 10 before.jpg after.jpg compare.jpg
+
+  # 스크린샷을 세로로 이어 붙이고 가장 좁은 폭에 맞춤
+  imagekit join --direction vertical --scale smallest "shots/*.png" long.png
+
+  # 3열 격자, 위쪽 정렬, 어두운 배경
+  imagekit join --direction grid --columns 3 --align top --background "#202020" *.jpg grid.jpg
+
+  # 높이를 1080픽셀로 맞추고 300 DPI, 간격 5mm
+  imagekit join --scale 1080 --dpi 300 --gap 5mm a.jpg b.jpg out.jpg
+
+--scale은 가로로 붙일 때는 높이를, 세로와 격자로 붙일 때는 폭을 맞춥니다 (none, smallest, largest 또는 픽셀).
+크기가 다른 이미지는 행이나 열 안에서 --align에 따라 정렬되고 남는 곳은 배경색으로 채웁니다.
+출력 해상도는 --dpi를 지정하지 않으면 첫 번째 이미지의 해상도를 따릅니다.
+출력 파일이 입력 패턴과 일치하면 입력에서 제외합니다.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runJoin,
+}
+
+func init() {
+	joinCmd.Flags().StringVar(&joinDirection, "direction", "horizontal", "붙이는 방향 (horizontal, vertical, grid)")
+	joinCmd.Flags().IntVar(&joinColumns, "columns", 0, "격자의 열 수 (0 = 정사각형에 가깝게)")
+	joinCmd.Flags().StringVar(&joinGap, "gap", "0", "이미지 사이 간격 (픽셀 또는 인쇄 크기: 10, 5mm)")
+	joinCmd.Flags().StringVar(&joinAlign, "align", "center", "행이나 열 안의 정렬 (start, center, end; top, left, bottom, right)")
+	joinCmd.Flags().StringVar(&joinScale, "scale", "none", "크기 맞춤 (none, smallest, largest 또는 픽셀)")
+	joinCmd.Flags().StringVar(&joinBackground, "background", "#ffffff", "간격과 빈 곳의 배경색")
+	joinCmd.Flags().StringVar(&joinDPI, "dpi", "", "출력 해상도 (72, 300, 가로x세로: 300x150; 기본값: 첫 번째 이미지 해상도)")
+	joinCmd.Flags().StringVar(&joinFormat, "format", "", "출력 형식 (jpeg, png, webp, gif; 기본값: 출력 파일 확장자)")
+	joinCmd.Flags().IntVar(&joinQuality, "quality", 95, "JPEG 품질 (1-100)")
+	joinCmd.Flags().BoolVar(&joinPNGOptimize, "png-optimize", false, "PNG 용량 최적화")
+	joinCmd.Flags().IntVar(&joinColors, "colors", 0, "PNG 팔레트 색상 수로 양자화 (2-256)")
+	joinCmd.Flags().StringVar(&joinSubsampling, "subsampling", "", "JPEG 크로마 서브샘플링 (444, 422, 420)")
+	joinCmd.Flags().BoolVar(&joinProgressive, "progressive", false, "프로그레시브 JPEG로 저장")
+	joinCmd.Flags().StringVar(&joinSort, "sort", "none", "이미지 순서 (none = 입력 순서, name, time)")
+}
+
+func runJoin(cmd *cobra.Command, args []string) error {
+	outputPath := args[len(args)-1]
+	
+	options, err := parseJoinOptions()
+	if err != nil {
+		return err
+	}
+	format, err := joinOutputFormat(outputPath)
+	if err != nil {
+		return err
+	}
+	
+	files, err := batch.FindImageFiles(args[:len(args)-1])
+	if err != nil {
+		return err
+	}
+	if files = batch.ExcludeFiles(files, outputPath); len(files) == 0 {
+		return fmt.Errorf("출력 파일 외에 이어 붙일 이미지가 없습니다")
+	}
+	if err := batch.SortFiles(files, joinSort); err != nil {
+		return fmt.Errorf("잘못된 sort 값: %w", err)
+	}
+	
+	var inputs []io.Reader
+	for _, path := range files {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
+		}
+		defer func() { _ = file.Close() }()
+		inputs = append(inputs, file)
+	}
+	
+	// Create transformer
+	transformer := transform.NewTransformer()
+	transformer.SetBackground(options.Background)
+	transformer.SetLogger(verboseLogf)
+	
+	// Write the output only after every input was read
+	output := &bytes.Buffer{}
+	size, err := transformer.Join(inputs, output, format, options)
+	if err != nil {
+		return fmt.Errorf("이어 붙이기 실패: %w", err)
+	}
+	if err := os.WriteFile(outputPath, output.Bytes(), 0644); err != nil {
+		return fmt.Errorf("출력 파일을 생성할 수 없습니다: %w", err)
+	}
+	fmt.Printf("✅ 이미지 %d장 → %s (%dx%d)\n", len(files), outputPath, size.X, size.Y)
+	
+	return nil
+}
+
+// parseJoinOptions builds join options from the command line flags
+func parseJoinOptions() (transform.JoinOptions, error) {
+	options := transform.JoinOptions{Columns: joinColumns, Quality: joinQuality}
+	var err error
+	
+	if options.Direction, err = transform.ParseJoinDirection(joinDirection); err != nil {
+		return options, fmt.Errorf("잘못된 direction 값: %w", err)
+	}
+	if options.Align, err = transform.ParseJoinAlign(joinAlign); err != nil {
+		return options, fmt.Errorf("잘못된 align 값: %w", err)
+	}
+	if size, err := strconv.Atoi(joinScale); err == nil {
+		if size <= 0 {
+			return options, fmt.Errorf("잘못된 scale 값: %d", size)
+		}
+		options.ScaleSize = size
+	} else if options.Scale, err = transform.ParseJoinScale(joinScale); err != nil {
+		return options, fmt.Errorf("잘못된 scale 값: %w", err)
+	}
+	if options.Gap, err = transform.ParseDimension(joinGap); err != nil {
+		return options, fmt.Errorf("잘못된 gap 값: %w", err)
+	}
+	if options.Background, err = transform.ParseHexColor(joinBackground); err != nil {
+		return options, fmt.Errorf("잘못된 background 값: %w", err)
+	}
+	if joinDPI != "" {
+		if options.Resolution, err = transform.ParseResolution(joinDPI); err != nil {
+			return options, fmt.Errorf("잘못된 dpi 값: %w", err)
+		}
+	}
+	
+	// Encoder options as in convert
+	if joinQuality < 1 || joinQuality > 100 {
+		return options, fmt.Errorf("quality는 1에서 100 사이여야 합니다: %d", joinQuality)
+	}
+	if joinColors != 0 && (joinColors < 2 || joinColors > 256) {
+		return options, fmt.Errorf("잘못된 colors 값: 2에서 256 사이여야 합니다 (%d)", joinColors)
+	}
+	options.PNG = transform.PNGOptions{Optimize: joinPNGOptimize, Colors: joinColors}
+	options.JPEG.Progressive = joinProgressive
+	if joinSubsampling != "" {
+		if options.JPEG.Subsampling, err = transform.ParseChromaSubsampling(joinSubsampling); err != nil {
+			return options, fmt.Errorf("잘못된 subsampling 값: %w", err)
+		}
+	}
+	
+	if err := transform.ValidateJoinOptions(options); err != nil {
+		return options, fmt.Errorf("잘못된 이어 붙이기 옵션: %w", err)
+	}
+	return options, nil
+}
+
+// joinOutputFormat picks the output format from --format or the output extension,
+// rejecting extensions that contradict --format
+func joinOutputFormat(outputPath string) (transform.ImageFormat, error) {
+	extFormat, ok := transform.FormatFromPath(outputPath)
+	if joinFormat == "" {
+		if !ok {
+			return "", fmt.Errorf("출력 파일 형식을 알 수 없습니다: %s", outputPath)
+		}
+		return extFormat, nil
+	}
+	
+	format, err := transform.ParseImageFormat(joinFormat)
+	if err != nil {
+		return "", fmt.Errorf("잘못된 format 값: %w", err)
+	}
+	if err := transform.ValidateOutputFormat(format); err != nil {
+		return "", fmt.Errorf("지원하지 않는 출력 형식: %w", err)
+	}
+	if ok && extFormat != format {
+		return "", fmt.Errorf("출력 파일 확장자(%s)가 --format(%s)과 일치하지 않습니다", filepath.Ext(outputPath), format)
+	}
+	return format, nil
+}
\ No newline at end of file
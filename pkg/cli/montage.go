@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	
+	"github.com/allieus/imagekit/pkg/batch"
+	"github.com/allieus/imagekit/pkg/transform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	montageColumns    int
+	montageRows       int
+	montageTile       string
+	montageSpacing    int
+	montageFit        bool
+	montageLabel      string
+	montageFont       string
+	montageFontSize   float64
+	montageBackground string
+	montageSort       string
+)
+
+var montageCmd = &cobra.Command{
+	Use:   "montage [input-pattern or files...] [output-file]",
+	Short: "썸네일을 격자로 모은 인덱스 시트(밀착 인화) 생성",
+	Long: `여러 이미지의 썸네일을 격자로 배치하고 파일 이름과 크기를 표시한 인덱스 이미지를 만듭니다.
+한 페이지에 다 들어가지 않으면 여러 장으로 나누어 저장합니다 (index-1.jpg, index-2.jpg ...).
+
+예제:
+  # 6열, 300x300 썸네일, 파일 이름과 픽셀 크기 표시
+  imagekit montage "photos/*.jpg" index.jpg --columns 6 --tile 300x300 --label "{name} {width}x{height}"
+
+  # 잘라내지 않고 이미지 전체 표시, 어두운 배경
+  imagekit montage --fit --background=#202020 "exports/*.png" review.png
+
+  # 한 페이지에 모두 배치, 라벨 없이
+  imagekit montage --rows 0 --label "" "*.jpg" all.jpg
+
+라벨에 쓸 수 있는 값: {name} {width} {height} {size} {format} {dpi} {index}
+기본 글꼴에는 한글이 없으므로 한글 파일 이름은 --font로 한글 글꼴을 지정하세요.
+출력 파일과 번호가 붙은 페이지(index-1.jpg ...)가 입력 패턴과 일치하면 입력에서 제외합니다.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runMontage,
+}
+
+func init() {
+	montageCmd.Flags().IntVar(&montageColumns, "columns", 6, "열 수")
+	montageCmd.Flags().IntVar(&montageRows, "rows", 8, "페이지당 행 수 (0 = 한 페이지에 모두)")
+	montageCmd.Flags().StringVar(&montageTile, "tile", "200x200", "썸네일 크기 (픽셀)")
+	montageCmd.Flags().IntVar(&montageSpacing, "spacing", 10, "썸네일 사이 간격 (픽셀)")
+	montageCmd.Flags().BoolVar(&montageFit, "fit", false, "잘라내지 않고 이미지 전체를 썸네일에 맞춤")
+	montageCmd.Flags().StringVar(&montageLabel, "label", "{name}", "썸네일 아래 라벨 (빈 문자열 = 라벨 없음)")
+	montageCmd.Flags().StringVar(&montageFont, "font", "", "라벨 글꼴 파일 (TTF, OTF, TTC)")
+	montageCmd.Flags().Float64Var(&montageFontSize, "font-size", 12, "라벨 글자 크기 (픽셀)")
+	montageCmd.Flags().StringVar(&montageBackground, "background", "#ffffff", "배경 색")
+	montageCmd.Flags().StringVar(&montageSort, "sort", "name", "이미지 순서 (name, time, none = 입력 순서)")
+}
+
+func runMontage(cmd *cobra.Command, args []string) error {
+	outputPath := args[len(args)-1]
+	format, ok := transform.FormatFromPath(outputPath)
+	if !ok {
+		return fmt.Errorf("출력 파일 형식을 알 수 없습니다: %s", outputPath)
+	}
+	
+	// Parse montage options
+	options, err := parseMontageOptions()
+	if err != nil {
+		return err
+	}
+	layout, err := transform.NewMontageLayout(options)
+	if err != nil {
+		return fmt.Errorf("배치 실패: %w", err)
+	}
+	
+	files, err := batch.FindImageFiles(args[:len(args)-1])
+	if err != nil {
+		return err
+	}
+	if files = batch.ExcludeNumberedOutputs(files, outputPath); len(files) == 0 {
+		return fmt.Errorf("출력 파일 외에 처리할 이미지가 없습니다")
+	}
+	if err := batch.SortFiles(files, montageSort); err != nil {
+		return fmt.Errorf("잘못된 sort 값: %w", err)
+	}
+	
+	// Create transformer
+	transformer := transform.NewTransformer()
+	transformer.SetLogger(verboseLogf)
+	
+	pages := layout.Pages(len(files))
+	perPage := layout.PerPage
+	if perPage == 0 {
+		perPage = len(files)
+	}
+	fmt.Printf("이미지 %d장 → %d페이지\n", len(files), pages)
+	
+	for page := 0; page < pages; page++ {
+		start := page * perPage
+		end := min(start+perPage, len(files))
+		pagePath := batch.NumberedOutputPath(outputPath, page+1, pages)
+		
+		if err := renderMontagePage(transformer, files[start:end], start+1, pagePath, format, layout); err != nil {
+			fmt.Printf("[%d/%d] %s ❌\n", page+1, pages, pagePath)
+			return err
+		}
+		fmt.Printf("[%d/%d] %s ✅\n", page+1, pages, pagePath)
+	}
+	
+	return nil
+}
+
+func parseMontageOptions() (transform.MontageOptions, error) {
+	options := transform.MontageOptions{
+		Columns:  montageColumns,
+		Rows:     montageRows,
+		Spacing:  montageSpacing,
+		Fit:      montageFit,
+		Label:    montageLabel,
+		FontSize: montageFontSize,
+	}
+	
+	width, height, err := transform.ParsePrintSize(montageTile)
+	if err != nil || width.IsPhysical() || height.IsPhysical() {
+		return options, fmt.Errorf("잘못된 tile 값: %s (예: 300x300)", montageTile)
+	}
+	options.TileWidth, options.TileHeight = width.Value, height.Value
+	
+	if options.Background, err = transform.ParseHexColor(montageBackground); err != nil {
+		return options, fmt.Errorf("잘못된 background 값: %w", err)
+	}
+	if montageFont != "" {
+		if options.Font, err = os.ReadFile(montageFont); err != nil {
+			return options, fmt.Errorf("글꼴 파일을 읽을 수 없습니다: %w", err)
+		}
+	}
+	
+	return options, nil
+}
+
+// renderMontagePage opens the files for one page and writes the contact sheet
+func renderMontagePage(transformer *transform.Transformer, files []string, index int, outputPath string, format transform.ImageFormat, layout *transform.MontageLayout) error {
+	var items []transform.MontageItem
+	for _, path := range files {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
+		}
+		defer func() { _ = file.Close() }()
+		items = append(items, transform.MontageItem{Name: path, Input: file})
+	}
+	
+	// Write the page only after every thumbnail was rendered
+	output := &bytes.Buffer{}
+	if err := transformer.Montage(items, index, output, format, layout); err != nil {
+		return fmt.Errorf("인덱스 시트 생성 실패: %w", err)
+	}
+	if err := os.WriteFile(outputPath, output.Bytes(), 0644); err != nil {
+		return fmt.Errorf("출력 파일을 생성할 수 없습니다: %w", err)
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/swatch"
+	"github.com/allieus/imagekit/pkg/transform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	paletteColors     int
+	paletteFormat     string
+	paletteMethod     string
+	palettePrefix     string
+	paletteSwatch     string
+	paletteSwatchSize string
+)
+
+var paletteCmd = &cobra.Command{
+	Use:   "palette [input-file] [output-file]",
+	Short: "이미지의 주요 색상 팔레트 추출 (JSON, CSS, ASE)",
+	Long: `사진에서 가장 많이 쓰인 색상을 뽑아 비율과 함께 보여 줍니다.
+색상은 사람이 느끼는 색 차이에 가까운 CIE Lab 공간에서 묶습니다.
+
+  json  색상별 hex, RGB, Lab 값과 비율(%)
+  css   :root { --palette-1: #hex; } 형식의 CSS 변수, 비율 순
+  ase   Adobe Swatch Exchange, Photoshop/Illustrator/InDesign 견본 패널에서 불러오기
+
+예제:
+  # 주요 색상 6개를 화면에 출력
+  imagekit palette photo.jpg
+
+  # 색상 8개를 JSON으로 출력
+  imagekit palette photo.jpg --colors 8 --format json
+
+  # CSS 변수 파일 (--brand-1, --brand-2, ...)
+  imagekit palette photo.jpg palette.css --prefix brand
+
+  # Adobe 견본 파일과 비율대로 칠한 견본 이미지
+  imagekit palette photo.jpg photo.ase --swatch swatch.png
+
+--format을 생략하면 출력 파일 확장자(.json, .css, .ase)를 따르고, 출력 파일이 없으면 표로 출력합니다.
+json과 css는 출력 파일을 생략하면 화면에 출력하고, ase는 출력 파일이 필요합니다.
+--method mediancut은 k-means 보정을 생략해 더 빠르지만 색 구분이 거칩니다.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runPalette,
+}
+
+func init() {
+	paletteCmd.Flags().IntVar(&paletteColors, "colors", 6, "추출할 색상 수 (1-64)")
+	paletteCmd.Flags().StringVar(&paletteFormat, "format", "", "출력 형식 (json, css, ase; 기본값: 출력 파일 확장자, 없으면 표)")
+	paletteCmd.Flags().StringVar(&paletteMethod, "method", "kmeans", "색상을 묶는 방법 (kmeans, mediancut)")
+	paletteCmd.Flags().StringVar(&palettePrefix, "prefix", "palette", "CSS 변수 이름과 ASE 견본 이름의 접두사")
+	paletteCmd.Flags().StringVar(&paletteSwatch, "swatch", "", "팔레트를 비율대로 칠한 견본 PNG 파일")
+	paletteCmd.Flags().StringVar(&paletteSwatchSize, "swatch-size", "600x100", "견본 이미지 크기 (가로x세로 픽셀)")
+}
+
+func runPalette(cmd *cobra.Command, args []string) error {
+	inputPath := args[0]
+	outputPath := ""
+	if len(args) > 1 {
+		outputPath = args[1]
+	}
+	
+	format, err := paletteOutputFormat(outputPath)
+	if err != nil {
+		return err
+	}
+	method, err := transform.ParsePaletteMethod(paletteMethod)
+	if err != nil {
+		return fmt.Errorf("잘못된 method 값: %w", err)
+	}
+	if paletteColors < 1 || paletteColors > 64 {
+		return fmt.Errorf("colors는 1에서 64 사이여야 합니다: %d", paletteColors)
+	}
+	var swatchWidth, swatchHeight int
+	if paletteSwatch != "" {
+		if _, err := fmt.Sscanf(paletteSwatchSize, "%dx%d", &swatchWidth, &swatchHeight); err != nil || swatchWidth < 1 || swatchHeight < 1 {
+			return fmt.Errorf("잘못된 swatch-size 값: %s (예: 600x100)", paletteSwatchSize)
+		}
+	}
+	
+	// Open input file
+	inputFile, err := os.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
+	}
+	defer func() { _ = inputFile.Close() }()
+	
+	// Create transformer
+	transformer := transform.NewTransformer()
+	transformer.SetLogger(verboseLogf)
+	
+	colors, err := transformer.Palette(inputFile, transform.PaletteOptions{Colors: paletteColors, Method: method})
+	if err != nil {
+		return fmt.Errorf("팔레트 추출 실패: %w", err)
+	}
+	
+	var data []byte
+	switch format {
+	case "json":
+		if data, err = json.MarshalIndent(colors, "", "  "); err != nil {
+			return err
+		}
+		data = append(data, '\n')
+	case "css":
+		data = []byte(transform.PaletteCSS(colors, palettePrefix))
+	case "ase":
+		var buf bytes.Buffer
+		swatches := make([]swatch.Swatch, len(colors))
+		for i, c := range colors {
+			swatches[i] = swatch.Swatch{Name: fmt.Sprintf("%s-%d %s", palettePrefix, i+1, c.Hex), Color: c.Color()}
+		}
+		name := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
+		if err := swatch.EncodeASE(&buf, name, swatches); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	default:
+		printPalette(colors)
+	}
+	
+	if data != nil {
+		if outputPath == "" {
+			fmt.Print(string(data))
+		} else {
+			if err := os.WriteFile(outputPath, data, 0644); err != nil {
+				return fmt.Errorf("출력 파일을 저장할 수 없습니다: %w", err)
+			}
+			fmt.Printf("✅ 팔레트 저장: %s (%d색)\n", outputPath, len(colors))
+		}
+	}
+	
+	if paletteSwatch != "" {
+		if err := savePaletteSwatch(colors, swatchWidth, swatchHeight); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// paletteOutputFormat picks the output format from --format or the output extension
+func paletteOutputFormat(outputPath string) (string, error) {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(outputPath)), ".")
+	format := strings.ToLower(strings.TrimSpace(paletteFormat))
+	if format == "" {
+		format = ext
+		if outputPath == "" {
+			return "", nil
+		}
+	}
+	
+	switch format {
+	case "json", "css", "ase":
+	default:
+		if paletteFormat == "" {
+			return "", fmt.Errorf("출력 파일 확장자로 형식을 알 수 없습니다: %s (--format json, css, ase 지정)", outputPath)
+		}
+		return "", fmt.Errorf("잘못된 format 값: %s (json, css, ase)", paletteFormat)
+	}
+	if format == "ase" && outputPath == "" {
+		return "", fmt.Errorf("ase 형식은 출력 파일을 지정하세요")
+	}
+	return format, nil
+}
+
+// printPalette prints the palette as a table
+func printPalette(colors []transform.PaletteColor) {
+	fmt.Println("🎨 팔레트")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	for i, c := range colors {
+		fmt.Printf("%2d. %s  RGB(%3d, %3d, %3d)  %5.1f%%\n", i+1, c.Hex, c.RGB[0], c.RGB[1], c.RGB[2], c.Percent)
+	}
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+}
+
+// savePaletteSwatch draws the palette stripes to the --swatch PNG file
+func savePaletteSwatch(colors []transform.PaletteColor, width, height int) error {
+	img, err := transform.PaletteSwatch(colors, width, height)
+	if err != nil {
+		return fmt.Errorf("견본 이미지 생성 실패: %w", err)
+	}
+	
+	outputFile, err := os.Create(paletteSwatch)
+	if err != nil {
+		return fmt.Errorf("견본 파일을 생성할 수 없습니다: %w", err)
+	}
+	defer func() { _ = outputFile.Close() }()
+	
+	if err := transform.SaveImage(outputFile, img, transform.FormatPNG, 0); err != nil {
+		return fmt.Errorf("견본 저장 실패: %w", err)
+	}
+	fmt.Printf("✅ 견본 저장: %s (%dx%d)\n", paletteSwatch, width, height)
+	return nil
+}
+
+// paletteSummary formats a palette on one line for the info command
+func paletteSummary(colors []transform.PaletteColor) string {
+	parts := make([]string, len(colors))
+	for i, c := range colors {
+		parts[i] = fmt.Sprintf("%s %.1f%%", c.Hex, c.Percent)
+	}
+	return strings.Join(parts, ", ")
+}
\ No newline at end of file
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/batch"
+	"github.com/allieus/imagekit/pkg/transform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pdfPage   string
+	pdfFit    bool
+	pdfMargin string
+	pdfDPI    string
+	pdfSort   string
+)
+
+var pdfCmd = &cobra.Command{
+	Use:   "pdf [input-pattern or files...] [output.pdf]",
+	Short: "여러 이미지를 하나의 PDF로 묶기",
+	Long: `여러 이미지를 한 페이지에 한 장씩 담은 PDF 파일을 만듭니다.
+JPEG은 다시 압축하지 않고 그대로 넣고, PNG 등 다른 형식은 무손실로 저장합니다.
+
+예제:
+  # 이미지 해상도(DPI) 기준의 실제 크기로 페이지 생성
+  imagekit pdf "*.jpg" out.pdf
+
+  # A4 용지 가운데에 배치하고, 여백 안을 가득 채우도록 확대/축소
+  imagekit pdf --page=A4 --fit --margin=10mm "approved/*.jpg" approved.pdf
+
+  # 파일 수정 시간 순서로 페이지 정렬
+  imagekit pdf --sort=time cover.png "pages/*.png" book.pdf
+
+--page 없이 만들면 각 페이지가 이미지의 인쇄 크기와 같습니다 (해상도 정보가 없으면 96 DPI).
+--page를 지정하면 이미지 방향에 맞춰 용지를 돌리고, 용지보다 큰 이미지만 줄입니다.
+기본 정렬(name)은 숫자를 크기순으로 비교하므로 img2.jpg가 img10.jpg보다 먼저 옵니다.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runPDF,
+}
+
+func init() {
+	pdfCmd.Flags().StringVar(&pdfPage, "page", "", "용지 크기 (A3, A4, A5, A6, B5, Letter, Legal 또는 210x297mm, 기본값: 이미지 크기)")
+	pdfCmd.Flags().BoolVar(&pdfFit, "fit", false, "--page 여백 안을 가득 채우도록 이미지 확대/축소")
+	pdfCmd.Flags().StringVar(&pdfMargin, "margin", "", "--page 가장자리 여백 (예: 10mm)")
+	pdfCmd.Flags().StringVar(&pdfDPI, "dpi", "", "인쇄 크기 계산에 사용할 해상도 (기본값: 이미지 해상도)")
+	pdfCmd.Flags().StringVar(&pdfSort, "sort", "name", "페이지 순서 (name, time, none = 입력 순서)")
+}
+
+func runPDF(cmd *cobra.Command, args []string) error {
+	outputPath := args[len(args)-1]
+	if strings.ToLower(filepath.Ext(outputPath)) != ".pdf" {
+		return fmt.Errorf("출력 파일은 .pdf여야 합니다: %s", outputPath)
+	}
+	
+	// Parse PDF options
+	options, err := parsePDFOptions()
+	if err != nil {
+		return err
+	}
+	
+	files, err := batch.FindImageFiles(args[:len(args)-1])
+	if err != nil {
+		return err
+	}
+	if err := batch.SortFiles(files, pdfSort); err != nil {
+		return fmt.Errorf("잘못된 sort 값: %w", err)
+	}
+	
+	// Create transformer
+	transformer := transform.NewTransformer()
+	transformer.SetLogger(verboseLogf)
+	
+	// Write to a temporary file next to the output and rename it once the PDF is
+	// complete, so a failed page doesn't leave a truncated or broken PDF behind
+	outputFile, err := os.CreateTemp(filepath.Dir(outputPath), ".imagekit-*.pdf")
+	if err != nil {
+		return fmt.Errorf("출력 파일을 생성할 수 없습니다: %w", err)
+	}
+	tempPath := outputFile.Name()
+	defer func() {
+		_ = outputFile.Close()
+		_ = os.Remove(tempPath)
+	}()
+	
+	writer, err := transformer.NewPDFWriter(outputFile, options)
+	if err != nil {
+		return fmt.Errorf("PDF 옵션 파싱 실패: %w", err)
+	}
+	for i, path := range files {
+		if err := addPDFPage(writer, path); err != nil {
+			fmt.Printf("[%d/%d] %s ❌\n", i+1, len(files), filepath.Base(path))
+			return err
+		}
+		fmt.Printf("[%d/%d] %s ✅\n", i+1, len(files), filepath.Base(path))
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("PDF 저장 실패: %w", err)
+	}
+	if err := outputFile.Close(); err != nil {
+		return fmt.Errorf("PDF 저장 실패: %w", err)
+	}
+	if err := os.Chmod(tempPath, 0644); err != nil {
+		return fmt.Errorf("PDF 저장 실패: %w", err)
+	}
+	if err := os.Rename(tempPath, outputPath); err != nil {
+		return fmt.Errorf("PDF 저장 실패: %w", err)
+	}
+	
+	fmt.Printf("✅ PDF 생성 완료: %s (%d페이지)\n", outputPath, writer.Pages())
+	return nil
+}
+
+func parsePDFOptions() (transform.PDFOptions, error) {
+	options := transform.PDFOptions{Fit: pdfFit}
+	var err error
+	
+	if pdfPage != "" {
+		paper, err := transform.ParsePaperSize(pdfPage)
+		if err != nil {
+			return options, fmt.Errorf("잘못된 page 값: %w", err)
+		}
+		options.Paper = &paper
+	}
+	if options.Margin, err = transform.ParseDimension(pdfMargin); err != nil {
+		return options, fmt.Errorf("잘못된 margin 값: %w", err)
+	}
+	if pdfDPI != "" {
+		if options.Resolution, err = transform.ParseResolution(pdfDPI); err != nil {
+			return options, fmt.Errorf("잘못된 dpi 값: %w", err)
+		}
+	}
+	
+	if err := transform.ValidatePDFOptions(options); err != nil {
+		return options, fmt.Errorf("PDF 옵션 파싱 실패: %w", err)
+	}
+	return options, nil
+}
+
+// addPDFPage adds one image file as a page
+func addPDFPage(writer *transform.PDFWriter, path string) error {
+	inputFile, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
+	}
+	defer func() { _ = inputFile.Close() }()
+	
+	if err := writer.AddImage(inputFile); err != nil {
+		return fmt.Errorf("페이지 추가 실패: %w", err)
+	}
+	return nil
+}
\ No newline at end of file
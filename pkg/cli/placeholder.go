@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	
+	"github.com/allieus/imagekit/pkg/batch"
+	"github.com/allieus/imagekit/pkg/transform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	placeholderType       string
+	placeholderJSON       string
+	placeholderComponents string
+	placeholderLQIPSize   int
+	placeholderQuality    int
+	placeholderSort       string
+	placeholderDecode     string
+	placeholderSize       string
+)
+
+var placeholderCmd = &cobra.Command{
+	Use:   "placeholder [input-pattern or files...]",
+	Short: "지연 로딩용 플레이스홀더 생성 (BlurHash, ThumbHash, LQIP)",
+	Long: `이미지가 로드되기 전에 보여 줄 작은 플레이스홀더를 계산합니다.
+
+  blurhash   흐린 이미지를 20-30자 문자열로 (https://blurha.sh)
+  thumbhash  비율과 투명도까지 담은 25바이트 안팎의 해시, base64 (https://evanw.github.io/thumbhash/)
+  lqip       가로세로 16픽셀 이하의 JPEG(투명하면 PNG) data URI, <img src>에 바로 사용
+
+예제:
+  # BlurHash를 화면에 출력
+  imagekit placeholder "*.jpg"
+
+  # 세 가지를 모두 계산해 JSON으로 저장 ({"a.jpg": {"width": ..., "height": ..., "blurhash": ...}})
+  imagekit placeholder "photos/*.jpg" --type blurhash,thumbhash,lqip --json placeholders.json
+
+  # 해시를 이미지로 되돌려 미리보기
+  imagekit placeholder --type blurhash --decode "LEHV6nWB2yk8pyo0adR*.7kCMdnj" --size 320x240 preview.png
+  imagekit placeholder --type thumbhash --decode "1QcSHQRnh493V4dIh4eXh1h4kJUI" preview.png
+
+--decode를 사용하면 입력 대신 저장할 PNG 파일 하나를 지정합니다.
+BlurHash는 비율을 저장하지 않으므로 --size를 생략하면 32x32로 그립니다.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runPlaceholder,
+}
+
+func init() {
+	placeholderCmd.Flags().StringVar(&placeholderType, "type", "blurhash", "플레이스홀더 종류, 쉼표로 여러 개 (blurhash, thumbhash, lqip)")
+	placeholderCmd.Flags().StringVar(&placeholderJSON, "json", "", "결과를 저장할 JSON 파일")
+	placeholderCmd.Flags().StringVar(&placeholderComponents, "components", "", "BlurHash 성분 수, 가로x세로 1-9 (기본값: 가로 사진 4x3, 세로 사진 3x4)")
+	placeholderCmd.Flags().IntVar(&placeholderLQIPSize, "lqip-size", 16, "LQIP 긴 변 크기 (픽셀)")
+	placeholderCmd.Flags().IntVar(&placeholderQuality, "quality", 60, "LQIP JPEG 품질 (1-100)")
+	placeholderCmd.Flags().StringVar(&placeholderSort, "sort", "name", "이미지 순서 (name, time, none = 입력 순서)")
+	placeholderCmd.Flags().StringVar(&placeholderDecode, "decode", "", "이미지로 되돌릴 해시 또는 data URI")
+	placeholderCmd.Flags().StringVar(&placeholderSize, "size", "", "--decode 결과 크기 (예: 320x240, 320x0; 기본값: 플레이스홀더 크기)")
+}
+
+func runPlaceholder(cmd *cobra.Command, args []string) error {
+	if cmd.Flags().Changed("decode") {
+		return decodePlaceholder(args)
+	}
+	
+	options, err := parsePlaceholderOptions()
+	if err != nil {
+		return err
+	}
+	files, err := batch.FindImageFiles(args)
+	if err != nil {
+		return err
+	}
+	if err := batch.SortFiles(files, placeholderSort); err != nil {
+		return fmt.Errorf("잘못된 sort 값: %w", err)
+	}
+	
+	// Create transformer
+	transformer := transform.NewTransformer()
+	transformer.SetLogger(verboseLogf)
+	
+	results := make(map[string]transform.Placeholder)
+	failed := 0
+	for i, path := range files {
+		result, err := computePlaceholder(transformer, path, options)
+		if err != nil {
+			fmt.Printf("[%d/%d] %s ❌ %v\n", i+1, len(files), path, err)
+			failed++
+			continue
+		}
+		results[path] = result
+		if placeholderJSON != "" {
+			fmt.Printf("[%d/%d] %s ✅\n", i+1, len(files), path)
+			continue
+		}
+		
+		fmt.Printf("📄 %s (%dx%d)\n", path, result.Width, result.Height)
+		for _, kind := range options.Types {
+			fmt.Printf("  %-10s %s\n", kind+":", placeholderValue(result, kind))
+		}
+	}
+	
+	if placeholderJSON != "" && len(results) > 0 {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(placeholderJSON, append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("JSON 파일을 저장할 수 없습니다: %w", err)
+		}
+		fmt.Printf("✅ JSON 저장: %s\n", placeholderJSON)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d/%d 파일 실패", failed, len(files))
+	}
+	return nil
+}
+
+// parsePlaceholderOptions builds placeholder options from the command line flags
+func parsePlaceholderOptions() (transform.PlaceholderOptions, error) {
+	options := transform.PlaceholderOptions{LQIPSize: placeholderLQIPSize, Quality: placeholderQuality}
+	var err error
+	
+	if options.Types, err = transform.ParsePlaceholderTypes(placeholderType); err != nil {
+		return options, fmt.Errorf("잘못된 type 값: %w", err)
+	}
+	if placeholderComponents != "" {
+		if options.ComponentsX, options.ComponentsY, err = transform.ParseGrid(placeholderComponents); err != nil {
+			return options, fmt.Errorf("잘못된 components 값: %w", err)
+		}
+	}
+	if placeholderLQIPSize < 1 {
+		return options, fmt.Errorf("lqip-size는 1 이상이어야 합니다: %d", placeholderLQIPSize)
+	}
+	if placeholderQuality < 1 || placeholderQuality > 100 {
+		return options, fmt.Errorf("quality는 1에서 100 사이여야 합니다: %d", placeholderQuality)
+	}
+	
+	if err := transform.ValidatePlaceholderOptions(options); err != nil {
+		return options, fmt.Errorf("잘못된 플레이스홀더 옵션: %w", err)
+	}
+	return options, nil
+}
+
+// computePlaceholder opens one file and computes its placeholders
+func computePlaceholder(transformer *transform.Transformer, path string, options transform.PlaceholderOptions) (transform.Placeholder, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return transform.Placeholder{}, fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	
+	return transformer.Placeholder(file, options)
+}
+
+// placeholderValue returns the placeholder of one type
+func placeholderValue(result transform.Placeholder, kind transform.PlaceholderType) string {
+	switch kind {
+	case transform.PlaceholderBlurHash:
+		return result.BlurHash
+	case transform.PlaceholderThumbHash:
+		return result.ThumbHash
+	default:
+		return result.LQIP
+	}
+}
+
+// decodePlaceholder renders the --decode value to the PNG file in args
+func decodePlaceholder(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("--decode에는 출력 파일 하나를 지정하세요")
+	}
+	outputPath := args[0]
+	
+	kind, err := transform.ParsePlaceholderType(placeholderType)
+	if err != nil {
+		return fmt.Errorf("잘못된 type 값: %w", err)
+	}
+	var width, height int
+	if placeholderSize != "" {
+		if _, err := fmt.Sscanf(placeholderSize, "%dx%d", &width, &height); err != nil || width < 0 || height < 0 {
+			return fmt.Errorf("잘못된 size 값: %s (예: 320x240)", placeholderSize)
+		}
+	}
+	
+	img, err := transform.DecodePlaceholder(kind, placeholderDecode, width, height)
+	if err != nil {
+		return fmt.Errorf("플레이스홀더 디코딩 실패: %w", err)
+	}
+	
+	outputFile, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("출력 파일을 생성할 수 없습니다: %w", err)
+	}
+	defer func() { _ = outputFile.Close() }()
+	
+	if err := transform.SaveImage(outputFile, img, transform.FormatPNG, 0); err != nil {
+		return fmt.Errorf("미리보기 저장 실패: %w", err)
+	}
+	fmt.Printf("✅ 미리보기 저장: %s (%dx%d)\n", outputPath, img.Bounds().Dx(), img.Bounds().Dy())
+	return nil
+}
\ No newline at end of file
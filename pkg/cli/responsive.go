@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/batch"
+	"github.com/allieus/imagekit/pkg/transform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	responsiveWidths  string
+	responsiveFormats string
+	responsiveOut     string
+	responsiveQuality int
+	responsiveSizes   string
+	responsiveBaseURL string
+	responsiveSort    string
+)
+
+var responsiveCmd = &cobra.Command{
+	Use:   "responsive [input-pattern or files...]",
+	Short: "반응형 웹용 이미지 세트(srcset)와 HTML 생성",
+	Long: `이미지마다 여러 너비와 형식의 파일을 만들고, JSON 목록과 <picture>/srcset HTML 조각을 저장합니다.
+원본보다 넓은 너비는 건너뛰며, 파일 이름은 <이름>-<너비>w.<확장자> 형식입니다 (beach-640w.jpg).
+
+예제:
+  # 4가지 너비의 JPEG를 dist/에 생성
+  imagekit responsive --widths 320,640,1280,1920 "*.jpg" --out dist/
+
+  # 로고 등 그래픽은 WebP와 PNG 대체 이미지로
+  imagekit responsive --formats webp,png "logos/*.png"
+
+  # CDN 경로와 sizes 속성 지정
+  imagekit responsive --base-url https://cdn.example.com/img/ --sizes "(max-width: 800px) 100vw, 800px" "photos/*.jpg"
+
+출력 폴더에는 manifest.json과 snippets.html이 함께 저장됩니다.
+--formats의 마지막 형식이 <img> 대체 이미지가 되므로 JPEG나 PNG를 마지막에 두세요.
+기본값은 JPEG만 만듭니다. WebP는 무손실로 저장되어 사진은 JPEG보다 커지므로 그래픽에만 추가하세요.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runResponsive,
+}
+
+func init() {
+	responsiveCmd.Flags().StringVar(&responsiveWidths, "widths", "320,640,1280,1920", "생성할 너비 목록 (픽셀, 쉼표로 구분)")
+	responsiveCmd.Flags().StringVar(&responsiveFormats, "formats", "jpeg", "생성할 형식 목록 (마지막 형식이 <img> 대체 이미지)")
+	responsiveCmd.Flags().StringVar(&responsiveOut, "out", "dist", "출력 폴더")
+	responsiveCmd.Flags().IntVar(&responsiveQuality, "quality", 85, "JPEG 품질 (1-100)")
+	responsiveCmd.Flags().StringVar(&responsiveSizes, "sizes", "100vw", "HTML sizes 속성")
+	responsiveCmd.Flags().StringVar(&responsiveBaseURL, "base-url", "", "srcset URL 앞에 붙일 경로 (예: /images/)")
+	responsiveCmd.Flags().StringVar(&responsiveSort, "sort", "name", "이미지 순서 (name, time, none = 입력 순서)")
+}
+
+func runResponsive(cmd *cobra.Command, args []string) error {
+	options, err := parseResponsiveOptions()
+	if err != nil {
+		return err
+	}
+	
+	files, err := batch.FindImageFiles(args)
+	if err != nil {
+		return err
+	}
+	if err := batch.SortFiles(files, responsiveSort); err != nil {
+		return fmt.Errorf("잘못된 sort 값: %w", err)
+	}
+	
+	// Create transformer and processor
+	transformer := transform.NewTransformer()
+	transformer.SetLogger(verboseLogf)
+	processor := batch.NewProcessor(transformer)
+	
+	fmt.Printf("이미지 %d장 → %s\n", len(files), options.OutputDir)
+	progressCallback := func(current, total int, fileName string, success bool) {
+		status := "✅"
+		if !success {
+			status = "❌"
+		}
+		fmt.Printf("[%d/%d] %s %s\n", current, total, fileName, status)
+	}
+	
+	images, result, err := processor.ProcessResponsive(files, options, progressCallback)
+	if err != nil {
+		return err
+	}
+	
+	// Save the manifest and HTML snippets
+	if images == nil {
+		images = []batch.ResponsiveImage{}
+	}
+	manifest, err := json.MarshalIndent(images, "", "  ")
+	if err != nil {
+		return err
+	}
+	manifestPath := filepath.Join(options.OutputDir, "manifest.json")
+	if err := os.WriteFile(manifestPath, append(manifest, '\n'), 0644); err != nil {
+		return fmt.Errorf("JSON 파일을 저장할 수 없습니다: %w", err)
+	}
+	
+	var snippets strings.Builder
+	for _, image := range images {
+		fmt.Fprintf(&snippets, "<!-- %s -->\n%s\n\n", filepath.Base(image.Source), image.HTML)
+	}
+	snippetsPath := filepath.Join(options.OutputDir, "snippets.html")
+	if err := os.WriteFile(snippetsPath, []byte(snippets.String()), 0644); err != nil {
+		return fmt.Errorf("HTML 파일을 저장할 수 없습니다: %w", err)
+	}
+	
+	// Show summary
+	variants := 0
+	for _, image := range images {
+		variants += len(image.Variants)
+	}
+	fmt.Printf("\n완료: %d/%d 성공, 파일 %d개", result.SuccessCount, result.TotalFiles, variants)
+	if result.HasErrors() {
+		fmt.Printf(", %d 실패\n", len(result.FailedFiles))
+		fmt.Println("\n실패한 파일:")
+		for _, failed := range result.FailedFiles {
+			fmt.Printf("  - %s: %v\n", failed.Path, failed.Error)
+		}
+	} else {
+		fmt.Println()
+	}
+	fmt.Printf("목록: %s\nHTML: %s\n", manifestPath, snippetsPath)
+	
+	return nil
+}
+
+// parseResponsiveOptions builds responsive options from the command line flags
+func parseResponsiveOptions() (batch.ResponsiveOptions, error) {
+	options := batch.ResponsiveOptions{
+		OutputDir: responsiveOut,
+		Quality:   responsiveQuality,
+		Sizes:     responsiveSizes,
+		BaseURL:   responsiveBaseURL,
+	}
+	if responsiveQuality < 1 || responsiveQuality > 100 {
+		return options, fmt.Errorf("quality는 1에서 100 사이여야 합니다: %d", responsiveQuality)
+	}
+	
+	for _, s := range strings.Split(responsiveWidths, ",") {
+		width, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || width <= 0 {
+			return options, fmt.Errorf("잘못된 widths 값: %s", responsiveWidths)
+		}
+		options.Widths = append(options.Widths, width)
+	}
+	
+	seen := make(map[transform.ImageFormat]bool)
+	for _, s := range strings.Split(responsiveFormats, ",") {
+		format, err := transform.ParseImageFormat(s)
+		if err != nil {
+			return options, fmt.Errorf("잘못된 formats 값: %w", err)
+		}
+		if err := transform.ValidateOutputFormat(format); err != nil {
+			return options, fmt.Errorf("지원하지 않는 출력 형식: %w", err)
+		}
+		if !seen[format] {
+			seen[format] = true
+			options.Formats = append(options.Formats, format)
+		}
+	}
+	
+	return options, nil
+}
\ No newline at end of file
@@ -1,9 +1,19 @@
 package cli
 
 import (
+	"fmt"
+	
+	"github.com/allieus/imagekit/pkg/transform"
 	"github.com/spf13/cobra"
 )
 
+var (
+	verbose     bool
+	maxPixels   string
+	maxFileSize string
+	maxFrames   int
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "imagekit",
 	Short: "이미지 변환 CLI 도구",
@@ -11,6 +21,9 @@ var rootCmd = &cobra.Command{
 	
 JPG 및 PNG 이미지의 크기, DPI를 변환할 수 있습니다.`,
 	Version: "1.0.0",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return applyLimits()
+	},
 }
 
 // SetVersion sets the version for the CLI
@@ -23,10 +36,58 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
+// verboseLogf prints a message only when --verbose is set
+func verboseLogf(format string, args ...interface{}) {
+	if verbose {
+		fmt.Printf("  ℹ️  "+format+"\n", args...)
+	}
+}
+
+// applyLimits sets the input limits from the global flags
+func applyLimits() error {
+	pixels, err := transform.ParsePixelCount(maxPixels)
+	if err != nil {
+		return fmt.Errorf("잘못된 max-pixels 값: %w", err)
+	}
+	size, err := transform.ParseByteSize(maxFileSize)
+	if err != nil {
+		return fmt.Errorf("잘못된 max-file-size 값: %w", err)
+	}
+	if maxFrames < 0 {
+		return fmt.Errorf("max-frames는 0 이상이어야 합니다: %d", maxFrames)
+	}
+	transform.DefaultLimits = transform.Limits{
+		MaxPixels: pixels,
+		MaxBytes:  size,
+		MaxFrames: maxFrames,
+	}
+	return nil
+}
+
 func init() {
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "상세 처리 과정 출력")
+	rootCmd.PersistentFlags().StringVar(&maxPixels, "max-pixels", "200MP", "입력 이미지 최대 픽셀 수 (예: 50MP, 0 = 제한 없음)")
+	rootCmd.PersistentFlags().StringVar(&maxFileSize, "max-file-size", "512MB", "입력 파일 최대 크기 (예: 100MB, 0 = 제한 없음)")
+	rootCmd.PersistentFlags().IntVar(&maxFrames, "max-frames", 1000, "애니메이션 최대 프레임 수 (0 = 제한 없음)")
+	
 	// Add subcommands
+	rootCmd.AddCommand(bleedCmd)
+	rootCmd.AddCommand(compareCmd)
 	rootCmd.AddCommand(convertCmd)
 	rootCmd.AddCommand(cropCmd)
+	rootCmd.AddCommand(dedupeCmd)
+	rootCmd.AddCommand(iconsCmd)
 	rootCmd.AddCommand(infoCmd)
+	rootCmd.AddCommand(joinCmd)
+	rootCmd.AddCommand(montageCmd)
+	rootCmd.AddCommand(paletteCmd)
+	rootCmd.AddCommand(pdfCmd)
+	rootCmd.AddCommand(placeholderCmd)
+	rootCmd.AddCommand(responsiveCmd)
+	rootCmd.AddCommand(rotateCmd)
+	rootCmd.AddCommand(sheetCmd)
+	rootCmd.AddCommand(splitCmd)
+	rootCmd.AddCommand(spriteCmd)
+	rootCmd.AddCommand(tilesCmd)
 	rootCmd.AddCommand(updateCmd)
 }
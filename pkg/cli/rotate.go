@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/batch"
+	"github.com/allieus/imagekit/pkg/transform"
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rotateAngle    int
+	rotateFlip     string
+	rotateLossless bool
+)
+
+var rotateCmd = &cobra.Command{
+	Use:   "rotate [input-pattern or file] [output-file (optional)]",
+	Short: "이미지 회전 및 뒤집기",
+	Long: `이미지를 시계 방향으로 회전하거나 좌우/상하로 뒤집습니다.
+	
+예제:
+  # 단일 파일 회전
+  imagekit rotate --angle=90 input.jpg output.jpg             # 시계 방향 90도 회전
+  imagekit rotate --angle=-90 input.jpg                       # 반시계 방향 90도 회전
+  imagekit rotate --flip=horizontal input.png output.png      # 좌우 반전
+  
+  # 여러 파일 회전 (glob 패턴)
+  imagekit rotate --angle=180 "*.jpg"
+  
+  # 가장자리의 블록을 잘라내더라도 JPEG를 무손실로 회전
+  imagekit rotate --angle=90 --lossless photo.jpg rotated.jpg
+
+JPEG는 가로/세로 크기가 8 또는 16픽셀 블록의 배수이면 재인코딩 없이 회전합니다.
+--lossless를 지정하면 블록에 맞지 않는 가장자리를 잘라내고 항상 무손실로 처리합니다.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runRotate,
+}
+
+func init() {
+	rotateCmd.Flags().IntVar(&rotateAngle, "angle", 0, "시계 방향 회전 각도 (90, 180, 270, -90)")
+	rotateCmd.Flags().StringVar(&rotateFlip, "flip", "", "회전 후 뒤집기 (horizontal, vertical)")
+	rotateCmd.Flags().BoolVar(&rotateLossless, "lossless", false, "JPEG 가장자리 블록을 잘라내고 재인코딩 없이 처리")
+}
+
+func runRotate(cmd *cobra.Command, args []string) error {
+	inputPattern := args[0]
+	
+	// Parse rotate options
+	flip, err := transform.ParseFlipMode(rotateFlip)
+	if err != nil {
+		return fmt.Errorf("잘못된 flip 값: %w", err)
+	}
+	options := transform.RotateOptions{
+		Angle:    rotateAngle,
+		Flip:     flip,
+		Lossless: rotateLossless,
+	}
+	if err := transform.ValidateRotateOptions(options); err != nil {
+		return fmt.Errorf("잘못된 angle 값: %w", err)
+	}
+	if options.Angle%360 == 0 && options.Flip == transform.FlipNone {
+		return fmt.Errorf("회전 각도나 뒤집기 옵션을 지정해주세요 (--angle, --flip)")
+	}
+	
+	// Create transformer
+	transformer := transform.NewTransformer()
+	transformer.SetLogger(verboseLogf)
+	
+	// Check if it's a glob pattern or contains wildcards
+	hasGlob := strings.Contains(inputPattern, "*") || strings.Contains(inputPattern, "?") || strings.Contains(inputPattern, "[")
+	
+	// Single file mode with explicit output
+	if len(args) == 2 && !hasGlob {
+		return processSingleRotateFile(transformer, inputPattern, args[1], options)
+	}
+	
+	// Check if it's a single file without glob patterns
+	if !hasGlob {
+		// Single file mode with auto-generated output name
+		if _, err := os.Stat(inputPattern); err == nil {
+			outputPath := batch.GenerateOutputPath(inputPattern)
+			return processSingleRotateFile(transformer, inputPattern, outputPath, options)
+		}
+		return fmt.Errorf("파일을 찾을 수 없습니다: %s", inputPattern)
+	}
+	
+	// Batch mode
+	return processBatchRotate(transformer, inputPattern, options)
+}
+
+func processSingleRotateFile(transformer *transform.Transformer, inputPath, outputPath string, options transform.RotateOptions) error {
+	// Show progress
+	bar := progressbar.Default(-1, "이미지 회전 중...")
+	
+	// Open input file
+	inputFile, err := os.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
+	}
+	defer func() { _ = inputFile.Close() }()
+	
+	// Write the output only after the whole image was processed, so a failure
+	// doesn't leave an empty file behind
+	output := &bytes.Buffer{}
+	if err := transformer.Rotate(inputFile, output, options); err != nil {
+		return fmt.Errorf("회전 실패: %w", err)
+	}
+	if err := os.WriteFile(outputPath, output.Bytes(), 0644); err != nil {
+		return fmt.Errorf("출력 파일을 생성할 수 없습니다: %w", err)
+	}
+	
+	_ = bar.Finish()
+	fmt.Printf("✅ 회전 완료: %s\n", outputPath)
+	
+	return nil
+}
+
+func processBatchRotate(transformer *transform.Transformer, pattern string, options transform.RotateOptions) error {
+	// Find matching files
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return fmt.Errorf("잘못된 glob 패턴: %w", err)
+	}
+	
+	if len(matches) == 0 {
+		return fmt.Errorf("패턴과 일치하는 파일이 없습니다: %s", pattern)
+	}
+	
+	// Filter valid image files
+	var filesToProcess []string
+	for _, match := range matches {
+		if batch.IsConvertedFile(match) || !batch.IsImageFile(match) {
+			continue
+		}
+		filesToProcess = append(filesToProcess, match)
+	}
+	
+	if len(filesToProcess) == 0 {
+		return fmt.Errorf("처리할 유효한 이미지 파일이 없습니다")
+	}
+	
+	// Process files
+	fmt.Println("Rotating images...")
+	successCount := 0
+	var failedFiles []string
+	
+	for i, inputPath := range filesToProcess {
+		outputPath := batch.GenerateOutputPath(inputPath)
+		
+		err := processSingleRotateFile(transformer, inputPath, outputPath, options)
+		
+		status := "✅"
+		if err != nil {
+			status = "❌"
+			failedFiles = append(failedFiles, inputPath)
+		} else {
+			successCount++
+		}
+		
+		fmt.Printf("[%d/%d] %s → %s %s\n", i+1, len(filesToProcess), 
+			filepath.Base(inputPath), filepath.Base(outputPath), status)
+		
+		if err != nil {
+			fmt.Printf("  에러: %v\n", err)
+		}
+	}
+	
+	// Show summary
+	fmt.Printf("\n완료: %d/%d 성공", successCount, len(filesToProcess))
+	if len(failedFiles) > 0 {
+		fmt.Printf(", %d 실패\n", len(failedFiles))
+		fmt.Println("\n실패한 파일:")
+		for _, path := range failedFiles {
+			fmt.Printf("  - %s\n", path)
+		}
+	} else {
+		fmt.Println()
+	}
+	
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	
+	"github.com/allieus/imagekit/pkg/batch"
+	"github.com/allieus/imagekit/pkg/transform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sheetPaper      string
+	sheetLandscape  bool
+	sheetDPI        string
+	sheetSize       string
+	sheetMargin     string
+	sheetGutter     string
+	sheetCropMarks  bool
+	sheetAutoRotate bool
+	sheetBackground string
+)
+
+var sheetCmd = &cobra.Command{
+	Use:   "sheet [input-pattern or files...] [output-file]",
+	Short: "여러 사진을 인쇄용 용지 한 장에 배치",
+	Long: `여러 이미지를 지정한 실제 크기로 A4, Letter 등의 용지에 배치합니다.
+한 장에 다 들어가지 않으면 여러 장으로 나누어 저장합니다 (sheet-1.png, sheet-2.png ...;
+10장 이상이면 sheet-01.png처럼 자릿수를 맞춥니다). 출력 파일과 번호가 붙은 페이지가
+입력 패턴과 일치하면 입력에서 제외합니다.
+
+예제:
+  # A4 용지에 9x13cm 사진 배치 (300 DPI)
+  imagekit sheet --paper=A4 --dpi=300 --size=9x13cm "*.jpg" sheet.png
+
+  # 재단선 표시, 여백 10mm, 사진 간격 3mm
+  imagekit sheet --size=10x15cm --margin=10mm --gutter=3mm --crop-marks "photos/*.jpg" print.jpg
+
+  # 가로 방향 Letter 용지에 3.5x5인치 사진
+  imagekit sheet --paper=Letter --landscape --size=3.5x5in a.jpg b.jpg c.jpg sheet.png
+
+사진은 칸을 가득 채우도록 확대/축소한 뒤 가운데를 기준으로 잘라냅니다.
+--auto-rotate(기본값)는 더 많이 들어가도록 칸을 회전하고, 칸과 방향이 다른 사진을
+회전해 잘리는 부분을 줄입니다. 결과 파일에는 --dpi 해상도가 기록됩니다.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runSheet,
+}
+
+func init() {
+	sheetCmd.Flags().StringVar(&sheetPaper, "paper", "A4", "용지 크기 (A3, A4, A5, A6, B5, Letter, Legal 또는 210x297mm)")
+	sheetCmd.Flags().BoolVar(&sheetLandscape, "landscape", false, "용지를 가로 방향으로 사용")
+	sheetCmd.Flags().StringVar(&sheetDPI, "dpi", "300", "출력 해상도")
+	sheetCmd.Flags().StringVar(&sheetSize, "size", "", "사진 인쇄 크기 (예: 9x13cm, 100x150mm, 3.5x5in)")
+	sheetCmd.Flags().StringVar(&sheetMargin, "margin", "5mm", "용지 가장자리 여백")
+	sheetCmd.Flags().StringVar(&sheetGutter, "gutter", "2mm", "사진 사이 간격")
+	sheetCmd.Flags().BoolVar(&sheetCropMarks, "crop-marks", false, "여백에 재단선 표시")
+	sheetCmd.Flags().BoolVar(&sheetAutoRotate, "auto-rotate", true, "더 많이 배치되도록 칸과 사진을 회전")
+	sheetCmd.Flags().StringVar(&sheetBackground, "background", "#ffffff", "용지 색")
+	_ = sheetCmd.MarkFlagRequired("size")
+}
+
+func runSheet(cmd *cobra.Command, args []string) error {
+	outputPath := args[len(args)-1]
+	format, ok := transform.FormatFromPath(outputPath)
+	if !ok || (format != transform.FormatJPEG && format != transform.FormatPNG) {
+		return fmt.Errorf("출력 파일은 PNG 또는 JPEG여야 합니다 (DPI 기록): %s", outputPath)
+	}
+	
+	// Parse sheet options
+	options, err := parseSheetOptions()
+	if err != nil {
+		return err
+	}
+	layout, err := transform.NewSheetLayout(options)
+	if err != nil {
+		return fmt.Errorf("배치 실패: %w", err)
+	}
+	
+	files, err := batch.FindImageFiles(args[:len(args)-1])
+	if err != nil {
+		return err
+	}
+	if files = batch.ExcludeNumberedOutputs(files, outputPath); len(files) == 0 {
+		return fmt.Errorf("출력 파일 외에 배치할 이미지가 없습니다")
+	}
+	
+	// Create transformer
+	transformer := transform.NewTransformer()
+	transformer.SetLogger(verboseLogf)
+	
+	pages := layout.Pages(len(files))
+	rotated := ""
+	if layout.Rotated {
+		rotated = ", 회전 배치"
+	}
+	fmt.Printf("%s 용지 %dx%d 픽셀에 %d x %d장%s, 사진 %d장 → %d페이지\n",
+		options.Paper.Name, layout.Size.X, layout.Size.Y, layout.Columns, layout.Rows, rotated, len(files), pages)
+	
+	for page := 0; page < pages; page++ {
+		start := page * len(layout.Cells)
+		end := min(start+len(layout.Cells), len(files))
+		pagePath := batch.NumberedOutputPath(outputPath, page+1, pages)
+		
+		if err := renderSheetPage(transformer, files[start:end], pagePath, format, layout); err != nil {
+			fmt.Printf("[%d/%d] %s ❌\n", page+1, pages, pagePath)
+			return err
+		}
+		fmt.Printf("[%d/%d] %s ✅\n", page+1, pages, pagePath)
+	}
+	
+	return nil
+}
+
+func parseSheetOptions() (transform.SheetOptions, error) {
+	options := transform.SheetOptions{
+		Landscape:  sheetLandscape,
+		CropMarks:  sheetCropMarks,
+		AutoRotate: sheetAutoRotate,
+	}
+	var err error
+	
+	if options.Paper, err = transform.ParsePaperSize(sheetPaper); err != nil {
+		return options, fmt.Errorf("잘못된 paper 값: %w", err)
+	}
+	if options.Resolution, err = transform.ParseResolution(sheetDPI); err != nil {
+		return options, fmt.Errorf("잘못된 dpi 값: %w", err)
+	}
+	if options.CellWidth, options.CellHeight, err = transform.ParsePrintSize(sheetSize); err != nil {
+		return options, fmt.Errorf("잘못된 size 값: %w", err)
+	}
+	if options.Margin, err = transform.ParseDimension(sheetMargin); err != nil {
+		return options, fmt.Errorf("잘못된 margin 값: %w", err)
+	}
+	if options.Gutter, err = transform.ParseDimension(sheetGutter); err != nil {
+		return options, fmt.Errorf("잘못된 gutter 값: %w", err)
+	}
+	if options.Background, err = transform.ParseHexColor(sheetBackground); err != nil {
+		return options, fmt.Errorf("잘못된 background 값: %w", err)
+	}
+	
+	return options, nil
+}
+
+// renderSheetPage opens the files for one page and writes the sheet
+func renderSheetPage(transformer *transform.Transformer, files []string, outputPath string, format transform.ImageFormat, layout *transform.SheetLayout) error {
+	var inputs []io.Reader
+	for _, path := range files {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
+		}
+		defer func() { _ = file.Close() }()
+		inputs = append(inputs, file)
+	}
+	
+	// Write the page only after every photo was placed
+	output := &bytes.Buffer{}
+	if err := transformer.Sheet(inputs, output, format, layout); err != nil {
+		return fmt.Errorf("시트 생성 실패: %w", err)
+	}
+	if err := os.WriteFile(outputPath, output.Bytes(), 0644); err != nil {
+		return fmt.Errorf("출력 파일을 생성할 수 없습니다: %w", err)
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/batch"
+	"github.com/allieus/imagekit/pkg/transform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	splitGrid       string
+	splitTileSize   string
+	splitOverlap    string
+	splitPad        bool
+	splitBackground string
+	splitDPI        string
+	splitKeepDPI    bool
+	splitFormat     string
+	splitQuality    int
+)
+
+var splitCmd = &cobra.Command{
+	Use:   "split [input-file] [output-file]",
+	Short: "이미지를 격자로 나누어 여러 장으로 저장",
+	Long: `이미지를 같은 크기의 조각으로 나눕니다. 파노라마를 인스타그램 캐러셀로 나누거나
+큰 포스터를 프린터 용지 크기로 나누어 인쇄할 때 사용합니다.
+조각 이름은 <이름>_r<행>c<열> 형식입니다 (poster_r1c2.jpg). 출력 파일을 생략하면 입력 파일 옆에 저장합니다.
+
+예제:
+  # 파노라마를 가로 3장으로 (pano_r1c1.jpg, pano_r1c2.jpg, pano_r1c3.jpg)
+  imagekit split --grid 3x1 pano.jpg
+
+  # A4 300 DPI 크기로 나누고 5mm씩 겹치게, 마지막 조각도 A4 크기로 채움
+  imagekit split --tile-size 2480x3508 --overlap 5mm --pad poster.jpg print/poster.jpg
+
+  # 실제 길이로 지정 (이미지 해상도 기준)
+  imagekit split --tile-size 210x297mm --overlap 5mm poster.png
+
+조각 크기와 겹침의 실제 길이(mm, cm, in)는 이미지 해상도(또는 --dpi)로 픽셀로 바꾸며,
+각 조각에는 같은 해상도가 기록되어 인쇄 크기가 유지됩니다 (--keep-dpi).`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runSplit,
+}
+
+func init() {
+	splitCmd.Flags().StringVar(&splitGrid, "grid", "", "열x행으로 나누기 (예: 3x1)")
+	splitCmd.Flags().StringVar(&splitTileSize, "tile-size", "", "조각 크기 (예: 2480x3508, 210x297mm)")
+	splitCmd.Flags().StringVar(&splitOverlap, "overlap", "0", "이웃 조각과 겹치는 폭 (예: 5mm, 50)")
+	splitCmd.Flags().BoolVar(&splitPad, "pad", false, "마지막 행과 열의 조각도 전체 크기로 채움")
+	splitCmd.Flags().StringVar(&splitBackground, "background", "#ffffff", "채움 색 (--pad)")
+	splitCmd.Flags().StringVar(&splitDPI, "dpi", "", "실제 길이 계산에 사용할 해상도 (기본값: 이미지 해상도)")
+	splitCmd.Flags().BoolVar(&splitKeepDPI, "keep-dpi", true, "각 조각에 해상도 기록 (JPEG, PNG)")
+	splitCmd.Flags().StringVar(&splitFormat, "format", "", "출력 형식 (jpeg, png, webp, gif)")
+	splitCmd.Flags().IntVar(&splitQuality, "quality", 95, "JPEG 품질 (1-100)")
+}
+
+func runSplit(cmd *cobra.Command, args []string) error {
+	inputPath := args[0]
+	outputPath := inputPath
+	if len(args) == 2 {
+		outputPath = args[1]
+	}
+	
+	options, err := parseSplitOptions()
+	if err != nil {
+		return err
+	}
+	
+	// The output format comes from --format, then the output extension
+	var format transform.ImageFormat
+	if splitFormat != "" {
+		if format, err = transform.ParseImageFormat(splitFormat); err != nil {
+			return fmt.Errorf("잘못된 format 값: %w", err)
+		}
+	} else if len(args) == 2 {
+		var ok bool
+		if format, ok = transform.FormatFromPath(outputPath); !ok {
+			return fmt.Errorf("출력 파일 형식을 알 수 없습니다: %s", outputPath)
+		}
+	}
+	if format != "" {
+		if err := transform.ValidateOutputFormat(format); err != nil {
+			return fmt.Errorf("지원하지 않는 출력 형식: %w", err)
+		}
+		if ext := filepath.Ext(outputPath); !format.MatchesExtension(ext) {
+			outputPath = strings.TrimSuffix(outputPath, ext) + format.Extension()
+		}
+	}
+	
+	inputFile, err := os.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
+	}
+	defer func() { _ = inputFile.Close() }()
+	
+	// Create transformer
+	transformer := transform.NewTransformer()
+	transformer.SetLogger(verboseLogf)
+	
+	write := func(tile transform.SplitTile, data []byte) error {
+		path := batch.GridOutputPath(outputPath, tile.Row, tile.Column, tile.Rows, tile.Columns)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("출력 폴더를 만들 수 없습니다: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("출력 파일을 저장할 수 없습니다: %w", err)
+		}
+		fmt.Printf("[%d/%d] %s ✅\n", (tile.Row-1)*tile.Columns+tile.Column, tile.Rows*tile.Columns, path)
+		return nil
+	}
+	
+	tiles, err := transformer.Split(inputFile, format, options, write)
+	if err != nil {
+		return fmt.Errorf("나누기 실패: %w", err)
+	}
+	fmt.Printf("✅ %d열 x %d행, %d장 저장\n", tiles[0].Columns, tiles[0].Rows, len(tiles))
+	
+	return nil
+}
+
+// parseSplitOptions builds split options from the command line flags
+func parseSplitOptions() (transform.SplitOptions, error) {
+	options := transform.SplitOptions{Pad: splitPad, KeepResolution: splitKeepDPI, Quality: splitQuality}
+	var err error
+	
+	if (splitGrid == "") == (splitTileSize == "") {
+		return options, fmt.Errorf("--grid 또는 --tile-size 중 하나를 지정하세요")
+	}
+	if splitGrid != "" {
+		if options.Columns, options.Rows, err = transform.ParseGrid(splitGrid); err != nil {
+			return options, fmt.Errorf("잘못된 grid 값: %w", err)
+		}
+	} else {
+		if options.TileWidth, options.TileHeight, err = transform.ParsePrintSize(splitTileSize); err != nil {
+			return options, fmt.Errorf("잘못된 tile-size 값: %w", err)
+		}
+	}
+	if options.Overlap, err = transform.ParseDimension(splitOverlap); err != nil {
+		return options, fmt.Errorf("잘못된 overlap 값: %w", err)
+	}
+	if options.Background, err = transform.ParseHexColor(splitBackground); err != nil {
+		return options, fmt.Errorf("잘못된 background 값: %w", err)
+	}
+	if splitDPI != "" {
+		if options.Resolution, err = transform.ParseResolution(splitDPI); err != nil {
+			return options, fmt.Errorf("잘못된 dpi 값: %w", err)
+		}
+	}
+	if splitQuality < 1 || splitQuality > 100 {
+		return options, fmt.Errorf("quality는 1에서 100 사이여야 합니다: %d", splitQuality)
+	}
+	
+	if err := transform.ValidateSplitOptions(options); err != nil {
+		return options, fmt.Errorf("나누기 옵션 파싱 실패: %w", err)
+	}
+	return options, nil
+}
\ No newline at end of file
@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/batch"
+	"github.com/allieus/imagekit/pkg/transform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	spriteCSS     string
+	spriteJSON    string
+	spritePadding int
+	spritePacking string
+	spriteRetina  bool
+	spritePrefix  string
+	spriteColors  int
+)
+
+var spriteCmd = &cobra.Command{
+	Use:   "sprite [input-pattern or files...] [output.png]",
+	Short: "아이콘을 스프라이트 시트로 묶고 CSS/JSON 좌표 생성",
+	Long: `여러 이미지를 하나의 PNG 스프라이트 시트로 빈틈없이 배치하고 좌표를 CSS와 JSON으로 저장합니다.
+스프라이트 이름은 파일 이름에서 확장자를 뺀 값입니다 (icons/arrow-left.png → arrow-left).
+
+예제:
+  # 스프라이트 시트와 CSS, JSON 좌표 생성
+  imagekit sprite "icons/*.png" sprite.png --css sprite.css --json sprite.json
+
+  # 아이콘 사이 4픽셀 간격, 선반(shelf) 방식 배치
+  imagekit sprite --padding 4 --packing shelf "icons/*.png" sprite.png --css sprite.css
+
+  # @2x 원본으로 sprite.png와 sprite@2x.png를 함께 생성 (CSS는 고해상도 화면에서 @2x 사용)
+  imagekit sprite --retina "icons/*@2x.png" sprite.png --css sprite.css
+
+CSS 클래스는 .<prefix>-<이름> 형식이며 (예: .sprite-arrow-left), 결과 PNG는 무손실 최적화됩니다.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runSprite,
+}
+
+func init() {
+	spriteCmd.Flags().StringVar(&spriteCSS, "css", "", "CSS 파일 경로")
+	spriteCmd.Flags().StringVar(&spriteJSON, "json", "", "JSON 좌표 파일 경로")
+	spriteCmd.Flags().IntVar(&spritePadding, "padding", 2, "스프라이트 사이 간격 (픽셀)")
+	spriteCmd.Flags().StringVar(&spritePacking, "packing", "maxrects", "배치 방식 (maxrects, shelf)")
+	spriteCmd.Flags().BoolVar(&spriteRetina, "retina", false, "입력을 @2x로 보고 1x 시트와 @2x 시트를 함께 생성")
+	spriteCmd.Flags().StringVar(&spritePrefix, "prefix", "sprite", "CSS 클래스 접두사")
+	spriteCmd.Flags().IntVar(&spriteColors, "colors", 0, "팔레트 색상 수로 양자화 (2-256, 0 = 무손실)")
+}
+
+func runSprite(cmd *cobra.Command, args []string) error {
+	outputPath := args[len(args)-1]
+	if format, ok := transform.FormatFromPath(outputPath); !ok || format != transform.FormatPNG {
+		return fmt.Errorf("출력 파일은 PNG여야 합니다: %s", outputPath)
+	}
+	packing, err := transform.ParsePackingMethod(spritePacking)
+	if err != nil {
+		return fmt.Errorf("잘못된 packing 값: %w", err)
+	}
+	if spriteColors != 0 && (spriteColors < 2 || spriteColors > 256) {
+		return fmt.Errorf("colors는 2에서 256 사이여야 합니다: %d", spriteColors)
+	}
+	
+	files, err := batch.FindImageFiles(args[:len(args)-1])
+	if err != nil {
+		return err
+	}
+	if err := batch.SortFiles(files, batch.SortName); err != nil {
+		return err
+	}
+	
+	// Load images
+	var names []string
+	var images []image.Image
+	for _, path := range files {
+		img, err := loadSpriteImage(path)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if spriteRetina {
+			name = strings.TrimSuffix(name, "@2x")
+		}
+		names = append(names, name)
+		images = append(images, img)
+	}
+	
+	sheet, err := transform.NewSpriteSheet(names, images, transform.SpriteOptions{
+		Packing: packing,
+		Padding: spritePadding,
+		Retina:  spriteRetina,
+	})
+	if err != nil {
+		return fmt.Errorf("스프라이트 생성 실패: %w", err)
+	}
+	
+	// Save sheets
+	retinaPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + "@2x" + filepath.Ext(outputPath)
+	if err := saveSpriteImage(outputPath, sheet.Image); err != nil {
+		return err
+	}
+	if sheet.Retina != nil {
+		if err := saveSpriteImage(retinaPath, sheet.Retina); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("✅ 스프라이트 %d개 → %s (%dx%d)\n", len(sheet.Sprites), outputPath, sheet.Width, sheet.Height)
+	
+	// Save coordinate maps with image paths relative to each file
+	if spriteCSS != "" {
+		css := sheet.CSS(relativeURL(spriteCSS, outputPath), relativeURL(spriteCSS, retinaPath), spritePrefix)
+		if err := os.WriteFile(spriteCSS, []byte(css), 0644); err != nil {
+			return fmt.Errorf("CSS 파일을 저장할 수 없습니다: %w", err)
+		}
+		fmt.Printf("✅ CSS 저장: %s\n", spriteCSS)
+	}
+	if spriteJSON != "" {
+		data, err := sheet.JSON(relativeURL(spriteJSON, outputPath), relativeURL(spriteJSON, retinaPath))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(spriteJSON, append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("JSON 파일을 저장할 수 없습니다: %w", err)
+		}
+		fmt.Printf("✅ JSON 저장: %s\n", spriteJSON)
+	}
+	
+	return nil
+}
+
+// loadSpriteImage loads one input image
+func loadSpriteImage(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	
+	img, _, err := transform.LoadImage(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return img, nil
+}
+
+// saveSpriteImage writes a sheet through the PNG optimizer
+func saveSpriteImage(path string, img image.Image) error {
+	outputFile, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("출력 파일을 생성할 수 없습니다: %w", err)
+	}
+	defer func() { _ = outputFile.Close() }()
+	
+	options := transform.SaveOptions{PNG: transform.PNGOptions{Optimize: true, Colors: spriteColors}}
+	if err := transform.SaveImageWithOptions(outputFile, img, transform.FormatPNG, options); err != nil {
+		return fmt.Errorf("스프라이트 저장 실패: %w", err)
+	}
+	return nil
+}
+
+// relativeURL returns the path of target as seen from the directory of file
+func relativeURL(file, target string) string {
+	rel, err := filepath.Rel(filepath.Dir(file), target)
+	if err != nil {
+		return filepath.ToSlash(target)
+	}
+	return filepath.ToSlash(rel)
+}
\ No newline at end of file
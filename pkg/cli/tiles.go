@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/transform"
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tilesLayout     string
+	tilesTileSize   int
+	tilesOverlap    int
+	tilesTileFormat string
+	tilesQuality    int
+	tilesName       string
+)
+
+var tilesCmd = &cobra.Command{
+	Use:   "tiles [input-file] [output-dir]",
+	Short: "확대 뷰어용 타일 피라미드 생성 (DZI, Zoomify, XYZ)",
+	Long: `큰 이미지를 절반씩 줄여 가며 여러 단계의 타일로 잘라 OpenSeadragon, Leaflet 등의 확대 뷰어에서 볼 수 있게 합니다.
+각 단계의 타일은 병렬로 저장하며, 메모리에는 현재 단계와 다음 단계 이미지만 둡니다.
+
+예제:
+  # Deep Zoom (out/poster.dzi, out/poster_files/)
+  imagekit tiles poster.tif --format dzi --tile-size 256 --overlap 1 out/
+
+  # Zoomify (out/ImageProperties.xml, out/TileGroup0/)
+  imagekit tiles --format zoomify scan.jpg out/
+
+  # 지도 라이브러리용 z/x/y 타일, PNG
+  imagekit tiles --format xyz --tile-format png map.png out/
+
+배치 방식:
+  dzi      <이름>.dzi와 <이름>_files/<단계>/<열>_<행>.jpg, 1x1까지 모든 단계
+  zoomify  ImageProperties.xml과 TileGroupN/<단계>-<열>-<행>.jpg (JPEG만)
+  xyz      <z>/<x>/<y>.jpg, 0단계는 타일 한 장, 가장자리 타일도 전체 크기로 채움
+겹침(--overlap)은 dzi에서만 사용합니다.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTiles,
+}
+
+func init() {
+	tilesCmd.Flags().StringVar(&tilesLayout, "format", "dzi", "타일 배치 방식 (dzi, zoomify, xyz)")
+	tilesCmd.Flags().IntVar(&tilesTileSize, "tile-size", 256, "타일 크기 (픽셀)")
+	tilesCmd.Flags().IntVar(&tilesOverlap, "overlap", 1, "이웃 타일과 겹치는 픽셀 (dzi)")
+	tilesCmd.Flags().StringVar(&tilesTileFormat, "tile-format", "jpeg", "타일 이미지 형식 (jpeg, png, webp)")
+	tilesCmd.Flags().IntVar(&tilesQuality, "quality", 90, "JPEG 품질 (1-100)")
+	tilesCmd.Flags().StringVar(&tilesName, "name", "", "DZI 파일 이름 (기본값: 입력 파일 이름)")
+}
+
+func runTiles(cmd *cobra.Command, args []string) error {
+	inputPath := args[0]
+	outputDir := args[1]
+	
+	options, err := parseTileOptions(cmd)
+	if err != nil {
+		return err
+	}
+	name := tilesName
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
+	}
+	
+	inputFile, err := os.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("입력 파일을 열 수 없습니다: %w", err)
+	}
+	defer func() { _ = inputFile.Close() }()
+	
+	// Create transformer
+	transformer := transform.NewTransformer()
+	transformer.SetLogger(verboseLogf)
+	
+	bar := progressbar.Default(-1, "타일 생성 중...")
+	write := func(name string, data []byte) error {
+		path := filepath.Join(outputDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("출력 폴더를 만들 수 없습니다: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("출력 파일을 저장할 수 없습니다: %w", err)
+		}
+		_ = bar.Add(1)
+		return nil
+	}
+	
+	pyramid, err := transformer.Tiles(inputFile, name, options, write)
+	_ = bar.Finish()
+	if err != nil {
+		return fmt.Errorf("타일 생성 실패: %w", err)
+	}
+	
+	fmt.Printf("✅ %dx%d → %d단계, 타일 %d개: %s\n", pyramid.Width, pyramid.Height, pyramid.Levels, pyramid.Tiles, outputDir)
+	switch options.Layout {
+	case transform.TileDZI:
+		fmt.Printf("📄 %s\n", filepath.Join(outputDir, name+".dzi"))
+	case transform.TileZoomify:
+		fmt.Printf("📄 %s\n", filepath.Join(outputDir, "ImageProperties.xml"))
+	case transform.TileXYZ:
+		fmt.Printf("📄 %s (maxZoom: %d)\n", filepath.Join(outputDir, "{z}", "{x}", "{y}"+options.Format.Extension()), pyramid.Levels-1)
+	}
+	
+	return nil
+}
+
+// parseTileOptions builds tile options from the command line flags
+func parseTileOptions(cmd *cobra.Command) (transform.TileOptions, error) {
+	options := transform.TileOptions{TileSize: tilesTileSize, Quality: tilesQuality}
+	
+	layout, err := transform.ParseTileLayout(tilesLayout)
+	if err != nil {
+		return options, fmt.Errorf("잘못된 format 값: %w", err)
+	}
+	options.Layout = layout
+	// The default overlap only applies to Deep Zoom
+	if layout == transform.TileDZI || cmd.Flags().Changed("overlap") {
+		options.Overlap = tilesOverlap
+	}
+	
+	if options.Format, err = transform.ParseImageFormat(tilesTileFormat); err != nil {
+		return options, fmt.Errorf("잘못된 tile-format 값: %w", err)
+	}
+	if tilesQuality < 1 || tilesQuality > 100 {
+		return options, fmt.Errorf("quality는 1에서 100 사이여야 합니다: %d", tilesQuality)
+	}
+	if err := transform.ValidateTileOptions(options); err != nil {
+		return options, fmt.Errorf("잘못된 타일 옵션: %w", err)
+	}
+	return options, nil
+}
\ No newline at end of file
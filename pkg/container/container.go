@@ -0,0 +1,20 @@
+// Package container parses and rebuilds the structure of JPEG and PNG files without
+// decoding pixels. JPEG files are split into marker segments up to the first scan and
+// PNG files into CRC-checked chunks, so metadata can be read, inserted, replaced or
+// removed while the image data is copied verbatim.
+package container
+
+import "errors"
+
+var (
+	// ErrNotJPEG is returned when data does not start with a JPEG SOI marker
+	ErrNotJPEG = errors.New("container: not a JPEG file")
+	// ErrNotPNG is returned when data does not start with the PNG signature
+	ErrNotPNG = errors.New("container: not a PNG file")
+	// ErrTruncated is returned when a segment or chunk runs past the end of the data
+	ErrTruncated = errors.New("container: truncated data")
+	// ErrMalformed is returned for structurally invalid data
+	ErrMalformed = errors.New("container: malformed data")
+	// ErrChecksum is returned when a PNG chunk CRC does not match its contents
+	ErrChecksum = errors.New("container: checksum mismatch")
+)
\ No newline at end of file
@@ -0,0 +1,139 @@
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// JPEG marker codes used by the parser
+const (
+	MarkerSOI  = 0xD8
+	MarkerEOI  = 0xD9
+	MarkerSOS  = 0xDA
+	MarkerAPP0 = 0xE0
+	MarkerAPP1 = 0xE1
+	MarkerCOM  = 0xFE
+)
+
+// maxSegmentData is the largest payload a segment length field can describe
+const maxSegmentData = 0xFFFF - 2
+
+// Segment is a JPEG marker segment
+type Segment struct {
+	Marker byte   // Marker code without the 0xFF prefix
+	Data   []byte // Payload without the length field
+}
+
+// HasPrefix reports whether the segment has the given marker and its payload starts
+// with prefix, such as "JFIF\x00" or "Exif\x00\x00"
+func (s Segment) HasPrefix(marker byte, prefix string) bool {
+	return s.Marker == marker && bytes.HasPrefix(s.Data, []byte(prefix))
+}
+
+// JPEG is a JPEG file split into the marker segments before the first scan and the
+// remaining data, which is kept verbatim
+type JPEG struct {
+	Segments []Segment
+	Scan     []byte // Everything from the first SOS marker (or EOI) to the end
+}
+
+// ParseJPEG splits JPEG data into segments. Parsing stops at the first SOS marker, so
+// bytes inside entropy-coded data are never mistaken for markers.
+func ParseJPEG(data []byte) (*JPEG, error) {
+	if len(data) < 2 || data[0] != 0xFF || data[1] != MarkerSOI {
+		return nil, ErrNotJPEG
+	}
+	
+	j := &JPEG{}
+	pos := 2
+	for {
+		if pos >= len(data) {
+			return nil, fmt.Errorf("%w: missing SOS marker", ErrTruncated)
+		}
+		if data[pos] != 0xFF {
+			return nil, fmt.Errorf("%w: expected marker at offset %d", ErrMalformed, pos)
+		}
+		
+		// Any number of 0xFF fill bytes may precede a marker
+		start := pos
+		for pos < len(data) && data[pos] == 0xFF {
+			pos++
+		}
+		if pos >= len(data) {
+			return nil, fmt.Errorf("%w: missing SOS marker", ErrTruncated)
+		}
+		marker := data[pos]
+		pos++
+		
+		switch {
+		case marker == 0:
+			return nil, fmt.Errorf("%w: stuffed byte outside scan data at offset %d", ErrMalformed, start)
+		case marker == MarkerSOS || marker == MarkerEOI:
+			j.Scan = data[pos-2:]
+			return j, nil
+		case marker == MarkerSOI || marker == 0x01 || marker >= 0xD0 && marker <= 0xD7:
+			return nil, fmt.Errorf("%w: unexpected marker %#x at offset %d", ErrMalformed, marker, start)
+		}
+		
+		if pos+2 > len(data) {
+			return nil, fmt.Errorf("%w: segment length at offset %d", ErrTruncated, pos)
+		}
+		length := int(binary.BigEndian.Uint16(data[pos:]))
+		if length < 2 {
+			return nil, fmt.Errorf("%w: segment length %d at offset %d", ErrMalformed, length, pos)
+		}
+		if pos+length > len(data) {
+			return nil, fmt.Errorf("%w: segment %#x at offset %d", ErrTruncated, marker, start)
+		}
+		j.Segments = append(j.Segments, Segment{Marker: marker, Data: data[pos+2 : pos+length]})
+		pos += length
+	}
+}
+
+// Find returns the index of the first segment with the marker and payload prefix, or -1
+func (j *JPEG) Find(marker byte, prefix string) int {
+	for i, seg := range j.Segments {
+		if seg.HasPrefix(marker, prefix) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Insert inserts a segment at index i
+func (j *JPEG) Insert(i int, seg Segment) {
+	j.Segments = append(j.Segments, Segment{})
+	copy(j.Segments[i+1:], j.Segments[i:])
+	j.Segments[i] = seg
+}
+
+// Replace replaces the segment at index i
+func (j *JPEG) Replace(i int, seg Segment) {
+	j.Segments[i] = seg
+}
+
+// Remove removes the segment at index i
+func (j *JPEG) Remove(i int) {
+	j.Segments = append(j.Segments[:i], j.Segments[i+1:]...)
+}
+
+// Bytes reassembles the file
+func (j *JPEG) Bytes() ([]byte, error) {
+	size := 2 + len(j.Scan)
+	for _, seg := range j.Segments {
+		if len(seg.Data) > maxSegmentData {
+			return nil, fmt.Errorf("%w: segment %#x has %d bytes, the limit is %d", ErrMalformed, seg.Marker, len(seg.Data), maxSegmentData)
+		}
+		size += 4 + len(seg.Data)
+	}
+	
+	out := make([]byte, 0, size)
+	out = append(out, 0xFF, MarkerSOI)
+	for _, seg := range j.Segments {
+		out = append(out, 0xFF, seg.Marker)
+		out = binary.BigEndian.AppendUint16(out, uint16(len(seg.Data)+2))
+		out = append(out, seg.Data...)
+	}
+	return append(out, j.Scan...), nil
+}
\ No newline at end of file
@@ -0,0 +1,131 @@
+package container
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"testing"
+)
+
+// testJPEG encodes a small JPEG, which the standard encoder writes without APPn segments
+func testJPEG(t testing.TB) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 16, 16)), nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseJPEG(t *testing.T) {
+	data := testJPEG(t)
+	file, err := ParseJPEG(data)
+	if err != nil {
+		t.Fatalf("ParseJPEG() error = %v", err)
+	}
+	if len(file.Segments) == 0 || !bytes.HasPrefix(file.Scan, []byte{0xFF, MarkerSOS}) {
+		t.Fatalf("ParseJPEG() = %d segments, scan starts with % x", len(file.Segments), file.Scan[:2])
+	}
+	
+	got, err := file.Bytes()
+	if err != nil || !bytes.Equal(got, data) {
+		t.Errorf("Bytes() did not reproduce the input (err = %v)", err)
+	}
+}
+
+func TestJPEGEditSegments(t *testing.T) {
+	file, err := ParseJPEG(testJPEG(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := len(file.Segments)
+	
+	file.Insert(0, Segment{Marker: MarkerAPP0, Data: []byte("JFIF\x00test")})
+	file.Insert(1, Segment{Marker: MarkerCOM, Data: []byte("comment")})
+	if i := file.Find(MarkerCOM, ""); i != 1 {
+		t.Fatalf("Find(COM) = %d, want 1", i)
+	}
+	file.Replace(1, Segment{Marker: MarkerCOM, Data: []byte("replaced")})
+	file.Remove(0)
+	
+	data, err := file.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reparsed, err := ParseJPEG(data)
+	if err != nil {
+		t.Fatalf("ParseJPEG() of edited file error = %v", err)
+	}
+	if len(reparsed.Segments) != count+1 || string(reparsed.Segments[0].Data) != "replaced" {
+		t.Errorf("edited file has %d segments, first %q", len(reparsed.Segments), reparsed.Segments[0].Data)
+	}
+	if reparsed.Find(MarkerAPP0, "JFIF\x00") >= 0 {
+		t.Error("removed JFIF segment is still present")
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(data)); err != nil {
+		t.Errorf("edited file does not decode: %v", err)
+	}
+	
+	file.Insert(0, Segment{Marker: MarkerAPP1, Data: make([]byte, maxSegmentData+1)})
+	if _, err := file.Bytes(); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Bytes() with an oversized segment error = %v, want ErrMalformed", err)
+	}
+}
+
+func TestParseJPEGErrors(t *testing.T) {
+	data := testJPEG(t)
+	sos := bytes.Index(data, []byte{0xFF, MarkerSOS})
+	
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"Empty", nil, ErrNotJPEG},
+		{"PNG signature", pngSignature, ErrNotJPEG},
+		{"Only SOI", data[:2], ErrTruncated},
+		{"Cut inside a segment", data[:sos-3], ErrTruncated},
+		{"Garbage between segments", append(append([]byte{}, data[:2]...), 0x12, 0x34), ErrMalformed},
+		{"Zero segment length", []byte{0xFF, MarkerSOI, 0xFF, MarkerAPP0, 0, 0}, ErrMalformed},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseJPEG(tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("ParseJPEG() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func FuzzParseJPEG(f *testing.F) {
+	f.Add(testJPEG(f))
+	f.Add([]byte{0xFF, MarkerSOI, 0xFF, 0xFF, MarkerEOI})
+	f.Add([]byte{0xFF, MarkerSOI, 0xFF, MarkerAPP0, 0, 7, 'J', 'F', 'I', 'F', 0, 0xFF, MarkerSOS})
+	
+	f.Fuzz(func(t *testing.T, data []byte) {
+		file, err := ParseJPEG(data)
+		if err != nil {
+			return
+		}
+		
+		// Rebuilding must produce a file that parses to the same structure
+		rebuilt, err := file.Bytes()
+		if err != nil {
+			t.Fatalf("Bytes() error = %v", err)
+		}
+		again, err := ParseJPEG(rebuilt)
+		if err != nil {
+			t.Fatalf("ParseJPEG() of rebuilt file error = %v", err)
+		}
+		if len(again.Segments) != len(file.Segments) || !bytes.Equal(again.Scan, file.Scan) {
+			t.Fatalf("round trip changed the structure")
+		}
+		for i, seg := range file.Segments {
+			if again.Segments[i].Marker != seg.Marker || !bytes.Equal(again.Segments[i].Data, seg.Data) {
+				t.Fatalf("round trip changed segment %d", i)
+			}
+		}
+	})
+}
\ No newline at end of file
@@ -0,0 +1,144 @@
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math"
+)
+
+// pngSignature is the 8-byte header of every PNG file
+var pngSignature = []byte{137, 80, 78, 71, 13, 10, 26, 10}
+
+// Chunk is a PNG chunk
+type Chunk struct {
+	Type string // Four-letter chunk type, such as "IHDR" or "pHYs"
+	Data []byte
+}
+
+// PNG is a PNG file split into chunks
+type PNG struct {
+	Chunks []Chunk // All chunks from IHDR through IEND
+}
+
+// ParsePNG splits PNG data into chunks, verifying each CRC. Parsing stops at IEND and
+// any trailing data is dropped.
+func ParsePNG(data []byte) (*PNG, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, ErrNotPNG
+	}
+	
+	p := &PNG{}
+	pos := len(pngSignature)
+	for {
+		if pos+8 > len(data) {
+			return nil, fmt.Errorf("%w: missing IEND chunk", ErrTruncated)
+		}
+		length := binary.BigEndian.Uint32(data[pos:])
+		if length > math.MaxInt32 {
+			return nil, fmt.Errorf("%w: chunk length %d at offset %d", ErrMalformed, length, pos)
+		}
+		chunkType := data[pos+4 : pos+8]
+		if !validChunkType(chunkType) {
+			return nil, fmt.Errorf("%w: invalid chunk type %q at offset %d", ErrMalformed, chunkType, pos)
+		}
+		end := pos + 12 + int(length)
+		if end > len(data) || end < pos {
+			return nil, fmt.Errorf("%w: chunk %s at offset %d", ErrTruncated, chunkType, pos)
+		}
+		if crc32.ChecksumIEEE(data[pos+4:end-4]) != binary.BigEndian.Uint32(data[end-4:]) {
+			return nil, fmt.Errorf("%w: chunk %s at offset %d", ErrChecksum, chunkType, pos)
+		}
+		
+		chunk := Chunk{Type: string(chunkType), Data: data[pos+8 : end-4]}
+		if len(p.Chunks) == 0 && chunk.Type != "IHDR" {
+			return nil, fmt.Errorf("%w: first chunk is %s, not IHDR", ErrMalformed, chunk.Type)
+		}
+		p.Chunks = append(p.Chunks, chunk)
+		pos = end
+		if chunk.Type == "IEND" {
+			return p, nil
+		}
+	}
+}
+
+// validChunkType reports whether all four bytes are ASCII letters
+func validChunkType(t []byte) bool {
+	for _, c := range t {
+		if (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// Find returns the index of the first chunk of the given type, or -1
+func (p *PNG) Find(chunkType string) int {
+	for i, chunk := range p.Chunks {
+		if chunk.Type == chunkType {
+			return i
+		}
+	}
+	return -1
+}
+
+// Insert inserts a chunk at index i
+func (p *PNG) Insert(i int, chunk Chunk) {
+	p.Chunks = append(p.Chunks, Chunk{})
+	copy(p.Chunks[i+1:], p.Chunks[i:])
+	p.Chunks[i] = chunk
+}
+
+// Replace replaces the chunk at index i
+func (p *PNG) Replace(i int, chunk Chunk) {
+	p.Chunks[i] = chunk
+}
+
+// Remove removes the chunk at index i
+func (p *PNG) Remove(i int) {
+	p.Chunks = append(p.Chunks[:i], p.Chunks[i+1:]...)
+}
+
+// RemoveAll removes every chunk of the given type
+func (p *PNG) RemoveAll(chunkType string) {
+	kept := p.Chunks[:0]
+	for _, chunk := range p.Chunks {
+		if chunk.Type != chunkType {
+			kept = append(kept, chunk)
+		}
+	}
+	p.Chunks = kept
+}
+
+// Bytes reassembles the file, computing fresh CRCs
+func (p *PNG) Bytes() []byte {
+	size := len(pngSignature)
+	for _, chunk := range p.Chunks {
+		size += 12 + len(chunk.Data)
+	}
+	
+	var buf bytes.Buffer
+	buf.Grow(size)
+	buf.Write(pngSignature)
+	for _, chunk := range p.Chunks {
+		WriteChunk(&buf, chunk.Type, chunk.Data)
+	}
+	return buf.Bytes()
+}
+
+// WriteChunk writes a chunk with its length and CRC
+func WriteChunk(w *bytes.Buffer, chunkType string, data []byte) {
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[0:4], uint32(len(data)))
+	copy(header[4:8], chunkType)
+	w.Write(header[:])
+	w.Write(data)
+	
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:8])
+	crc.Write(data)
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc.Sum32())
+	w.Write(sum[:])
+}
\ No newline at end of file
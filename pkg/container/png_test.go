@@ -0,0 +1,125 @@
+package container
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+)
+
+// testPNG encodes a small PNG
+func testPNG(t testing.TB) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 16, 16))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParsePNG(t *testing.T) {
+	data := testPNG(t)
+	file, err := ParsePNG(data)
+	if err != nil {
+		t.Fatalf("ParsePNG() error = %v", err)
+	}
+	if file.Chunks[0].Type != "IHDR" || file.Chunks[len(file.Chunks)-1].Type != "IEND" {
+		t.Fatalf("ParsePNG() chunks = %v", file.Chunks)
+	}
+	if got := file.Bytes(); !bytes.Equal(got, data) {
+		t.Error("Bytes() did not reproduce the input")
+	}
+	
+	// Data after IEND is dropped
+	trailing, err := ParsePNG(append(append([]byte{}, data...), "trailing"...))
+	if err != nil || !bytes.Equal(trailing.Bytes(), data) {
+		t.Errorf("ParsePNG() with trailing data error = %v", err)
+	}
+}
+
+func TestPNGEditChunks(t *testing.T) {
+	file, err := ParsePNG(testPNG(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	
+	idat := file.Find("IDAT")
+	file.Insert(idat, Chunk{Type: "tEXt", Data: []byte("Comment\x00one")})
+	file.Insert(idat, Chunk{Type: "tEXt", Data: []byte("Comment\x00two")})
+	file.Insert(1, Chunk{Type: "gAMA", Data: []byte{0, 0, 0xB1, 0x8F}})
+	file.Replace(file.Find("gAMA"), Chunk{Type: "gAMA", Data: []byte{0, 1, 0x86, 0xA0}})
+	file.RemoveAll("tEXt")
+	
+	data := file.Bytes()
+	reparsed, err := ParsePNG(data)
+	if err != nil {
+		t.Fatalf("ParsePNG() of edited file error = %v", err)
+	}
+	if reparsed.Find("tEXt") >= 0 {
+		t.Error("removed tEXt chunks are still present")
+	}
+	if i := reparsed.Find("gAMA"); i != 1 || !bytes.Equal(reparsed.Chunks[i].Data, []byte{0, 1, 0x86, 0xA0}) {
+		t.Errorf("gAMA chunk at %d was not replaced", i)
+	}
+	reparsed.Remove(1)
+	if _, err := png.Decode(bytes.NewReader(reparsed.Bytes())); err != nil {
+		t.Errorf("edited file does not decode: %v", err)
+	}
+}
+
+func TestParsePNGErrors(t *testing.T) {
+	data := testPNG(t)
+	badCRC := append([]byte{}, data...)
+	badCRC[len(pngSignature)+8] ^= 0xFF // First byte of the IHDR payload
+	badType := append([]byte{}, data...)
+	badType[len(pngSignature)+4] = '1'
+	
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"Empty", nil, ErrNotPNG},
+		{"JPEG", []byte{0xFF, MarkerSOI, 0xFF, MarkerEOI}, ErrNotPNG},
+		{"Only signature", pngSignature, ErrTruncated},
+		{"Missing IEND", data[:len(data)-12], ErrTruncated},
+		{"Cut inside a chunk", data[:len(data)-20], ErrTruncated},
+		{"Bad CRC", badCRC, ErrChecksum},
+		{"Bad chunk type", badType, ErrMalformed},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParsePNG(tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("ParsePNG() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func FuzzParsePNG(f *testing.F) {
+	f.Add(testPNG(f))
+	f.Add(append(append([]byte{}, pngSignature...), 0, 0, 0, 0, 'I', 'E', 'N', 'D', 0xAE, 0x42, 0x60, 0x82))
+	
+	f.Fuzz(func(t *testing.T, data []byte) {
+		file, err := ParsePNG(data)
+		if err != nil {
+			return
+		}
+		
+		// Rebuilding must produce a file that parses to the same chunks
+		again, err := ParsePNG(file.Bytes())
+		if err != nil {
+			t.Fatalf("ParsePNG() of rebuilt file error = %v", err)
+		}
+		if len(again.Chunks) != len(file.Chunks) {
+			t.Fatalf("round trip changed the chunk count from %d to %d", len(file.Chunks), len(again.Chunks))
+		}
+		for i, chunk := range file.Chunks {
+			if again.Chunks[i].Type != chunk.Type || !bytes.Equal(again.Chunks[i].Data, chunk.Data) {
+				t.Fatalf("round trip changed chunk %d", i)
+			}
+		}
+	})
+}
\ No newline at end of file
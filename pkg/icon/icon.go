@@ -0,0 +1,129 @@
+// Package icon writes Windows ICO and Apple ICNS icon files. Every entry is stored as
+// PNG data, which both formats accept for all sizes on current systems, so the images
+// are encoded once and copied into the container unchanged.
+package icon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+var (
+	// ErrNoImages is returned when an icon file would have no entries
+	ErrNoImages = errors.New("icon: no images")
+	// ErrNotPNG is returned when entry data does not start with the PNG signature
+	ErrNotPNG = errors.New("icon: entry is not PNG data")
+)
+
+// pngSignature starts every PNG file
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// Image is one square icon size encoded as PNG
+type Image struct {
+	Size int
+	PNG  []byte
+}
+
+// MaxICOSize is the largest size an ICO directory entry can describe
+const MaxICOSize = 256
+
+// EncodeICO writes an ICO file with one PNG compressed entry per image, smallest first
+func EncodeICO(w io.Writer, images []Image) error {
+	images, err := sortedImages(images)
+	if err != nil {
+		return err
+	}
+	
+	// ICONDIR header followed by a 16 byte ICONDIRENTRY per image
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, [3]uint16{0, 1, uint16(len(images))})
+	offset := 6 + 16*len(images)
+	for _, img := range images {
+		if img.Size > MaxICOSize {
+			return fmt.Errorf("icon: ICO entries cannot be larger than %d pixels: %d", MaxICOSize, img.Size)
+		}
+		// A size of 256 is stored as 0
+		side := byte(img.Size % 256)
+		buf.Write([]byte{side, side, 0, 0})
+		_ = binary.Write(&buf, binary.LittleEndian, [2]uint16{1, 32}) // Planes, bits per pixel
+		_ = binary.Write(&buf, binary.LittleEndian, [2]uint32{uint32(len(img.PNG)), uint32(offset)})
+		offset += len(img.PNG)
+	}
+	for _, img := range images {
+		buf.Write(img.PNG)
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// icnsTypes lists the ICNS element types that hold PNG data of each pixel size.
+// Sizes shared by a 1x and a @2x slot are written to both.
+var icnsTypes = map[int][]string{
+	16:   {"icp4"},
+	32:   {"icp5", "ic11"},
+	64:   {"icp6", "ic12"},
+	128:  {"ic07"},
+	256:  {"ic08", "ic13"},
+	512:  {"ic09", "ic14"},
+	1024: {"ic10"},
+}
+
+// ICNSSizes are the pixel sizes an ICNS file can hold
+var ICNSSizes = []int{16, 32, 64, 128, 256, 512, 1024}
+
+// EncodeICNS writes an ICNS file with the images in every slot of their size
+func EncodeICNS(w io.Writer, images []Image) error {
+	images, err := sortedImages(images)
+	if err != nil {
+		return err
+	}
+	
+	var body bytes.Buffer
+	for _, img := range images {
+		types, ok := icnsTypes[img.Size]
+		if !ok {
+			return fmt.Errorf("icon: ICNS has no slot for %dx%d images", img.Size, img.Size)
+		}
+		for _, t := range types {
+			body.WriteString(t)
+			_ = binary.Write(&body, binary.BigEndian, uint32(8+len(img.PNG)))
+			body.Write(img.PNG)
+		}
+	}
+	
+	var header bytes.Buffer
+	header.WriteString("icns")
+	_ = binary.Write(&header, binary.BigEndian, uint32(8+body.Len()))
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return err
+	}
+	_, err = w.Write(body.Bytes())
+	return err
+}
+
+// sortedImages checks the entries and returns them by increasing size
+func sortedImages(images []Image) ([]Image, error) {
+	if len(images) == 0 {
+		return nil, ErrNoImages
+	}
+	seen := make(map[int]bool)
+	for _, img := range images {
+		if img.Size <= 0 {
+			return nil, fmt.Errorf("icon: invalid size %d", img.Size)
+		}
+		if seen[img.Size] {
+			return nil, fmt.Errorf("icon: duplicate %dx%d image", img.Size, img.Size)
+		}
+		seen[img.Size] = true
+		if !bytes.HasPrefix(img.PNG, pngSignature) {
+			return nil, ErrNotPNG
+		}
+	}
+	sorted := append([]Image(nil), images...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Size < sorted[j].Size })
+	return sorted, nil
+}
\ No newline at end of file
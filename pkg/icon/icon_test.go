@@ -0,0 +1,101 @@
+package icon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"testing"
+)
+
+// testPNG encodes a blank square image
+func testPNG(t *testing.T, size int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, size, size))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestEncodeICO(t *testing.T) {
+	images := []Image{{Size: 256, PNG: testPNG(t, 256)}, {Size: 16, PNG: testPNG(t, 16)}, {Size: 32, PNG: testPNG(t, 32)}}
+	var buf bytes.Buffer
+	if err := EncodeICO(&buf, images); err != nil {
+		t.Fatalf("EncodeICO() error = %v", err)
+	}
+	data := buf.Bytes()
+	
+	if got := binary.LittleEndian.Uint16(data[2:]); got != 1 {
+		t.Errorf("type = %d, want 1", got)
+	}
+	if got := binary.LittleEndian.Uint16(data[4:]); got != 3 {
+		t.Fatalf("count = %d, want 3", got)
+	}
+	// Entries are sorted by size and 256 is stored as 0
+	for i, want := range []int{16, 32, 256} {
+		entry := data[6+16*i:]
+		if got := int(entry[0]); got != want%256 {
+			t.Errorf("entry %d width = %d, want %d", i, got, want%256)
+		}
+		size := binary.LittleEndian.Uint32(entry[8:])
+		offset := binary.LittleEndian.Uint32(entry[12:])
+		img, err := png.DecodeConfig(bytes.NewReader(data[offset : offset+size]))
+		if err != nil {
+			t.Fatalf("entry %d: %v", i, err)
+		}
+		if img.Width != want {
+			t.Errorf("entry %d PNG width = %d, want %d", i, img.Width, want)
+		}
+	}
+	
+	if err := EncodeICO(&buf, []Image{{Size: 512, PNG: testPNG(t, 1)}}); err == nil {
+		t.Error("EncodeICO() accepted a 512 pixel entry")
+	}
+}
+
+func TestEncodeICNS(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeICNS(&buf, []Image{{Size: 32, PNG: testPNG(t, 32)}, {Size: 16, PNG: testPNG(t, 16)}}); err != nil {
+		t.Fatalf("EncodeICNS() error = %v", err)
+	}
+	data := buf.Bytes()
+	if string(data[:4]) != "icns" || int(binary.BigEndian.Uint32(data[4:])) != len(data) {
+		t.Fatalf("bad header %q, length %d of %d", data[:4], binary.BigEndian.Uint32(data[4:]), len(data))
+	}
+	
+	var types []string
+	for pos := 8; pos < len(data); {
+		length := int(binary.BigEndian.Uint32(data[pos+4:]))
+		types = append(types, string(data[pos:pos+4]))
+		pos += length
+	}
+	want := []string{"icp4", "icp5", "ic11"}
+	if len(types) != len(want) {
+		t.Fatalf("types = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("types = %v, want %v", types, want)
+			break
+		}
+	}
+	
+	if err := EncodeICNS(&buf, []Image{{Size: 48, PNG: testPNG(t, 48)}}); err == nil {
+		t.Error("EncodeICNS() accepted a 48 pixel entry")
+	}
+}
+
+func TestInvalidImages(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeICO(&buf, nil); err != ErrNoImages {
+		t.Errorf("EncodeICO(nil) error = %v, want ErrNoImages", err)
+	}
+	if err := EncodeICO(&buf, []Image{{Size: 16, PNG: []byte("GIF89a")}}); err != ErrNotPNG {
+		t.Errorf("EncodeICO() error = %v, want ErrNotPNG", err)
+	}
+	data := testPNG(t, 16)
+	if err := EncodeICNS(&buf, []Image{{Size: 16, PNG: data}, {Size: 16, PNG: data}}); err == nil {
+		t.Error("EncodeICNS() accepted duplicate sizes")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,163 @@
+// Package imagehash computes 64-bit perceptual hashes of images. Copies of a photo that
+// were resized, recompressed or slightly retouched get hashes that differ in only a few
+// bits, so the Hamming distance between hashes measures how alike two images look.
+package imagehash
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+	
+	"github.com/disintegration/imaging"
+)
+
+// Hash is a 64-bit perceptual hash, the first sample in the most significant bit
+type Hash uint64
+
+// String returns the hash as 16 hex digits
+func (h Hash) String() string {
+	return fmt.Sprintf("%016x", uint64(h))
+}
+
+// ParseHash parses a hash written by String
+func ParseHash(s string) (Hash, error) {
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil || len(s) != 16 {
+		return 0, fmt.Errorf("imagehash: invalid hash: %q", s)
+	}
+	return Hash(v), nil
+}
+
+// Distance returns the number of bits in which two hashes differ
+func Distance(a, b Hash) int {
+	return bits.OnesCount64(uint64(a ^ b))
+}
+
+// Average computes the aHash: each pixel of an 8x8 grayscale thumbnail is compared with
+// the mean. Fast, but sensitive to brightness and contrast changes.
+func Average(img image.Image) Hash {
+	pixels := grayPixels(img, 8, 8)
+	mean := 0.0
+	for _, v := range pixels {
+		mean += v
+	}
+	mean /= float64(len(pixels))
+	return threshold(pixels, func(i int) bool { return pixels[i] > mean })
+}
+
+// Difference computes the dHash: each pixel of a 9x8 grayscale thumbnail is compared
+// with its right neighbour, which follows the gradients of the image.
+func Difference(img image.Image) Hash {
+	pixels := grayPixels(img, 9, 8)
+	var h Hash
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			h <<= 1
+			if pixels[y*9+x] < pixels[y*9+x+1] {
+				h |= 1
+			}
+		}
+	}
+	return h
+}
+
+// Perceptual computes the pHash: the lowest 8x8 frequencies of the discrete cosine
+// transform of a 32x32 grayscale thumbnail are compared with their median. The most
+// robust of the three against resizing, compression and color changes.
+func Perceptual(img image.Image) Hash {
+	const size = 32
+	pixels := grayPixels(img, size, size)
+	
+	// Separable 2D DCT-II, keeping only the low frequencies
+	var rows [size][8]float64
+	for y := 0; y < size; y++ {
+		for u := 0; u < 8; u++ {
+			for x := 0; x < size; x++ {
+				rows[y][u] += pixels[y*size+x] * dctCos(x, u, size)
+			}
+		}
+	}
+	coefficients := make([]float64, 64)
+	for v := 0; v < 8; v++ {
+		for u := 0; u < 8; u++ {
+			sum := 0.0
+			for y := 0; y < size; y++ {
+				sum += rows[y][u] * dctCos(y, v, size)
+			}
+			coefficients[v*8+u] = sum
+		}
+	}
+	
+	sorted := append([]float64(nil), coefficients...)
+	sort.Float64s(sorted)
+	median := (sorted[31] + sorted[32]) / 2
+	return threshold(coefficients, func(i int) bool { return coefficients[i] > median })
+}
+
+// dctCos returns the DCT-II basis value of sample i for frequency k
+func dctCos(i, k, n int) float64 {
+	return math.Cos(math.Pi / float64(n) * (float64(i) + 0.5) * float64(k))
+}
+
+// threshold packs 64 comparisons into a hash
+func threshold(values []float64, set func(i int) bool) Hash {
+	var h Hash
+	for i := range values {
+		h <<= 1
+		if set(i) {
+			h |= 1
+		}
+	}
+	return h
+}
+
+// grayPixels shrinks an image to w x h and returns its luma row by row
+func grayPixels(img image.Image, w, h int) []float64 {
+	small := imaging.Resize(img, w, h, imaging.Lanczos)
+	pixels := make([]float64, w*h)
+	for i := range pixels {
+		p := small.Pix[i*4 : i*4+3]
+		pixels[i] = 0.299*float64(p[0]) + 0.587*float64(p[1]) + 0.114*float64(p[2])
+	}
+	return pixels
+}
+
+// Group clusters hashes so that every pair within a group is at most maxDistance apart
+// (complete linkage), so a chain of similar images never joins two images that aren't.
+// Each hash joins the first group, in input order, whose members are all close enough.
+// It returns the groups with more than one member as sorted indexes, ordered by their
+// first index.
+func Group(hashes []Hash, maxDistance int) [][]int {
+	var groups [][]int
+	for i, h := range hashes {
+		joined := false
+		for g, members := range groups {
+			fits := true
+			for _, j := range members {
+				if Distance(h, hashes[j]) > maxDistance {
+					fits = false
+					break
+				}
+			}
+			if fits {
+				groups[g] = append(members, i)
+				joined = true
+				break
+			}
+		}
+		if !joined {
+			groups = append(groups, []int{i})
+		}
+	}
+	
+	var result [][]int
+	for _, members := range groups {
+		if len(members) > 1 {
+			result = append(result, members)
+		}
+	}
+	return result
+}
\ No newline at end of file
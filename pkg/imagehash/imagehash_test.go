@@ -0,0 +1,100 @@
+package imagehash
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	
+	"github.com/disintegration/imaging"
+)
+
+// sceneImage draws a gradient with a few shapes so every hash has structure to follow
+func sceneImage(w, h int, shift uint8) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.NRGBA{R: uint8(x * 255 / w), G: uint8(y * 255 / h), B: 0x80, A: 0xFF}
+			if (x-w/3)*(x-w/3)+(y-h/2)*(y-h/2) < (h/4)*(h/4) {
+				c = color.NRGBA{R: 0xF0, G: 0xF0, B: 0x20, A: 0xFF}
+			}
+			if x > w*2/3 && y > h/5 && y < h*3/5 {
+				c = color.NRGBA{R: 0x10, G: 0x20, B: 0x60, A: 0xFF}
+			}
+			c.R, c.G = c.R/2+shift, c.G/2+shift
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestHashes(t *testing.T) {
+	original := sceneImage(400, 300, 0)
+	resized := imaging.Resize(original, 160, 120, imaging.Lanczos)
+	brighter := sceneImage(400, 300, 20)
+	different := imaging.FlipH(imaging.Rotate90(original))
+	
+	for _, tt := range []struct {
+		name string
+		hash func(image.Image) Hash
+	}{
+		{"average", Average},
+		{"difference", Difference},
+		{"perceptual", Perceptual},
+	} {
+		h := tt.hash(original)
+		if h == 0 || h == ^Hash(0) {
+			t.Errorf("%s hash = %s, want a mix of bits", tt.name, h)
+		}
+		if d := Distance(h, tt.hash(resized)); d > 4 {
+			t.Errorf("%s distance to resized copy = %d, want at most 4", tt.name, d)
+		}
+		if d := Distance(h, tt.hash(brighter)); d > 8 {
+			t.Errorf("%s distance to brighter copy = %d, want at most 8", tt.name, d)
+		}
+		if d := Distance(h, tt.hash(different)); d < 16 {
+			t.Errorf("%s distance to different image = %d, want at least 16", tt.name, d)
+		}
+	}
+}
+
+func TestParseHash(t *testing.T) {
+	h := Hash(0x00ff00ff12345678)
+	if got, err := ParseHash(h.String()); err != nil || got != h {
+		t.Errorf("ParseHash(%q) = %s, %v", h.String(), got, err)
+	}
+	for _, s := range []string{"", "ff", "zz00ff00ff123456", "00ff00ff123456789"} {
+		if _, err := ParseHash(s); err == nil {
+			t.Errorf("ParseHash(%q) accepted an invalid hash", s)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	hashes := []Hash{
+		0b0000,       // 0: with 2 and 6
+		0xFFFF << 32, // 1: alone
+		0b0011,       // 2
+		0xFFFF,       // 3: with 5
+		0b1111,       // 4: close to 2 but 4 bits from 0, so not chained into their group
+		0xFFFE,       // 5
+		0b0001,       // 6
+	}
+	got := Group(hashes, 2)
+	want := [][]int{{0, 2, 6}, {3, 5}}
+	if len(got) != len(want) {
+		t.Fatalf("Group() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if len(got[i]) != len(want[i]) {
+			t.Fatalf("Group() = %v, want %v", got, want)
+		}
+		for j := range want[i] {
+			if got[i][j] != want[i][j] {
+				t.Errorf("Group() = %v, want %v", got, want)
+			}
+		}
+	}
+	if groups := Group(hashes, 0); len(groups) != 0 {
+		t.Errorf("Group(0) = %v, want no groups", groups)
+	}
+}
\ No newline at end of file
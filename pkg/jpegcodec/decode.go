@@ -0,0 +1,643 @@
+package jpegcodec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrUnsupported is returned for valid JPEG variants the decoder does not handle,
+// such as arithmetic coding, lossless or 12-bit JPEG
+var ErrUnsupported = errors.New("jpegcodec: unsupported JPEG")
+
+// huffmanDecoder decodes symbols with a 9-bit lookup table and a canonical code fallback
+type huffmanDecoder struct {
+	lookup  [1 << lookupBits]uint16 // code length << 8 | symbol, 0 for longer codes
+	maxCode [17]int32
+	minCode [17]int32
+	valPtr  [17]int32
+	values  []byte
+}
+
+// lookupBits is the number of bits resolved by a single table lookup
+const lookupBits = 9
+
+// errHuffmanLength is returned when a DHT table has more codes than fit its lengths
+var errHuffmanLength = errors.New("jpegcodec: Huffman table has excessive length")
+
+// newHuffmanDecoder builds a decoder from a DHT table
+func newHuffmanDecoder(spec *huffmanSpec) (*huffmanDecoder, error) {
+	h := &huffmanDecoder{values: spec.values}
+	code, k := int32(0), int32(0)
+	for length := 1; length <= 16; length++ {
+		count := int32(spec.counts[length-1])
+		// The codes of each length must fit in the code space left by shorter ones
+		if code+count > 1<<length {
+			return nil, errHuffmanLength
+		}
+		h.valPtr[length] = k
+		h.minCode[length] = code
+		h.maxCode[length] = code + count - 1
+		if count == 0 {
+			h.maxCode[length] = -1
+		}
+		if length <= lookupBits {
+			for i := int32(0); i < count; i++ {
+				shift := lookupBits - length
+				start := (code + i) << shift
+				for j := int32(0); j < 1<<shift; j++ {
+					h.lookup[start+j] = uint16(length)<<8 | uint16(spec.values[k+i])
+				}
+			}
+		}
+		code += count
+		k += count
+		code <<= 1
+	}
+	return h, nil
+}
+
+// bitReader reads entropy-coded data, removing byte stuffing.
+// Bits are kept left-aligned in acc; after a marker it supplies zero bits.
+type bitReader struct {
+	data   []byte
+	pos    int
+	acc    uint64
+	n      uint
+	marker bool
+}
+
+// fill loads bytes until at least 57 bits are buffered
+func (b *bitReader) fill() {
+	for b.n <= 56 {
+		var c byte
+		if !b.marker && b.pos < len(b.data) {
+			c = b.data[b.pos]
+			if c == 0xFF {
+				if b.pos+1 < len(b.data) && b.data[b.pos+1] == 0 {
+					b.pos += 2
+				} else {
+					b.marker = true
+					c = 0
+				}
+			} else {
+				b.pos++
+			}
+		}
+		b.acc |= uint64(c) << (56 - b.n)
+		b.n += 8
+	}
+}
+
+// bits reads n bits (n <= 16)
+func (b *bitReader) bits(n uint) int32 {
+	if n == 0 {
+		return 0
+	}
+	if b.n < n {
+		b.fill()
+	}
+	v := int32(b.acc >> (64 - n))
+	b.acc <<= n
+	b.n -= n
+	return v
+}
+
+// bit reads one bit
+func (b *bitReader) bit() bool {
+	return b.bits(1) != 0
+}
+
+// receiveExtend reads an n-bit magnitude category value and sign extends it
+func (b *bitReader) receiveExtend(n uint) int32 {
+	v := b.bits(n)
+	if n > 0 && v < 1<<(n-1) {
+		v += -1<<n + 1
+	}
+	return v
+}
+
+// decode reads one Huffman coded symbol
+func (b *bitReader) decode(h *huffmanDecoder) (byte, error) {
+	if b.n < 16 {
+		b.fill()
+	}
+	if entry := h.lookup[b.acc>>(64-lookupBits)]; entry != 0 {
+		length := uint(entry >> 8)
+		b.acc <<= length
+		b.n -= length
+		return byte(entry), nil
+	}
+	for length := lookupBits + 1; length <= 16; length++ {
+		code := int32(b.acc >> (64 - uint(length)))
+		if code <= h.maxCode[length] {
+			b.acc <<= uint(length)
+			b.n -= uint(length)
+			return h.values[h.valPtr[length]+code-h.minCode[length]], nil
+		}
+	}
+	return 0, errors.New("jpegcodec: invalid Huffman code")
+}
+
+// decoder holds the state while parsing a JPEG file
+type decoder struct {
+	data            []byte
+	c               *Coefficients
+	progressive     bool
+	restartInterval int
+	huffman         [2][4]*huffmanDecoder
+	quant           [4]*[64]uint16
+	segments        []Segment
+	eobRun          int32
+	headerOnly      bool         // Stop after the frame header
+	scaled          *scaledImage // Receives finished blocks instead of storing them, if set
+}
+
+// Decode reads a JPEG file into quantized DCT coefficients.
+// Baseline, extended sequential and progressive Huffman-coded 8-bit JPEGs are supported.
+func Decode(r io.Reader) (*Coefficients, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeBytes(data)
+}
+
+// DecodeBytes is like Decode for data already in memory
+func DecodeBytes(data []byte) (*Coefficients, error) {
+	d := &decoder{data: data}
+	if err := d.decode(); err != nil {
+		return nil, err
+	}
+	return d.c, nil
+}
+
+// DecodeHeader reads the frame header and the segments before it without decoding any
+// scans. The returned coefficients have components but no blocks or quantization tables.
+func DecodeHeader(data []byte) (*Coefficients, error) {
+	d := &decoder{data: data, headerOnly: true}
+	if err := d.decode(); err != nil {
+		return nil, err
+	}
+	return d.c, nil
+}
+
+// decode parses the markers of the file
+func (d *decoder) decode() error {
+	data := d.data
+	if len(data) < 4 || data[0] != 0xFF || data[1] != markerSOI {
+		return errors.New("jpegcodec: missing SOI marker")
+	}
+	pos := 2
+	for {
+		// Find the next marker, skipping fill bytes
+		for pos < len(data) && data[pos] != 0xFF {
+			pos++
+		}
+		for pos+1 < len(data) && data[pos+1] == 0xFF {
+			pos++
+		}
+		if pos+1 >= len(data) {
+			return errors.New("jpegcodec: unexpected end of data")
+		}
+		marker := data[pos+1]
+		pos += 2
+		
+		if marker == markerEOI {
+			break
+		}
+		if marker >= markerRST0 && marker < markerRST0+8 || marker == 0x01 {
+			continue // Stray markers without payload
+		}
+		if pos+2 > len(data) {
+			return errors.New("jpegcodec: truncated marker segment")
+		}
+		length := int(data[pos])<<8 | int(data[pos+1])
+		if length < 2 || pos+length > len(data) {
+			return fmt.Errorf("jpegcodec: invalid length for marker %#x", marker)
+		}
+		payload := data[pos+2 : pos+length]
+		pos += length
+		
+		switch {
+		case marker == markerSOF0 || marker == markerSOF1 || marker == markerSOF2:
+			if d.c != nil {
+				return errors.New("jpegcodec: multiple frames")
+			}
+			d.progressive = marker == markerSOF2
+			if err := d.parseSOF(payload); err != nil {
+				return err
+			}
+			d.c.Segments = d.segments
+			d.c.Progressive = d.progressive
+			if d.headerOnly {
+				return nil
+			}
+			if d.scaled != nil {
+				if err := d.scaled.init(d.c); err != nil {
+					return err
+				}
+			}
+		case marker >= 0xC3 && marker <= 0xCF && marker != markerDHT && marker != 0xC8 && marker != 0xCC:
+			return fmt.Errorf("%w: frame type SOF%d", ErrUnsupported, marker-markerSOF0)
+		case marker == 0xCC:
+			return fmt.Errorf("%w: arithmetic coding", ErrUnsupported)
+		case marker == markerDHT:
+			if err := d.parseDHT(payload); err != nil {
+				return err
+			}
+		case marker == markerDQT:
+			if err := d.parseDQT(payload); err != nil {
+				return err
+			}
+		case marker == markerDRI:
+			if len(payload) != 2 {
+				return errors.New("jpegcodec: invalid DRI segment")
+			}
+			d.restartInterval = int(payload[0])<<8 | int(payload[1])
+		case marker == markerSOS:
+			if d.c == nil {
+				return errors.New("jpegcodec: scan before frame header")
+			}
+			end, err := d.decodeScan(payload, pos)
+			if err != nil {
+				return err
+			}
+			pos = end
+		case marker >= markerAPP0 && marker <= markerAPP0+15 || marker == markerCOM:
+			if d.c == nil {
+				d.segments = append(d.segments, Segment{Marker: marker, Data: append([]byte(nil), payload...)})
+			}
+		}
+	}
+	
+	if d.c == nil {
+		return errors.New("jpegcodec: no frame found")
+	}
+	for _, comp := range d.c.Components {
+		if d.quant[comp.Tq] == nil {
+			return fmt.Errorf("jpegcodec: missing quantization table %d", comp.Tq)
+		}
+		d.c.Quant[comp.Tq] = d.quant[comp.Tq]
+	}
+	return nil
+}
+
+// parseSOF reads the frame header
+func (d *decoder) parseSOF(p []byte) error {
+	if len(p) < 6 {
+		return errors.New("jpegcodec: invalid frame header")
+	}
+	if p[0] != 8 {
+		return fmt.Errorf("%w: %d-bit precision", ErrUnsupported, p[0])
+	}
+	height := int(p[1])<<8 | int(p[2])
+	width := int(p[3])<<8 | int(p[4])
+	n := int(p[5])
+	if width == 0 || height == 0 {
+		return fmt.Errorf("%w: image height defined by DNL", ErrUnsupported)
+	}
+	if n == 0 || n > 4 || len(p) != 6+3*n {
+		return errors.New("jpegcodec: invalid frame header")
+	}
+	
+	d.c = &Coefficients{Width: width, Height: height}
+	for i := 0; i < n; i++ {
+		b := p[6+3*i:]
+		comp := &Component{ID: b[0], H: int(b[1] >> 4), V: int(b[1] & 0x0F), Tq: int(b[2])}
+		if comp.H < 1 || comp.H > 4 || comp.V < 1 || comp.V > 4 || comp.Tq > 3 {
+			return errors.New("jpegcodec: invalid component parameters")
+		}
+		d.c.Components = append(d.c.Components, comp)
+	}
+	if d.headerOnly || d.streaming() {
+		d.c.layout()
+	} else {
+		d.c.allocate()
+	}
+	return nil
+}
+
+// streaming reports whether blocks go straight to the scaled image without being stored,
+// which is possible when every block is complete after a single scan
+func (d *decoder) streaming() bool {
+	return d.scaled != nil && !d.progressive
+}
+
+// parseDHT reads one or more Huffman tables
+func (d *decoder) parseDHT(p []byte) error {
+	for len(p) > 0 {
+		if len(p) < 17 {
+			return errors.New("jpegcodec: invalid DHT segment")
+		}
+		class, id := int(p[0]>>4), int(p[0]&0x0F)
+		if class > 1 || id > 3 {
+			return errors.New("jpegcodec: invalid Huffman table selector")
+		}
+		spec := &huffmanSpec{}
+		copy(spec.counts[:], p[1:17])
+		total := 0
+		for _, count := range spec.counts {
+			total += int(count)
+		}
+		if total == 0 || total > 256 || len(p) < 17+total {
+			return errors.New("jpegcodec: invalid Huffman table")
+		}
+		spec.values = append([]byte(nil), p[17:17+total]...)
+		h, err := newHuffmanDecoder(spec)
+		if err != nil {
+			return err
+		}
+		d.huffman[class][id] = h
+		p = p[17+total:]
+	}
+	return nil
+}
+
+// parseDQT reads one or more quantization tables
+func (d *decoder) parseDQT(p []byte) error {
+	for len(p) > 0 {
+		precision, id := p[0]>>4, int(p[0]&0x0F)
+		if precision > 1 || id > 3 {
+			return errors.New("jpegcodec: invalid DQT segment")
+		}
+		size := 64 * (int(precision) + 1)
+		if len(p) < 1+size {
+			return errors.New("jpegcodec: truncated DQT segment")
+		}
+		table := &[64]uint16{}
+		for k := 0; k < 64; k++ {
+			if precision == 1 {
+				table[unzig[k]] = uint16(p[1+2*k])<<8 | uint16(p[2+2*k])
+			} else {
+				table[unzig[k]] = uint16(p[1+k])
+			}
+		}
+		d.quant[id] = table
+		p = p[1+size:]
+	}
+	return nil
+}
+
+// decodeScan decodes the scan that starts at pos and returns the position of the marker after it
+func (d *decoder) decodeScan(p []byte, pos int) (int, error) {
+	if len(p) < 1 {
+		return 0, errors.New("jpegcodec: invalid scan header")
+	}
+	n := int(p[0])
+	if n < 1 || n > len(d.c.Components) || len(p) != 4+2*n {
+		return 0, errors.New("jpegcodec: invalid scan header")
+	}
+	
+	s := scan{ss: int(p[1+2*n]), se: int(p[2+2*n]), ah: int(p[3+2*n] >> 4), al: int(p[3+2*n] & 0x0F)}
+	var dcTables, acTables [4]*huffmanDecoder
+	for i := 0; i < n; i++ {
+		id, tables := p[1+2*i], p[2+2*i]
+		ci := -1
+		for j, comp := range d.c.Components {
+			if comp.ID == id {
+				ci = j
+			}
+		}
+		if ci < 0 {
+			return 0, fmt.Errorf("jpegcodec: scan references unknown component %d", id)
+		}
+		s.comps = append(s.comps, ci)
+		dcTables[ci] = d.huffman[classDC][tables>>4&3]
+		acTables[ci] = d.huffman[classAC][tables&3]
+	}
+	
+	if d.progressive {
+		if s.ss > s.se || s.se > 63 || s.ss == 0 && s.se != 0 || s.ss > 0 && n != 1 || s.al > 13 {
+			return 0, errors.New("jpegcodec: invalid progressive scan parameters")
+		}
+	} else {
+		s.ss, s.se, s.ah, s.al = 0, 63, 0, 0
+	}
+	
+	// Check that the tables the scan needs are defined
+	for _, ci := range s.comps {
+		if s.ss == 0 && s.ah == 0 && dcTables[ci] == nil || s.se > 0 && acTables[ci] == nil {
+			return 0, errors.New("jpegcodec: scan uses an undefined Huffman table")
+		}
+		if d.streaming() && d.quant[d.c.Components[ci].Tq] == nil {
+			return 0, fmt.Errorf("jpegcodec: missing quantization table %d", d.c.Components[ci].Tq)
+		}
+	}
+	
+	b := &bitReader{data: d.data, pos: pos}
+	lastDC := make([]int32, len(d.c.Components))
+	d.eobRun = 0
+	expectedRST := 0
+	currentMCU := 0
+	var err error
+	var scratch Block
+	d.c.forEachBlockAt(s.comps, func(mcu, ci, bx, by int) {
+		if err != nil {
+			return
+		}
+		if mcu != currentMCU {
+			currentMCU = mcu
+			if d.restartInterval > 0 && mcu%d.restartInterval == 0 {
+				// Discard remaining bits and skip the RSTn marker
+				if err = b.restart(expectedRST); err != nil {
+					return
+				}
+				expectedRST = (expectedRST + 1) % 8
+				for i := range lastDC {
+					lastDC[i] = 0
+				}
+				d.eobRun = 0
+			}
+		}
+		
+		var blk *Block
+		if d.streaming() {
+			scratch = Block{}
+			blk = &scratch
+		} else {
+			blk = d.c.Components[ci].Block(bx, by)
+		}
+		
+		switch {
+		case !d.progressive:
+			err = d.decodeSequential(b, blk, dcTables[ci], acTables[ci], &lastDC[ci])
+			if err == nil && d.streaming() {
+				d.scaled.put(ci, bx, by, blk, d.quant[d.c.Components[ci].Tq])
+			}
+		case s.ss == 0 && s.ah == 0:
+			var t byte
+			if t, err = b.decode(dcTables[ci]); err == nil {
+				lastDC[ci] += b.receiveExtend(uint(t))
+				blk[0] = int16(lastDC[ci] << s.al)
+			}
+		case s.ss == 0:
+			if b.bit() {
+				blk[0] |= 1 << s.al
+			}
+		case s.ah == 0:
+			err = d.decodeACFirst(b, blk, acTables[ci], s)
+		default:
+			err = d.decodeACRefine(b, blk, acTables[ci], s)
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+	
+	// Continue after the entropy-coded data
+	end := b.pos
+	for end+1 < len(d.data) && (d.data[end] != 0xFF || d.data[end+1] == 0 || d.data[end+1] >= markerRST0 && d.data[end+1] < markerRST0+8) {
+		end++
+	}
+	return end, nil
+}
+
+// restart resynchronizes at a restart marker
+func (b *bitReader) restart(expected int) error {
+	b.acc, b.n, b.marker = 0, 0, false
+	for b.pos+1 < len(b.data) && !(b.data[b.pos] == 0xFF && b.data[b.pos+1] != 0 && b.data[b.pos+1] != 0xFF) {
+		b.pos++
+	}
+	if b.pos+1 >= len(b.data) || b.data[b.pos+1] != byte(markerRST0+expected) {
+		return errors.New("jpegcodec: missing restart marker")
+	}
+	b.pos += 2
+	return nil
+}
+
+// decodeSequential decodes one block of a baseline or extended sequential scan
+func (d *decoder) decodeSequential(b *bitReader, blk *Block, dc, ac *huffmanDecoder, lastDC *int32) error {
+	t, err := b.decode(dc)
+	if err != nil {
+		return err
+	}
+	*lastDC += b.receiveExtend(uint(t))
+	blk[0] = int16(*lastDC)
+	
+	for k := 1; k < 64; k++ {
+		rs, err := b.decode(ac)
+		if err != nil {
+			return err
+		}
+		r, size := int(rs>>4), uint(rs&0x0F)
+		if size == 0 {
+			if r != 15 {
+				break // End of block
+			}
+			k += 15
+			continue
+		}
+		k += r
+		if k > 63 {
+			return errors.New("jpegcodec: coefficient index out of range")
+		}
+		blk[unzig[k]] = int16(b.receiveExtend(size))
+	}
+	return nil
+}
+
+// decodeACFirst decodes the first pass over a spectral band
+func (d *decoder) decodeACFirst(b *bitReader, blk *Block, ac *huffmanDecoder, s scan) error {
+	if d.eobRun > 0 {
+		d.eobRun--
+		return nil
+	}
+	for k := s.ss; k <= s.se; k++ {
+		rs, err := b.decode(ac)
+		if err != nil {
+			return err
+		}
+		r, size := int(rs>>4), uint(rs&0x0F)
+		if size == 0 {
+			if r != 15 {
+				d.eobRun = 1<<r - 1
+				if r > 0 {
+					d.eobRun += b.bits(uint(r))
+				}
+				break
+			}
+			k += 15
+			continue
+		}
+		k += r
+		if k > s.se {
+			return errors.New("jpegcodec: coefficient index out of range")
+		}
+		blk[unzig[k]] = int16(b.receiveExtend(size) << s.al)
+	}
+	return nil
+}
+
+// decodeACRefine decodes the next bit of a spectral band that was already partly sent
+func (d *decoder) decodeACRefine(b *bitReader, blk *Block, ac *huffmanDecoder, s scan) error {
+	p1 := int16(1) << s.al
+	m1 := int16(-1) << s.al
+	k := s.ss
+	
+	if d.eobRun == 0 {
+		for ; k <= s.se; k++ {
+			rs, err := b.decode(ac)
+			if err != nil {
+				return err
+			}
+			r, size := int(rs>>4), rs&0x0F
+			var value int16
+			if size != 0 {
+				if size != 1 {
+					return errors.New("jpegcodec: invalid refinement coefficient")
+				}
+				value = m1
+				if b.bit() {
+					value = p1
+				}
+			} else if r != 15 {
+				d.eobRun = 1 << r
+				if r > 0 {
+					d.eobRun += b.bits(uint(r))
+				}
+				break
+			}
+			
+			// Skip r zero coefficients, refining the nonzero ones passed on the way
+			for ; k <= s.se; k++ {
+				coef := &blk[unzig[k]]
+				if *coef != 0 {
+					refineCoefficient(b, coef, p1, m1)
+				} else {
+					if r == 0 {
+						break
+					}
+					r--
+				}
+			}
+			if value != 0 {
+				if k > s.se {
+					return errors.New("jpegcodec: coefficient index out of range")
+				}
+				blk[unzig[k]] = value
+			}
+		}
+	}
+	
+	if d.eobRun > 0 {
+		for ; k <= s.se; k++ {
+			if coef := &blk[unzig[k]]; *coef != 0 {
+				refineCoefficient(b, coef, p1, m1)
+			}
+		}
+		d.eobRun--
+	}
+	return nil
+}
+
+// refineCoefficient adds a correction bit to a coefficient that is already nonzero
+func refineCoefficient(b *bitReader, coef *int16, p1, m1 int16) {
+	if b.bit() && *coef&p1 == 0 {
+		if *coef >= 0 {
+			*coef += p1
+		} else {
+			*coef += m1
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,59 @@
+package jpegcodec
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// sampleJPEG encodes a small gradient
+func sampleJPEG(t testing.TB) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 16), uint8(y * 16), 0x80, 0xFF})
+		}
+	}
+	buf := &bytes.Buffer{}
+	if err := Encode(buf, img, &Options{Quality: 90}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeOverfullHuffmanTable(t *testing.T) {
+	data := sampleJPEG(t)
+	i := bytes.Index(data, []byte{0xFF, 0xC4})
+	if i < 0 {
+		t.Fatal("no DHT segment")
+	}
+	
+	// Move every code of the first table to length 1, which only has room for two
+	counts := data[i+5 : i+21]
+	total := 0
+	for j := range counts {
+		total += int(counts[j])
+		counts[j] = 0
+	}
+	counts[0] = byte(total)
+	
+	if _, err := DecodeBytes(data); err == nil {
+		t.Error("DecodeBytes() accepted an overfull Huffman table")
+	}
+	if _, err := DecodeScaled(data, 2); err == nil {
+		t.Error("DecodeScaled() accepted an overfull Huffman table")
+	}
+}
+
+func FuzzDecode(f *testing.F) {
+	f.Add(sampleJPEG(f))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Malformed input must fail with an error, never panic
+		if _, err := Decode(bytes.NewReader(data)); err != nil {
+			return
+		}
+		_, _ = DecodeScaled(data, 2)
+	})
+}
\ No newline at end of file
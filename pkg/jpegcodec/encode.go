@@ -0,0 +1,315 @@
+package jpegcodec
+
+import (
+	"image"
+	"image/color"
+	"io"
+)
+
+// DefaultQuality is the quality used when Options.Quality is not set
+const DefaultQuality = 75
+
+// Subsampling is the chroma subsampling mode of a color image
+type Subsampling int
+
+const (
+	// Subsampling420 halves chroma resolution in both directions
+	Subsampling420 Subsampling = iota
+	// Subsampling422 halves chroma resolution horizontally
+	Subsampling422
+	// Subsampling444 keeps full chroma resolution
+	Subsampling444
+)
+
+// String returns the conventional J:a:b notation
+func (s Subsampling) String() string {
+	switch s {
+	case Subsampling422:
+		return "4:2:2"
+	case Subsampling444:
+		return "4:4:4"
+	default:
+		return "4:2:0"
+	}
+}
+
+// lumaFactors returns the luma sampling factors relative to chroma
+func (s Subsampling) lumaFactors() (int, int) {
+	switch s {
+	case Subsampling422:
+		return 2, 1
+	case Subsampling444:
+		return 1, 1
+	default:
+		return 2, 2
+	}
+}
+
+// Options are the encoding options
+type Options struct {
+	Quality         int         // 1-100 (0 = DefaultQuality)
+	Subsampling     Subsampling // Chroma subsampling of color images
+	Progressive     bool        // Write a progressive instead of a baseline JPEG
+	OptimizeHuffman bool        // Build Huffman tables from the image statistics
+}
+
+// Encode writes the image as a JPEG
+func Encode(w io.Writer, img image.Image, o *Options) error {
+	if o == nil {
+		o = &Options{}
+	}
+	coefs := FromImage(img, o.Quality, o.Subsampling)
+	return Write(w, coefs, &WriteOptions{Progressive: o.Progressive, OptimizeHuffman: o.OptimizeHuffman})
+}
+
+// unscaledQuant are the example quantization tables from Annex K.1 in natural order
+var unscaledQuant = [2][64]uint16{
+	// Luminance
+	{
+		16, 11, 10, 16, 24, 40, 51, 61,
+		12, 12, 14, 19, 26, 58, 60, 55,
+		14, 13, 16, 24, 40, 57, 69, 56,
+		14, 17, 22, 29, 51, 87, 80, 62,
+		18, 22, 37, 56, 68, 109, 103, 77,
+		24, 35, 55, 64, 81, 104, 113, 92,
+		49, 64, 78, 87, 103, 121, 120, 101,
+		72, 92, 95, 98, 112, 100, 103, 99,
+	},
+	// Chrominance
+	{
+		17, 18, 24, 47, 99, 99, 99, 99,
+		18, 21, 26, 66, 99, 99, 99, 99,
+		24, 26, 56, 99, 99, 99, 99, 99,
+		47, 66, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+		99, 99, 99, 99, 99, 99, 99, 99,
+	},
+}
+
+// scaleQuant scales a quantization table by quality like libjpeg does
+func scaleQuant(table *[64]uint16, quality int) *[64]uint16 {
+	quality = min(max(quality, 1), 100)
+	scale := 200 - quality*2
+	if quality < 50 {
+		scale = 5000 / quality
+	}
+	scaled := &[64]uint16{}
+	for i, q := range table {
+		scaled[i] = uint16(min(max((int(q)*scale+50)/100, 1), 255))
+	}
+	return scaled
+}
+
+// aanScale are the output scale factors of the AAN forward DCT
+var aanScale = [8]float64{
+	1.0, 1.387039845, 1.306562965, 1.175875602,
+	1.0, 0.785694958, 0.541196100, 0.275899379,
+}
+
+// fdct computes the forward DCT of a level-shifted 8x8 block in place using the
+// Arai-Agui-Nakajima algorithm. The outputs are scaled by aanScale[u]*aanScale[v]*8,
+// which quantizeDivisors folds into the quantization step.
+func fdct(block *[64]float64) {
+	for i := 0; i < 8; i++ {
+		fdct1D(block, i*8, 1)
+	}
+	for i := 0; i < 8; i++ {
+		fdct1D(block, i, 8)
+	}
+}
+
+// fdct1D transforms the 8 values starting at offset with the given stride
+func fdct1D(d *[64]float64, offset, stride int) {
+	d0, d1, d2, d3 := d[offset], d[offset+stride], d[offset+2*stride], d[offset+3*stride]
+	d4, d5, d6, d7 := d[offset+4*stride], d[offset+5*stride], d[offset+6*stride], d[offset+7*stride]
+	
+	tmp0, tmp7 := d0+d7, d0-d7
+	tmp1, tmp6 := d1+d6, d1-d6
+	tmp2, tmp5 := d2+d5, d2-d5
+	tmp3, tmp4 := d3+d4, d3-d4
+	
+	// Even part
+	tmp10, tmp13 := tmp0+tmp3, tmp0-tmp3
+	tmp11, tmp12 := tmp1+tmp2, tmp1-tmp2
+	d[offset] = tmp10 + tmp11
+	d[offset+4*stride] = tmp10 - tmp11
+	z1 := (tmp12 + tmp13) * 0.707106781
+	d[offset+2*stride] = tmp13 + z1
+	d[offset+6*stride] = tmp13 - z1
+	
+	// Odd part
+	tmp10 = tmp4 + tmp5
+	tmp11 = tmp5 + tmp6
+	tmp12 = tmp6 + tmp7
+	z5 := (tmp10 - tmp12) * 0.382683433
+	z2 := 0.541196100*tmp10 + z5
+	z4 := 1.306562965*tmp12 + z5
+	z3 := tmp11 * 0.707106781
+	z11, z13 := tmp7+z3, tmp7-z3
+	d[offset+5*stride] = z13 + z2
+	d[offset+3*stride] = z13 - z2
+	d[offset+stride] = z11 + z4
+	d[offset+7*stride] = z11 - z4
+}
+
+// quantizeDivisors combines a quantization table with the AAN output scaling
+func quantizeDivisors(table *[64]uint16) *[64]float64 {
+	divisors := &[64]float64{}
+	for i, q := range table {
+		divisors[i] = float64(q) * aanScale[i/8] * aanScale[i%8] * 8
+	}
+	return divisors
+}
+
+// quantize divides DCT output by the divisors, rounding to the nearest integer and
+// clamping to the range that baseline Huffman tables can code
+func quantize(dst *Block, src *[64]float64, divisors *[64]float64) {
+	for i := range src {
+		limit := 1023
+		if i == 0 {
+			limit = 2047
+		}
+		v := src[i] / divisors[i]
+		var q int
+		if v < 0 {
+			q = -int(0.5 - v)
+		} else {
+			q = int(v + 0.5)
+		}
+		dst[i] = int16(min(max(q, -limit), limit))
+	}
+}
+
+// FromImage converts an image to quantized DCT coefficients.
+// Grayscale images get a single component, everything else is converted to YCbCr.
+func FromImage(img image.Image, quality int, subsampling Subsampling) *Coefficients {
+	if quality <= 0 {
+		quality = DefaultQuality
+	}
+	bounds := img.Bounds()
+	c := &Coefficients{
+		Width:    bounds.Dx(),
+		Height:   bounds.Dy(),
+		Segments: []Segment{jfifSegment()},
+	}
+	c.Quant[0] = scaleQuant(&unscaledQuant[0], quality)
+	
+	pixel := pixelReader(img)
+	if isGray(img) {
+		c.Components = []*Component{{ID: 1, H: 1, V: 1, Tq: 0}}
+	} else {
+		c.Quant[1] = scaleQuant(&unscaledQuant[1], quality)
+		h, v := subsampling.lumaFactors()
+		c.Components = []*Component{
+			{ID: 1, H: h, V: v, Tq: 0},
+			{ID: 2, H: 1, V: 1, Tq: 1},
+			{ID: 3, H: 1, V: 1, Tq: 1},
+		}
+	}
+	c.allocate()
+	
+	hmax, vmax := c.maxSampling()
+	mcusX, mcusY := c.mcus()
+	rowWidth, rowHeight := mcusX*8*hmax, 8*vmax
+	
+	// Convert one MCU row at a time, replicating edge pixels into the padding
+	planes := make([][]uint8, len(c.Components))
+	for i := range planes {
+		planes[i] = make([]uint8, rowWidth*rowHeight)
+	}
+	divisors := make([]*[64]float64, len(c.Components))
+	for ci, comp := range c.Components {
+		divisors[ci] = quantizeDivisors(c.Quant[comp.Tq])
+	}
+	var samples [64]float64
+	var ycc [3]uint8
+	for my := 0; my < mcusY; my++ {
+		for r := 0; r < rowHeight; r++ {
+			y := min(my*rowHeight+r, c.Height-1)
+			for x := 0; x < rowWidth; x++ {
+				ycc[0], ycc[1], ycc[2] = pixel(bounds.Min.X+min(x, c.Width-1), bounds.Min.Y+y)
+				for i := range planes {
+					planes[i][r*rowWidth+x] = ycc[i]
+				}
+			}
+		}
+		
+		for ci, comp := range c.Components {
+			sx, sy := hmax/comp.H, vmax/comp.V
+			area := float64(sx * sy)
+			for by := 0; by < comp.V; by++ {
+				for bx := 0; bx < comp.BlocksWide; bx++ {
+					// Average the full-resolution pixels that each sample covers
+					for j := 0; j < 8; j++ {
+						for i := 0; i < 8; i++ {
+							x0, y0 := (bx*8+i)*sx, (by*8+j)*sy
+							sum := 0
+							for dy := 0; dy < sy; dy++ {
+								for dx := 0; dx < sx; dx++ {
+									sum += int(planes[ci][(y0+dy)*rowWidth+x0+dx])
+								}
+							}
+							samples[j*8+i] = float64(sum)/area - 128
+						}
+					}
+					fdct(&samples)
+					quantize(comp.Block(bx, my*comp.V+by), &samples, divisors[ci])
+				}
+			}
+		}
+	}
+	return c
+}
+
+// isGray reports whether the image has a single gray channel
+func isGray(img image.Image) bool {
+	switch img.(type) {
+	case *image.Gray, *image.Gray16:
+		return true
+	default:
+		return false
+	}
+}
+
+// pixelReader returns a function that reads a pixel as Y, Cb, Cr
+func pixelReader(img image.Image) func(x, y int) (uint8, uint8, uint8) {
+	switch m := img.(type) {
+	case *image.Gray:
+		return func(x, y int) (uint8, uint8, uint8) {
+			return m.Pix[m.PixOffset(x, y)], 128, 128
+		}
+	case *image.YCbCr:
+		return func(x, y int) (uint8, uint8, uint8) {
+			return m.Y[m.YOffset(x, y)], m.Cb[m.COffset(x, y)], m.Cr[m.COffset(x, y)]
+		}
+	case *image.RGBA:
+		return func(x, y int) (uint8, uint8, uint8) {
+			i := m.PixOffset(x, y)
+			return color.RGBToYCbCr(m.Pix[i], m.Pix[i+1], m.Pix[i+2])
+		}
+	case *image.NRGBA:
+		return func(x, y int) (uint8, uint8, uint8) {
+			i := m.PixOffset(x, y)
+			return color.RGBToYCbCr(m.Pix[i], m.Pix[i+1], m.Pix[i+2])
+		}
+	default:
+		return func(x, y int) (uint8, uint8, uint8) {
+			if isGray(img) {
+				g := color.GrayModel.Convert(img.At(x, y)).(color.Gray)
+				return g.Y, 128, 128
+			}
+			r, g, b, _ := img.At(x, y).RGBA()
+			return color.RGBToYCbCr(uint8(r>>8), uint8(g>>8), uint8(b>>8))
+		}
+	}
+}
+
+// jfifSegment returns a JFIF APP0 segment without physical density
+func jfifSegment() Segment {
+	return Segment{
+		Marker: markerAPP0,
+		Data:   []byte{'J', 'F', 'I', 'F', 0, 1, 1, 0, 0, 1, 0, 1, 0, 0},
+	}
+}
\ No newline at end of file
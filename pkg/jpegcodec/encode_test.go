@@ -0,0 +1,220 @@
+package jpegcodec
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math"
+	"testing"
+)
+
+// testImage returns a smooth color image with odd dimensions
+func testImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 255 / width), uint8(y * 255 / height), uint8((x + y) % 256), 255})
+		}
+	}
+	return img
+}
+
+// psnr returns the peak signal-to-noise ratio between two images in dB
+func psnr(a, b image.Image) float64 {
+	var sum float64
+	n := 0
+	bounds := a.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r1, g1, b1, _ := a.At(x, y).RGBA()
+			r2, g2, b2, _ := b.At(x, y).RGBA()
+			for _, d := range []float64{
+				float64(r1>>8) - float64(r2>>8),
+				float64(g1>>8) - float64(g2>>8),
+				float64(b1>>8) - float64(b2>>8),
+			} {
+				sum += d * d
+				n++
+			}
+		}
+	}
+	if sum == 0 {
+		return math.Inf(1)
+	}
+	return 10 * math.Log10(255*255/(sum/float64(n)))
+}
+
+// frameHeader returns the SOF marker and the luma sampling factors of a JPEG
+func frameHeader(t *testing.T, data []byte) (byte, int, int) {
+	t.Helper()
+	for i := 2; i+9 < len(data); {
+		if data[i] != 0xFF {
+			t.Fatalf("expected marker at offset %d", i)
+		}
+		marker := data[i+1]
+		length := int(data[i+2])<<8 | int(data[i+3])
+		if marker >= markerSOF0 && marker <= markerSOF2 {
+			factors := data[i+11]
+			return marker, int(factors >> 4), int(factors & 0x0F)
+		}
+		i += 2 + length
+	}
+	t.Fatal("no frame header found")
+	return 0, 0, 0
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	img := testImage(203, 117)
+	gray := image.NewGray(image.Rect(0, 0, 50, 33))
+	for i := range gray.Pix {
+		gray.Pix[i] = uint8(i % 251)
+	}
+	
+	tests := []struct {
+		name       string
+		img        image.Image
+		options    Options
+		wantMarker byte
+		wantH      int
+		wantV      int
+	}{
+		{name: "Baseline 4:2:0", img: img, options: Options{Quality: 90}, wantMarker: markerSOF0, wantH: 2, wantV: 2},
+		{name: "Baseline 4:2:2", img: img, options: Options{Quality: 90, Subsampling: Subsampling422}, wantMarker: markerSOF0, wantH: 2, wantV: 1},
+		{name: "Baseline 4:4:4 optimized", img: img, options: Options{Quality: 90, Subsampling: Subsampling444, OptimizeHuffman: true}, wantMarker: markerSOF0, wantH: 1, wantV: 1},
+		{name: "Progressive 4:2:0", img: img, options: Options{Quality: 90, Progressive: true}, wantMarker: markerSOF2, wantH: 2, wantV: 2},
+		{name: "Progressive 4:4:4", img: img, options: Options{Quality: 90, Subsampling: Subsampling444, Progressive: true}, wantMarker: markerSOF2, wantH: 1, wantV: 1},
+		{name: "Grayscale", img: gray, options: Options{Quality: 90, OptimizeHuffman: true}, wantMarker: markerSOF0, wantH: 1, wantV: 1},
+		{name: "Progressive grayscale", img: gray, options: Options{Quality: 90, Progressive: true}, wantMarker: markerSOF2, wantH: 1, wantV: 1},
+		{name: "Single pixel", img: testImage(1, 1), options: Options{Progressive: true}, wantMarker: markerSOF2, wantH: 2, wantV: 2},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := Encode(buf, tt.img, &tt.options); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			
+			marker, h, v := frameHeader(t, buf.Bytes())
+			if marker != tt.wantMarker || h != tt.wantH || v != tt.wantV {
+				t.Errorf("frame = SOF%d %dx%d, want SOF%d %dx%d", marker-markerSOF0, h, v, tt.wantMarker-markerSOF0, tt.wantH, tt.wantV)
+			}
+			
+			decoded, err := jpeg.Decode(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("jpeg.Decode() error = %v", err)
+			}
+			if decoded.Bounds() != tt.img.Bounds() {
+				t.Fatalf("decoded bounds = %v, want %v", decoded.Bounds(), tt.img.Bounds())
+			}
+			if p := psnr(tt.img, decoded); p < 30 {
+				t.Errorf("PSNR = %.2f dB, want at least 30", p)
+			}
+		})
+	}
+}
+
+func TestEncodeMatchesStandardLibraryQuality(t *testing.T) {
+	img := testImage(160, 120)
+	
+	std := &bytes.Buffer{}
+	if err := jpeg.Encode(std, img, &jpeg.Options{Quality: 85}); err != nil {
+		t.Fatalf("jpeg.Encode() error = %v", err)
+	}
+	stdDecoded, _ := jpeg.Decode(bytes.NewReader(std.Bytes()))
+	
+	ours := &bytes.Buffer{}
+	if err := Encode(ours, img, &Options{Quality: 85, OptimizeHuffman: true}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	oursDecoded, _ := jpeg.Decode(bytes.NewReader(ours.Bytes()))
+	
+	if ours.Len() >= std.Len() {
+		t.Errorf("optimized size %d is not smaller than standard library size %d", ours.Len(), std.Len())
+	}
+	if diff := psnr(img, stdDecoded) - psnr(img, oursDecoded); diff > 0.5 {
+		t.Errorf("PSNR is %.2f dB worse than the standard library", diff)
+	}
+}
+
+func TestEntropyCodingIsLossless(t *testing.T) {
+	img := testImage(97, 65)
+	var decoded []image.Image
+	for _, options := range []WriteOptions{{}, {OptimizeHuffman: true}, {Progressive: true}} {
+		buf := &bytes.Buffer{}
+		if err := Write(buf, FromImage(img, 80, Subsampling420), &options); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		m, err := jpeg.Decode(buf)
+		if err != nil {
+			t.Fatalf("jpeg.Decode() error = %v", err)
+		}
+		decoded = append(decoded, m)
+	}
+	
+	// The same coefficients must decode to the same pixels regardless of entropy coding
+	for i := 1; i < len(decoded); i++ {
+		if p := psnr(decoded[0], decoded[i]); !math.IsInf(p, 1) {
+			t.Errorf("entropy coding %d changed the decoded image (PSNR %.2f dB)", i, p)
+		}
+	}
+}
+
+func TestSubsampling444KeepsRedDetail(t *testing.T) {
+	// One-pixel red lines on white, like thin red text
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			c := color.RGBA{255, 255, 255, 255}
+			if x%4 == 0 {
+				c = color.RGBA{220, 0, 0, 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	
+	quality := func(s Subsampling) float64 {
+		buf := &bytes.Buffer{}
+		if err := Encode(buf, img, &Options{Quality: 90, Subsampling: s}); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		decoded, err := jpeg.Decode(buf)
+		if err != nil {
+			t.Fatalf("jpeg.Decode() error = %v", err)
+		}
+		return psnr(img, decoded)
+	}
+	
+	if full, half := quality(Subsampling444), quality(Subsampling420); full <= half+3 {
+		t.Errorf("4:4:4 PSNR %.2f dB is not clearly better than 4:2:0 PSNR %.2f dB", full, half)
+	}
+}
+
+func TestOptimalHuffmanSpecLimitsCodeLength(t *testing.T) {
+	// Fibonacci frequencies produce a maximally skewed tree
+	var freq [256]int
+	a, b := 1, 1
+	for i := 0; i < 40; i++ {
+		freq[i] = a
+		a, b = b, a+b
+	}
+	
+	spec := optimalHuffmanSpec(freq)
+	total := 0
+	for _, count := range spec.counts {
+		total += int(count)
+	}
+	if total != 40 || len(spec.values) != 40 {
+		t.Fatalf("table has %d codes and %d values, want 40", total, len(spec.values))
+	}
+	
+	// Kraft inequality must hold strictly, since the all-ones code is reserved
+	kraft := 0.0
+	for length, count := range spec.counts {
+		kraft += float64(count) / float64(uint(1)<<(length+1))
+	}
+	if kraft >= 1 {
+		t.Errorf("Kraft sum = %f, want < 1", kraft)
+	}
+}
\ No newline at end of file
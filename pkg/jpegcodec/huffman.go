@@ -0,0 +1,241 @@
+package jpegcodec
+
+import (
+	"bufio"
+	"sort"
+)
+
+// Huffman table classes
+const (
+	classDC = 0
+	classAC = 1
+)
+
+// huffmanSpec is a Huffman table as stored in a DHT segment
+type huffmanSpec struct {
+	counts [16]byte // Number of codes of each length 1-16
+	values []byte   // Symbols in order of increasing code length
+}
+
+// huffmanCode maps symbols to their codes for encoding
+type huffmanCode struct {
+	code [256]uint16
+	size [256]uint8
+}
+
+// standardHuffmanSpecs are the example tables from Annex K.3 of the JPEG specification,
+// indexed by [class][table id]
+var standardHuffmanSpecs = [2][2]huffmanSpec{
+	{
+		// Luminance DC
+		{
+			counts: [16]byte{0, 1, 5, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0, 0, 0},
+			values: []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+		},
+		// Chrominance DC
+		{
+			counts: [16]byte{0, 3, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0},
+			values: []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+		},
+	},
+	{
+		// Luminance AC
+		{
+			counts: [16]byte{0, 2, 1, 3, 3, 2, 4, 3, 5, 5, 4, 4, 0, 0, 1, 125},
+			values: []byte{
+				0x01, 0x02, 0x03, 0x00, 0x04, 0x11, 0x05, 0x12,
+				0x21, 0x31, 0x41, 0x06, 0x13, 0x51, 0x61, 0x07,
+				0x22, 0x71, 0x14, 0x32, 0x81, 0x91, 0xa1, 0x08,
+				0x23, 0x42, 0xb1, 0xc1, 0x15, 0x52, 0xd1, 0xf0,
+				0x24, 0x33, 0x62, 0x72, 0x82, 0x09, 0x0a, 0x16,
+				0x17, 0x18, 0x19, 0x1a, 0x25, 0x26, 0x27, 0x28,
+				0x29, 0x2a, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39,
+				0x3a, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49,
+				0x4a, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59,
+				0x5a, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69,
+				0x6a, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79,
+				0x7a, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89,
+				0x8a, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98,
+				0x99, 0x9a, 0xa2, 0xa3, 0xa4, 0xa5, 0xa6, 0xa7,
+				0xa8, 0xa9, 0xaa, 0xb2, 0xb3, 0xb4, 0xb5, 0xb6,
+				0xb7, 0xb8, 0xb9, 0xba, 0xc2, 0xc3, 0xc4, 0xc5,
+				0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xd2, 0xd3, 0xd4,
+				0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda, 0xe1, 0xe2,
+				0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9, 0xea,
+				0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8,
+				0xf9, 0xfa,
+			},
+		},
+		// Chrominance AC
+		{
+			counts: [16]byte{0, 2, 1, 2, 4, 4, 3, 4, 7, 5, 4, 4, 0, 1, 2, 119},
+			values: []byte{
+				0x00, 0x01, 0x02, 0x03, 0x11, 0x04, 0x05, 0x21,
+				0x31, 0x06, 0x12, 0x41, 0x51, 0x07, 0x61, 0x71,
+				0x13, 0x22, 0x32, 0x81, 0x08, 0x14, 0x42, 0x91,
+				0xa1, 0xb1, 0xc1, 0x09, 0x23, 0x33, 0x52, 0xf0,
+				0x15, 0x62, 0x72, 0xd1, 0x0a, 0x16, 0x24, 0x34,
+				0xe1, 0x25, 0xf1, 0x17, 0x18, 0x19, 0x1a, 0x26,
+				0x27, 0x28, 0x29, 0x2a, 0x35, 0x36, 0x37, 0x38,
+				0x39, 0x3a, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48,
+				0x49, 0x4a, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58,
+				0x59, 0x5a, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68,
+				0x69, 0x6a, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78,
+				0x79, 0x7a, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87,
+				0x88, 0x89, 0x8a, 0x92, 0x93, 0x94, 0x95, 0x96,
+				0x97, 0x98, 0x99, 0x9a, 0xa2, 0xa3, 0xa4, 0xa5,
+				0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xb2, 0xb3, 0xb4,
+				0xb5, 0xb6, 0xb7, 0xb8, 0xb9, 0xba, 0xc2, 0xc3,
+				0xc4, 0xc5, 0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xd2,
+				0xd3, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda,
+				0xe2, 0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9,
+				0xea, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8,
+				0xf9, 0xfa,
+			},
+		},
+	},
+}
+
+// codes assigns canonical codes to the symbols of the table (Annex C)
+func (s *huffmanSpec) codes() *huffmanCode {
+	h := &huffmanCode{}
+	code, k := uint16(0), 0
+	for length := 1; length <= 16; length++ {
+		for i := 0; i < int(s.counts[length-1]); i++ {
+			h.code[s.values[k]] = code
+			h.size[s.values[k]] = uint8(length)
+			code++
+			k++
+		}
+		code <<= 1
+	}
+	return h
+}
+
+// optimalHuffmanSpec builds a table from symbol frequencies, following Annex K.2.
+// Code lengths are limited to 16 bits and the all-ones code is never used.
+func optimalHuffmanSpec(freq [256]int) huffmanSpec {
+	var f [257]int
+	copy(f[:], freq[:])
+	f[256] = 1 // Reserved symbol that keeps the all-ones code unused
+	
+	var codeSize [257]int
+	others := [257]int{}
+	for i := range others {
+		others[i] = -1
+	}
+	
+	for {
+		// Find the two least frequent symbols, preferring later ones on ties
+		c1, c2 := -1, -1
+		for i := 0; i < 257; i++ {
+			if f[i] > 0 && (c1 < 0 || f[i] <= f[c1]) {
+				c1 = i
+			}
+		}
+		for i := 0; i < 257; i++ {
+			if f[i] > 0 && i != c1 && (c2 < 0 || f[i] <= f[c2]) {
+				c2 = i
+			}
+		}
+		if c2 < 0 {
+			break
+		}
+		
+		// Merge the two trees
+		f[c1] += f[c2]
+		f[c2] = 0
+		codeSize[c1]++
+		for others[c1] >= 0 {
+			c1 = others[c1]
+			codeSize[c1]++
+		}
+		others[c1] = c2
+		codeSize[c2]++
+		for others[c2] >= 0 {
+			c2 = others[c2]
+			codeSize[c2]++
+		}
+	}
+	
+	maxLength := 0
+	for _, size := range codeSize {
+		maxLength = max(maxLength, size)
+	}
+	counts := make([]int, max(maxLength, 16)+1)
+	for _, size := range codeSize {
+		if size > 0 {
+			counts[size]++
+		}
+	}
+	
+	// Shorten codes longer than 16 bits by moving pairs up the tree
+	for i := len(counts) - 1; i > 16; i-- {
+		for counts[i] > 0 {
+			j := i - 2
+			for counts[j] == 0 {
+				j--
+			}
+			counts[i] -= 2
+			counts[i-1]++
+			counts[j+1] += 2
+			counts[j]--
+		}
+	}
+	
+	// Drop the reserved symbol, which has the longest code
+	for i := 16; i > 0; i-- {
+		if counts[i] > 0 {
+			counts[i]--
+			break
+		}
+	}
+	
+	spec := huffmanSpec{}
+	for i := 1; i <= 16; i++ {
+		spec.counts[i-1] = byte(counts[i])
+	}
+	
+	// Symbols are listed by code length, then by value
+	symbols := make([]int, 0, 256)
+	for i := 0; i < 256; i++ {
+		if codeSize[i] > 0 {
+			symbols = append(symbols, i)
+		}
+	}
+	sort.SliceStable(symbols, func(a, b int) bool {
+		return codeSize[symbols[a]] < codeSize[symbols[b]]
+	})
+	for _, sym := range symbols {
+		spec.values = append(spec.values, byte(sym))
+	}
+	return spec
+}
+
+// bitWriter writes entropy-coded data with 0xFF byte stuffing
+type bitWriter struct {
+	w   *bufio.Writer
+	acc uint64
+	n   uint
+}
+
+// writeBits writes the low n bits of bits, most significant first
+func (b *bitWriter) writeBits(bits uint32, n uint) {
+	b.acc = b.acc<<n | uint64(bits)&(1<<n-1)
+	b.n += n
+	for b.n >= 8 {
+		c := byte(b.acc >> (b.n - 8))
+		_ = b.w.WriteByte(c)
+		if c == 0xFF {
+			_ = b.w.WriteByte(0)
+		}
+		b.n -= 8
+	}
+}
+
+// flush pads the last byte with one bits
+func (b *bitWriter) flush() {
+	if b.n > 0 {
+		b.writeBits(1<<(8-b.n)-1, 8-b.n)
+	}
+}
\ No newline at end of file
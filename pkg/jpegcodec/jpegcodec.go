@@ -0,0 +1,149 @@
+// Package jpegcodec reads and writes JPEG files as quantized DCT coefficients.
+// Working on coefficients gives control over chroma subsampling, progressive scans
+// and Huffman tables, and allows lossless transforms that never leave the DCT domain.
+package jpegcodec
+
+// Block holds the 64 quantized DCT coefficients of an 8x8 block in natural (row-major) order
+type Block [64]int16
+
+// Segment is a marker segment, such as APP1 (EXIF) or COM, kept verbatim
+type Segment struct {
+	Marker byte   // Marker code without the 0xFF prefix
+	Data   []byte // Segment payload without the length field
+}
+
+// Component is one color channel of a JPEG image
+type Component struct {
+	ID         uint8 // Component identifier used in the frame and scan headers
+	H, V       int   // Horizontal and vertical sampling factors
+	Tq         int   // Quantization table index
+	BlocksWide int   // Blocks per row, padded to whole MCUs
+	BlocksHigh int   // Block rows, padded to whole MCUs
+	Blocks     []Block
+}
+
+// Block returns the block at the given block coordinates
+func (c *Component) Block(bx, by int) *Block {
+	return &c.Blocks[by*c.BlocksWide+bx]
+}
+
+// Coefficients is a JPEG image in the DCT domain
+type Coefficients struct {
+	Width, Height int
+	Components    []*Component
+	Quant         [4]*[64]uint16 // Quantization tables in natural order
+	Segments      []Segment      // APPn and COM segments written after SOI
+	Progressive   bool           // Whether the source used progressive scans
+}
+
+// maxSampling returns the largest horizontal and vertical sampling factors
+func (c *Coefficients) maxSampling() (int, int) {
+	hmax, vmax := 1, 1
+	for _, comp := range c.Components {
+		hmax = max(hmax, comp.H)
+		vmax = max(vmax, comp.V)
+	}
+	return hmax, vmax
+}
+
+// mcus returns the number of MCU columns and rows of an interleaved scan
+func (c *Coefficients) mcus() (int, int) {
+	hmax, vmax := c.maxSampling()
+	return ceilDiv(c.Width, 8*hmax), ceilDiv(c.Height, 8*vmax)
+}
+
+// componentBlocks returns the number of blocks that cover the component's own area,
+// which is what a non-interleaved scan codes
+func (c *Coefficients) componentBlocks(comp *Component) (int, int) {
+	hmax, vmax := c.maxSampling()
+	width := ceilDiv(c.Width*comp.H, hmax)
+	height := ceilDiv(c.Height*comp.V, vmax)
+	return ceilDiv(width, 8), ceilDiv(height, 8)
+}
+
+// layout sets the block grid size of all components to whole MCUs
+func (c *Coefficients) layout() {
+	mcusX, mcusY := c.mcus()
+	for _, comp := range c.Components {
+		comp.BlocksWide = mcusX * comp.H
+		comp.BlocksHigh = mcusY * comp.V
+	}
+}
+
+// allocate sizes the block arrays of all components to whole MCUs
+func (c *Coefficients) allocate() {
+	c.layout()
+	for _, comp := range c.Components {
+		comp.Blocks = make([]Block, comp.BlocksWide*comp.BlocksHigh)
+	}
+}
+
+// forEachBlock visits the blocks of a scan in coding order, along with the index of
+// the MCU they belong to
+func (c *Coefficients) forEachBlock(comps []int, fn func(mcu, ci int, blk *Block)) {
+	c.forEachBlockAt(comps, func(mcu, ci, bx, by int) {
+		fn(mcu, ci, c.Components[ci].Block(bx, by))
+	})
+}
+
+// forEachBlockAt is like forEachBlock but passes block coordinates, so it works
+// without block storage
+func (c *Coefficients) forEachBlockAt(comps []int, fn func(mcu, ci, bx, by int)) {
+	if len(comps) == 1 {
+		comp := c.Components[comps[0]]
+		blocksX, blocksY := c.componentBlocks(comp)
+		for by := 0; by < blocksY; by++ {
+			for bx := 0; bx < blocksX; bx++ {
+				fn(by*blocksX+bx, comps[0], bx, by)
+			}
+		}
+		return
+	}
+	
+	mcusX, mcusY := c.mcus()
+	for my := 0; my < mcusY; my++ {
+		for mx := 0; mx < mcusX; mx++ {
+			for _, ci := range comps {
+				comp := c.Components[ci]
+				for v := 0; v < comp.V; v++ {
+					for h := 0; h < comp.H; h++ {
+						fn(my*mcusX+mx, ci, mx*comp.H+h, my*comp.V+v)
+					}
+				}
+			}
+		}
+	}
+}
+
+// unzig maps zigzag order indexes to natural order indexes
+var unzig = [64]int{
+	0, 1, 8, 16, 9, 2, 3, 10,
+	17, 24, 32, 25, 18, 11, 4, 5,
+	12, 19, 26, 33, 40, 48, 41, 34,
+	27, 20, 13, 6, 7, 14, 21, 28,
+	35, 42, 49, 56, 57, 50, 43, 36,
+	29, 22, 15, 23, 30, 37, 44, 51,
+	58, 59, 52, 45, 38, 31, 39, 46,
+	53, 60, 61, 54, 47, 55, 62, 63,
+}
+
+// Marker codes
+const (
+	markerSOF0 = 0xC0 // Baseline DCT
+	markerSOF1 = 0xC1 // Extended sequential DCT
+	markerSOF2 = 0xC2 // Progressive DCT
+	markerDHT  = 0xC4
+	markerRST0 = 0xD0
+	markerSOI  = 0xD8
+	markerEOI  = 0xD9
+	markerSOS  = 0xDA
+	markerDQT  = 0xDB
+	markerDRI  = 0xDD
+	markerAPP0 = 0xE0
+	markerCOM  = 0xFE
+)
+
+// ceilDiv divides rounding up
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
\ No newline at end of file
@@ -0,0 +1,202 @@
+package jpegcodec
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math"
+)
+
+// DecodeScaled decodes a JPEG to pixels at 1/scale of its size, where scale is 1, 2, 4
+// or 8. Reduced sizes use a smaller inverse DCT on the low frequencies of each block,
+// so a 1/8 decode only needs the DC coefficients. Sequential files are decoded block by
+// block without storing coefficients. The result is an *image.Gray or *image.YCbCr.
+func DecodeScaled(data []byte, scale int) (image.Image, error) {
+	if scale != 1 && scale != 2 && scale != 4 && scale != 8 {
+		return nil, fmt.Errorf("jpegcodec: unsupported scale 1/%d", scale)
+	}
+	d := &decoder{data: data, scaled: &scaledImage{scale: scale}}
+	if err := d.decode(); err != nil {
+		return nil, err
+	}
+	
+	// Progressive blocks are only complete after the last scan
+	if d.progressive {
+		for ci, comp := range d.c.Components {
+			q := d.c.Quant[comp.Tq]
+			for by := 0; by < comp.BlocksHigh; by++ {
+				for bx := 0; bx < comp.BlocksWide; bx++ {
+					d.scaled.put(ci, bx, by, comp.Block(bx, by), q)
+				}
+			}
+		}
+	}
+	return d.scaled.img, nil
+}
+
+// plane is one channel of the output image
+type plane struct {
+	pix           []uint8
+	stride        int
+	width, height int
+}
+
+// scaledImage collects inverse transformed blocks into an output image
+type scaledImage struct {
+	scale     int
+	blockSize int // Output pixels per block side
+	planes    []plane
+	img       image.Image
+}
+
+// init creates the output image once the frame header is known
+func (s *scaledImage) init(c *Coefficients) error {
+	s.blockSize = 8 / s.scale
+	r := image.Rect(0, 0, ceilDiv(c.Width, s.scale), ceilDiv(c.Height, s.scale))
+	switch len(c.Components) {
+	case 1:
+		img := image.NewGray(r)
+		s.planes = []plane{{img.Pix, img.Stride, r.Dx(), r.Dy()}}
+		s.img = img
+	case 3:
+		if isAdobeRGB(c.Segments) {
+			return fmt.Errorf("%w: RGB color transform", ErrUnsupported)
+		}
+		ratio, ok := subsampleRatio(c.Components)
+		if !ok {
+			return fmt.Errorf("%w: sampling factors", ErrUnsupported)
+		}
+		img := image.NewYCbCr(r, ratio)
+		chromaHeight := len(img.Cb) / img.CStride
+		s.planes = []plane{
+			{img.Y, img.YStride, r.Dx(), r.Dy()},
+			{img.Cb, img.CStride, img.CStride, chromaHeight},
+			{img.Cr, img.CStride, img.CStride, chromaHeight},
+		}
+		s.img = img
+	default:
+		return fmt.Errorf("%w: %d color components", ErrUnsupported, len(c.Components))
+	}
+	return nil
+}
+
+// put inverse transforms a block into its plane
+func (s *scaledImage) put(ci, bx, by int, blk *Block, q *[64]uint16) {
+	n := s.blockSize
+	var pixels [64]uint8
+	idctScaled(&pixels, blk, q, n)
+	
+	p := &s.planes[ci]
+	x0, y0 := bx*n, by*n
+	for y := 0; y < n && y0+y < p.height; y++ {
+		row := p.pix[(y0+y)*p.stride:]
+		for x := 0; x < n && x0+x < p.width; x++ {
+			row[x0+x] = pixels[y*n+x]
+		}
+	}
+}
+
+// subsampleRatio maps the sampling factors of a YCbCr frame to the image package ratio
+func subsampleRatio(comps []*Component) (image.YCbCrSubsampleRatio, bool) {
+	if comps[1].H != 1 || comps[1].V != 1 || comps[2].H != 1 || comps[2].V != 1 {
+		return 0, false
+	}
+	switch [2]int{comps[0].H, comps[0].V} {
+	case [2]int{1, 1}:
+		return image.YCbCrSubsampleRatio444, true
+	case [2]int{2, 1}:
+		return image.YCbCrSubsampleRatio422, true
+	case [2]int{2, 2}:
+		return image.YCbCrSubsampleRatio420, true
+	case [2]int{1, 2}:
+		return image.YCbCrSubsampleRatio440, true
+	case [2]int{4, 1}:
+		return image.YCbCrSubsampleRatio411, true
+	case [2]int{4, 2}:
+		return image.YCbCrSubsampleRatio410, true
+	default:
+		return 0, false
+	}
+}
+
+// isAdobeRGB reports whether an Adobe APP14 segment marks the components as RGB
+func isAdobeRGB(segments []Segment) bool {
+	for _, seg := range segments {
+		if seg.Marker == 0xEE && len(seg.Data) >= 12 && bytes.HasPrefix(seg.Data, []byte("Adobe")) {
+			return seg.Data[11] == 0
+		}
+	}
+	return false
+}
+
+// idctTables holds the n-point inverse DCT basis for n = 1, 2, 4 and 8, indexed
+// [n][x*8+u]. Reduced sizes keep the 8-point scaling, which samples the full
+// reconstruction at the centers of 2x2, 4x4 or 8x8 pixel groups.
+var idctTables = func() (t [9][64]float32) {
+	for _, n := range []int{1, 2, 4, 8} {
+		for x := 0; x < n; x++ {
+			for u := 0; u < n; u++ {
+				c := 1.0
+				if u == 0 {
+					c = 1 / math.Sqrt2
+				}
+				t[n][x*8+u] = float32(0.5 * c * math.Cos(float64((2*x+1)*u)*math.Pi/float64(2*n)))
+			}
+		}
+	}
+	return t
+}()
+
+// idctScaled dequantizes a block and computes an n x n inverse DCT of its lowest
+// frequencies, writing level-shifted samples in row-major order
+func idctScaled(dst *[64]uint8, blk *Block, q *[64]uint16, n int) {
+	if n == 1 {
+		dst[0] = clampSample(float32(blk[0]) * float32(q[0]) / 8)
+		return
+	}
+	
+	table := &idctTables[n]
+	var tmp [64]float32
+	for v := 0; v < n; v++ {
+		var coefs [8]float32
+		nonzero := false
+		for u := 0; u < n; u++ {
+			if c := blk[v*8+u]; c != 0 {
+				coefs[u] = float32(c) * float32(q[v*8+u])
+				nonzero = true
+			}
+		}
+		if !nonzero {
+			continue
+		}
+		for x := 0; x < n; x++ {
+			var sum float32
+			for u := 0; u < n; u++ {
+				sum += table[x*8+u] * coefs[u]
+			}
+			tmp[v*8+x] = sum
+		}
+	}
+	
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			var sum float32
+			for v := 0; v < n; v++ {
+				sum += table[y*8+v] * tmp[v*8+x]
+			}
+			dst[y*n+x] = clampSample(sum)
+		}
+	}
+}
+
+// clampSample level shifts and rounds a sample to 0-255
+func clampSample(v float32) uint8 {
+	v += 128.5
+	if v <= 0 {
+		return 0
+	}
+	if v >= 255 {
+		return 255
+	}
+	return uint8(v)
+}
\ No newline at end of file
@@ -0,0 +1,113 @@
+package jpegcodec
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+	
+	"github.com/disintegration/imaging"
+)
+
+func TestDecodeScaled(t *testing.T) {
+	// Dimensions divisible by 8 keep the box filter aligned with the blocks
+	img := image.NewRGBA(image.Rect(0, 0, 200, 120))
+	for y := 0; y < 120; y++ {
+		for x := 0; x < 200; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 255 / 200), uint8(y * 2), uint8(128 + (x-y)/2), 255})
+		}
+	}
+	
+	for _, o := range []Options{
+		{Quality: 90},
+		{Quality: 90, Subsampling: Subsampling422},
+		{Quality: 90, Subsampling: Subsampling444, Progressive: true},
+		{Quality: 90, Progressive: true},
+	} {
+		buf := &bytes.Buffer{}
+		if err := Encode(buf, img, &o); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		full, err := jpeg.Decode(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("jpeg.Decode() error = %v", err)
+		}
+		
+		for _, scale := range []int{1, 2, 4, 8} {
+			scaled, err := DecodeScaled(buf.Bytes(), scale)
+			if err != nil {
+				t.Fatalf("DecodeScaled(%+v, %d) error = %v", o, scale, err)
+			}
+			wantWidth, wantHeight := 200/scale, 120/scale
+			if scaled.Bounds() != image.Rect(0, 0, wantWidth, wantHeight) {
+				t.Fatalf("DecodeScaled(%+v, %d) bounds = %v, want %dx%d", o, scale, scaled.Bounds(), wantWidth, wantHeight)
+			}
+			
+			// Compare with a box-filtered full decode
+			want := imaging.Resize(full, wantWidth, wantHeight, imaging.Box)
+			minPSNR := 30.0
+			if scale == 1 {
+				want, minPSNR = imaging.Clone(full), 40
+			}
+			if p := psnr(want, scaled); p < minPSNR {
+				t.Errorf("DecodeScaled(%+v, %d) PSNR = %.2f dB, want at least %.0f", o, scale, p, minPSNR)
+			}
+		}
+	}
+}
+
+func TestDecodeScaledOddSize(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := Encode(buf, testImage(203, 117), &Options{}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	for _, scale := range []int{2, 4, 8} {
+		scaled, err := DecodeScaled(buf.Bytes(), scale)
+		if err != nil {
+			t.Fatalf("DecodeScaled() error = %v", err)
+		}
+		if want := image.Rect(0, 0, ceilDiv(203, scale), ceilDiv(117, scale)); scaled.Bounds() != want {
+			t.Errorf("DecodeScaled(%d) bounds = %v, want %v", scale, scaled.Bounds(), want)
+		}
+	}
+}
+
+func TestDecodeScaledGray(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 64, 40))
+	for i := range gray.Pix {
+		gray.Pix[i] = uint8(i % 200)
+	}
+	buf := &bytes.Buffer{}
+	if err := Encode(buf, gray, &Options{Quality: 90}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	scaled, err := DecodeScaled(buf.Bytes(), 4)
+	if err != nil {
+		t.Fatalf("DecodeScaled() error = %v", err)
+	}
+	if _, ok := scaled.(*image.Gray); !ok || scaled.Bounds().Dx() != 16 || scaled.Bounds().Dy() != 10 {
+		t.Errorf("DecodeScaled() = %T %v, want *image.Gray 16x10", scaled, scaled.Bounds())
+	}
+	
+	if _, err := DecodeScaled(buf.Bytes(), 3); err == nil {
+		t.Error("DecodeScaled() expected an error for scale 3")
+	}
+}
+
+func TestDecodeHeader(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := Encode(buf, testImage(203, 117), &Options{Progressive: true}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	c, err := DecodeHeader(buf.Bytes())
+	if err != nil {
+		t.Fatalf("DecodeHeader() error = %v", err)
+	}
+	if c.Width != 203 || c.Height != 117 || len(c.Components) != 3 || !c.Progressive {
+		t.Errorf("DecodeHeader() = %dx%d, %d components, progressive %v", c.Width, c.Height, len(c.Components), c.Progressive)
+	}
+	if len(c.Segments) == 0 || c.Components[0].Blocks != nil {
+		t.Errorf("DecodeHeader() should keep segments and skip block storage")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,262 @@
+package jpegcodec
+
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+// Transform is a geometric transform that can be applied to coefficients without
+// decoding, like jpegtran does
+type Transform int
+
+const (
+	TransformNone  Transform = iota
+	FlipHorizontal           // Mirror left and right
+	FlipVertical             // Mirror top and bottom
+	Transpose                // Mirror across the top-left to bottom-right diagonal
+	Transverse               // Mirror across the top-right to bottom-left diagonal
+	Rotate90                 // Rotate 90° clockwise
+	Rotate180                // Rotate 180°
+	Rotate270                // Rotate 270° clockwise (90° counter-clockwise)
+)
+
+// String returns the name of the transform
+func (t Transform) String() string {
+	switch t {
+	case FlipHorizontal:
+		return "flip-horizontal"
+	case FlipVertical:
+		return "flip-vertical"
+	case Transpose:
+		return "transpose"
+	case Transverse:
+		return "transverse"
+	case Rotate90:
+		return "rotate-90"
+	case Rotate180:
+		return "rotate-180"
+	case Rotate270:
+		return "rotate-270"
+	default:
+		return "none"
+	}
+}
+
+// primitive is one of the three operations all transforms are built from
+type primitive int
+
+const (
+	primitiveFlipH primitive = iota
+	primitiveFlipV
+	primitiveTranspose
+)
+
+// steps decomposes the transform into primitives applied in order
+func (t Transform) steps() []primitive {
+	switch t {
+	case FlipHorizontal:
+		return []primitive{primitiveFlipH}
+	case FlipVertical:
+		return []primitive{primitiveFlipV}
+	case Transpose:
+		return []primitive{primitiveTranspose}
+	case Transverse:
+		return []primitive{primitiveTranspose, primitiveFlipH, primitiveFlipV}
+	case Rotate90:
+		return []primitive{primitiveTranspose, primitiveFlipH}
+	case Rotate180:
+		return []primitive{primitiveFlipH, primitiveFlipV}
+	case Rotate270:
+		return []primitive{primitiveTranspose, primitiveFlipV}
+	default:
+		return nil
+	}
+}
+
+// ErrTooSmall is returned when an image is smaller than one MCU along an edge
+// that a lossless transform would have to trim
+var ErrTooSmall = errors.New("jpegcodec: image too small for lossless transform")
+
+// MCUSize returns the width and height of a minimum coded unit in pixels.
+// Lossless transforms can only move whole MCUs.
+func (c *Coefficients) MCUSize() (int, int) {
+	hmax, vmax := c.maxSampling()
+	return 8 * hmax, 8 * vmax
+}
+
+// Perfect reports whether the transform keeps every pixel. Flips need the mirrored
+// dimension to be a whole number of MCUs; otherwise Apply trims the partial MCU.
+func (c *Coefficients) Perfect(transforms ...Transform) bool {
+	width, height := c.Width, c.Height
+	mcuW, mcuH := c.MCUSize()
+	for _, t := range transforms {
+		for _, step := range t.steps() {
+			switch step {
+			case primitiveFlipH:
+				if width%mcuW != 0 {
+					return false
+				}
+			case primitiveFlipV:
+				if height%mcuH != 0 {
+					return false
+				}
+			case primitiveTranspose:
+				width, height = height, width
+				mcuW, mcuH = mcuH, mcuW
+			}
+		}
+	}
+	return true
+}
+
+// Apply performs the transform in the DCT domain. Partial MCUs on an edge that
+// would move to the opposite side are trimmed, like jpegtran -trim.
+func (c *Coefficients) Apply(t Transform) (*Coefficients, error) {
+	result := c
+	for _, step := range t.steps() {
+		var err error
+		switch step {
+		case primitiveFlipH:
+			result, err = result.flipHorizontal()
+		case primitiveFlipV:
+			result, err = result.flipVertical()
+		case primitiveTranspose:
+			result = result.transpose()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Crop extracts a rectangle without decoding. The top-left corner must be on an MCU
+// boundary; the right and bottom edges can be anywhere.
+func (c *Coefficients) Crop(r image.Rectangle) (*Coefficients, error) {
+	mcuW, mcuH := c.MCUSize()
+	if r.Empty() || !r.In(image.Rect(0, 0, c.Width, c.Height)) {
+		return nil, fmt.Errorf("jpegcodec: crop %v is outside the %dx%d image", r, c.Width, c.Height)
+	}
+	if r.Min.X%mcuW != 0 || r.Min.Y%mcuH != 0 {
+		return nil, fmt.Errorf("jpegcodec: crop origin %v is not aligned to the %dx%d MCU grid", r.Min, mcuW, mcuH)
+	}
+	
+	result := c.derive(r.Dx(), r.Dy(), false)
+	for ci, comp := range result.Components {
+		src := c.Components[ci]
+		offsetX, offsetY := r.Min.X/mcuW*src.H, r.Min.Y/mcuH*src.V
+		for by := 0; by < comp.BlocksHigh; by++ {
+			for bx := 0; bx < comp.BlocksWide; bx++ {
+				sx, sy := bx+offsetX, by+offsetY
+				if sx < src.BlocksWide && sy < src.BlocksHigh {
+					*comp.Block(bx, by) = *src.Block(sx, sy)
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// derive creates empty coefficients of a new size that share tables and metadata.
+// With transpose, sampling factors and quantization tables are transposed as well.
+func (c *Coefficients) derive(width, height int, transpose bool) *Coefficients {
+	result := &Coefficients{
+		Width:       width,
+		Height:      height,
+		Quant:       c.Quant,
+		Segments:    c.Segments,
+		Progressive: c.Progressive,
+	}
+	for _, comp := range c.Components {
+		h, v := comp.H, comp.V
+		if transpose {
+			h, v = v, h
+		}
+		result.Components = append(result.Components, &Component{ID: comp.ID, H: h, V: v, Tq: comp.Tq})
+	}
+	if transpose {
+		for i, table := range c.Quant {
+			if table != nil {
+				transposed := &[64]uint16{}
+				for k, q := range table {
+					transposed[k%8*8+k/8] = q
+				}
+				result.Quant[i] = transposed
+			}
+		}
+	}
+	result.allocate()
+	return result
+}
+
+// flipHorizontal mirrors block columns and negates odd horizontal frequencies
+func (c *Coefficients) flipHorizontal() (*Coefficients, error) {
+	mcuW, _ := c.MCUSize()
+	width := c.Width / mcuW * mcuW
+	if width == 0 {
+		return nil, ErrTooSmall
+	}
+	
+	result := c.derive(width, c.Height, false)
+	for ci, comp := range result.Components {
+		src := c.Components[ci]
+		for by := 0; by < comp.BlocksHigh; by++ {
+			for bx := 0; bx < comp.BlocksWide; bx++ {
+				blk := comp.Block(bx, by)
+				*blk = *src.Block(comp.BlocksWide-1-bx, by)
+				for k := 1; k < 64; k += 2 {
+					blk[k] = -blk[k]
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// flipVertical mirrors block rows and negates odd vertical frequencies
+func (c *Coefficients) flipVertical() (*Coefficients, error) {
+	_, mcuH := c.MCUSize()
+	height := c.Height / mcuH * mcuH
+	if height == 0 {
+		return nil, ErrTooSmall
+	}
+	
+	result := c.derive(c.Width, height, false)
+	for ci, comp := range result.Components {
+		src := c.Components[ci]
+		for by := 0; by < comp.BlocksHigh; by++ {
+			for bx := 0; bx < comp.BlocksWide; bx++ {
+				blk := comp.Block(bx, by)
+				*blk = *src.Block(bx, comp.BlocksHigh-1-by)
+				for k := 8; k < 64; k++ {
+					if k/8%2 == 1 {
+						blk[k] = -blk[k]
+					}
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// transpose swaps block rows with columns and transposes each block
+func (c *Coefficients) transpose() *Coefficients {
+	result := c.derive(c.Height, c.Width, true)
+	for ci, comp := range result.Components {
+		src := c.Components[ci]
+		for by := 0; by < comp.BlocksHigh; by++ {
+			for bx := 0; bx < comp.BlocksWide; bx++ {
+				if by >= src.BlocksWide || bx >= src.BlocksHigh {
+					continue
+				}
+				from := src.Block(by, bx)
+				blk := comp.Block(bx, by)
+				for k := range blk {
+					blk[k] = from[k%8*8+k/8]
+				}
+			}
+		}
+	}
+	return result
+}
\ No newline at end of file
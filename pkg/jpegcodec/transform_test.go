@@ -0,0 +1,192 @@
+package jpegcodec
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"testing"
+	
+	"github.com/disintegration/imaging"
+)
+
+// encodeDecode encodes the image and reads the coefficients back
+func encodeDecode(t *testing.T, img image.Image, o Options) *Coefficients {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	if err := Encode(buf, img, &o); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	c, err := DecodeBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("DecodeBytes() error = %v", err)
+	}
+	return c
+}
+
+// pixels writes the coefficients and decodes them with the standard library
+func pixels(t *testing.T, c *Coefficients) image.Image {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	if err := Write(buf, c, &WriteOptions{OptimizeHuffman: true}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	img, err := jpeg.Decode(buf)
+	if err != nil {
+		t.Fatalf("jpeg.Decode() error = %v", err)
+	}
+	return img
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	img := testImage(203, 117)
+	for _, o := range []Options{
+		{Quality: 85},
+		{Quality: 85, Subsampling: Subsampling422, OptimizeHuffman: true},
+		{Quality: 85, Subsampling: Subsampling444, Progressive: true},
+		{Quality: 85, Progressive: true},
+	} {
+		buf := &bytes.Buffer{}
+		if err := Encode(buf, img, &o); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		original := FromImage(img, o.Quality, o.Subsampling)
+		decoded, err := DecodeBytes(buf.Bytes())
+		if err != nil {
+			t.Fatalf("DecodeBytes(%+v) error = %v", o, err)
+		}
+		if decoded.Progressive != o.Progressive {
+			t.Errorf("Progressive = %v, want %v", decoded.Progressive, o.Progressive)
+		}
+		
+		// Padding blocks outside a component's area are not coded by non-interleaved scans
+		for ci, comp := range decoded.Components {
+			blocksX, blocksY := decoded.componentBlocks(comp)
+			for by := 0; by < blocksY; by++ {
+				for bx := 0; bx < blocksX; bx++ {
+					if *comp.Block(bx, by) != *original.Components[ci].Block(bx, by) {
+						t.Fatalf("%+v: component %d block (%d, %d) differs after decoding", o, ci, bx, by)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestApplyMatchesPixelTransforms(t *testing.T) {
+	// 64x48 is a whole number of 16x16 MCUs, so every transform is perfect
+	img := testImage(64, 48)
+	
+	tests := []struct {
+		transform Transform
+		want      func(image.Image) *image.NRGBA
+	}{
+		{FlipHorizontal, imaging.FlipH},
+		{FlipVertical, imaging.FlipV},
+		{Transpose, imaging.Transpose},
+		{Transverse, imaging.Transverse},
+		{Rotate90, imaging.Rotate270}, // imaging rotates counter-clockwise
+		{Rotate180, imaging.Rotate180},
+		{Rotate270, imaging.Rotate90},
+	}
+	
+	for _, subsampling := range []Subsampling{Subsampling420, Subsampling422, Subsampling444} {
+		c := encodeDecode(t, img, Options{Quality: 90, Subsampling: subsampling})
+		original := pixels(t, c)
+		for _, tt := range tests {
+			t.Run(subsampling.String()+" "+tt.transform.String(), func(t *testing.T) {
+				if !c.Perfect(tt.transform) {
+					t.Fatalf("Perfect(%v) = false for an MCU-aligned image", tt.transform)
+				}
+				result, err := c.Apply(tt.transform)
+				if err != nil {
+					t.Fatalf("Apply() error = %v", err)
+				}
+				got := pixels(t, result)
+				want := tt.want(original)
+				if got.Bounds() != want.Bounds() {
+					t.Fatalf("bounds = %v, want %v", got.Bounds(), want.Bounds())
+				}
+				if p := psnr(want, got); p < 45 {
+					t.Errorf("PSNR against pixel transform = %.2f dB, want at least 45", p)
+				}
+			})
+		}
+	}
+}
+
+func TestApplyRotationsCompose(t *testing.T) {
+	c := encodeDecode(t, testImage(48, 32), Options{Quality: 80, Progressive: true})
+	result := c
+	for i := 0; i < 4; i++ {
+		var err error
+		if result, err = result.Apply(Rotate90); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+	}
+	for ci, comp := range result.Components {
+		for i := range comp.Blocks {
+			if comp.Blocks[i] != c.Components[ci].Blocks[i] {
+				t.Fatalf("four rotations changed component %d block %d", ci, i)
+			}
+		}
+	}
+}
+
+func TestApplyTrimsPartialMCUs(t *testing.T) {
+	c := encodeDecode(t, testImage(70, 37), Options{Quality: 80})
+	
+	tests := []struct {
+		transform  Transform
+		wantWidth  int
+		wantHeight int
+	}{
+		{Transpose, 37, 70},
+		{FlipHorizontal, 64, 37},
+		{FlipVertical, 70, 32},
+		{Rotate90, 32, 70},
+		{Rotate180, 64, 32},
+	}
+	
+	for _, tt := range tests {
+		if perfect := c.Perfect(tt.transform); perfect != (tt.transform == Transpose) {
+			t.Errorf("Perfect(%v) = %v", tt.transform, perfect)
+		}
+		result, err := c.Apply(tt.transform)
+		if err != nil {
+			t.Fatalf("Apply(%v) error = %v", tt.transform, err)
+		}
+		if result.Width != tt.wantWidth || result.Height != tt.wantHeight {
+			t.Errorf("Apply(%v) size = %dx%d, want %dx%d", tt.transform, result.Width, result.Height, tt.wantWidth, tt.wantHeight)
+		}
+	}
+	
+	if _, err := encodeDecode(t, testImage(10, 10), Options{}).Apply(FlipHorizontal); err != ErrTooSmall {
+		t.Errorf("Apply() on an image smaller than an MCU error = %v, want ErrTooSmall", err)
+	}
+}
+
+func TestCrop(t *testing.T) {
+	img := testImage(100, 80)
+	c := encodeDecode(t, img, Options{Quality: 90})
+	original := pixels(t, c)
+	
+	rect := image.Rect(32, 16, 91, 75)
+	cropped, err := c.Crop(rect)
+	if err != nil {
+		t.Fatalf("Crop() error = %v", err)
+	}
+	got := pixels(t, cropped)
+	want := imaging.Crop(original, rect)
+	if got.Bounds() != want.Bounds() {
+		t.Fatalf("bounds = %v, want %v", got.Bounds(), want.Bounds())
+	}
+	if p := psnr(want, got); p < 45 {
+		t.Errorf("PSNR against pixel crop = %.2f dB, want at least 45", p)
+	}
+	
+	for _, r := range []image.Rectangle{image.Rect(8, 0, 50, 50), image.Rect(0, 0, 120, 50), image.Rect(16, 16, 16, 40)} {
+		if _, err := c.Crop(r); err == nil {
+			t.Errorf("Crop(%v) expected an error", r)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,461 @@
+package jpegcodec
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// maxEOBRun is the longest end-of-band run a single symbol can code
+const maxEOBRun = 0x7FFF
+
+// WriteOptions controls how coefficients are entropy coded
+type WriteOptions struct {
+	Progressive     bool // Write progressive scans (always uses optimized Huffman tables)
+	OptimizeHuffman bool // Build Huffman tables from the image statistics
+}
+
+// scan describes one scan of the image
+type scan struct {
+	comps  []int // Component indexes
+	ss, se int   // Spectral selection
+	ah, al int   // Successive approximation bit positions
+}
+
+// Write encodes coefficients as a JPEG file
+func Write(w io.Writer, c *Coefficients, o *WriteOptions) error {
+	if o == nil {
+		o = &WriteOptions{}
+	}
+	if c.Width <= 0 || c.Height <= 0 || c.Width > 0xFFFF || c.Height > 0xFFFF {
+		return fmt.Errorf("jpegcodec: invalid image size %dx%d", c.Width, c.Height)
+	}
+	if len(c.Components) == 0 || len(c.Components) > 4 {
+		return fmt.Errorf("jpegcodec: unsupported number of components: %d", len(c.Components))
+	}
+	
+	bw := bufio.NewWriter(w)
+	writeMarker(bw, markerSOI)
+	for _, segment := range c.Segments {
+		writeSegment(bw, segment.Marker, segment.Data)
+	}
+	extended := writeDQT(bw, c)
+	
+	if o.Progressive {
+		writeSOF(bw, c, markerSOF2)
+		for _, s := range progressiveScript(len(c.Components)) {
+			if err := writeScan(bw, c, s, nil); err != nil {
+				return err
+			}
+		}
+	} else {
+		marker := byte(markerSOF0)
+		if extended {
+			marker = markerSOF1
+		}
+		writeSOF(bw, c, marker)
+		s := scan{comps: allComponents(c), se: 63}
+		var tables *[2][2]huffmanSpec
+		if !o.OptimizeHuffman {
+			tables = &standardHuffmanSpecs
+		}
+		if err := writeScan(bw, c, s, tables); err != nil {
+			return err
+		}
+	}
+	
+	writeMarker(bw, markerEOI)
+	return bw.Flush()
+}
+
+// allComponents returns the indexes of all components
+func allComponents(c *Coefficients) []int {
+	comps := make([]int, len(c.Components))
+	for i := range comps {
+		comps[i] = i
+	}
+	return comps
+}
+
+// progressiveScript returns the scan sequence libjpeg uses for progressive output:
+// a coarse DC and low-frequency luma pass first, then refinements
+func progressiveScript(numComponents int) []scan {
+	all := make([]int, numComponents)
+	for i := range all {
+		all[i] = i
+	}
+	if numComponents != 3 {
+		script := []scan{{comps: all, ss: 0, se: 0, ah: 0, al: 1}}
+		for ci := range all {
+			script = append(script,
+				scan{comps: []int{ci}, ss: 1, se: 5, ah: 0, al: 2},
+				scan{comps: []int{ci}, ss: 6, se: 63, ah: 0, al: 2},
+				scan{comps: []int{ci}, ss: 1, se: 63, ah: 2, al: 1},
+			)
+		}
+		script = append(script, scan{comps: all, ss: 0, se: 0, ah: 1, al: 0})
+		for ci := range all {
+			script = append(script, scan{comps: []int{ci}, ss: 1, se: 63, ah: 1, al: 0})
+		}
+		return script
+	}
+	return []scan{
+		{comps: all, ss: 0, se: 0, ah: 0, al: 1},
+		{comps: []int{0}, ss: 1, se: 5, ah: 0, al: 2},
+		{comps: []int{2}, ss: 1, se: 63, ah: 0, al: 1},
+		{comps: []int{1}, ss: 1, se: 63, ah: 0, al: 1},
+		{comps: []int{0}, ss: 6, se: 63, ah: 0, al: 2},
+		{comps: []int{0}, ss: 1, se: 63, ah: 2, al: 1},
+		{comps: all, ss: 0, se: 0, ah: 1, al: 0},
+		{comps: []int{2}, ss: 1, se: 63, ah: 1, al: 0},
+		{comps: []int{1}, ss: 1, se: 63, ah: 1, al: 0},
+		{comps: []int{0}, ss: 1, se: 63, ah: 1, al: 0},
+	}
+}
+
+// tableID returns the Huffman table used by a component: 0 for luma, 1 for chroma
+func tableID(ci int) int {
+	return min(ci, 1)
+}
+
+// writeScan writes the Huffman tables, header and entropy-coded data of one scan.
+// With nil tables, optimal tables are computed from a statistics pass first.
+func writeScan(bw *bufio.Writer, c *Coefficients, s scan, tables *[2][2]huffmanSpec) error {
+	used := [2][2]bool{}
+	for _, ci := range s.comps {
+		if s.ss == 0 && s.ah == 0 {
+			used[classDC][tableID(ci)] = true
+		}
+		if s.se > 0 {
+			used[classAC][tableID(ci)] = true
+		}
+	}
+	
+	if tables == nil {
+		stats := newEntropyEncoder(c, nil)
+		stats.encodeScan(s)
+		tables = &[2][2]huffmanSpec{}
+		for class := 0; class < 2; class++ {
+			for id := 0; id < 2; id++ {
+				if used[class][id] {
+					freq := stats.freqs[class][id]
+					if freq == ([256]int{}) {
+						freq[0] = 1 // An empty table is not valid
+					}
+					tables[class][id] = optimalHuffmanSpec(freq)
+				}
+			}
+		}
+	}
+	
+	for class := 0; class < 2; class++ {
+		for id := 0; id < 2; id++ {
+			if used[class][id] {
+				writeDHT(bw, class, id, &tables[class][id])
+			}
+		}
+	}
+	writeSOS(bw, c, s)
+	
+	e := newEntropyEncoder(c, &bitWriter{w: bw})
+	for class := 0; class < 2; class++ {
+		for id := 0; id < 2; id++ {
+			if used[class][id] {
+				e.codes[class][id] = tables[class][id].codes()
+			}
+		}
+	}
+	e.encodeScan(s)
+	e.bits.flush()
+	return nil
+}
+
+// entropyEncoder Huffman codes blocks, or only counts symbols when bits is nil
+type entropyEncoder struct {
+	c          *Coefficients
+	bits       *bitWriter
+	codes      [2][2]*huffmanCode
+	freqs      [2][2][256]int
+	lastDC     []int
+	eobRun     int
+	eobTable   int
+	correction []byte // Refinement bits that belong to the pending EOB run
+	blockBits  []byte // Refinement bits of the current block not yet written
+}
+
+// newEntropyEncoder creates an encoder for one scan
+func newEntropyEncoder(c *Coefficients, bits *bitWriter) *entropyEncoder {
+	return &entropyEncoder{c: c, bits: bits, lastDC: make([]int, len(c.Components))}
+}
+
+// symbol writes a Huffman coded symbol, or counts it
+func (e *entropyEncoder) symbol(class, id int, sym byte) {
+	if e.bits == nil {
+		e.freqs[class][id][sym]++
+		return
+	}
+	code := e.codes[class][id]
+	e.bits.writeBits(uint32(code.code[sym]), uint(code.size[sym]))
+}
+
+// raw writes bits that are not Huffman coded
+func (e *entropyEncoder) raw(bits uint32, n uint) {
+	if e.bits != nil && n > 0 {
+		e.bits.writeBits(bits, n)
+	}
+}
+
+// value writes the magnitude category of v as a symbol followed by its extra bits
+func (e *entropyEncoder) value(class, id int, prefix byte, v int) {
+	n, extra := category(v)
+	e.symbol(class, id, prefix|byte(n))
+	e.raw(extra, n)
+}
+
+// category returns the bit length of |v| and the bits that code v
+func category(v int) (uint, uint32) {
+	a := v
+	if a < 0 {
+		a = -a
+		v--
+	}
+	n := uint(bits.Len(uint(a)))
+	return n, uint32(v) & (1<<n - 1)
+}
+
+// encodeScan codes all blocks of a scan
+func (e *entropyEncoder) encodeScan(s scan) {
+	e.c.forEachBlock(s.comps, func(_, ci int, blk *Block) {
+		id := tableID(ci)
+		switch {
+		case s.ss == 0 && s.se == 63:
+			e.encodeSequential(ci, id, blk)
+		case s.ss == 0 && s.ah == 0:
+			dc := int(blk[0]) >> s.al
+			e.value(classDC, id, 0, dc-e.lastDC[ci])
+			e.lastDC[ci] = dc
+		case s.ss == 0:
+			e.raw(uint32(int(blk[0])>>s.al)&1, 1)
+		case s.ah == 0:
+			e.encodeACFirst(id, blk, s)
+		default:
+			e.encodeACRefine(id, blk, s)
+		}
+	})
+	e.flushEOBRun()
+}
+
+// encodeSequential codes a block of a baseline scan
+func (e *entropyEncoder) encodeSequential(ci, id int, blk *Block) {
+	dc := int(blk[0])
+	e.value(classDC, id, 0, dc-e.lastDC[ci])
+	e.lastDC[ci] = dc
+	
+	run := 0
+	for k := 1; k < 64; k++ {
+		v := int(blk[unzig[k]])
+		if v == 0 {
+			run++
+			continue
+		}
+		for run > 15 {
+			e.symbol(classAC, id, 0xF0)
+			run -= 16
+		}
+		e.value(classAC, id, byte(run<<4), v)
+		run = 0
+	}
+	if run > 0 {
+		e.symbol(classAC, id, 0x00)
+	}
+}
+
+// encodeACFirst codes the first pass over a spectral band of a progressive scan
+func (e *entropyEncoder) encodeACFirst(id int, blk *Block, s scan) {
+	e.eobTable = id
+	run := 0
+	for k := s.ss; k <= s.se; k++ {
+		v := int(blk[unzig[k]])
+		a := v
+		if a < 0 {
+			a = -a
+		}
+		a >>= s.al
+		if a == 0 {
+			run++
+			continue
+		}
+		if v < 0 {
+			a = -a
+		}
+		
+		e.flushEOBRun()
+		for run > 15 {
+			e.symbol(classAC, id, 0xF0)
+			run -= 16
+		}
+		e.value(classAC, id, byte(run<<4), a)
+		run = 0
+	}
+	
+	if run > 0 {
+		e.eobRun++
+		if e.eobRun == maxEOBRun {
+			e.flushEOBRun()
+		}
+	}
+}
+
+// encodeACRefine codes the next bit of a spectral band that was already partly sent
+func (e *entropyEncoder) encodeACRefine(id int, blk *Block, s scan) {
+	e.eobTable = id
+	
+	// Position of the last coefficient that becomes nonzero in this pass
+	var abs [64]int
+	eob := 0
+	for k := s.ss; k <= s.se; k++ {
+		v := int(blk[unzig[k]])
+		if v < 0 {
+			v = -v
+		}
+		abs[k] = v >> s.al
+		if abs[k] == 1 {
+			eob = k
+		}
+	}
+	
+	run := 0
+	e.blockBits = e.blockBits[:0]
+	for k := s.ss; k <= s.se; k++ {
+		a := abs[k]
+		if a == 0 {
+			run++
+			continue
+		}
+		
+		for run > 15 && k <= eob {
+			e.flushEOBRun()
+			e.symbol(classAC, id, 0xF0)
+			run -= 16
+			e.writeBlockBits()
+		}
+		
+		if a > 1 {
+			// Previously nonzero, only the correction bit is sent
+			e.blockBits = append(e.blockBits, byte(a&1))
+			continue
+		}
+		
+		// Newly nonzero coefficient
+		e.flushEOBRun()
+		e.symbol(classAC, id, byte(run<<4|1))
+		sign := uint32(1)
+		if blk[unzig[k]] < 0 {
+			sign = 0
+		}
+		e.raw(sign, 1)
+		e.writeBlockBits()
+		run = 0
+	}
+	
+	if run > 0 || len(e.blockBits) > 0 {
+		e.eobRun++
+		e.correction = append(e.correction, e.blockBits...)
+		e.blockBits = e.blockBits[:0]
+		if e.eobRun == maxEOBRun || len(e.correction) > 1000-63 {
+			e.flushEOBRun()
+		}
+	}
+}
+
+// writeBlockBits writes the buffered correction bits of the current block
+func (e *entropyEncoder) writeBlockBits() {
+	for _, b := range e.blockBits {
+		e.raw(uint32(b), 1)
+	}
+	e.blockBits = e.blockBits[:0]
+}
+
+// flushEOBRun writes the pending end-of-band run and its correction bits
+func (e *entropyEncoder) flushEOBRun() {
+	if e.eobRun > 0 {
+		n := uint(bits.Len(uint(e.eobRun))) - 1
+		e.symbol(classAC, e.eobTable, byte(n<<4))
+		e.raw(uint32(e.eobRun), n)
+		e.eobRun = 0
+	}
+	for _, b := range e.correction {
+		e.raw(uint32(b), 1)
+	}
+	e.correction = e.correction[:0]
+}
+
+// writeMarker writes a marker without payload
+func writeMarker(bw *bufio.Writer, marker byte) {
+	_, _ = bw.Write([]byte{0xFF, marker})
+}
+
+// writeSegment writes a marker segment with its length field
+func writeSegment(bw *bufio.Writer, marker byte, data []byte) {
+	length := len(data) + 2
+	_, _ = bw.Write([]byte{0xFF, marker, byte(length >> 8), byte(length)})
+	_, _ = bw.Write(data)
+}
+
+// writeDQT writes all quantization tables in zigzag order.
+// It reports whether a table needs 16-bit precision, which baseline does not allow.
+func writeDQT(bw *bufio.Writer, c *Coefficients) bool {
+	extended := false
+	var data []byte
+	for i, table := range c.Quant {
+		if table == nil {
+			continue
+		}
+		precision := 0
+		for _, q := range table {
+			if q > 255 {
+				precision = 1
+				extended = true
+			}
+		}
+		data = append(data, byte(precision<<4|i))
+		for k := 0; k < 64; k++ {
+			q := table[unzig[k]]
+			if precision == 1 {
+				data = append(data, byte(q>>8))
+			}
+			data = append(data, byte(q))
+		}
+	}
+	writeSegment(bw, markerDQT, data)
+	return extended
+}
+
+// writeSOF writes the frame header
+func writeSOF(bw *bufio.Writer, c *Coefficients, marker byte) {
+	data := []byte{8, byte(c.Height >> 8), byte(c.Height), byte(c.Width >> 8), byte(c.Width), byte(len(c.Components))}
+	for _, comp := range c.Components {
+		data = append(data, comp.ID, byte(comp.H<<4|comp.V), byte(comp.Tq))
+	}
+	writeSegment(bw, marker, data)
+}
+
+// writeDHT writes one Huffman table
+func writeDHT(bw *bufio.Writer, class, id int, spec *huffmanSpec) {
+	data := []byte{byte(class<<4 | id)}
+	data = append(data, spec.counts[:]...)
+	data = append(data, spec.values...)
+	writeSegment(bw, markerDHT, data)
+}
+
+// writeSOS writes a scan header
+func writeSOS(bw *bufio.Writer, c *Coefficients, s scan) {
+	data := []byte{byte(len(s.comps))}
+	for _, ci := range s.comps {
+		id := byte(tableID(ci))
+		data = append(data, c.Components[ci].ID, id<<4|id)
+	}
+	data = append(data, byte(s.ss), byte(s.se), byte(s.ah<<4|s.al))
+	writeSegment(bw, markerSOS, data)
+}
\ No newline at end of file
@@ -0,0 +1,111 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"image"
+	"image/color"
+)
+
+// Image is an image XObject
+type Image struct {
+	Width, Height int
+	ColorSpace    string    // DeviceGray, DeviceRGB or DeviceCMYK
+	Filter        string    // DCTDecode or FlateDecode
+	Decode        []float64 // Optional sample mapping, e.g. for inverted CMYK
+	Data          []byte
+	SMask         *Image // Optional alpha channel
+}
+
+// JPEGImage wraps JPEG data for embedding without re-encoding. Components is the
+// number of color channels in the frame header. Adobe CMYK JPEGs store inverted
+// values, which adobeInverted undoes when drawing.
+func JPEGImage(data []byte, width, height, components int, adobeInverted bool) *Image {
+	img := &Image{Width: width, Height: height, Filter: "DCTDecode", Data: data}
+	switch components {
+	case 1:
+		img.ColorSpace = "DeviceGray"
+	case 4:
+		img.ColorSpace = "DeviceCMYK"
+		if adobeInverted {
+			img.Decode = []float64{1, 0, 1, 0, 1, 0, 1, 0}
+		}
+	default:
+		img.ColorSpace = "DeviceRGB"
+	}
+	return img
+}
+
+// FlateImage stores the pixels of img as a Flate compressed RGB or grayscale
+// image. Transparency is kept in a soft mask.
+func FlateImage(img image.Image) (*Image, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	gray := isGray(img)
+	
+	channels := 3
+	if gray {
+		channels = 1
+	}
+	samples := make([]byte, 0, width*height*channels)
+	alpha := make([]byte, 0, width*height)
+	opaque := true
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if gray {
+				samples = append(samples, c.R)
+			} else {
+				samples = append(samples, c.R, c.G, c.B)
+			}
+			alpha = append(alpha, c.A)
+			opaque = opaque && c.A == 0xFF
+		}
+	}
+	
+	result, err := flateImage(width, height, "DeviceRGB", samples)
+	if err != nil {
+		return nil, err
+	}
+	if gray {
+		result.ColorSpace = "DeviceGray"
+	}
+	if !opaque {
+		if result.SMask, err = flateImage(width, height, "DeviceGray", alpha); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// flateImage compresses 8-bit samples
+func flateImage(width, height int, colorSpace string, samples []byte) (*Image, error) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(samples); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return &Image{Width: width, Height: height, ColorSpace: colorSpace, Filter: "FlateDecode", Data: buf.Bytes()}, nil
+}
+
+// isGray reports whether img has no color, either by type or because every pixel
+// is neutral
+func isGray(img image.Image) bool {
+	switch img.(type) {
+	case *image.Gray, *image.Gray16:
+		return true
+	}
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.R != c.G || c.G != c.B {
+				return false
+			}
+		}
+	}
+	return true
+}
\ No newline at end of file
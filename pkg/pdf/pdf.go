@@ -0,0 +1,217 @@
+// Package pdf writes PDF documents with one image per page. Objects are streamed to
+// the output as pages are added, so only the current image is held in memory. JPEG
+// data is embedded verbatim with DCTDecode and other images as Flate streams.
+package pdf
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// PointsPerInch converts inches to PDF user space units
+const PointsPerInch = 72
+
+// Object numbers of the catalog and page tree, which are written last
+const (
+	catalogObject = 1
+	pagesObject   = 2
+)
+
+// ErrClosed is returned when adding a page to a closed writer
+var ErrClosed = errors.New("pdf: writer is closed")
+
+// Rect is a rectangle in points with the origin at the bottom left of the page
+type Rect struct {
+	X, Y, Width, Height float64
+}
+
+// Page is a page showing a single image
+type Page struct {
+	Width, Height float64 // Page size in points
+	Image         *Image
+	Rect          Rect // Where the image is drawn, after orientation
+	Orientation   int  // EXIF orientation (1-8) applied when drawing; 0 = 1
+}
+
+// Writer writes a PDF document
+type Writer struct {
+	w       *bufio.Writer
+	offset  int64
+	offsets []int64 // Byte offset of each object, indexed by object number - 1
+	pages   []int   // Object numbers of the pages
+	closed  bool
+	err     error
+}
+
+// NewWriter starts a PDF document
+func NewWriter(w io.Writer) *Writer {
+	pw := &Writer{w: bufio.NewWriter(w), offsets: make([]int64, pagesObject)}
+	// The binary comment marks the file as containing binary data
+	pw.printf("%%PDF-1.4\n%%\xE2\xE3\xCF\xD3\n")
+	return pw
+}
+
+// printf writes formatted output, keeping the first error
+func (pw *Writer) printf(format string, args ...interface{}) {
+	if pw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(pw.w, format, args...)
+	pw.offset += int64(n)
+	pw.err = err
+}
+
+// write writes raw bytes, keeping the first error
+func (pw *Writer) write(data []byte) {
+	if pw.err != nil {
+		return
+	}
+	n, err := pw.w.Write(data)
+	pw.offset += int64(n)
+	pw.err = err
+}
+
+// reserve allocates an object number
+func (pw *Writer) reserve() int {
+	pw.offsets = append(pw.offsets, 0)
+	return len(pw.offsets)
+}
+
+// begin starts object n at the current offset
+func (pw *Writer) begin(n int) {
+	pw.offsets[n-1] = pw.offset
+	pw.printf("%d 0 obj\n", n)
+}
+
+// object writes a dictionary object
+func (pw *Writer) object(n int, dict string) {
+	pw.begin(n)
+	pw.printf("%s\nendobj\n", dict)
+}
+
+// stream writes a stream object; dict holds the entries besides /Length
+func (pw *Writer) stream(n int, dict string, data []byte) {
+	pw.begin(n)
+	if dict != "" {
+		dict += " "
+	}
+	pw.printf("<< %s/Length %d >>\nstream\n", dict, len(data))
+	pw.write(data)
+	pw.printf("\nendstream\nendobj\n")
+}
+
+// AddPage writes a page and its image
+func (pw *Writer) AddPage(page Page) error {
+	if pw.closed {
+		return ErrClosed
+	}
+	if page.Image == nil || page.Width <= 0 || page.Height <= 0 {
+		return errors.New("pdf: page needs an image and a positive size")
+	}
+	
+	image := pw.image(page.Image)
+	a, b, c, d, e, f := orientationMatrix(page.Rect, page.Orientation)
+	content := fmt.Sprintf("q %s %s %s %s %s %s cm /Im0 Do Q", num(a), num(b), num(c), num(d), num(e), num(f))
+	contentObject := pw.reserve()
+	pw.stream(contentObject, "", []byte(content))
+	
+	pageObject := pw.reserve()
+	pw.object(pageObject, fmt.Sprintf(
+		"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /XObject << /Im0 %d 0 R >> >> /Contents %d 0 R >>",
+		pagesObject, num(page.Width), num(page.Height), image, contentObject))
+	pw.pages = append(pw.pages, pageObject)
+	return pw.err
+}
+
+// image writes an image XObject and its soft mask, returning the object number
+func (pw *Writer) image(img *Image) int {
+	var smask string
+	if img.SMask != nil {
+		smask = fmt.Sprintf(" /SMask %d 0 R", pw.image(img.SMask))
+	}
+	var decode string
+	if len(img.Decode) > 0 {
+		values := make([]string, len(img.Decode))
+		for i, v := range img.Decode {
+			values[i] = num(v)
+		}
+		decode = " /Decode [" + strings.Join(values, " ") + "]"
+	}
+	
+	n := pw.reserve()
+	pw.stream(n, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /%s%s%s",
+		img.Width, img.Height, img.ColorSpace, img.Filter, decode, smask), img.Data)
+	return n
+}
+
+// Close writes the page tree, cross-reference table and trailer. It does not close
+// the underlying writer.
+func (pw *Writer) Close() error {
+	if pw.closed {
+		return pw.err
+	}
+	pw.closed = true
+	if len(pw.pages) == 0 && pw.err == nil {
+		return errors.New("pdf: document has no pages")
+	}
+	
+	kids := make([]string, len(pw.pages))
+	for i, page := range pw.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+	}
+	pw.object(pagesObject, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pw.pages)))
+	pw.object(catalogObject, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObject))
+	info := pw.reserve()
+	pw.object(info, "<< /Producer (imagekit) >>")
+	
+	xref := pw.offset
+	pw.printf("xref\n0 %d\n0000000000 65535 f \n", len(pw.offsets)+1)
+	for _, offset := range pw.offsets {
+		pw.printf("%010d 00000 n \n", offset)
+	}
+	pw.printf("trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(pw.offsets)+1, catalogObject, info, xref)
+	
+	if pw.err != nil {
+		return pw.err
+	}
+	return pw.w.Flush()
+}
+
+// orientationMatrix returns the transformation that draws the unit image square
+// into r, applying an EXIF orientation
+func orientationMatrix(r Rect, orientation int) (a, b, c, d, e, f float64) {
+	x, y, w, h := r.X, r.Y, r.Width, r.Height
+	switch orientation {
+	case 2: // Mirrored horizontally
+		return -w, 0, 0, h, x + w, y
+	case 3: // Rotated 180
+		return -w, 0, 0, -h, x + w, y + h
+	case 4: // Mirrored vertically
+		return w, 0, 0, -h, x, y + h
+	case 5: // Transposed
+		return 0, -h, -w, 0, x + w, y + h
+	case 6: // Rotated 90 clockwise to display
+		return 0, -h, w, 0, x, y + h
+	case 7: // Transversed
+		return 0, h, w, 0, x, y
+	case 8: // Rotated 270 clockwise to display
+		return 0, h, -w, 0, x + w, y
+	default:
+		return w, 0, 0, h, x, y
+	}
+}
+
+// num formats a number with at most four decimal places
+func num(v float64) string {
+	v = math.Round(v*10000) / 10000
+	if v == 0 {
+		v = 0 // Avoid writing -0
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
\ No newline at end of file
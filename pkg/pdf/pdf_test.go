@@ -0,0 +1,118 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestWriterStructure(t *testing.T) {
+	jpegData := []byte("\xFF\xD8 not really a JPEG \xFF\xD9")
+	buf := &bytes.Buffer{}
+	w := NewWriter(buf)
+	pages := []Page{
+		{Width: 200, Height: 100, Image: JPEGImage(jpegData, 20, 10, 3, false), Rect: Rect{Width: 200, Height: 100}},
+		{Width: 100, Height: 200, Image: JPEGImage(jpegData, 20, 10, 4, true), Rect: Rect{Width: 100, Height: 200}, Orientation: 6},
+	}
+	for _, page := range pages {
+		if err := w.AddPage(page); err != nil {
+			t.Fatalf("AddPage() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := w.AddPage(pages[0]); err != ErrClosed {
+		t.Errorf("AddPage() after Close() error = %v, want ErrClosed", err)
+	}
+	
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+	if n := bytes.Count(data, jpegData); n != 2 {
+		t.Errorf("JPEG data embedded %d times, want 2", n)
+	}
+	for _, want := range []string{
+		"/Type /Pages /Kids [5 0 R 8 0 R] /Count 2",
+		"/MediaBox [0 0 100 200]",
+		"/ColorSpace /DeviceCMYK /BitsPerComponent 8 /Filter /DCTDecode /Decode [1 0 1 0 1 0 1 0]",
+		"q 0 -200 100 0 0 200 cm /Im0 Do Q",
+	} {
+		if !bytes.Contains(data, []byte(want)) {
+			t.Errorf("output does not contain %q", want)
+		}
+	}
+	
+	// Every cross-reference entry must point at its object
+	xref := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(data)
+	start, _ := strconv.Atoi(string(xref[1]))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[start:], -1)
+	if len(entries) != 9 {
+		t.Fatalf("xref has %d objects, want 9", len(entries))
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(data[offset:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q", i+1, data[offset:offset+10])
+		}
+	}
+}
+
+func TestCloseWithoutPages(t *testing.T) {
+	if err := NewWriter(&bytes.Buffer{}).Close(); err == nil {
+		t.Error("Close() should fail without pages")
+	}
+}
+
+func TestFlateImage(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 4, 4))
+	img, err := FlateImage(gray)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.ColorSpace != "DeviceGray" || img.Filter != "FlateDecode" || img.SMask != nil {
+		t.Errorf("FlateImage(gray) = %s %s, mask %v", img.ColorSpace, img.Filter, img.SMask != nil)
+	}
+	
+	rgba := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	rgba.Set(1, 1, color.NRGBA{R: 255, A: 128})
+	img, err = FlateImage(rgba)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.ColorSpace != "DeviceRGB" || img.SMask == nil || img.SMask.ColorSpace != "DeviceGray" {
+		t.Errorf("FlateImage(rgba) = %s, mask %v", img.ColorSpace, img.SMask != nil)
+	}
+}
+
+func TestOrientationMatrix(t *testing.T) {
+	r := Rect{X: 10, Y: 20, Width: 100, Height: 50}
+	// The top left pixel of the stored image is at (0, 1) in image space
+	tests := []struct {
+		orientation int
+		wantX       float64
+		wantY       float64
+	}{
+		{1, 10, 70},
+		{2, 110, 70},
+		{3, 110, 20},
+		{4, 10, 20},
+		{5, 10, 70},
+		{6, 110, 70},
+		{7, 110, 20},
+		{8, 10, 20},
+	}
+	
+	for _, tt := range tests {
+		_, _, c, d, e, f := orientationMatrix(r, tt.orientation)
+		x, y := c+e, d+f
+		if x != tt.wantX || y != tt.wantY {
+			t.Errorf("orientation %d puts the top left pixel at (%v, %v), want (%v, %v)", tt.orientation, x, y, tt.wantX, tt.wantY)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,172 @@
+package placeholder
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+)
+
+// base83 holds the digits of BlurHash's base 83 encoding
+const base83 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// encode83 writes value as length base 83 digits
+func encode83(b *strings.Builder, value, length int) {
+	digits := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		digits[i] = base83[value%83]
+		value /= 83
+	}
+	b.Write(digits)
+}
+
+// decode83 reads base 83 digits
+func decode83(s string) (int, error) {
+	value := 0
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(base83, s[i])
+		if digit < 0 {
+			return 0, fmt.Errorf("%w: unexpected character %q", ErrInvalidHash, s[i])
+		}
+		value = value*83 + digit
+	}
+	return value, nil
+}
+
+// signPow raises the magnitude of v to exp, keeping its sign
+func signPow(v, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), exp), v)
+}
+
+// EncodeBlurHash encodes an image as a BlurHash with 1-9 cosine components along
+// each axis. Transparent pixels are encoded by their color alone.
+func EncodeBlurHash(img image.Image, xComponents, yComponents int) (string, error) {
+	if xComponents < 1 || xComponents > 9 || yComponents < 1 || yComponents > 9 {
+		return "", fmt.Errorf("blurhash components must be 1-9: %dx%d", xComponents, yComponents)
+	}
+	colors, w, h := pixels(img)
+	if w == 0 || h == 0 {
+		return "", fmt.Errorf("blurhash of an empty image")
+	}
+	linear := make([][3]float64, len(colors))
+	for i, c := range colors {
+		linear[i] = [3]float64{srgbToLinear(c.R), srgbToLinear(c.G), srgbToLinear(c.B)}
+	}
+	
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			normalization := 2.0
+			if i == 0 && j == 0 {
+				normalization = 1
+			}
+			var f [3]float64
+			for y := 0; y < h; y++ {
+				fy := math.Cos(math.Pi * float64(j) * float64(y) / float64(h))
+				for x := 0; x < w; x++ {
+					basis := fy * math.Cos(math.Pi*float64(i)*float64(x)/float64(w))
+					p := linear[y*w+x]
+					f[0] += basis * p[0]
+					f[1] += basis * p[1]
+					f[2] += basis * p[2]
+				}
+			}
+			scale := normalization / float64(w*h)
+			factors = append(factors, [3]float64{f[0] * scale, f[1] * scale, f[2] * scale})
+		}
+	}
+	
+	var b strings.Builder
+	encode83(&b, (xComponents-1)+(yComponents-1)*9, 1)
+	maximum := 1.0
+	if len(factors) > 1 {
+		actual := 0.0
+		for _, f := range factors[1:] {
+			actual = max(actual, math.Abs(f[0]), math.Abs(f[1]), math.Abs(f[2]))
+		}
+		quantised := int(max(0, min(82, math.Floor(actual*166-0.5))))
+		maximum = float64(quantised+1) / 166
+		encode83(&b, quantised, 1)
+	} else {
+		encode83(&b, 0, 1)
+	}
+	
+	dc := factors[0]
+	encode83(&b, linearToSRGB(dc[0])<<16|linearToSRGB(dc[1])<<8|linearToSRGB(dc[2]), 4)
+	for _, f := range factors[1:] {
+		quant := func(v float64) int {
+			return int(max(0, min(18, math.Floor(signPow(v/maximum, 0.5)*9+9.5))))
+		}
+		encode83(&b, quant(f[0])*19*19+quant(f[1])*19+quant(f[2]), 2)
+	}
+	return b.String(), nil
+}
+
+// DecodeBlurHash renders a BlurHash at the given size. Punch above 1 strengthens the
+// contrast of the colors.
+func DecodeBlurHash(hash string, width, height int, punch float64) (*image.NRGBA, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid blurhash size: %dx%d", width, height)
+	}
+	if len(hash) < 6 {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidHash)
+	}
+	sizeFlag, err := decode83(hash[:1])
+	if err != nil {
+		return nil, err
+	}
+	xComponents, yComponents := sizeFlag%9+1, sizeFlag/9+1
+	if len(hash) != 4+2*xComponents*yComponents {
+		return nil, fmt.Errorf("%w: length %d does not match %dx%d components", ErrInvalidHash, len(hash), xComponents, yComponents)
+	}
+	quantisedMaximum, err := decode83(hash[1:2])
+	if err != nil {
+		return nil, err
+	}
+	maximum := float64(quantisedMaximum+1) / 166 * max(punch, 1)
+	
+	factors := make([][3]float64, xComponents*yComponents)
+	for i := range factors {
+		if i == 0 {
+			v, err := decode83(hash[2:6])
+			if err != nil {
+				return nil, err
+			}
+			factors[0] = [3]float64{srgbToLinear(uint8(v >> 16)), srgbToLinear(uint8(v >> 8)), srgbToLinear(uint8(v))}
+			continue
+		}
+		v, err := decode83(hash[4+i*2 : 6+i*2])
+		if err != nil {
+			return nil, err
+		}
+		unquant := func(q int) float64 {
+			return signPow(float64(q-9)/9, 2) * maximum
+		}
+		factors[i] = [3]float64{unquant(v / (19 * 19)), unquant(v / 19 % 19), unquant(v % 19)}
+	}
+	
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var c [3]float64
+			for j := 0; j < yComponents; j++ {
+				fy := math.Cos(math.Pi * float64(y) * float64(j) / float64(height))
+				for i := 0; i < xComponents; i++ {
+					basis := math.Cos(math.Pi*float64(x)*float64(i)/float64(width)) * fy
+					f := factors[i+j*xComponents]
+					c[0] += f[0] * basis
+					c[1] += f[1] * basis
+					c[2] += f[2] * basis
+				}
+			}
+			img.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(linearToSRGB(c[0])),
+				G: uint8(linearToSRGB(c[1])),
+				B: uint8(linearToSRGB(c[2])),
+				A: 0xFF,
+			})
+		}
+	}
+	return img, nil
+}
\ No newline at end of file
@@ -0,0 +1,49 @@
+// Package placeholder encodes and decodes compact image placeholders, BlurHash and
+// ThumbHash, shown while the real image of a page is still loading. Both encoders read
+// every pixel, so images should be shrunk to about 100 pixels first.
+package placeholder
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+)
+
+var (
+	// ErrInvalidHash is returned when a hash cannot be decoded
+	ErrInvalidHash = errors.New("placeholder: invalid hash")
+	// ErrTooLarge is returned when an image is too large to encode as a ThumbHash
+	ErrTooLarge = errors.New("placeholder: image larger than 100x100")
+)
+
+// srgbToLinear converts an 8-bit sRGB value to linear light
+func srgbToLinear(v uint8) float64 {
+	f := float64(v) / 255
+	if f <= 0.04045 {
+		return f / 12.92
+	}
+	return math.Pow((f+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB converts linear light to an 8-bit sRGB value
+func linearToSRGB(v float64) int {
+	v = max(0, min(1, v))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+// pixels returns the non-premultiplied colors of an image row by row
+func pixels(img image.Image) ([]color.NRGBA, int, int) {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	colors := make([]color.NRGBA, 0, w*h)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			colors = append(colors, color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA))
+		}
+	}
+	return colors, w, h
+}
\ No newline at end of file
@@ -0,0 +1,205 @@
+package placeholder
+
+import (
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// gradient returns an image fading from red on the left to blue on the right
+func gradient(w, h int, alpha uint8) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(255 * x / (w - 1))
+			img.SetNRGBA(x, y, color.NRGBA{R: 255 - v, B: v, A: alpha})
+		}
+	}
+	return img
+}
+
+// near reports whether two colors differ by at most tolerance in every channel
+func near(a, b color.NRGBA, tolerance int) bool {
+	diff := func(x, y uint8) bool {
+		d := int(x) - int(y)
+		return d <= tolerance && d >= -tolerance
+	}
+	return diff(a.R, b.R) && diff(a.G, b.G) && diff(a.B, b.B) && diff(a.A, b.A)
+}
+
+func TestBlurHash(t *testing.T) {
+	hash, err := EncodeBlurHash(gradient(64, 32, 0xFF), 4, 3)
+	if err != nil {
+		t.Fatalf("EncodeBlurHash() error = %v", err)
+	}
+	if len(hash) != 4+2*4*3 {
+		t.Errorf("EncodeBlurHash() = %q, want %d characters", hash, 4+2*4*3)
+	}
+	
+	img, err := DecodeBlurHash(hash, 32, 16, 1)
+	if err != nil {
+		t.Fatalf("DecodeBlurHash() error = %v", err)
+	}
+	if left := img.NRGBAAt(1, 8); !near(left, color.NRGBA{R: 0xFF, A: 0xFF}, 40) {
+		t.Errorf("left pixel = %v, want red", left)
+	}
+	if right := img.NRGBAAt(30, 8); !near(right, color.NRGBA{B: 0xFF, A: 0xFF}, 40) {
+		t.Errorf("right pixel = %v, want blue", right)
+	}
+	
+	// A solid color keeps its color exactly
+	want := color.NRGBA{R: 0x20, G: 0x80, B: 0xC0, A: 0xFF}
+	solid := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for i := 0; i < len(solid.Pix); i += 4 {
+		solid.Pix[i], solid.Pix[i+1], solid.Pix[i+2], solid.Pix[i+3] = want.R, want.G, want.B, want.A
+	}
+	if hash, err = EncodeBlurHash(solid, 3, 3); err != nil {
+		t.Fatal(err)
+	}
+	if img, err = DecodeBlurHash(hash, 4, 4, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := img.NRGBAAt(2, 2); got != want {
+		t.Errorf("solid color = %v, want %v", got, want)
+	}
+	
+	if _, err := EncodeBlurHash(&image.NRGBA{}, 1, 1); err == nil {
+		t.Error("EncodeBlurHash() accepted an empty image")
+	}
+	if _, err := EncodeBlurHash(solid, 10, 1); err == nil {
+		t.Error("EncodeBlurHash() accepted 10 components")
+	}
+	for _, invalid := range []string{"", "LEHV6", "LEHV6nWB2yk8", "L\"HV6nWB2yk8pyo0adR*.7kCMdnj"} {
+		if _, err := DecodeBlurHash(invalid, 8, 8, 1); !errors.Is(err, ErrInvalidHash) {
+			t.Errorf("DecodeBlurHash(%q) error = %v, want ErrInvalidHash", invalid, err)
+		}
+	}
+}
+
+func TestBlurHashReference(t *testing.T) {
+	// The example hash of the reference implementation: 4x3 components and a DC color
+	// of #979695, which is the mean of the decoded image in linear light
+	img, err := DecodeBlurHash("LEHV6nWB2yk8pyo0adR*.7kCMdnj", 128, 128, 1)
+	if err != nil {
+		t.Fatalf("DecodeBlurHash() error = %v", err)
+	}
+	var sum [3]float64
+	for i := 0; i < len(img.Pix); i += 4 {
+		for c := range sum {
+			sum[c] += srgbToLinear(img.Pix[i+c])
+		}
+	}
+	n := float64(len(img.Pix) / 4)
+	mean := color.NRGBA{uint8(linearToSRGB(sum[0] / n)), uint8(linearToSRGB(sum[1] / n)), uint8(linearToSRGB(sum[2] / n)), 0xFF}
+	if !near(mean, color.NRGBA{0x97, 0x96, 0x95, 0xFF}, 1) {
+		t.Errorf("mean color = %v, want #979695", mean)
+	}
+	
+	// Every reference encoder produces this hash for a black image
+	black := image.NewNRGBA(image.Rect(0, 0, 16, 16))
+	for i := 3; i < len(black.Pix); i += 4 {
+		black.Pix[i] = 0xFF
+	}
+	if hash, err := EncodeBlurHash(black, 4, 3); err != nil || hash != "L00000fQfQfQfQfQfQfQfQfQfQfQ" {
+		t.Errorf("EncodeBlurHash(black) = %q, %v, want L00000fQfQfQfQfQfQfQfQfQfQfQ", hash, err)
+	}
+	
+	// The gradient fixture as hashed by github.com/buckket/go-blurhash
+	if hash, err := EncodeBlurHash(gradient(64, 32, 0xFF), 4, 3); err != nil || hash != "L,HZ1k|T$Awuo3n~jujtfQfQfQfQ" {
+		t.Errorf("EncodeBlurHash(gradient) = %q, %v, want L,HZ1k|T$Awuo3n~jujtfQfQfQfQ", hash, err)
+	}
+}
+
+func TestThumbHash(t *testing.T) {
+	hash, err := EncodeThumbHash(gradient(100, 50, 0xFF))
+	if err != nil {
+		t.Fatalf("EncodeThumbHash() error = %v", err)
+	}
+	if ratio, _ := ThumbHashAspectRatio(hash); ratio < 1.5 || ratio > 2.5 {
+		t.Errorf("ThumbHashAspectRatio() = %v, want about 2", ratio)
+	}
+	
+	img, err := DecodeThumbHash(hash)
+	if err != nil {
+		t.Fatalf("DecodeThumbHash() error = %v", err)
+	}
+	size := img.Bounds().Size()
+	if size.X != 32 || size.Y < 12 || size.Y > 20 {
+		t.Errorf("DecodeThumbHash() size = %v, want 32 wide and about 16 high", size)
+	}
+	if left := img.NRGBAAt(0, size.Y/2); !near(left, color.NRGBA{R: 0xFF, A: 0xFF}, 60) {
+		t.Errorf("left pixel = %v, want red", left)
+	}
+	if right := img.NRGBAAt(size.X-1, size.Y/2); !near(right, color.NRGBA{B: 0xFF, A: 0xFF}, 60) {
+		t.Errorf("right pixel = %v, want blue", right)
+	}
+	
+	// Transparency is kept
+	if hash, err = EncodeThumbHash(gradient(40, 80, 0x80)); err != nil {
+		t.Fatal(err)
+	}
+	if hash[2]&0x80 == 0 {
+		t.Error("EncodeThumbHash() did not set the alpha flag")
+	}
+	if img, err = DecodeThumbHash(hash); err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size.Y != 32 || size.X > 20 {
+		t.Errorf("portrait size = %v, want 32 high", size)
+	}
+	if a := img.NRGBAAt(8, 16).A; a < 0x60 || a > 0xA0 {
+		t.Errorf("alpha = %d, want about 128", a)
+	}
+	
+	if _, err := EncodeThumbHash(gradient(101, 10, 0xFF)); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("EncodeThumbHash(101x10) error = %v, want ErrTooLarge", err)
+	}
+	if _, err := DecodeThumbHash(hash[:7]); !errors.Is(err, ErrInvalidHash) {
+		t.Errorf("DecodeThumbHash(truncated) error = %v, want ErrInvalidHash", err)
+	}
+}
+
+func TestThumbHashReference(t *testing.T) {
+	// An opaque portrait hash: 5x7 luminance components decode to 23x32 pixels whose mean
+	// is the average color in the header
+	hash, err := base64.RawStdEncoding.DecodeString("1QcSHQRnh493V4dIh4eXh1h4kJUI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ratio, err := ThumbHashAspectRatio(hash); err != nil || ratio != 5.0/7 {
+		t.Errorf("ThumbHashAspectRatio() = %v, %v, want 5/7", ratio, err)
+	}
+	img, err := DecodeThumbHash(hash)
+	if err != nil {
+		t.Fatalf("DecodeThumbHash() error = %v", err)
+	}
+	if size := img.Bounds().Size(); size != image.Pt(23, 32) {
+		t.Errorf("DecodeThumbHash() size = %v, want 23x32", size)
+	}
+	var sum [4]int
+	for i := 0; i < len(img.Pix); i++ {
+		sum[i%4] += int(img.Pix[i])
+	}
+	n := len(img.Pix) / 4
+	mean := color.NRGBA{uint8(sum[0] / n), uint8(sum[1] / n), uint8(sum[2] / n), uint8(sum[3] / n)}
+	if !near(mean, color.NRGBA{86, 82, 88, 0xFF}, 2) {
+		t.Errorf("mean color = %v, want about {86 82 88 255}", mean)
+	}
+	
+	// The gradient fixtures as hashed by go.n16f.net/thumbhash, given straight (not
+	// premultiplied) RGBA as the reference implementation expects
+	for _, tt := range []struct {
+		img  *image.NRGBA
+		want string
+	}{
+		{gradient(100, 50, 0xFF), "FfYCnJqIiIiIiIh3eIB/d/iHhw"},
+		{gradient(40, 80, 0x80), "FfaCUw4I+HuXiId/h/h3eH9rind4iIg"},
+	} {
+		hash, err := EncodeThumbHash(tt.img)
+		if got := base64.RawStdEncoding.EncodeToString(hash); err != nil || got != tt.want {
+			t.Errorf("EncodeThumbHash(%v) = %q, %v, want %q", tt.img.Bounds().Size(), got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package placeholder
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+)
+
+// round rounds halves up like JavaScript's Math.round, which the reference ThumbHash
+// implementation uses
+func round(v float64) int {
+	return int(math.Floor(v + 0.5))
+}
+
+// thumbChannel holds the DCT terms of one ThumbHash channel
+type thumbChannel struct {
+	dc    float64
+	ac    []float64 // Normalized to 0-1 by scale when encoding
+	scale float64
+}
+
+// encodeThumbChannel takes the DCT of a w x h channel, keeping the nx x ny terms of
+// the triangle below the diagonal
+func encodeThumbChannel(channel []float64, w, h, nx, ny int) thumbChannel {
+	var c thumbChannel
+	fx := make([]float64, w)
+	for cy := 0; cy < ny; cy++ {
+		for cx := 0; cx*ny < nx*(ny-cy); cx++ {
+			for x := 0; x < w; x++ {
+				fx[x] = math.Cos(math.Pi / float64(w) * float64(cx) * (float64(x) + 0.5))
+			}
+			f := 0.0
+			for y := 0; y < h; y++ {
+				fy := math.Cos(math.Pi / float64(h) * float64(cy) * (float64(y) + 0.5))
+				for x := 0; x < w; x++ {
+					f += channel[x+y*w] * fx[x] * fy
+				}
+			}
+			f /= float64(w * h)
+			if cx > 0 || cy > 0 {
+				c.ac = append(c.ac, f)
+				c.scale = max(c.scale, math.Abs(f))
+			} else {
+				c.dc = f
+			}
+		}
+	}
+	if c.scale > 0 {
+		for i := range c.ac {
+			c.ac[i] = 0.5 + 0.5/c.scale*c.ac[i]
+		}
+	}
+	return c
+}
+
+// EncodeThumbHash encodes an image of at most 100x100 pixels as a ThumbHash, which
+// also keeps the aspect ratio and transparency
+func EncodeThumbHash(img image.Image) ([]byte, error) {
+	colors, w, h := pixels(img)
+	if w > 100 || h > 100 {
+		return nil, ErrTooLarge
+	}
+	if w == 0 || h == 0 {
+		return nil, fmt.Errorf("thumbhash of an empty image")
+	}
+	
+	// Average color, weighted by alpha
+	var avgR, avgG, avgB, avgA float64
+	for _, c := range colors {
+		alpha := float64(c.A) / 255
+		avgR += alpha / 255 * float64(c.R)
+		avgG += alpha / 255 * float64(c.G)
+		avgB += alpha / 255 * float64(c.B)
+		avgA += alpha
+	}
+	if avgA > 0 {
+		avgR /= avgA
+		avgG /= avgA
+		avgB /= avgA
+	}
+	
+	hasAlpha := avgA < float64(w*h)
+	limit := 7.0
+	if hasAlpha {
+		limit = 5 // Fewer luminance terms leave room for alpha
+	}
+	longest := float64(max(w, h))
+	lx := max(1, round(limit*float64(w)/longest))
+	ly := max(1, round(limit*float64(h)/longest))
+	
+	// Convert to luminance, yellow-blue, red-green and alpha over the average color
+	l := make([]float64, len(colors))
+	p := make([]float64, len(colors))
+	q := make([]float64, len(colors))
+	a := make([]float64, len(colors))
+	for i, c := range colors {
+		alpha := float64(c.A) / 255
+		r := avgR*(1-alpha) + alpha/255*float64(c.R)
+		g := avgG*(1-alpha) + alpha/255*float64(c.G)
+		b := avgB*(1-alpha) + alpha/255*float64(c.B)
+		l[i] = (r + g + b) / 3
+		p[i] = (r+g)/2 - b
+		q[i] = r - g
+		a[i] = alpha
+	}
+	lc := encodeThumbChannel(l, w, h, max(3, lx), max(3, ly))
+	pc := encodeThumbChannel(p, w, h, 3, 3)
+	qc := encodeThumbChannel(q, w, h, 3, 3)
+	channels := []thumbChannel{lc, pc, qc}
+	
+	landscape := 0
+	short := lx
+	if w > h {
+		landscape, short = 1, ly
+	}
+	alphaBit := 0
+	if hasAlpha {
+		alphaBit = 1
+	}
+	header24 := round(63*lc.dc) | round(31.5+31.5*pc.dc)<<6 | round(31.5+31.5*qc.dc)<<12 | round(31*lc.scale)<<18 | alphaBit<<23
+	header16 := short | round(63*pc.scale)<<3 | round(63*qc.scale)<<9 | landscape<<15
+	hash := []byte{byte(header24), byte(header24 >> 8), byte(header24 >> 16), byte(header16), byte(header16 >> 8)}
+	if hasAlpha {
+		ac := encodeThumbChannel(a, w, h, 5, 5)
+		hash = append(hash, byte(round(15*ac.dc)|round(15*ac.scale)<<4))
+		channels = append(channels, ac)
+	}
+	
+	// Pack the varying terms as 4-bit values, low nibble first
+	start, index := len(hash), 0
+	for _, c := range channels {
+		for _, f := range c.ac {
+			if start+index>>1 == len(hash) {
+				hash = append(hash, 0)
+			}
+			hash[start+index>>1] |= byte(round(15*f) << ((index & 1) << 2))
+			index++
+		}
+	}
+	return hash, nil
+}
+
+// ThumbHashAspectRatio returns the approximate width to height ratio of the image a
+// ThumbHash was made from
+func ThumbHashAspectRatio(hash []byte) (float64, error) {
+	if len(hash) < 5 {
+		return 0, fmt.Errorf("%w: too short", ErrInvalidHash)
+	}
+	lx, ly := thumbHashSize(hash)
+	return float64(lx) / float64(ly), nil
+}
+
+// thumbHashSize returns the number of luminance terms along each axis, before the
+// minimum of 3
+func thumbHashSize(hash []byte) (int, int) {
+	hasAlpha := hash[2]&0x80 != 0
+	landscape := hash[4]&0x80 != 0
+	long := 7
+	if hasAlpha {
+		long = 5
+	}
+	if landscape {
+		return long, int(hash[3] & 7)
+	}
+	return int(hash[3] & 7), long
+}
+
+// DecodeThumbHash renders a ThumbHash at its own size of at most 32x32 pixels
+func DecodeThumbHash(hash []byte) (*image.NRGBA, error) {
+	if len(hash) < 5 {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidHash)
+	}
+	header24 := int(hash[0]) | int(hash[1])<<8 | int(hash[2])<<16
+	header16 := int(hash[3]) | int(hash[4])<<8
+	lDC := float64(header24&63) / 63
+	pDC := float64(header24>>6&63)/31.5 - 1
+	qDC := float64(header24>>12&63)/31.5 - 1
+	lScale := float64(header24>>18&31) / 31
+	hasAlpha := header24>>23 != 0
+	pScale := float64(header16>>3&63) / 63
+	qScale := float64(header16>>9&63) / 63
+	lx, ly := thumbHashSize(hash)
+	lx, ly = max(3, lx), max(3, ly)
+	aDC, aScale := 1.0, 0.0
+	start := 5
+	if hasAlpha {
+		if len(hash) < 6 {
+			return nil, fmt.Errorf("%w: too short", ErrInvalidHash)
+		}
+		aDC = float64(hash[5]&15) / 15
+		aScale = float64(hash[5]>>4) / 15
+		start = 6
+	}
+	
+	// Read the varying terms, boosting saturation to make up for quantization
+	index := 0
+	var err error
+	decodeChannel := func(nx, ny int, scale float64) []float64 {
+		var ac []float64
+		for cy := 0; cy < ny; cy++ {
+			cx := 0
+			if cy == 0 {
+				cx = 1
+			}
+			for ; cx*ny < nx*(ny-cy); cx++ {
+				i := start + index>>1
+				if i >= len(hash) {
+					err = fmt.Errorf("%w: too short", ErrInvalidHash)
+					return nil
+				}
+				ac = append(ac, (float64(hash[i]>>((index&1)<<2)&15)/7.5-1)*scale)
+				index++
+			}
+		}
+		return ac
+	}
+	lAC := decodeChannel(lx, ly, lScale)
+	pAC := decodeChannel(3, 3, pScale*1.25)
+	qAC := decodeChannel(3, 3, qScale*1.25)
+	var aAC []float64
+	if hasAlpha {
+		aAC = decodeChannel(5, 5, aScale)
+	}
+	if err != nil {
+		return nil, err
+	}
+	
+	ratio, _ := ThumbHashAspectRatio(hash)
+	w, h := 32, round(32/ratio)
+	if ratio <= 1 {
+		w, h = round(32*ratio), 32
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	n := 3
+	if hasAlpha {
+		n = 5
+	}
+	fx := make([]float64, max(lx, n))
+	fy := make([]float64, max(ly, n))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			l, p, q, a := lDC, pDC, qDC, aDC
+			for cx := range fx {
+				fx[cx] = math.Cos(math.Pi / float64(w) * (float64(x) + 0.5) * float64(cx))
+			}
+			for cy := range fy {
+				fy[cy] = math.Cos(math.Pi / float64(h) * (float64(y) + 0.5) * float64(cy))
+			}
+			
+			// Sum each channel over the triangle of terms it keeps
+			sum := func(ac []float64, nx, ny int) float64 {
+				v, j := 0.0, 0
+				for cy := 0; cy < ny; cy++ {
+					cx := 0
+					if cy == 0 {
+						cx = 1
+					}
+					for ; cx*ny < nx*(ny-cy); cx++ {
+						v += ac[j] * fx[cx] * fy[cy] * 2
+						j++
+					}
+				}
+				return v
+			}
+			l += sum(lAC, lx, ly)
+			p += sum(pAC, 3, 3)
+			q += sum(qAC, 3, 3)
+			if hasAlpha {
+				a += sum(aAC, 5, 5)
+			}
+			
+			b := l - 2.0/3*p
+			r := (3*l - b + q) / 2
+			g := r - q
+			img.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(max(0, 255*min(1, r))),
+				G: uint8(max(0, 255*min(1, g))),
+				B: uint8(max(0, 255*min(1, b))),
+				A: uint8(max(0, 255*min(1, a))),
+			})
+		}
+	}
+	return img, nil
+}
\ No newline at end of file
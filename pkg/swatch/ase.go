@@ -0,0 +1,87 @@
+// Package swatch writes Adobe Swatch Exchange (.ase) files, the palette format that
+// Photoshop, Illustrator and InDesign import into their swatch panels.
+package swatch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image/color"
+	"io"
+	"math"
+	"unicode/utf16"
+)
+
+// ErrNoSwatches is returned when a swatch file would be empty
+var ErrNoSwatches = errors.New("swatch: no colors")
+
+// Swatch is one named color
+type Swatch struct {
+	Name  string
+	Color color.Color
+}
+
+// ASE block types
+const (
+	blockGroupStart = 0xC001
+	blockGroupEnd   = 0xC002
+	blockColor      = 0x0001
+)
+
+// colorTypeNormal marks a process (non-spot, non-global) color
+const colorTypeNormal = 2
+
+// EncodeASE writes an ASE file with the swatches as RGB colors, inside a group with
+// the given name when it is not empty
+func EncodeASE(w io.Writer, group string, swatches []Swatch) error {
+	if len(swatches) == 0 {
+		return ErrNoSwatches
+	}
+	
+	var blocks bytes.Buffer
+	count := len(swatches)
+	if group != "" {
+		writeBlock(&blocks, blockGroupStart, aseString(group))
+		count += 2
+	}
+	for _, s := range swatches {
+		r, g, b, _ := color.NRGBAModel.Convert(s.Color).RGBA()
+		body := aseString(s.Name)
+		body = append(body, "RGB "...)
+		for _, v := range []uint32{r, g, b} {
+			body = binary.BigEndian.AppendUint32(body, math.Float32bits(float32(v)/0xFFFF))
+		}
+		body = binary.BigEndian.AppendUint16(body, colorTypeNormal)
+		writeBlock(&blocks, blockColor, body)
+	}
+	if group != "" {
+		writeBlock(&blocks, blockGroupEnd, nil)
+	}
+	
+	// Signature, version 1.0 and block count
+	header := append([]byte("ASEF"), 0, 1, 0, 0)
+	header = binary.BigEndian.AppendUint32(header, uint32(count))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(blocks.Bytes())
+	return err
+}
+
+// writeBlock writes a block type, its body length and the body
+func writeBlock(buf *bytes.Buffer, kind uint16, body []byte) {
+	_ = binary.Write(buf, binary.BigEndian, kind)
+	_ = binary.Write(buf, binary.BigEndian, uint32(len(body)))
+	buf.Write(body)
+}
+
+// aseString encodes a name as its length in UTF-16 units, including the terminating
+// zero, followed by the big endian UTF-16 text and the zero
+func aseString(s string) []byte {
+	units := append(utf16.Encode([]rune(s)), 0)
+	b := binary.BigEndian.AppendUint16(nil, uint16(len(units)))
+	for _, u := range units {
+		b = binary.BigEndian.AppendUint16(b, u)
+	}
+	return b
+}
\ No newline at end of file
@@ -0,0 +1,67 @@
+package swatch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestEncodeASE(t *testing.T) {
+	swatches := []Swatch{
+		{Name: "하늘", Color: color.NRGBA{R: 0x33, G: 0x66, B: 0xFF, A: 0xFF}},
+		{Name: "ink", Color: color.Black},
+	}
+	var buf bytes.Buffer
+	if err := EncodeASE(&buf, "photo", swatches); err != nil {
+		t.Fatalf("EncodeASE() error = %v", err)
+	}
+	data := buf.Bytes()
+	
+	if string(data[:4]) != "ASEF" || binary.BigEndian.Uint32(data[4:]) != 0x00010000 {
+		t.Fatalf("header = % x", data[:8])
+	}
+	if got := binary.BigEndian.Uint32(data[8:]); got != 4 {
+		t.Errorf("block count = %d, want 4 (group start, 2 colors, group end)", got)
+	}
+	
+	// Walk the blocks and check the first color
+	var kinds []uint16
+	for p := 12; p < len(data); {
+		kind := binary.BigEndian.Uint16(data[p:])
+		length := int(binary.BigEndian.Uint32(data[p+2:]))
+		body := data[p+6 : p+6+length]
+		kinds = append(kinds, kind)
+		if kind == blockColor && len(kinds) == 2 {
+			// Two UTF-16 units and the terminator
+			if n := binary.BigEndian.Uint16(body); n != 3 {
+				t.Errorf("name length = %d, want 3", n)
+			}
+			values := body[2+3*2:]
+			if string(values[:4]) != "RGB " {
+				t.Errorf("color model = %q, want RGB", values[:4])
+			}
+			if b := math.Float32frombits(binary.BigEndian.Uint32(values[12:])); b != 1 {
+				t.Errorf("blue = %v, want 1", b)
+			}
+			if typ := binary.BigEndian.Uint16(values[16:]); typ != colorTypeNormal {
+				t.Errorf("color type = %d, want %d", typ, colorTypeNormal)
+			}
+		}
+		p += 6 + length
+	}
+	want := []uint16{blockGroupStart, blockColor, blockColor, blockGroupEnd}
+	if len(kinds) != len(want) {
+		t.Fatalf("blocks = %x, want %x", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Errorf("block %d = %x, want %x", i, kinds[i], want[i])
+		}
+	}
+	
+	if err := EncodeASE(&buf, "", nil); err != ErrNoSwatches {
+		t.Errorf("EncodeASE(nil) error = %v, want ErrNoSwatches", err)
+	}
+}
\ No newline at end of file
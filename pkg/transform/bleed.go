@@ -0,0 +1,193 @@
+package transform
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strings"
+	
+	"github.com/disintegration/imaging"
+)
+
+// BleedFill selects how the bleed area around an image is filled
+type BleedFill string
+
+const (
+	BleedMirror  BleedFill = "mirror"  // Reflect the pixels at each edge
+	BleedStretch BleedFill = "stretch" // Repeat the outermost row or column
+	BleedColor   BleedFill = "color"   // Fill with a solid color
+)
+
+// Print mark geometry in inches
+const (
+	cropMarkLength    = 5 / 25.4  // 5mm marks outside the bleed
+	cropMarkThickness = 0.25 / 72 // 0.25pt hairline
+)
+
+var (
+	cropMarkColor = color.NRGBA{0, 0, 0, 255}
+	slugColor     = color.NRGBA{255, 255, 255, 255}
+	safeAreaColor = color.NRGBA{0, 174, 239, 255} // Process cyan
+)
+
+// BleedOptions contains options for extending an image with print bleed
+type BleedOptions struct {
+	Size       DimensionValue // Bleed on each side, a length like "3mm" or pixels
+	Fill       BleedFill      // How the bleed area is filled (empty = mirror)
+	Color      color.Color    // Fill color for BleedColor (nil = white)
+	CropMarks  bool           // Add a margin with crop marks at the trim corners
+	SafeArea   DimensionValue // Inset of the safe-area guide from the trim edge (zero = none)
+	Resolution Resolution     // Resolution for physical lengths (zero = the image's own)
+}
+
+// ParseBleedFill parses a bleed fill like "mirror", "stretch" or "color"
+func ParseBleedFill(s string) (BleedFill, error) {
+	switch fill := BleedFill(strings.ToLower(strings.TrimSpace(s))); fill {
+	case "":
+		return BleedMirror, nil
+	case BleedMirror, BleedStretch, BleedColor:
+		return fill, nil
+	default:
+		return "", fmt.Errorf("unsupported bleed fill: %s (use mirror, stretch or color)", s)
+	}
+}
+
+// ValidateBleedOptions checks that the bleed and safe area are lengths or pixel counts
+func ValidateBleedOptions(options BleedOptions) error {
+	for _, d := range []DimensionValue{options.Size, options.SafeArea} {
+		if d.IsMultiplier || d.Constraint != ConstraintNone {
+			return fmt.Errorf("bleed and safe area must be a length or pixel count: %s", d)
+		}
+	}
+	if options.Size.IsZero() && !options.CropMarks && options.SafeArea.IsZero() {
+		return fmt.Errorf("bleed size, crop marks or safe area must be specified")
+	}
+	switch options.Fill {
+	case "", BleedMirror, BleedStretch, BleedColor:
+		return nil
+	default:
+		return fmt.Errorf("unsupported bleed fill: %s", options.Fill)
+	}
+}
+
+// AddBleed extends an image by the bleed on each side and draws the requested print
+// marks. Physical lengths use options.Resolution, or 96 DPI when it is zero.
+func AddBleed(img image.Image, options BleedOptions) (*image.NRGBA, error) {
+	if err := ValidateBleedOptions(options); err != nil {
+		return nil, err
+	}
+	resolution := options.Resolution
+	if resolution.IsZero() {
+		resolution = SquareDPI(96)
+	}
+	dpiX, dpiY := resolution.DPI()
+	
+	src := imaging.Clone(img)
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	bleedX := options.Size.Resolve(dpiX).Value
+	bleedY := options.Size.Resolve(dpiY).Value
+	
+	// Crop marks sit in a slug outside the bleed so they never reach the trimmed page
+	var slugX, slugY int
+	if options.CropMarks {
+		slugX = inchesToPixels(cropMarkLength, dpiX)
+		slugY = inchesToPixels(cropMarkLength, dpiY)
+	}
+	
+	dst := image.NewNRGBA(image.Rect(0, 0, width+2*(bleedX+slugX), height+2*(bleedY+slugY)))
+	if options.CropMarks {
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(slugColor), image.Point{}, draw.Src)
+	}
+	trim := image.Rect(slugX+bleedX, slugY+bleedY, slugX+bleedX+width, slugY+bleedY+height)
+	page := image.Rect(slugX, slugY, trim.Max.X+bleedX, trim.Max.Y+bleedY)
+	fillBleed(dst, page, trim, src, options)
+	
+	lineX := inchesToPixels(cropMarkThickness, dpiX)
+	lineY := inchesToPixels(cropMarkThickness, dpiY)
+	
+	if !options.SafeArea.IsZero() {
+		insetX := options.SafeArea.Resolve(dpiX).Value
+		insetY := options.SafeArea.Resolve(dpiY).Value
+		safe := image.Rect(trim.Min.X+insetX, trim.Min.Y+insetY, trim.Max.X-insetX, trim.Max.Y-insetY)
+		if safe.Empty() {
+			return nil, fmt.Errorf("safe area inset %s leaves no room in a %dx%d image", options.SafeArea, width, height)
+		}
+		strokeRect(dst, safe, lineX, lineY, safeAreaColor)
+	}
+	
+	if options.CropMarks {
+		drawCropMarks(dst, trim, slugX, slugY, lineX, lineY)
+	}
+	return dst, nil
+}
+
+// fillBleed copies src into the trim area and fills the rest of page around it
+func fillBleed(dst *image.NRGBA, page, trim image.Rectangle, src *image.NRGBA, options BleedOptions) {
+	if options.Fill == BleedColor {
+		c := options.Color
+		if c == nil {
+			c = color.White
+		}
+		draw.Draw(dst, page, image.NewUniform(c), image.Point{}, draw.Src)
+		draw.Draw(dst, trim, src, image.Point{}, draw.Src)
+		return
+	}
+	
+	// Map every destination column and row to a source one
+	mirror := options.Fill != BleedStretch
+	columns := make([]int, page.Dx())
+	for x := range columns {
+		columns[x] = edgeIndex(page.Min.X+x-trim.Min.X, trim.Dx(), mirror)
+	}
+	for y := 0; y < page.Dy(); y++ {
+		srcRow := src.Pix[edgeIndex(page.Min.Y+y-trim.Min.Y, trim.Dy(), mirror)*src.Stride:]
+		dstRow := dst.Pix[(page.Min.Y+y)*dst.Stride+page.Min.X*4:]
+		for x, sx := range columns {
+			copy(dstRow[x*4:x*4+4], srcRow[sx*4:sx*4+4])
+		}
+	}
+}
+
+// edgeIndex maps a coordinate outside 0..n-1 back into the image, reflecting at the
+// edges when mirror is set and clamping otherwise
+func edgeIndex(i, n int, mirror bool) int {
+	if !mirror {
+		return min(max(i, 0), n-1)
+	}
+	period := 2 * n
+	i = (i%period + period) % period
+	if i >= n {
+		return period - 1 - i
+	}
+	return i
+}
+
+// drawCropMarks draws marks in the slug in line with each trim edge
+func drawCropMarks(dst *image.NRGBA, trim image.Rectangle, slugX, slugY, lineX, lineY int) {
+	bounds := dst.Bounds()
+	mark := image.NewUniform(cropMarkColor)
+	for _, x := range []int{trim.Min.X, trim.Max.X - lineX} {
+		draw.Draw(dst, image.Rect(x, 0, x+lineX, slugY), mark, image.Point{}, draw.Src)
+		draw.Draw(dst, image.Rect(x, bounds.Max.Y-slugY, x+lineX, bounds.Max.Y), mark, image.Point{}, draw.Src)
+	}
+	for _, y := range []int{trim.Min.Y, trim.Max.Y - lineY} {
+		draw.Draw(dst, image.Rect(0, y, slugX, y+lineY), mark, image.Point{}, draw.Src)
+		draw.Draw(dst, image.Rect(bounds.Max.X-slugX, y, bounds.Max.X, y+lineY), mark, image.Point{}, draw.Src)
+	}
+}
+
+// strokeRect draws the outline of r just inside its bounds
+func strokeRect(dst *image.NRGBA, r image.Rectangle, lineX, lineY int, c color.Color) {
+	u := image.NewUniform(c)
+	draw.Draw(dst, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+lineY), u, image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(r.Min.X, r.Max.Y-lineY, r.Max.X, r.Max.Y), u, image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(r.Min.X, r.Min.Y, r.Min.X+lineX, r.Max.Y), u, image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(r.Max.X-lineX, r.Min.Y, r.Max.X, r.Max.Y), u, image.Point{}, draw.Src)
+}
+
+// inchesToPixels converts a length to at least one pixel
+func inchesToPixels(inches, dpi float64) int {
+	return max(int(math.Round(inches*dpi)), 1)
+}
\ No newline at end of file
@@ -0,0 +1,174 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// gradientImage returns an image whose pixels encode their own coordinates
+func gradientImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x), uint8(y), 0, 255})
+		}
+	}
+	return img
+}
+
+func TestAddBleedFill(t *testing.T) {
+	src := gradientImage(4, 3)
+	tests := []struct {
+		fill BleedFill
+		// Expected pixels at (0,0), (1,1) and (7,6) of the 8x7 result
+		want [3]color.NRGBA
+	}{
+		{BleedMirror, [3]color.NRGBA{{1, 1, 0, 255}, {0, 0, 0, 255}, {2, 1, 0, 255}}},
+		{BleedStretch, [3]color.NRGBA{{0, 0, 0, 255}, {0, 0, 0, 255}, {3, 2, 0, 255}}},
+		{BleedColor, [3]color.NRGBA{{255, 0, 0, 255}, {255, 0, 0, 255}, {255, 0, 0, 255}}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(string(tt.fill), func(t *testing.T) {
+			result, err := AddBleed(src, BleedOptions{
+				Size:  DimensionValue{Value: 2},
+				Fill:  tt.fill,
+				Color: color.NRGBA{255, 0, 0, 255},
+			})
+			if err != nil {
+				t.Fatalf("AddBleed() error = %v", err)
+			}
+			if got := result.Bounds().Size(); got != image.Pt(8, 7) {
+				t.Fatalf("AddBleed() size = %v, want (8,7)", got)
+			}
+			for i, p := range []image.Point{{0, 0}, {1, 1}, {7, 6}} {
+				if got := result.NRGBAAt(p.X, p.Y); got != tt.want[i] {
+					t.Errorf("pixel %v = %v, want %v", p, got, tt.want[i])
+				}
+			}
+			// The original image is copied unchanged into the trim area
+			for y := 0; y < 3; y++ {
+				for x := 0; x < 4; x++ {
+					if got, want := result.NRGBAAt(x+2, y+2), src.NRGBAAt(x, y); got != want {
+						t.Fatalf("trim pixel (%d,%d) = %v, want %v", x, y, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestAddBleedMirrorWiderThanImage(t *testing.T) {
+	result, err := AddBleed(gradientImage(2, 2), BleedOptions{Size: DimensionValue{Value: 5}})
+	if err != nil {
+		t.Fatalf("AddBleed() error = %v", err)
+	}
+	if got := result.Bounds().Size(); got != image.Pt(12, 12) {
+		t.Fatalf("AddBleed() size = %v, want (12,12)", got)
+	}
+}
+
+func TestAddBleedPhysicalSize(t *testing.T) {
+	result, err := AddBleed(gradientImage(100, 50), BleedOptions{
+		Size:       DimensionValue{Length: 3, Unit: LengthMillimeter},
+		Resolution: Resolution{X: 300, Y: 150, Unit: UnitInch},
+	})
+	if err != nil {
+		t.Fatalf("AddBleed() error = %v", err)
+	}
+	// 3mm is 35 pixels at 300 DPI and 18 pixels at 150 DPI
+	if got := result.Bounds().Size(); got != image.Pt(170, 86) {
+		t.Errorf("AddBleed() size = %v, want (170,86)", got)
+	}
+}
+
+func TestAddBleedMarks(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 300, 300))
+	result, err := AddBleed(src, BleedOptions{
+		Size:       DimensionValue{Length: 3, Unit: LengthMillimeter},
+		Fill:       BleedColor,
+		CropMarks:  true,
+		SafeArea:   DimensionValue{Length: 5, Unit: LengthMillimeter},
+		Resolution: SquareDPI(300),
+	})
+	if err != nil {
+		t.Fatalf("AddBleed() error = %v", err)
+	}
+	
+	// 5mm slug (59px) + 3mm bleed (35px) on each side
+	if got := result.Bounds().Size(); got != image.Pt(488, 488) {
+		t.Fatalf("AddBleed() size = %v, want (488,488)", got)
+	}
+	trim := 59 + 35
+	if got := result.NRGBAAt(trim, 0); got != cropMarkColor {
+		t.Errorf("crop mark at trim line = %v, want %v", got, cropMarkColor)
+	}
+	if got := result.NRGBAAt(trim-1, 0); got != slugColor {
+		t.Errorf("slug beside crop mark = %v, want %v", got, slugColor)
+	}
+	if got := result.NRGBAAt(trim, 59); got == cropMarkColor {
+		t.Error("crop mark reaches into the bleed")
+	}
+	if got := result.NRGBAAt(trim+59, trim+150); got != safeAreaColor {
+		t.Errorf("safe area guide = %v, want %v", got, safeAreaColor)
+	}
+}
+
+func TestValidateBleedOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options BleedOptions
+		wantErr bool
+	}{
+		{"Length", BleedOptions{Size: DimensionValue{Length: 3, Unit: LengthMillimeter}}, false},
+		{"Crop marks only", BleedOptions{CropMarks: true}, false},
+		{"Nothing to do", BleedOptions{}, true},
+		{"Multiplier", BleedOptions{Size: DimensionValue{IsMultiplier: true, Multiplier: 0.1}}, true},
+		{"Unknown fill", BleedOptions{Size: DimensionValue{Value: 10}, Fill: "blur"}, true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateBleedOptions(tt.options); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBleedOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBleedKeepsResolution(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, gradientImage(100, 100)); err != nil {
+		t.Fatal(err)
+	}
+	source, err := SetPNGResolution(buf.Bytes(), SquareDPI(300))
+	if err != nil {
+		t.Fatal(err)
+	}
+	
+	output := &bytes.Buffer{}
+	err = NewTransformer().Bleed(bytes.NewReader(source), output, BleedOptions{
+		Size: DimensionValue{Length: 3, Unit: LengthMillimeter},
+	})
+	if err != nil {
+		t.Fatalf("Bleed() error = %v", err)
+	}
+	
+	config, err := png.DecodeConfig(bytes.NewReader(output.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Width != 170 || config.Height != 170 {
+		t.Errorf("Bleed() size = %dx%d, want 170x170", config.Width, config.Height)
+	}
+	resolution, err := GetImageResolution(bytes.NewReader(output.Bytes()), FormatPNG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolution.String() != "300 DPI" {
+		t.Errorf("Bleed() resolution = %s, want 300 DPI", resolution)
+	}
+}
\ No newline at end of file
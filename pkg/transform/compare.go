@@ -0,0 +1,221 @@
+package transform
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	
+	"github.com/disintegration/imaging"
+)
+
+// CompareOptions contains options for comparing two images
+type CompareOptions struct {
+	Resize    bool        // Resize the second image to the size of the first instead of failing
+	Tolerance int         // Channel difference still counted as equal (0-255)
+	Diff      bool        // Render a diff image
+	DiffColor color.Color // Highlight color of differing pixels (nil = red)
+}
+
+// CompareResult holds the similarity metrics of two images
+type CompareResult struct {
+	Width       int
+	Height      int
+	Resized     bool        // The second image was resized to match the first
+	PSNR        float64     // Peak signal-to-noise ratio in dB, +Inf for identical images
+	SSIM        float64     // Mean structural similarity of the luma, 1 for identical images
+	MaxDelta    int         // Largest difference of any RGB channel (0-255)
+	DiffPixels  int         // Pixels with a channel difference above the tolerance
+	DiffPercent float64     // DiffPixels as a share of all pixels
+	Diff        *image.RGBA // Diff image when requested
+}
+
+// SSIM constants from Wang et al. for 8-bit values
+const (
+	ssimC1     = (0.01 * 255) * (0.01 * 255)
+	ssimC2     = (0.03 * 255) * (0.03 * 255)
+	ssimRadius = 5
+	ssimSigma  = 1.5
+)
+
+// Compare decodes two images and measures how much the second differs from the first
+func (t *Transformer) Compare(original, modified io.Reader, options CompareOptions) (CompareResult, error) {
+	var imgs [2]image.Image
+	for i, input := range []io.Reader{original, modified} {
+		data, err := t.readImage(input)
+		if err != nil {
+			return CompareResult{}, err
+		}
+		if imgs[i], _, err = decodeImage(data); err != nil {
+			return CompareResult{}, fmt.Errorf("failed to decode image: %w", err)
+		}
+	}
+	
+	result, err := CompareImages(imgs[0], imgs[1], options)
+	if err != nil {
+		return CompareResult{}, err
+	}
+	t.logf("compared %dx%d images: PSNR %.2f dB, SSIM %.4f", result.Width, result.Height, result.PSNR, result.SSIM)
+	return result, nil
+}
+
+// CompareImages measures how much b differs from a. Transparent images are compared as
+// flattened onto white, so a PNG and its JPEG export match.
+func CompareImages(a, b image.Image, options CompareOptions) (CompareResult, error) {
+	if options.Tolerance < 0 || options.Tolerance > 255 {
+		return CompareResult{}, fmt.Errorf("tolerance must be between 0 and 255: %d", options.Tolerance)
+	}
+	sizeA, sizeB := a.Bounds().Size(), b.Bounds().Size()
+	if sizeA.X == 0 || sizeA.Y == 0 || sizeB.X == 0 || sizeB.Y == 0 {
+		return CompareResult{}, fmt.Errorf("empty image")
+	}
+	result := CompareResult{Width: sizeA.X, Height: sizeA.Y}
+	if sizeA != sizeB {
+		if !options.Resize {
+			return CompareResult{}, fmt.Errorf("image sizes differ: %dx%d and %dx%d", sizeA.X, sizeA.Y, sizeB.X, sizeB.Y)
+		}
+		b = imaging.Resize(b, sizeA.X, sizeA.Y, imaging.Lanczos)
+		result.Resized = true
+	}
+	
+	pa := Flatten(a, DefaultBackground).(*image.RGBA)
+	pb := Flatten(b, DefaultBackground).(*image.RGBA)
+	if options.Diff {
+		result.Diff = image.NewRGBA(pa.Bounds())
+	}
+	highlight := color.RGBA{R: 0xFF, A: 0xFF}
+	if options.DiffColor != nil {
+		highlight = color.RGBAModel.Convert(options.DiffColor).(color.RGBA)
+	}
+	
+	var squared float64
+	for y := 0; y < sizeA.Y; y++ {
+		for x := 0; x < sizeA.X; x++ {
+			i := pa.PixOffset(x, y)
+			delta := 0
+			for c := 0; c < 3; c++ {
+				d := int(pa.Pix[i+c]) - int(pb.Pix[i+c])
+				squared += float64(d * d)
+				delta = max(delta, d, -d)
+			}
+			result.MaxDelta = max(result.MaxDelta, delta)
+			differs := delta > options.Tolerance
+			if differs {
+				result.DiffPixels++
+			}
+			
+			if result.Diff != nil {
+				// Faded grayscale of the original with differing pixels highlighted
+				px := highlight
+				if !differs {
+					v := 255 - (255-uint8(luma(pa.Pix[i:i+3])+0.5))/4
+					px = color.RGBA{R: v, G: v, B: v, A: 0xFF}
+				}
+				result.Diff.SetRGBA(x, y, px)
+			}
+		}
+	}
+	
+	pixels := sizeA.X * sizeA.Y
+	result.DiffPercent = float64(result.DiffPixels) * 100 / float64(pixels)
+	result.PSNR = math.Inf(1)
+	if mse := squared / float64(pixels*3); mse > 0 {
+		result.PSNR = 10 * math.Log10(255*255/mse)
+	}
+	result.SSIM = ssim(pa, pb)
+	return result, nil
+}
+
+// luma returns the Rec. 601 luma of an RGB pixel
+func luma(rgb []uint8) float64 {
+	return 0.299*float64(rgb[0]) + 0.587*float64(rgb[1]) + 0.114*float64(rgb[2])
+}
+
+// ssim computes the mean structural similarity of the luma of two images of the same
+// size with an 11x11 Gaussian window. Like the reference implementation, large images
+// are first averaged down so the shorter side is about 256 pixels.
+func ssim(a, b *image.RGBA) float64 {
+	w, h := a.Bounds().Dx(), a.Bounds().Dy()
+	factor := max(1, int(math.Round(float64(min(w, h))/256)))
+	x, _, _ := lumaPlane(a, factor)
+	y, w, h := lumaPlane(b, factor)
+	
+	n := w * h
+	xx, yy, xy := make([]float64, n), make([]float64, n), make([]float64, n)
+	for i := range x {
+		xx[i], yy[i], xy[i] = x[i]*x[i], y[i]*y[i], x[i]*y[i]
+	}
+	kernel := gaussianKernel(ssimRadius, ssimSigma)
+	muX, muY := blurPlane(x, w, h, kernel), blurPlane(y, w, h, kernel)
+	xx, yy, xy = blurPlane(xx, w, h, kernel), blurPlane(yy, w, h, kernel), blurPlane(xy, w, h, kernel)
+	
+	total := 0.0
+	for i := 0; i < n; i++ {
+		mx, my := muX[i], muY[i]
+		varX, varY, cov := xx[i]-mx*mx, yy[i]-my*my, xy[i]-mx*my
+		total += (2*mx*my + ssimC1) * (2*cov + ssimC2) / ((mx*mx + my*my + ssimC1) * (varX + varY + ssimC2))
+	}
+	return total / float64(n)
+}
+
+// lumaPlane returns the luma of an image averaged over factor x factor blocks, with
+// the size of the plane
+func lumaPlane(img *image.RGBA, factor int) ([]float64, int, int) {
+	w, h := img.Bounds().Dx()/factor, img.Bounds().Dy()/factor
+	plane := make([]float64, w*h)
+	for y := 0; y < h*factor; y++ {
+		for x := 0; x < w*factor; x++ {
+			i := img.PixOffset(x, y)
+			plane[(y/factor)*w+x/factor] += luma(img.Pix[i : i+3])
+		}
+	}
+	area := float64(factor * factor)
+	for i := range plane {
+		plane[i] /= area
+	}
+	return plane, w, h
+}
+
+// gaussianKernel returns a normalized 1D Gaussian kernel of 2*radius+1 taps
+func gaussianKernel(radius int, sigma float64) []float64 {
+	kernel := make([]float64, 2*radius+1)
+	sum := 0.0
+	for i := range kernel {
+		d := float64(i - radius)
+		kernel[i] = math.Exp(-d * d / (2 * sigma * sigma))
+		sum += kernel[i]
+	}
+	for i := range kernel {
+		kernel[i] /= sum
+	}
+	return kernel
+}
+
+// blurPlane convolves a plane with a separable kernel, repeating the edge values
+func blurPlane(plane []float64, w, h int, kernel []float64) []float64 {
+	radius := len(kernel) / 2
+	tmp := make([]float64, len(plane))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			sum := 0.0
+			for k, weight := range kernel {
+				sx := min(max(x+k-radius, 0), w-1)
+				sum += plane[y*w+sx] * weight
+			}
+			tmp[y*w+x] = sum
+		}
+	}
+	result := make([]float64, len(plane))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			sum := 0.0
+			for k, weight := range kernel {
+				sy := min(max(y+k-radius, 0), h-1)
+				sum += tmp[sy*w+x] * weight
+			}
+			result[y*w+x] = sum
+		}
+	}
+	return result
+}
\ No newline at end of file
@@ -0,0 +1,122 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"testing"
+)
+
+// noiseImage returns a deterministic textured image
+func noiseImage(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	seed := uint32(1)
+	for i := 0; i < len(img.Pix); i += 4 {
+		seed = seed*1664525 + 1013904223
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = uint8(seed>>24), uint8(seed>>16), uint8(seed>>8), 0xFF
+	}
+	return img
+}
+
+func TestCompareImages(t *testing.T) {
+	original := noiseImage(64, 48)
+	
+	// Identical images
+	result, err := CompareImages(original, original, CompareOptions{})
+	if err != nil {
+		t.Fatalf("CompareImages() error = %v", err)
+	}
+	if !math.IsInf(result.PSNR, 1) || math.Abs(result.SSIM-1) > 1e-9 || result.MaxDelta != 0 || result.DiffPixels != 0 {
+		t.Errorf("CompareImages(identical) = %+v", result)
+	}
+	
+	// Flip two low bits of every channel in a 16x16 block, changing each by 6 or 10
+	changed := image.NewNRGBA(original.Bounds())
+	copy(changed.Pix, original.Pix)
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			i := changed.PixOffset(x, y)
+			for c := 0; c < 3; c++ {
+				changed.Pix[i+c] ^= 10
+			}
+		}
+	}
+	result, err = CompareImages(original, changed, CompareOptions{Diff: true, Tolerance: 2})
+	if err != nil {
+		t.Fatalf("CompareImages() error = %v", err)
+	}
+	if result.DiffPixels != 256 || result.MaxDelta != 10 {
+		t.Errorf("CompareImages() diff pixels = %d, max delta = %d, want 256 and 10", result.DiffPixels, result.MaxDelta)
+	}
+	if want := 256 * 100 / float64(64*48); math.Abs(result.DiffPercent-want) > 1e-9 {
+		t.Errorf("CompareImages() diff percent = %v, want %v", result.DiffPercent, want)
+	}
+	if result.PSNR < 20 || math.IsInf(result.PSNR, 1) || result.SSIM >= 1 || result.SSIM < 0.5 {
+		t.Errorf("CompareImages() PSNR = %v, SSIM = %v", result.PSNR, result.SSIM)
+	}
+	if c := result.Diff.RGBAAt(3, 3); c != (color.RGBA{R: 0xFF, A: 0xFF}) {
+		t.Errorf("diff image changed pixel = %v, want red", c)
+	}
+	if c := result.Diff.RGBAAt(40, 40); c.R != c.G || c.R < 0xBF {
+		t.Errorf("diff image unchanged pixel = %v, want light gray", c)
+	}
+	
+	// Different sizes fail unless resizing
+	small := noiseImage(32, 24)
+	if _, err := CompareImages(original, small, CompareOptions{}); err == nil {
+		t.Error("CompareImages() accepted images of different sizes")
+	}
+	result, err = CompareImages(original, small, CompareOptions{Resize: true})
+	if err != nil || !result.Resized || result.Width != 64 || result.Height != 48 {
+		t.Errorf("CompareImages(resize) = %+v, %v", result, err)
+	}
+}
+
+func TestCompareSSIM(t *testing.T) {
+	// SSIM falls as the distortion grows
+	original := noiseImage(80, 80)
+	previous := 1.0
+	for _, amount := range []uint8{4, 16, 64} {
+		distorted := image.NewNRGBA(original.Bounds())
+		copy(distorted.Pix, original.Pix)
+		for i := 0; i < len(distorted.Pix); i += 4 {
+			if (i/4)%2 == 0 {
+				distorted.Pix[i] += amount
+				distorted.Pix[i+1] += amount
+			}
+		}
+		result, err := CompareImages(original, distorted, CompareOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.SSIM >= previous {
+			t.Errorf("SSIM with distortion %d = %v, want below %v", amount, result.SSIM, previous)
+		}
+		previous = result.SSIM
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// A transparent PNG matches the same image flattened onto white
+	img := image.NewNRGBA(image.Rect(0, 0, 20, 20))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+3] = 0xFF, 0x80
+	}
+	var a, b bytes.Buffer
+	if err := png.Encode(&a, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(&b, Flatten(img, DefaultBackground)); err != nil {
+		t.Fatal(err)
+	}
+	
+	result, err := NewTransformer().Compare(&a, &b, CompareOptions{})
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	if result.MaxDelta > 1 {
+		t.Errorf("Compare() max delta = %d, want at most 1", result.MaxDelta)
+	}
+}
\ No newline at end of file
@@ -15,6 +15,7 @@ type EdgeCropOptions struct {
 	Bottom CropValue
 	Left   CropValue
 	Right  CropValue
+	Lossless bool // For JPEG, snap the crop origin to the block grid to avoid re-encoding
 }
 
 // CropValue represents a crop value that can be pixels or percentage
@@ -62,6 +63,16 @@ func (cv CropValue) GetPixelValue(dimension int) int {
 	return cv.Value
 }
 
+// rect returns the area that remains after cropping an image of the given size
+func (o EdgeCropOptions) rect(width, height int) image.Rectangle {
+	return image.Rect(
+		o.Left.GetPixelValue(width),
+		o.Top.GetPixelValue(height),
+		width - o.Right.GetPixelValue(width),
+		height - o.Bottom.GetPixelValue(height),
+	)
+}
+
 // CropEdges crops the edges of an image based on the specified options
 func CropEdges(img image.Image, options EdgeCropOptions) (image.Image, error) {
 	bounds := img.Bounds()
@@ -6,14 +6,56 @@ import (
 	"strings"
 )
 
-// DimensionValue represents a dimension that can be either pixels or a multiplier
+// LengthUnit is a physical unit for print sizes
+type LengthUnit string
+
+const (
+	// LengthCentimeter is a length in centimeters
+	LengthCentimeter LengthUnit = "cm"
+	// LengthMillimeter is a length in millimeters
+	LengthMillimeter LengthUnit = "mm"
+	// LengthInch is a length in inches
+	LengthInch LengthUnit = "in"
+)
+
+// inches returns the length of one unit in inches
+func (u LengthUnit) inches() float64 {
+	switch u {
+	case LengthCentimeter:
+		return 1 / 2.54
+	case LengthMillimeter:
+		return 1 / 25.4
+	default:
+		return 1
+	}
+}
+
+// DimensionConstraint limits a dimension relative to the original size
+type DimensionConstraint string
+
+const (
+	// ConstraintNone resizes to the dimension as given
+	ConstraintNone DimensionConstraint = ""
+	// ShrinkOnly never makes the dimension larger than the original ("<=1920")
+	ShrinkOnly DimensionConstraint = "<="
+	// EnlargeOnly never makes the dimension smaller than the original (">=800")
+	EnlargeOnly DimensionConstraint = ">="
+)
+
+// DimensionValue represents a dimension that can be pixels, a multiplier or a
+// physical length
 type DimensionValue struct {
-	Value        int     // Pixel value when IsMultiplier is false
-	IsMultiplier bool    // Whether this is a multiplier
-	Multiplier   float64 // Multiplier value when IsMultiplier is true
+	Value        int                 // Pixel value when IsMultiplier is false
+	IsMultiplier bool                // Whether this is a multiplier
+	Multiplier   float64             // Multiplier value when IsMultiplier is true
+	Length       float64             // Physical length when Unit is set
+	Unit         LengthUnit          // Physical unit (empty = pixels or multiplier)
+	Constraint   DimensionConstraint // Shrink-only or enlarge-only limit
 }
 
-// ParseDimension parses a dimension string like "1920", "2x", "x2", "0.5x"
+// ParseDimension parses a dimension string like "1920", "2x", "x2", "0.5x", "50%",
+// "10cm", "210mm" or "4in", optionally prefixed with "<=" (shrink only) or ">="
+// (enlarge only)
 func ParseDimension(s string) (DimensionValue, error) {
 	if s == "" || s == "0" {
 		return DimensionValue{Value: 0}, nil
@@ -22,6 +64,50 @@ func ParseDimension(s string) (DimensionValue, error) {
 	s = strings.TrimSpace(s)
 	s = strings.ToLower(s)
 	
+	// Check for constraints: "<=1920", ">=800"
+	for _, constraint := range []DimensionConstraint{ShrinkOnly, EnlargeOnly} {
+		if rest, ok := strings.CutPrefix(s, string(constraint)); ok {
+			d, err := ParseDimension(strings.TrimSpace(rest))
+			if err != nil {
+				return DimensionValue{}, err
+			}
+			if d.IsZero() || d.Constraint != ConstraintNone {
+				return DimensionValue{}, fmt.Errorf("invalid constrained dimension: %s", s)
+			}
+			d.Constraint = constraint
+			return d, nil
+		}
+	}
+	
+	// Check for percentages: "50%", "150%"
+	if numStr, ok := strings.CutSuffix(s, "%"); ok {
+		percent, err := strconv.ParseFloat(strings.TrimSpace(numStr), 64)
+		if err != nil {
+			return DimensionValue{}, fmt.Errorf("invalid percentage value: %s", s)
+		}
+		if percent <= 0 {
+			return DimensionValue{}, fmt.Errorf("percentage must be positive: %s", s)
+		}
+		if percent > 1000 {
+			return DimensionValue{}, fmt.Errorf("percentage too large (max 1000%%): %s", s)
+		}
+		return DimensionValue{IsMultiplier: true, Multiplier: percent / 100}, nil
+	}
+	
+	// Check for physical lengths: "10cm", "210mm", "4in"
+	for _, unit := range []LengthUnit{LengthCentimeter, LengthMillimeter, LengthInch} {
+		if numStr, ok := strings.CutSuffix(s, string(unit)); ok {
+			length, err := strconv.ParseFloat(strings.TrimSpace(numStr), 64)
+			if err != nil {
+				return DimensionValue{}, fmt.Errorf("invalid length value: %s", s)
+			}
+			if length <= 0 {
+				return DimensionValue{}, fmt.Errorf("length must be positive: %s", s)
+			}
+			return DimensionValue{Length: length, Unit: unit}, nil
+		}
+	}
+	
 	// Check for multiplier formats: "2x", "x2", "2.5x", "x2.5"
 	if strings.Contains(s, "x") {
 		// Remove 'x' and get the number part
@@ -66,18 +152,47 @@ func ParseDimension(s string) (DimensionValue, error) {
 	}, nil
 }
 
-// Calculate returns the final pixel value based on the original size
+// IsPhysical reports whether the dimension is a physical length
+func (d DimensionValue) IsPhysical() bool {
+	return d.Unit != ""
+}
+
+// Resolve converts a physical length to pixels at the given DPI. Pixel values and
+// multipliers are returned unchanged.
+func (d DimensionValue) Resolve(dpi float64) DimensionValue {
+	if !d.IsPhysical() {
+		return d
+	}
+	return DimensionValue{Value: max(int(d.Length*d.Unit.inches()*dpi+0.5), 1), Constraint: d.Constraint}
+}
+
+// Calculate returns the final pixel value based on the original size, applying the
+// constraint. Physical lengths that were not resolved use 96 DPI.
 func (d DimensionValue) Calculate(originalSize int) int {
-	if d.IsMultiplier {
+	value := d.Value
+	if d.IsPhysical() {
+		value = d.Resolve(96).Value
+	} else if d.IsMultiplier {
 		result := float64(originalSize) * d.Multiplier
 		// Round to nearest integer
-		return int(result + 0.5)
+		value = int(result + 0.5)
+	}
+	
+	switch d.Constraint {
+	case ShrinkOnly:
+		return min(value, originalSize)
+	case EnlargeOnly:
+		return max(value, originalSize)
+	default:
+		return value
 	}
-	return d.Value
 }
 
 // IsZero returns true if the dimension is not set (zero value)
 func (d DimensionValue) IsZero() bool {
+	if d.IsPhysical() {
+		return d.Length == 0
+	}
 	if d.IsMultiplier {
 		return d.Multiplier == 0
 	}
@@ -86,12 +201,16 @@ func (d DimensionValue) IsZero() bool {
 
 // String returns the string representation of the dimension
 func (d DimensionValue) String() string {
+	prefix := string(d.Constraint)
+	if d.IsPhysical() {
+		return prefix + strconv.FormatFloat(d.Length, 'f', -1, 64) + string(d.Unit)
+	}
 	if d.IsMultiplier {
 		// Format multiplier with minimal decimal places
 		if d.Multiplier == float64(int(d.Multiplier)) {
-			return fmt.Sprintf("%dx", int(d.Multiplier))
+			return fmt.Sprintf("%s%dx", prefix, int(d.Multiplier))
 		}
-		return fmt.Sprintf("%.2fx", d.Multiplier)
+		return fmt.Sprintf("%s%.2fx", prefix, d.Multiplier)
 	}
-	return fmt.Sprintf("%d", d.Value)
+	return fmt.Sprintf("%s%d", prefix, d.Value)
 }
\ No newline at end of file
@@ -0,0 +1,170 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"testing"
+)
+
+func TestParseDimension(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    DimensionValue
+		wantErr bool
+	}{
+		{"1920", DimensionValue{Value: 1920}, false},
+		{"2x", DimensionValue{IsMultiplier: true, Multiplier: 2}, false},
+		{"x0.5", DimensionValue{IsMultiplier: true, Multiplier: 0.5}, false},
+		{"10cm", DimensionValue{Length: 10, Unit: LengthCentimeter}, false},
+		{"210mm", DimensionValue{Length: 210, Unit: LengthMillimeter}, false},
+		{"4IN", DimensionValue{Length: 4, Unit: LengthInch}, false},
+		{"2.5 cm", DimensionValue{Length: 2.5, Unit: LengthCentimeter}, false},
+		{"0cm", DimensionValue{}, true},
+		{"cm", DimensionValue{}, true},
+		{"-1in", DimensionValue{}, true},
+		{"10px", DimensionValue{}, true},
+		{"50%", DimensionValue{IsMultiplier: true, Multiplier: 0.5}, false},
+		{"150 %", DimensionValue{IsMultiplier: true, Multiplier: 1.5}, false},
+		{"<=1920", DimensionValue{Value: 1920, Constraint: ShrinkOnly}, false},
+		{">= 800", DimensionValue{Value: 800, Constraint: EnlargeOnly}, false},
+		{"<=10cm", DimensionValue{Length: 10, Unit: LengthCentimeter, Constraint: ShrinkOnly}, false},
+		{"0%", DimensionValue{}, true},
+		{"<=", DimensionValue{}, true},
+		{"<=>=100", DimensionValue{}, true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDimension(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDimension() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDimension() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateDimensionsConstraints(t *testing.T) {
+	dim := func(s string) DimensionValue {
+		d, err := ParseDimension(s)
+		if err != nil {
+			t.Fatalf("ParseDimension(%q) error = %v", s, err)
+		}
+		return d
+	}
+	
+	tests := []struct {
+		name                  string
+		srcWidth, srcHeight   int
+		opts                  ResizeOptions
+		wantWidth, wantHeight int
+	}{
+		{"percent", 1000, 500, ResizeOptions{WidthDim: dim("50%")}, 500, 250},
+		{"shrink only, larger", 4000, 3000, ResizeOptions{WidthDim: dim("<=1920")}, 1920, 1440},
+		{"shrink only, smaller", 1000, 750, ResizeOptions{WidthDim: dim("<=1920")}, 1000, 750},
+		{"enlarge only, smaller", 400, 300, ResizeOptions{WidthDim: dim(">=800")}, 800, 600},
+		{"enlarge only, larger", 1000, 750, ResizeOptions{WidthDim: dim(">=800")}, 1000, 750},
+		{"shrink only fit box", 1500, 1500, ResizeOptions{WidthDim: dim("<=1920"), HeightDim: dim("<=1080"), Mode: ResizeFit}, 1080, 1080},
+		{"shrink only fill", 1000, 400, ResizeOptions{WidthDim: dim("<=800"), HeightDim: dim("<=600"), Mode: ResizeFill}, 800, 400},
+		{"shrink only exact", 1000, 400, ResizeOptions{WidthDim: dim("<=800"), HeightDim: dim("<=600"), Mode: ResizeExact}, 800, 400},
+		{"pixel cap only", 6000, 4000, ResizeOptions{MaxPixels: 12_000_000}, 4242, 2828},
+		{"pixel cap under limit", 3000, 2000, ResizeOptions{MaxPixels: 12_000_000}, 3000, 2000},
+		{"pixel cap after enlarge", 1000, 1000, ResizeOptions{WidthDim: dim("4x"), MaxPixels: 4_000_000}, 2000, 2000},
+		{"pixel cap exact", 100, 100, ResizeOptions{Width: 4000, Height: 1000, Mode: ResizeExact, MaxPixels: 1_000_000}, 2000, 500},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height := CalculateDimensions(tt.srcWidth, tt.srcHeight, tt.opts)
+			if width != tt.wantWidth || height != tt.wantHeight {
+				t.Errorf("CalculateDimensions() = (%d, %d), want (%d, %d)", width, height, tt.wantWidth, tt.wantHeight)
+			}
+			if tt.opts.MaxPixels > 0 && int64(width)*int64(height) > tt.opts.MaxPixels {
+				t.Errorf("CalculateDimensions() = %d pixels, cap is %d", width*height, tt.opts.MaxPixels)
+			}
+		})
+	}
+}
+
+func TestDimensionResolve(t *testing.T) {
+	tests := []struct {
+		name string
+		dim  DimensionValue
+		dpi  float64
+		want int
+	}{
+		{"10cm at 300 DPI", DimensionValue{Length: 10, Unit: LengthCentimeter}, 300, 1181},
+		{"A4 width at 300 DPI", DimensionValue{Length: 210, Unit: LengthMillimeter}, 300, 2480},
+		{"4in at 150 DPI", DimensionValue{Length: 4, Unit: LengthInch}, 150, 600},
+		{"Pixels are unchanged", DimensionValue{Value: 640}, 300, 640},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dim.Resolve(tt.dpi).Calculate(1000); got != tt.want {
+				t.Errorf("Resolve(%v).Calculate() = %d, want %d", tt.dpi, got, tt.want)
+			}
+		})
+	}
+	
+	if got := (DimensionValue{IsMultiplier: true, Multiplier: 2}).Resolve(300).Calculate(100); got != 200 {
+		t.Errorf("multiplier Resolve().Calculate() = %d, want 200", got)
+	}
+}
+
+func TestConvertPhysicalSize(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2000, 1000))
+	inputBuf := &bytes.Buffer{}
+	if err := jpeg.Encode(inputBuf, img, nil); err != nil {
+		t.Fatalf("Failed to encode JPEG: %v", err)
+	}
+	source, err := SetJPEGResolution(inputBuf.Bytes(), SquareDPI(200))
+	if err != nil {
+		t.Fatal(err)
+	}
+	
+	tests := []struct {
+		name           string
+		resolution     Resolution
+		wantWidth      int
+		wantHeight     int
+		wantResolution Resolution
+	}{
+		{"Target DPI", SquareDPI(300), 1181, 591, SquareDPI(300)},
+		{"Target non-square DPI", Resolution{X: 300, Y: 150}, 1181, 295, Resolution{X: 300, Y: 150, Unit: UnitInch}},
+		{"Target dpcm", Resolution{X: 50, Y: 50, Unit: UnitCentimeter}, 500, 250, Resolution{X: 50, Y: 50, Unit: UnitCentimeter}},
+		{"Image's own DPI is kept", Resolution{}, 787, 394, SquareDPI(200)},
+	}
+	
+	transformer := NewTransformer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputBuf := &bytes.Buffer{}
+			err := transformer.Convert(bytes.NewReader(source), outputBuf, ConvertOptions{
+				Resize: &ResizeOptions{
+					WidthDim:  DimensionValue{Length: 10, Unit: LengthCentimeter},
+					HeightDim: DimensionValue{Length: 50, Unit: LengthMillimeter},
+					Mode:      ResizeExact,
+				},
+				Resolution: tt.resolution,
+			})
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			
+			result, err := jpeg.DecodeConfig(bytes.NewReader(outputBuf.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Width != tt.wantWidth || result.Height != tt.wantHeight {
+				t.Errorf("Convert() size = %dx%d, want %dx%d", result.Width, result.Height, tt.wantWidth, tt.wantHeight)
+			}
+			if got, _ := GetImageResolution(outputBuf, FormatJPEG); got != tt.wantResolution {
+				t.Errorf("output resolution = %v, want %v", got, tt.wantResolution)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -8,6 +8,8 @@ import (
 	"image/jpeg"
 	"image/png"
 	"io"
+	
+	"github.com/allieus/imagekit/pkg/container"
 )
 
 const (
@@ -21,263 +23,267 @@ const (
 	inchesToMeters = 0.0254
 )
 
+// jfifIdentifier starts the payload of a JFIF APP0 segment
+const jfifIdentifier = "JFIF\x00"
+
+// JFIF density units
+const (
+	jfifUnitsNone       = 0
+	jfifUnitsInch       = 1
+	jfifUnitsCentimeter = 2
+)
+
+// EXIF ResolutionUnit values
+const (
+	exifUnitNone       = 1
+	exifUnitInch       = 2
+	exifUnitCentimeter = 3
+)
+
 // SetJPEGDPI sets DPI for JPEG images by modifying JFIF header
 func SetJPEGDPI(data []byte, dpi int) ([]byte, error) {
-	// JPEG files start with SOI marker (0xFFD8)
-	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
-		return nil, fmt.Errorf("not a valid JPEG file")
+	return SetJPEGResolution(data, SquareDPI(dpi))
+}
+
+// SetJPEGResolution sets the resolution in the JFIF header, inserting one if needed,
+// and updates the EXIF resolution tags when present so both agree
+func SetJPEGResolution(data []byte, r Resolution) ([]byte, error) {
+	file, err := container.ParseJPEG(data)
+	if err != nil {
+		return nil, fmt.Errorf("not a valid JPEG file: %w", err)
 	}
 	
-	// Look for JFIF APP0 marker (0xFFE0)
-	index := 2
-	for index < len(data)-10 {
-		if data[index] == 0xFF && data[index+1] == 0xE0 {
-			// Found APP0 marker
-			length := int(data[index+2])<<8 | int(data[index+3])
-			
-			// Check for JFIF identifier
-			if index+4+5 < len(data) &&
-				data[index+4] == 'J' &&
-				data[index+5] == 'F' &&
-				data[index+6] == 'I' &&
-				data[index+7] == 'F' &&
-				data[index+8] == 0 {
-				
-				// JFIF header found
-				// Density units: 0=no units, 1=dots/inch, 2=dots/cm
-				data[index+11] = 1 // Set units to dots/inch
-				
-				// Set X and Y density (DPI)
-				binary.BigEndian.PutUint16(data[index+12:], uint16(dpi))
-				binary.BigEndian.PutUint16(data[index+14:], uint16(dpi))
-				
-				return data, nil
-			}
-			index += 2 + length
-		} else if data[index] == 0xFF {
-			index += 2
-		} else {
-			index++
+	// JFIF payload: identifier (5), version (2), units (1), X density (2), Y density (2)
+	index := file.Find(container.MarkerAPP0, jfifIdentifier)
+	if index >= 0 && len(file.Segments[index].Data) >= 12 {
+		jfif := append([]byte(nil), file.Segments[index].Data...)
+		copy(jfif[7:12], jfifDensity(r))
+		file.Replace(index, container.Segment{Marker: container.MarkerAPP0, Data: jfif})
+	} else {
+		// Replace a truncated JFIF header, or insert one right after SOI
+		if index >= 0 {
+			file.Remove(index)
 		}
+		file.Insert(0, jfifSegment(r))
 	}
 	
-	// If no JFIF header found, we need to insert one
-	return insertJFIFHeader(data, dpi)
+	if index := file.Find(container.MarkerAPP1, exifHeader); index >= 0 {
+		exif := append([]byte(nil), file.Segments[index].Data...)
+		if ifd, ok := parseExifIFD0(exif); ok {
+			setExifResolution(ifd, r)
+			file.Replace(index, container.Segment{Marker: container.MarkerAPP1, Data: exif})
+		}
+	}
+	return file.Bytes()
+}
+
+// jfifDensity returns the units and X/Y density fields of a JFIF header
+func jfifDensity(r Resolution) []byte {
+	density := make([]byte, 5)
+	density[0] = jfifUnitsInch
+	if r.Unit == UnitCentimeter {
+		density[0] = jfifUnitsCentimeter
+	}
+	binary.BigEndian.PutUint16(density[1:], clampUint16(r.X))
+	binary.BigEndian.PutUint16(density[3:], clampUint16(r.Y))
+	return density
 }
 
-// insertJFIFHeader inserts a JFIF APP0 segment with DPI information
-func insertJFIFHeader(data []byte, dpi int) ([]byte, error) {
-	// Create JFIF APP0 segment
-	jfif := []byte{
-		0xFF, 0xE0, // APP0 marker
-		0x00, 0x10, // Length (16 bytes)
+// jfifSegment creates a JFIF APP0 segment with resolution information
+func jfifSegment(r Resolution) container.Segment {
+	data := []byte{
 		'J', 'F', 'I', 'F', 0x00, // JFIF identifier
 		0x01, 0x01, // Version 1.1
-		0x01,       // Units: dots/inch
-		byte(dpi >> 8), byte(dpi), // X density
-		byte(dpi >> 8), byte(dpi), // Y density
-		0x00, 0x00, // No thumbnail
 	}
-	
-	// Insert after SOI marker
-	result := make([]byte, 0, len(data)+len(jfif))
-	result = append(result, data[:2]...) // SOI marker
-	result = append(result, jfif...)      // JFIF header
-	result = append(result, data[2:]...)  // Rest of the file
-	
-	return result, nil
+	data = append(data, jfifDensity(r)...)
+	data = append(data, 0x00, 0x00) // No thumbnail
+	return container.Segment{Marker: container.MarkerAPP0, Data: data}
+}
+
+// setExifResolution updates the resolution tags that exist in the EXIF IFD. Without a
+// ResolutionUnit tag readers assume inches, so the values are converted to DPI.
+func setExifResolution(ifd *exifIFD0, r Resolution) {
+	unit := exifUnitInch
+	x, y := r.DPI()
+	if r.Unit == UnitCentimeter {
+		unit = exifUnitCentimeter
+		x, y = r.X, r.Y
+	}
+	if !ifd.setShort(exifTagResolutionUnit, unit) {
+		x, y = r.DPI()
+	}
+	ifd.setRational(exifTagXResolution, x)
+	ifd.setRational(exifTagYResolution, y)
 }
 
 // SetPNGDPI sets DPI for PNG images by modifying pHYs chunk
 func SetPNGDPI(data []byte, dpi int) ([]byte, error) {
-	// PNG signature
-	pngSignature := []byte{137, 80, 78, 71, 13, 10, 26, 10}
-	
-	if len(data) < 8 || !bytes.Equal(data[:8], pngSignature) {
-		return nil, fmt.Errorf("not a valid PNG file")
-	}
-	
-	// Calculate pixels per meter from DPI
-	pixelsPerMeter := uint32(float64(dpi) / inchesToMeters)
-	
-	// Create pHYs chunk
-	phys := createPHYsChunk(pixelsPerMeter, pixelsPerMeter)
-	
-	// Find IDAT chunk and insert pHYs before it
-	index := 8
-	result := make([]byte, 0, len(data)+len(phys))
-	result = append(result, data[:8]...) // PNG signature
-	
-	inserted := false
-	for index < len(data) {
-		// Read chunk length
-		if index+8 > len(data) {
-			break
-		}
-		
-		length := binary.BigEndian.Uint32(data[index : index+4])
-		chunkType := string(data[index+4 : index+8])
-		
-		// Insert pHYs before IDAT
-		if !inserted && chunkType == "IDAT" {
-			result = append(result, phys...)
-			inserted = true
-		}
-		
-		// Skip existing pHYs chunks
-		if chunkType != "pHYs" {
-			chunkEnd := index + 12 + int(length) // 4 (length) + 4 (type) + length + 4 (CRC)
-			if chunkEnd > len(data) {
-				break
-			}
-			result = append(result, data[index:chunkEnd]...)
-		}
-		
-		index += 12 + int(length)
+	return SetPNGResolution(data, SquareDPI(dpi))
+}
+
+// SetPNGResolution sets the resolution in the pHYs chunk, which PNG stores in pixels
+// per meter
+func SetPNGResolution(data []byte, r Resolution) ([]byte, error) {
+	file, err := container.ParsePNG(data)
+	if err != nil {
+		return nil, fmt.Errorf("not a valid PNG file: %w", err)
 	}
 	
-	// If no IDAT found, append pHYs at the end (before IEND)
-	if !inserted && len(result) > 0 {
-		// Find IEND and insert before it
-		iendIndex := bytes.Index(result, []byte("IEND"))
-		if iendIndex > 0 {
-			newResult := make([]byte, 0, len(result)+len(phys))
-			newResult = append(newResult, result[:iendIndex-4]...)
-			newResult = append(newResult, phys...)
-			newResult = append(newResult, result[iendIndex-4:]...)
-			return newResult, nil
-		}
+	// pHYs must come before the first IDAT chunk
+	file.RemoveAll("pHYs")
+	index := file.Find("IDAT")
+	if index < 0 {
+		index = len(file.Chunks) - 1 // Before IEND
 	}
-	
-	return result, nil
+	file.Insert(index, container.Chunk{Type: "pHYs", Data: physData(r.pixelsPerMeter())})
+	return file.Bytes(), nil
 }
 
-// createPHYsChunk creates a PNG pHYs chunk with the given resolution
-func createPHYsChunk(xPixelsPerMeter, yPixelsPerMeter uint32) []byte {
+// physData creates the payload of a PNG pHYs chunk with the given resolution
+func physData(xPixelsPerMeter, yPixelsPerMeter uint32) []byte {
 	data := make([]byte, 9)
 	binary.BigEndian.PutUint32(data[0:4], xPixelsPerMeter)
 	binary.BigEndian.PutUint32(data[4:8], yPixelsPerMeter)
 	data[8] = 1 // Unit: meter
-	
-	// Create chunk
-	chunk := make([]byte, 0, 21)
-	chunk = append(chunk, 0, 0, 0, 9) // Length
-	chunk = append(chunk, 'p', 'H', 'Y', 's') // Type
-	chunk = append(chunk, data...) // Data
-	
-	// Calculate CRC
-	crc := calculateCRC(append([]byte("pHYs"), data...))
-	crcBytes := make([]byte, 4)
-	binary.BigEndian.PutUint32(crcBytes, crc)
-	chunk = append(chunk, crcBytes...)
-	
-	return chunk
+	return data
 }
 
-// calculateCRC calculates CRC32 for PNG chunk
-func calculateCRC(data []byte) uint32 {
-	// CRC32 table for PNG
-	var crcTable [256]uint32
-	for i := 0; i < 256; i++ {
-		c := uint32(i)
-		for j := 0; j < 8; j++ {
-			if c&1 == 1 {
-				c = 0xEDB88320 ^ (c >> 1)
-			} else {
-				c = c >> 1
-			}
-		}
-		crcTable[i] = c
-	}
-	
-	crc := uint32(0xFFFFFFFF)
-	for _, b := range data {
-		crc = crcTable[(crc^uint32(b))&0xFF] ^ (crc >> 8)
+// GetImageDPI extracts DPI information from an image, rounding the horizontal
+// resolution to whole dots per inch
+func GetImageDPI(r io.Reader, format ImageFormat) (int, error) {
+	resolution, err := GetImageResolution(r, format)
+	if err != nil {
+		return 0, err
 	}
-	return ^crc
+	x, _ := resolution.DPI()
+	return int(x + 0.5), nil
 }
 
-// GetImageDPI extracts DPI information from an image
-func GetImageDPI(r io.Reader, format ImageFormat) (int, error) {
+// GetImageResolution extracts the resolution from an image. JPEG files are checked
+// for EXIF tags first, then the JFIF header. Images without one report 96 DPI.
+func GetImageResolution(r io.Reader, format ImageFormat) (Resolution, error) {
 	// Read all data
-	buf := new(bytes.Buffer)
-	if _, err := io.Copy(buf, r); err != nil {
-		return 0, err
+	data, err := DefaultLimits.read(r)
+	if err != nil {
+		return Resolution{}, err
 	}
-	data := buf.Bytes()
 	
 	switch format {
 	case FormatJPEG:
-		return getJPEGDPI(data)
+		return getJPEGResolution(data)
 	case FormatPNG:
-		return getPNGDPI(data)
+		return getPNGResolution(data)
 	default:
-		return 96, nil // Default DPI
+		return SquareDPI(96), nil // Default DPI
+	}
+}
+
+// imageResolution returns the resolution stored in JPEG or PNG data, or 96 DPI
+func imageResolution(data []byte) Resolution {
+	if r, err := getJPEGResolution(data); err == nil {
+		return r
 	}
+	if r, err := getPNGResolution(data); err == nil {
+		return r
+	}
+	return SquareDPI(96)
 }
 
-// getJPEGDPI extracts DPI from JPEG JFIF header
+// getJPEGDPI extracts DPI from JPEG EXIF or JFIF header
 func getJPEGDPI(data []byte) (int, error) {
-	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
-		return 0, fmt.Errorf("not a valid JPEG file")
+	resolution, err := getJPEGResolution(data)
+	if err != nil {
+		return 0, err
+	}
+	x, _ := resolution.DPI()
+	return int(x + 0.5), nil
+}
+
+// getJPEGResolution extracts the resolution from the EXIF tags or the JFIF header
+func getJPEGResolution(data []byte) (Resolution, error) {
+	file, err := container.ParseJPEG(data)
+	if err != nil {
+		return Resolution{}, fmt.Errorf("not a valid JPEG file: %w", err)
 	}
 	
-	index := 2
-	for index < len(data)-10 {
-		if data[index] == 0xFF && data[index+1] == 0xE0 {
-			// Found APP0 marker
-			if index+14 < len(data) &&
-				data[index+4] == 'J' &&
-				data[index+5] == 'F' &&
-				data[index+6] == 'I' &&
-				data[index+7] == 'F' &&
-				data[index+8] == 0 {
-				
-				// Check density units
-				if data[index+11] == 1 { // dots/inch
-					xDPI := binary.BigEndian.Uint16(data[index+12:])
-					return int(xDPI), nil
-				}
+	// Cameras write EXIF resolution and often no JFIF header at all
+	if index := file.Find(container.MarkerAPP1, exifHeader); index >= 0 {
+		if ifd, ok := parseExifIFD0(file.Segments[index].Data); ok {
+			if r, ok := exifResolution(ifd); ok {
+				return r, nil
 			}
 		}
-		index++
 	}
 	
-	return 96, nil // Default DPI if not found
+	if index := file.Find(container.MarkerAPP0, jfifIdentifier); index >= 0 {
+		jfif := file.Segments[index].Data
+		if len(jfif) >= 12 && jfif[7] != jfifUnitsNone {
+			r := Resolution{
+				X:    float64(binary.BigEndian.Uint16(jfif[8:])),
+				Y:    float64(binary.BigEndian.Uint16(jfif[10:])),
+				Unit: UnitInch,
+			}
+			if jfif[7] == jfifUnitsCentimeter {
+				r.Unit = UnitCentimeter
+			}
+			if !r.IsZero() {
+				return r, nil
+			}
+		}
+	}
+	
+	return SquareDPI(96), nil // Default DPI if not found
+}
+
+// exifResolution reads the EXIF XResolution, YResolution and ResolutionUnit tags
+func exifResolution(ifd *exifIFD0) (Resolution, bool) {
+	x, okX := ifd.rational(exifTagXResolution)
+	y, okY := ifd.rational(exifTagYResolution)
+	if !okX || !okY {
+		return Resolution{}, false
+	}
+	
+	r := Resolution{X: x, Y: y, Unit: UnitInch}
+	unit, ok := ifd.short(exifTagResolutionUnit)
+	switch {
+	case !ok || unit == exifUnitInch:
+	case unit == exifUnitCentimeter:
+		r.Unit = UnitCentimeter
+	default:
+		return Resolution{}, false // Aspect ratio only
+	}
+	return r, !r.IsZero()
 }
 
 // getPNGDPI extracts DPI from PNG pHYs chunk
 func getPNGDPI(data []byte) (int, error) {
-	pngSignature := []byte{137, 80, 78, 71, 13, 10, 26, 10}
-	
-	if len(data) < 8 || !bytes.Equal(data[:8], pngSignature) {
-		return 0, fmt.Errorf("not a valid PNG file")
+	resolution, err := getPNGResolution(data)
+	if err != nil {
+		return 0, err
+	}
+	x, _ := resolution.DPI()
+	return int(x + 0.5), nil
+}
+
+// getPNGResolution extracts the resolution from the PNG pHYs chunk
+func getPNGResolution(data []byte) (Resolution, error) {
+	file, err := container.ParsePNG(data)
+	if err != nil {
+		return Resolution{}, fmt.Errorf("not a valid PNG file: %w", err)
 	}
 	
-	index := 8
-	for index < len(data) {
-		if index+8 > len(data) {
-			break
-		}
-		
-		length := binary.BigEndian.Uint32(data[index : index+4])
-		chunkType := string(data[index+4 : index+8])
-		
-		if chunkType == "pHYs" && index+12+9 <= len(data) {
-			// Found pHYs chunk
-			xPixelsPerMeter := binary.BigEndian.Uint32(data[index+8 : index+12])
-			unit := data[index+16]
-			
-			if unit == 1 { // meter
-				dpi := int(float64(xPixelsPerMeter) * inchesToMeters)
-				return dpi, nil
+	index := file.Find("pHYs")
+	if index >= 0 {
+		phys := file.Chunks[index].Data
+		if len(phys) == 9 && phys[8] == 1 { // meter
+			r := resolutionFromPixelsPerMeter(binary.BigEndian.Uint32(phys[0:4]), binary.BigEndian.Uint32(phys[4:8]))
+			if !r.IsZero() {
+				return r, nil
 			}
 		}
-		
-		index += 12 + int(length)
 	}
 	
-	return 96, nil // Default DPI if not found
+	return SquareDPI(96), nil // Default DPI if not found
 }
 
 // ConvertDPIValue converts between different DPI units
@@ -310,8 +316,20 @@ func ConvertDPIValue(value float64, fromUnit, toUnit string) (float64, error) {
 
 // ProcessImageWithDPI processes an image and sets its DPI
 func ProcessImageWithDPI(r io.Reader, w io.Writer, format ImageFormat, dpi int) error {
+	return ProcessImageWithResolution(r, w, format, SquareDPI(dpi))
+}
+
+// ProcessImageWithResolution re-encodes an image and sets its resolution
+func ProcessImageWithResolution(r io.Reader, w io.Writer, format ImageFormat, resolution Resolution) error {
 	// Read image
-	img, _, err := image.Decode(r)
+	data, err := DefaultLimits.read(r)
+	if err != nil {
+		return fmt.Errorf("failed to read image data: %w", err)
+	}
+	if err := DefaultLimits.Check(data); err != nil {
+		return err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
 	if err != nil {
 		return fmt.Errorf("failed to decode image: %w", err)
 	}
@@ -332,12 +350,12 @@ func ProcessImageWithDPI(r io.Reader, w io.Writer, format ImageFormat, dpi int)
 	}
 	
 	// Modify DPI in encoded data
-	data := buf.Bytes()
+	data = buf.Bytes()
 	switch format {
 	case FormatJPEG:
-		data, err = SetJPEGDPI(data, dpi)
+		data, err = SetJPEGResolution(data, resolution)
 	case FormatPNG:
-		data, err = SetPNGDPI(data, dpi)
+		data, err = SetPNGResolution(data, resolution)
 	}
 	
 	if err != nil {
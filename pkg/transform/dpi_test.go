@@ -2,10 +2,13 @@ package transform
 
 import (
 	"bytes"
+	"encoding/binary"
 	"image"
 	"image/jpeg"
 	"image/png"
 	"testing"
+	
+	"github.com/allieus/imagekit/pkg/container"
 )
 
 func TestProcessImageWithDPI(t *testing.T) {
@@ -245,4 +248,215 @@ func TestProcessImageWithDPIEdgeCases(t *testing.T) {
 			}
 		})
 	}
+}
+
+func TestJPEGDPIIgnoresMarkersInScanData(t *testing.T) {
+	inputBuf := &bytes.Buffer{}
+	if err := jpeg.Encode(inputBuf, image.NewGray(image.Rect(0, 0, 16, 16)), nil); err != nil {
+		t.Fatalf("Failed to encode JPEG: %v", err)
+	}
+	data := inputBuf.Bytes()
+	
+	// A JFIF header claiming 999 DPI placed inside the entropy-coded data before EOI
+	fake := []byte{0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0, 1, 1, 1, 0x03, 0xE7, 0x03, 0xE7, 0, 0}
+	data = append(append(append([]byte{}, data[:len(data)-2]...), fake...), 0xFF, 0xD9)
+	
+	dpi, err := getJPEGDPI(data)
+	if err != nil || dpi != 96 {
+		t.Fatalf("getJPEGDPI() = %d, %v; want the default 96", dpi, err)
+	}
+	
+	result, err := SetJPEGDPI(data, 300)
+	if err != nil {
+		t.Fatalf("SetJPEGDPI() error = %v", err)
+	}
+	if dpi, _ := getJPEGDPI(result); dpi != 300 {
+		t.Errorf("getJPEGDPI() after SetJPEGDPI = %d, want 300", dpi)
+	}
+	if !bytes.Contains(result, fake) {
+		t.Error("SetJPEGDPI() modified the scan data")
+	}
+}
+
+func TestPNGDPIRoundTrip(t *testing.T) {
+	inputBuf := &bytes.Buffer{}
+	if err := png.Encode(inputBuf, image.NewGray(image.Rect(0, 0, 16, 16))); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+	
+	data := inputBuf.Bytes()
+	for _, dpi := range []int{72, 300, 150} {
+		var err error
+		data, err = SetPNGDPI(data, dpi)
+		if err != nil {
+			t.Fatalf("SetPNGDPI(%d) error = %v", dpi, err)
+		}
+		if got, _ := getPNGDPI(data); got != dpi {
+			t.Errorf("getPNGDPI() = %d, want %d", got, dpi)
+		}
+	}
+	if count := bytes.Count(data, []byte("pHYs")); count != 1 {
+		t.Errorf("PNG has %d pHYs chunks, want 1", count)
+	}
+	if _, err := png.Decode(bytes.NewReader(data)); err != nil {
+		t.Errorf("PNG with DPI does not decode: %v", err)
+	}
+	
+	// Corrupt chunks are reported instead of silently dropped
+	corrupt := append([]byte{}, data...)
+	corrupt[len(corrupt)-20] ^= 0xFF
+	if _, err := SetPNGDPI(corrupt, 96); err == nil {
+		t.Error("SetPNGDPI() accepted a PNG with a bad CRC")
+	}
+}
+
+// cameraJPEG returns a JPEG without a JFIF header whose little-endian EXIF block stores
+// the given resolution, like a typical camera file. A unit of 0 omits ResolutionUnit.
+func cameraJPEG(t *testing.T, x, y uint32, unit uint16) []byte {
+	t.Helper()
+	inputBuf := &bytes.Buffer{}
+	if err := jpeg.Encode(inputBuf, image.NewGray(image.Rect(0, 0, 16, 16)), nil); err != nil {
+		t.Fatalf("Failed to encode JPEG: %v", err)
+	}
+	
+	entries := []uint16{exifTagXResolution, exifTagYResolution}
+	if unit > 0 {
+		entries = append(entries, exifTagResolutionUnit)
+	}
+	tiff := []byte("II\x2a\x00\x08\x00\x00\x00")
+	tiff = binary.LittleEndian.AppendUint16(tiff, uint16(len(entries)))
+	values := 8 + 2 + len(entries)*12 + 4 // Rationals follow the IFD
+	for i, tag := range entries {
+		tiff = binary.LittleEndian.AppendUint16(tiff, tag)
+		if tag == exifTagResolutionUnit {
+			tiff = binary.LittleEndian.AppendUint16(tiff, exifTypeShort)
+			tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+			tiff = binary.LittleEndian.AppendUint32(tiff, uint32(unit))
+			continue
+		}
+		tiff = binary.LittleEndian.AppendUint16(tiff, exifTypeRational)
+		tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+		tiff = binary.LittleEndian.AppendUint32(tiff, uint32(values+i*8))
+	}
+	tiff = binary.LittleEndian.AppendUint32(tiff, 0)
+	for _, v := range []uint32{x, y} {
+		tiff = binary.LittleEndian.AppendUint32(tiff, v)
+		tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	}
+	
+	file, err := container.ParseJPEG(inputBuf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Insert(0, container.Segment{Marker: container.MarkerAPP1, Data: append([]byte(exifHeader), tiff...)})
+	data, err := file.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestJPEGResolutionFromEXIF(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want Resolution
+	}{
+		{"Inches", cameraJPEG(t, 300, 300, exifUnitInch), Resolution{X: 300, Y: 300, Unit: UnitInch}},
+		{"Non-square", cameraJPEG(t, 300, 150, exifUnitInch), Resolution{X: 300, Y: 150, Unit: UnitInch}},
+		{"Centimeters", cameraJPEG(t, 118, 118, exifUnitCentimeter), Resolution{X: 118, Y: 118, Unit: UnitCentimeter}},
+		{"Missing unit means inches", cameraJPEG(t, 240, 240, 0), Resolution{X: 240, Y: 240, Unit: UnitInch}},
+		{"No absolute unit", cameraJPEG(t, 1, 1, exifUnitNone), SquareDPI(96)},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetImageResolution(bytes.NewReader(tt.data), FormatJPEG)
+			if err != nil {
+				t.Fatalf("GetImageResolution() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetImageResolution() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetJPEGResolutionUpdatesEXIF(t *testing.T) {
+	tests := []struct {
+		name       string
+		unit       uint16
+		resolution Resolution
+		wantEXIF   Resolution
+	}{
+		{"Non-square DPI", exifUnitInch, Resolution{X: 300, Y: 150, Unit: UnitInch}, Resolution{X: 300, Y: 150, Unit: UnitInch}},
+		{"Centimeters", exifUnitInch, Resolution{X: 118, Y: 59, Unit: UnitCentimeter}, Resolution{X: 118, Y: 59, Unit: UnitCentimeter}},
+		{"Centimeters without a unit tag", 0, Resolution{X: 100, Y: 100, Unit: UnitCentimeter}, SquareDPI(254)},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := SetJPEGResolution(cameraJPEG(t, 72, 72, tt.unit), tt.resolution)
+			if err != nil {
+				t.Fatalf("SetJPEGResolution() error = %v", err)
+			}
+			
+			file, err := container.ParseJPEG(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ifd, ok := parseExifIFD0(file.Segments[file.Find(container.MarkerAPP1, exifHeader)].Data)
+			if !ok {
+				t.Fatal("EXIF segment was lost")
+			}
+			if got, _ := exifResolution(ifd); got != tt.wantEXIF {
+				t.Errorf("EXIF resolution = %v, want %v", got, tt.wantEXIF)
+			}
+			
+			// The JFIF header is written in the requested unit
+			jfif := file.Segments[file.Find(container.MarkerAPP0, jfifIdentifier)].Data
+			x, y := binary.BigEndian.Uint16(jfif[8:]), binary.BigEndian.Uint16(jfif[10:])
+			if x != uint16(tt.resolution.X) || y != uint16(tt.resolution.Y) {
+				t.Errorf("JFIF density = %dx%d, want %v", x, y, tt.resolution)
+			}
+			if _, err := jpeg.Decode(bytes.NewReader(data)); err != nil {
+				t.Errorf("JPEG with resolution does not decode: %v", err)
+			}
+		})
+	}
+}
+
+func TestResolutionRoundTrip(t *testing.T) {
+	resolutions := []Resolution{
+		SquareDPI(300),
+		{X: 300, Y: 150, Unit: UnitInch},
+		{X: 118, Y: 118, Unit: UnitCentimeter},
+		{X: 40, Y: 80, Unit: UnitCentimeter},
+	}
+	img := image.NewGray(image.Rect(0, 0, 16, 16))
+	
+	for _, resolution := range resolutions {
+		t.Run(resolution.String(), func(t *testing.T) {
+			for _, format := range []ImageFormat{FormatJPEG, FormatPNG} {
+				inputBuf := &bytes.Buffer{}
+				if format == FormatJPEG {
+					_ = jpeg.Encode(inputBuf, img, nil)
+				} else {
+					_ = png.Encode(inputBuf, img)
+				}
+				
+				outputBuf := &bytes.Buffer{}
+				if err := ProcessImageWithResolution(inputBuf, outputBuf, format, resolution); err != nil {
+					t.Fatalf("ProcessImageWithResolution(%s) error = %v", format, err)
+				}
+				got, err := GetImageResolution(outputBuf, format)
+				if err != nil {
+					t.Fatalf("GetImageResolution(%s) error = %v", format, err)
+				}
+				if got.String() != resolution.String() {
+					t.Errorf("%s resolution = %v, want %v", format, got, resolution)
+				}
+			}
+		})
+	}
 }
\ No newline at end of file
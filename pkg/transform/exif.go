@@ -0,0 +1,187 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/binary"
+	
+	"github.com/allieus/imagekit/pkg/container"
+)
+
+// EXIF tags of the first IFD used by the transformer
+const (
+	exifTagOrientation    = 0x0112
+	exifTagXResolution    = 0x011A
+	exifTagYResolution    = 0x011B
+	exifTagResolutionUnit = 0x0128
+)
+
+// EXIF field types
+const (
+	exifTypeShort    = 3
+	exifTypeRational = 5
+)
+
+// exifHeader starts the payload of an EXIF APP1 segment
+const exifHeader = "Exif\x00\x00"
+
+// exifIFD0 is the first image file directory of an EXIF segment. Offsets are relative
+// to the start of the segment payload, so values can be patched in place.
+type exifIFD0 struct {
+	data    []byte // Segment payload, starting with exifHeader
+	order   binary.ByteOrder
+	entries int // Offset of the first 12-byte entry
+	count   int
+}
+
+// jpegOrientation reads the EXIF orientation of a JPEG from its APP1 segment, 1 when
+// absent
+func jpegOrientation(data []byte) int {
+	file, err := container.ParseJPEG(data)
+	if err != nil {
+		return 1
+	}
+	index := file.Find(container.MarkerAPP1, exifHeader)
+	if index < 0 {
+		return 1
+	}
+	orientation, _, _ := parseOrientation(file.Segments[index].Data)
+	return orientation
+}
+
+// parseOrientation reads the orientation tag of an EXIF APP1 payload. It returns the
+// orientation (1 when absent or invalid), the offset of the tag value (-1 when absent)
+// and false when the payload isn't EXIF.
+func parseOrientation(data []byte) (int, int, bool) {
+	ifd, ok := parseExifIFD0(data)
+	if !ok {
+		return 1, -1, false
+	}
+	offset := ifd.shortOffset(exifTagOrientation)
+	if offset < 0 {
+		return 1, -1, true
+	}
+	orientation := int(ifd.order.Uint16(data[offset:]))
+	if orientation < 1 || orientation > 8 {
+		orientation = 1
+	}
+	return orientation, offset, true
+}
+
+// parseExifIFD0 locates the first IFD of an EXIF APP1 payload
+func parseExifIFD0(data []byte) (*exifIFD0, bool) {
+	if !bytes.HasPrefix(data, []byte(exifHeader)) || len(data) < len(exifHeader)+8 {
+		return nil, false
+	}
+	tiff := data[len(exifHeader):]
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, false
+	}
+	
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return nil, false
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	entries := len(exifHeader) + ifd + 2
+	count = min(count, (len(data)-entries)/12)
+	return &exifIFD0{data: data, order: order, entries: entries, count: count}, true
+}
+
+// entry returns the offset of the entry with the given tag and type, or -1
+func (e *exifIFD0) entry(tag, fieldType uint16) int {
+	for i := 0; i < e.count; i++ {
+		offset := e.entries + i*12
+		if e.order.Uint16(e.data[offset:]) == tag {
+			if e.order.Uint16(e.data[offset+2:]) != fieldType || e.order.Uint32(e.data[offset+4:]) != 1 {
+				return -1
+			}
+			return offset
+		}
+	}
+	return -1
+}
+
+// shortOffset returns the offset of a SHORT tag value, or -1
+func (e *exifIFD0) shortOffset(tag uint16) int {
+	offset := e.entry(tag, exifTypeShort)
+	if offset < 0 {
+		return -1
+	}
+	return offset + 8
+}
+
+// short reads a SHORT tag
+func (e *exifIFD0) short(tag uint16) (int, bool) {
+	offset := e.shortOffset(tag)
+	if offset < 0 {
+		return 0, false
+	}
+	return int(e.order.Uint16(e.data[offset:])), true
+}
+
+// setShort writes a SHORT tag in place, reporting whether the tag exists
+func (e *exifIFD0) setShort(tag uint16, value int) bool {
+	offset := e.shortOffset(tag)
+	if offset < 0 {
+		return false
+	}
+	e.order.PutUint16(e.data[offset:], uint16(value))
+	return true
+}
+
+// rationalOffset returns the offset of a RATIONAL tag value, which is stored
+// outside the entry, or -1
+func (e *exifIFD0) rationalOffset(tag uint16) int {
+	offset := e.entry(tag, exifTypeRational)
+	if offset < 0 {
+		return -1
+	}
+	value := len(exifHeader) + int(e.order.Uint32(e.data[offset+8:]))
+	if value < len(exifHeader) || value+8 > len(e.data) {
+		return -1
+	}
+	return value
+}
+
+// rational reads a RATIONAL tag
+func (e *exifIFD0) rational(tag uint16) (float64, bool) {
+	offset := e.rationalOffset(tag)
+	if offset < 0 {
+		return 0, false
+	}
+	numerator := e.order.Uint32(e.data[offset:])
+	denominator := e.order.Uint32(e.data[offset+4:])
+	if denominator == 0 {
+		return 0, false
+	}
+	return float64(numerator) / float64(denominator), true
+}
+
+// setRational writes a RATIONAL tag in place with two decimal places, reporting
+// whether the tag exists
+func (e *exifIFD0) setRational(tag uint16, value float64) bool {
+	offset := e.rationalOffset(tag)
+	if offset < 0 {
+		return false
+	}
+	numerator, denominator := rationalParts(value)
+	e.order.PutUint32(e.data[offset:], numerator)
+	e.order.PutUint32(e.data[offset+4:], denominator)
+	return true
+}
+
+// rationalParts converts a non-negative value to a fraction, using a denominator of 1
+// for whole numbers
+func rationalParts(value float64) (uint32, uint32) {
+	hundredths := uint32(min(max(value*100+0.5, 0), 0xFFFFFFFF))
+	if hundredths%100 == 0 {
+		return hundredths / 100, 1
+	}
+	return hundredths, 100
+}
\ No newline at end of file
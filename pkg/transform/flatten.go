@@ -0,0 +1,119 @@
+package transform
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strconv"
+	"strings"
+)
+
+// DefaultBackground is the color used to flatten transparency when none is specified
+var DefaultBackground = color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+
+// ParseHexColor parses a color string like "#ffffff", "#fff" or "ffffff"
+func ParseHexColor(s string) (color.NRGBA, error) {
+	s = strings.TrimSpace(s)
+	hex := strings.TrimPrefix(s, "#")
+	
+	// Expand short form "#abc" to "#aabbcc"
+	if len(hex) == 3 || len(hex) == 4 {
+		expanded := make([]byte, 0, len(hex)*2)
+		for i := 0; i < len(hex); i++ {
+			expanded = append(expanded, hex[i], hex[i])
+		}
+		hex = string(expanded)
+	}
+	
+	if len(hex) != 6 && len(hex) != 8 {
+		return color.NRGBA{}, fmt.Errorf("invalid color format: %s", s)
+	}
+	
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("invalid color value: %s", s)
+	}
+	
+	if len(hex) == 6 {
+		return color.NRGBA{
+			R: uint8(value >> 16),
+			G: uint8(value >> 8),
+			B: uint8(value),
+			A: 0xFF,
+		}, nil
+	}
+	
+	return color.NRGBA{
+		R: uint8(value >> 24),
+		G: uint8(value >> 16),
+		B: uint8(value >> 8),
+		A: uint8(value),
+	}, nil
+}
+
+// FormatHexColor returns the "#rrggbb" representation of a color
+func FormatHexColor(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if n.A != 0xFF {
+		return fmt.Sprintf("#%02x%02x%02x%02x", n.R, n.G, n.B, n.A)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+}
+
+// FormatSupportsAlpha reports whether the format can store transparency
+func FormatSupportsAlpha(format ImageFormat) bool {
+	switch format {
+	case FormatJPEG:
+		return false
+	default:
+		return true
+	}
+}
+
+// HasAlpha reports whether the image contains any non-opaque pixels
+func HasAlpha(img image.Image) bool {
+	// Most standard library image types can answer this directly
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return !o.Opaque()
+	}
+	
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a != 0xFFFF {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Flatten composites an image over a solid background, removing transparency.
+// Pixels are blended with the source alpha, so semi-transparent edges fade
+// into the background instead of turning black as with a plain JPEG encode.
+func Flatten(img image.Image, background color.Color) image.Image {
+	if background == nil {
+		background = DefaultBackground
+	}
+	
+	// The background itself must be opaque or the result would keep an alpha channel
+	bg := color.NRGBAModel.Convert(background).(color.NRGBA)
+	bg.A = 0xFF
+	
+	bounds := img.Bounds()
+	result := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(result, result.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	draw.Draw(result, result.Bounds(), img, bounds.Min, draw.Over)
+	
+	return result
+}
+
+// FlattenIfNeeded flattens the image when the target format has no alpha channel
+// and the image actually contains transparency. It reports whether flattening happened.
+func FlattenIfNeeded(img image.Image, format ImageFormat, background color.Color) (image.Image, bool) {
+	if FormatSupportsAlpha(format) || !HasAlpha(img) {
+		return img, false
+	}
+	return Flatten(img, background), true
+}
\ No newline at end of file
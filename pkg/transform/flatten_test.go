@@ -0,0 +1,143 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    color.NRGBA
+		wantErr bool
+	}{
+		{name: "Long form", input: "#ffffff", want: color.NRGBA{255, 255, 255, 255}},
+		{name: "Without hash", input: "ff0000", want: color.NRGBA{255, 0, 0, 255}},
+		{name: "Short form", input: "#0f0", want: color.NRGBA{0, 255, 0, 255}},
+		{name: "With alpha", input: "#00000080", want: color.NRGBA{0, 0, 0, 128}},
+		{name: "Uppercase", input: "#ABCDEF", want: color.NRGBA{0xAB, 0xCD, 0xEF, 255}},
+		{name: "Invalid length", input: "#12345", wantErr: true},
+		{name: "Invalid characters", input: "#gggggg", wantErr: true},
+		{name: "Empty", input: "", wantErr: true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHexColor(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseHexColor(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseHexColor(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 1))
+	img.SetNRGBA(0, 0, color.NRGBA{255, 0, 0, 255}) // Opaque red
+	img.SetNRGBA(1, 0, color.NRGBA{255, 0, 0, 0})   // Fully transparent
+	img.SetNRGBA(2, 0, color.NRGBA{0, 0, 0, 128})   // Half transparent black
+	
+	result := Flatten(img, color.NRGBA{255, 255, 255, 255})
+	
+	if HasAlpha(result) {
+		t.Fatal("Flatten() result still has alpha")
+	}
+	
+	tests := []struct {
+		x    int
+		want color.NRGBA
+	}{
+		{x: 0, want: color.NRGBA{255, 0, 0, 255}},
+		{x: 1, want: color.NRGBA{255, 255, 255, 255}},
+		{x: 2, want: color.NRGBA{127, 127, 127, 255}},
+	}
+	
+	for _, tt := range tests {
+		got := color.NRGBAModel.Convert(result.At(tt.x, 0)).(color.NRGBA)
+		if absDiff(int(got.R), int(tt.want.R)) > 1 || absDiff(int(got.G), int(tt.want.G)) > 1 ||
+			absDiff(int(got.B), int(tt.want.B)) > 1 || got.A != tt.want.A {
+			t.Errorf("pixel %d = %v, want %v", tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestFlattenIfNeeded(t *testing.T) {
+	transparent := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	opaque := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	for i := 3; i < len(opaque.Pix); i += 4 {
+		opaque.Pix[i] = 255
+	}
+	
+	tests := []struct {
+		name          string
+		img           image.Image
+		format        ImageFormat
+		wantFlattened bool
+	}{
+		{name: "Transparent to JPEG", img: transparent, format: FormatJPEG, wantFlattened: true},
+		{name: "Transparent to PNG", img: transparent, format: FormatPNG, wantFlattened: false},
+		{name: "Opaque to JPEG", img: opaque, format: FormatJPEG, wantFlattened: false},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, flattened := FlattenIfNeeded(tt.img, tt.format, nil)
+			if flattened != tt.wantFlattened {
+				t.Errorf("FlattenIfNeeded() flattened = %v, want %v", flattened, tt.wantFlattened)
+			}
+		})
+	}
+}
+
+func TestSaveImageFlattensJPEG(t *testing.T) {
+	// A fully transparent image used to come out black in JPEG
+	img := image.NewNRGBA(image.Rect(0, 0, 16, 16))
+	
+	buf := &bytes.Buffer{}
+	if err := SaveImage(buf, img, FormatJPEG, 95); err != nil {
+		t.Fatalf("SaveImage() error = %v", err)
+	}
+	
+	decoded, err := jpeg.Decode(buf)
+	if err != nil {
+		t.Fatalf("Failed to decode JPEG: %v", err)
+	}
+	
+	r, g, b, _ := decoded.At(8, 8).RGBA()
+	if r>>8 < 250 || g>>8 < 250 || b>>8 < 250 {
+		t.Errorf("Expected white background, got (%d, %d, %d)", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestTransformerLogsFlatten(t *testing.T) {
+	transformer := NewTransformer()
+	var messages []string
+	transformer.SetLogger(func(format string, args ...interface{}) {
+		messages = append(messages, format)
+	})
+	
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	buf := &bytes.Buffer{}
+	if err := transformer.save(buf, img, FormatJPEG, SaveOptions{Quality: 90}); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+	
+	if len(messages) != 1 {
+		t.Errorf("Expected 1 log message, got %d", len(messages))
+	}
+}
+
+func absDiff(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
\ No newline at end of file
@@ -0,0 +1,93 @@
+package transform
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// formatExtensions maps file extensions to image formats
+var formatExtensions = map[string]ImageFormat{
+	".jpg":  FormatJPEG,
+	".jpeg": FormatJPEG,
+	".png":  FormatPNG,
+	".webp": FormatWebP,
+	".gif":  FormatGIF,
+	".tif":  FormatTIFF,
+	".tiff": FormatTIFF,
+}
+
+// ParseImageFormat parses a format name like "jpeg", "jpg", "png", "webp", "gif" or "tiff"
+func ParseImageFormat(s string) (ImageFormat, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	name = strings.TrimPrefix(name, ".")
+	
+	switch name {
+	case "jpeg", "jpg":
+		return FormatJPEG, nil
+	case "png":
+		return FormatPNG, nil
+	case "webp":
+		return FormatWebP, nil
+	case "gif":
+		return FormatGIF, nil
+	case "tiff", "tif":
+		return FormatTIFF, nil
+	default:
+		return "", fmt.Errorf("unsupported image format: %s", s)
+	}
+}
+
+// FormatFromPath returns the image format implied by a file extension
+func FormatFromPath(path string) (ImageFormat, bool) {
+	format, ok := formatExtensions[strings.ToLower(filepath.Ext(path))]
+	return format, ok
+}
+
+// IsSupportedExtension reports whether a file extension belongs to a readable image format
+func IsSupportedExtension(ext string) bool {
+	_, ok := formatExtensions[strings.ToLower(ext)]
+	return ok
+}
+
+// Extension returns the canonical file extension for the format
+func (f ImageFormat) Extension() string {
+	switch f {
+	case FormatJPEG:
+		return ".jpg"
+	case FormatPNG:
+		return ".png"
+	case FormatWebP:
+		return ".webp"
+	case FormatGIF:
+		return ".gif"
+	case FormatTIFF:
+		return ".tif"
+	default:
+		return ""
+	}
+}
+
+// MatchesExtension reports whether ext is a valid extension for the format
+func (f ImageFormat) MatchesExtension(ext string) bool {
+	format, ok := formatExtensions[strings.ToLower(ext)]
+	return ok && format == f
+}
+
+// IsEncodable reports whether images can be written in the format
+func IsEncodable(format ImageFormat) bool {
+	switch format {
+	case FormatJPEG, FormatPNG, FormatWebP, FormatGIF:
+		return true
+	default:
+		return false
+	}
+}
+
+// ValidateOutputFormat checks that a format can be used for output
+func ValidateOutputFormat(format ImageFormat) error {
+	if !IsEncodable(format) {
+		return fmt.Errorf("cannot encode images as %q", format)
+	}
+	return nil
+}
\ No newline at end of file
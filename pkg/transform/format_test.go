@@ -0,0 +1,204 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"testing"
+	
+	"golang.org/x/image/webp"
+)
+
+func TestParseImageFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ImageFormat
+		wantErr bool
+	}{
+		{input: "jpeg", want: FormatJPEG},
+		{input: "JPG", want: FormatJPEG},
+		{input: "png", want: FormatPNG},
+		{input: ".webp", want: FormatWebP},
+		{input: "gif", want: FormatGIF},
+		{input: "TIF", want: FormatTIFF},
+		{input: "heic", wantErr: true},
+		{input: "", wantErr: true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseImageFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseImageFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseImageFormat(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := []struct {
+		path   string
+		want   ImageFormat
+		wantOK bool
+	}{
+		{path: "photo.jpg", want: FormatJPEG, wantOK: true},
+		{path: "dir/photo.JPEG", want: FormatJPEG, wantOK: true},
+		{path: "icon.png", want: FormatPNG, wantOK: true},
+		{path: "image.webp", want: FormatWebP, wantOK: true},
+		{path: "anim.gif", want: FormatGIF, wantOK: true},
+		{path: "scan.tiff", want: FormatTIFF, wantOK: true},
+		{path: "notes.txt", wantOK: false},
+		{path: "noext", wantOK: false},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := FormatFromPath(tt.path)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("FormatFromPath(%q) = %v, %v, want %v, %v", tt.path, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestEncodeWebPRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	
+	gradient := image.NewNRGBA(image.Rect(0, 0, 67, 45))
+	noise := image.NewNRGBA(image.Rect(0, 0, 33, 20))
+	transparent := image.NewNRGBA(image.Rect(0, 0, 40, 40))
+	for y := 0; y < 45; y++ {
+		for x := 0; x < 67; x++ {
+			gradient.SetNRGBA(x, y, color.NRGBA{uint8(x * 3), uint8(y * 5), uint8(x + y), 255})
+		}
+	}
+	for i := range noise.Pix {
+		noise.Pix[i] = uint8(rng.Intn(256))
+	}
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			if (x/8+y/8)%2 == 0 {
+				transparent.SetNRGBA(x, y, color.NRGBA{200, 30, 60, uint8(x * 6)})
+			}
+		}
+	}
+	
+	tests := []struct {
+		name string
+		img  *image.NRGBA
+	}{
+		{name: "Gradient", img: gradient},
+		{name: "Noise with alpha", img: noise},
+		{name: "Checkerboard with transparency", img: transparent},
+		{name: "Single pixel", img: image.NewNRGBA(image.Rect(0, 0, 1, 1))},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			if err := SaveImage(buf, tt.img, FormatWebP, 0); err != nil {
+				t.Fatalf("SaveImage() error = %v", err)
+			}
+			
+			decoded, err := webp.Decode(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("webp.Decode() error = %v", err)
+			}
+			if decoded.Bounds().Size() != tt.img.Bounds().Size() {
+				t.Fatalf("decoded size = %v, want %v", decoded.Bounds().Size(), tt.img.Bounds().Size())
+			}
+			
+			bounds := tt.img.Bounds()
+			for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+				for x := bounds.Min.X; x < bounds.Max.X; x++ {
+					want := tt.img.NRGBAAt(x, y)
+					got := color.NRGBAModel.Convert(decoded.At(x, y)).(color.NRGBA)
+					if want.A == 0 && got.A == 0 {
+						continue
+					}
+					if got != want {
+						t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeWebPSmallerThanPNG(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 256, 256))
+	for y := 0; y < 256; y++ {
+		for x := 0; x < 256; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x), uint8(y), uint8((x + y) / 2), 255})
+		}
+	}
+	
+	webpBuf := &bytes.Buffer{}
+	if err := encodeWebP(webpBuf, img); err != nil {
+		t.Fatalf("encodeWebP() error = %v", err)
+	}
+	pngBuf := &bytes.Buffer{}
+	if err := png.Encode(pngBuf, img); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	
+	if webpBuf.Len() >= pngBuf.Len() {
+		t.Errorf("WebP size %d is not smaller than PNG size %d", webpBuf.Len(), pngBuf.Len())
+	}
+}
+
+func TestConvertFormat(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 30))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i)
+	}
+	input := &bytes.Buffer{}
+	if err := png.Encode(input, img); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+	
+	tests := []struct {
+		name    string
+		options ConvertOptions
+		want    ImageFormat
+		wantErr bool
+	}{
+		{name: "PNG to JPEG", options: ConvertOptions{Format: FormatJPEG}, want: FormatJPEG},
+		{name: "PNG to WebP", options: ConvertOptions{Format: FormatWebP}, want: FormatWebP},
+		{name: "PNG to GIF", options: ConvertOptions{Format: FormatGIF}, want: FormatGIF},
+		{name: "Keep format", options: ConvertOptions{DPI: 300}, want: FormatPNG},
+		{name: "WebP with DPI", options: ConvertOptions{Format: FormatWebP, DPI: 300}, wantErr: true},
+		{name: "Unknown format", options: ConvertOptions{Format: "bmp"}, wantErr: true},
+	}
+	
+	transformer := NewTransformer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			err := transformer.Convert(bytes.NewReader(input.Bytes()), output, tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			
+			decoded, format, err := LoadImage(output)
+			if err != nil {
+				t.Fatalf("LoadImage() error = %v", err)
+			}
+			if format != tt.want {
+				t.Errorf("output format = %v, want %v", format, tt.want)
+			}
+			if decoded.Bounds().Dx() != 40 || decoded.Bounds().Dy() != 30 {
+				t.Errorf("output size = %v, want 40x30", decoded.Bounds().Size())
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,81 @@
+package transform
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/imagehash"
+)
+
+// HashAlgorithm selects a perceptual hash
+type HashAlgorithm string
+
+const (
+	HashAverage    HashAlgorithm = "ahash" // Mean of an 8x8 thumbnail, fastest
+	HashDifference HashAlgorithm = "dhash" // Gradients of a 9x8 thumbnail
+	HashPerceptual HashAlgorithm = "phash" // Low DCT frequencies, most robust
+)
+
+// ParseHashAlgorithm parses a perceptual hash name
+func ParseHashAlgorithm(s string) (HashAlgorithm, error) {
+	switch HashAlgorithm(strings.ToLower(strings.TrimSpace(s))) {
+	case HashAverage:
+		return HashAverage, nil
+	case HashDifference:
+		return HashDifference, nil
+	case HashPerceptual, "":
+		return HashPerceptual, nil
+	default:
+		return "", fmt.Errorf("unsupported hash: %s (use ahash, dhash or phash)", s)
+	}
+}
+
+// ImageHash is the perceptual hash of an image with its size
+type ImageHash struct {
+	Width  int
+	Height int
+	Hash   imagehash.Hash
+}
+
+// Hash decodes an image and computes its perceptual hash
+func (t *Transformer) Hash(input io.Reader, algorithm HashAlgorithm) (ImageHash, error) {
+	data, err := t.readImage(input)
+	if err != nil {
+		return ImageHash{}, err
+	}
+	img, _, err := decodeImage(data)
+	if err != nil {
+		return ImageHash{}, fmt.Errorf("failed to decode image: %w", err)
+	}
+	
+	hash, err := HashImage(img, algorithm)
+	if err != nil {
+		return ImageHash{}, err
+	}
+	bounds := img.Bounds()
+	t.logf("%s of a %dx%d image: %s", algorithm, bounds.Dx(), bounds.Dy(), hash)
+	return ImageHash{Width: bounds.Dx(), Height: bounds.Dy(), Hash: hash}, nil
+}
+
+// HashImage computes a perceptual hash. Transparent images are hashed as flattened onto
+// white so they match their JPEG exports.
+func HashImage(img image.Image, algorithm HashAlgorithm) (imagehash.Hash, error) {
+	algorithm, err := ParseHashAlgorithm(string(algorithm))
+	if err != nil {
+		return 0, err
+	}
+	if HasAlpha(img) {
+		img = Flatten(img, DefaultBackground)
+	}
+	
+	switch algorithm {
+	case HashAverage:
+		return imagehash.Average(img), nil
+	case HashDifference:
+		return imagehash.Difference(img), nil
+	default:
+		return imagehash.Perceptual(img), nil
+	}
+}
\ No newline at end of file
@@ -0,0 +1,52 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestParseHashAlgorithm(t *testing.T) {
+	for input, want := range map[string]HashAlgorithm{"": HashPerceptual, "aHash": HashAverage, " dhash ": HashDifference} {
+		if got, err := ParseHashAlgorithm(input); err != nil || got != want {
+			t.Errorf("ParseHashAlgorithm(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseHashAlgorithm("md5"); err == nil {
+		t.Error("ParseHashAlgorithm() accepted an unknown hash")
+	}
+}
+
+func TestHash(t *testing.T) {
+	// A logo on a transparent background hashes like the same logo on white
+	logo := image.NewNRGBA(image.Rect(0, 0, 200, 120))
+	for y := 30; y < 90; y++ {
+		for x := 20; x < 120; x++ {
+			logo.SetNRGBA(x, y, color.NRGBA{R: 0xC0, G: 0x30, B: 0x30, A: 0xFF})
+		}
+	}
+	var input bytes.Buffer
+	if err := png.Encode(&input, logo); err != nil {
+		t.Fatal(err)
+	}
+	flattened := Flatten(logo, DefaultBackground)
+	
+	for _, algorithm := range []HashAlgorithm{HashAverage, HashDifference, HashPerceptual} {
+		result, err := NewTransformer().Hash(bytes.NewReader(input.Bytes()), algorithm)
+		if err != nil {
+			t.Fatalf("Hash(%s) error = %v", algorithm, err)
+		}
+		if result.Width != 200 || result.Height != 120 {
+			t.Errorf("Hash(%s) size = %dx%d, want 200x120", algorithm, result.Width, result.Height)
+		}
+		hash, err := HashImage(flattened, algorithm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Hash != hash {
+			t.Errorf("Hash(%s) = %s, want %s as for the flattened copy", algorithm, result.Hash, hash)
+		}
+	}
+}
\ No newline at end of file
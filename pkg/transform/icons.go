@@ -0,0 +1,206 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	
+	"github.com/allieus/imagekit/pkg/icon"
+	"github.com/disintegration/imaging"
+)
+
+// IconOptions contains options for generating app icons and favicons
+type IconOptions struct {
+	Background      color.Color // Fill behind every icon (nil = keep transparency)
+	AppleBackground color.Color // Fill behind the Apple touch icon when Background is nil (nil = white)
+	Padding         float64     // Fraction of each side left empty around the artwork (0-0.4)
+	ICNS            bool        // Also make an Apple .icns file
+}
+
+// IconFile is one generated file of an icon set
+type IconFile struct {
+	Name     string
+	Size     int  // Width and height in pixels (0 for multi-size files)
+	Manifest bool // Listed in the web app manifest
+	Data     []byte
+}
+
+// iconSpec describes one PNG of the standard icon set
+type iconSpec struct {
+	name     string
+	size     int
+	manifest bool
+	apple    bool
+}
+
+// iconSpecs are the favicon, Android and PWA sizes, plus the Apple touch icon
+var iconSpecs = []iconSpec{
+	{"icon-16x16.png", 16, false, false},
+	{"icon-32x32.png", 32, false, false},
+	{"icon-72x72.png", 72, true, false},
+	{"icon-96x96.png", 96, true, false},
+	{"icon-128x128.png", 128, true, false},
+	{"icon-144x144.png", 144, true, false},
+	{"icon-152x152.png", 152, true, false},
+	{"icon-180x180.png", 180, false, false},
+	{"icon-192x192.png", 192, true, false},
+	{"icon-384x384.png", 384, true, false},
+	{"icon-512x512.png", 512, true, false},
+	{"apple-touch-icon.png", 180, false, true},
+}
+
+// faviconSizes are the entries of favicon.ico
+var faviconSizes = []int{16, 32, 48}
+
+// ValidateIconOptions checks icon options
+func ValidateIconOptions(options IconOptions) error {
+	if options.Padding < 0 || options.Padding > 0.4 {
+		return fmt.Errorf("padding must be between 0 and 40%%: %g%%", options.Padding*100)
+	}
+	return nil
+}
+
+// Icons renders the standard icon set, favicon.ico and optionally icon.icns from one
+// source image. Non-square images are centered on a transparent square first.
+func (t *Transformer) Icons(input io.Reader, options IconOptions) ([]IconFile, error) {
+	if err := ValidateIconOptions(options); err != nil {
+		return nil, err
+	}
+	data, err := t.readImage(input)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := decodeImage(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	source := squareImage(img)
+	if side := source.Bounds().Dx(); side < 512 {
+		t.logf("source is only %dx%d, larger icons are upscaled", side, side)
+	}
+	
+	apple := options.Background
+	if apple == nil {
+		apple = options.AppleBackground
+		if apple == nil {
+			apple = DefaultBackground
+		}
+	}
+	
+	rendered := make(map[int][]byte)
+	render := func(size int, background color.Color) ([]byte, error) {
+		if background == nil {
+			if data, ok := rendered[size]; ok {
+				return data, nil
+			}
+		}
+		var buf bytes.Buffer
+		if _, err := OptimizePNG(&buf, renderIcon(source, size, options.Padding, background), PNGOptions{Optimize: true}); err != nil {
+			return nil, err
+		}
+		if background == nil {
+			rendered[size] = buf.Bytes()
+		}
+		return buf.Bytes(), nil
+	}
+	
+	var files []IconFile
+	for _, spec := range iconSpecs {
+		background := options.Background
+		if spec.apple {
+			// iOS shows transparent areas of touch icons as black
+			background = apple
+		}
+		data, err := render(spec.size, background)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, IconFile{Name: spec.name, Size: spec.size, Manifest: spec.manifest, Data: data})
+	}
+	
+	containers := []struct {
+		name    string
+		sizes   []int
+		encode  func(io.Writer, []icon.Image) error
+		enabled bool
+	}{
+		{"favicon.ico", faviconSizes, icon.EncodeICO, true},
+		{"icon.icns", icon.ICNSSizes, icon.EncodeICNS, options.ICNS},
+	}
+	for _, container := range containers {
+		if !container.enabled {
+			continue
+		}
+		var images []icon.Image
+		for _, size := range container.sizes {
+			data, err := render(size, options.Background)
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, icon.Image{Size: size, PNG: data})
+		}
+		var buf bytes.Buffer
+		if err := container.encode(&buf, images); err != nil {
+			return nil, err
+		}
+		files = append(files, IconFile{Name: container.name, Data: buf.Bytes()})
+	}
+	t.logf("rendered %d icon files from a %dx%d source", len(files), img.Bounds().Dx(), img.Bounds().Dy())
+	
+	return files, nil
+}
+
+// squareImage centers an image on a transparent square canvas
+func squareImage(img image.Image) *image.NRGBA {
+	bounds := img.Bounds()
+	side := max(bounds.Dx(), bounds.Dy())
+	square := image.NewNRGBA(image.Rect(0, 0, side, side))
+	corner := image.Pt((side-bounds.Dx())/2, (side-bounds.Dy())/2)
+	draw.Draw(square, bounds.Sub(bounds.Min).Add(corner), img, bounds.Min, draw.Src)
+	return square
+}
+
+// renderIcon scales a square source to size pixels, leaving padding around it and
+// filling the background when one is given
+func renderIcon(source image.Image, size int, padding float64, background color.Color) *image.NRGBA {
+	inner := max(1, size-2*int(float64(size)*padding+0.5))
+	canvas := image.NewNRGBA(image.Rect(0, 0, size, size))
+	if background != nil {
+		bg := color.NRGBAModel.Convert(background).(color.NRGBA)
+		bg.A = 0xFF
+		draw.Draw(canvas, canvas.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	}
+	scaled := imaging.Resize(source, inner, inner, imaging.Lanczos)
+	offset := (size - inner) / 2
+	draw.Draw(canvas, image.Rect(offset, offset, offset+inner, offset+inner), scaled, image.Point{}, draw.Over)
+	return canvas
+}
+
+// IconManifest returns the "icons" member of a web app manifest for the files listed
+// in it, with src set to baseURL followed by the file name
+func IconManifest(files []IconFile, baseURL string) ([]byte, error) {
+	type manifestIcon struct {
+		Src     string `json:"src"`
+		Sizes   string `json:"sizes"`
+		Type    string `json:"type"`
+		Purpose string `json:"purpose"`
+	}
+	manifest := struct {
+		Icons []manifestIcon `json:"icons"`
+	}{Icons: []manifestIcon{}}
+	for _, file := range files {
+		if file.Manifest {
+			manifest.Icons = append(manifest.Icons, manifestIcon{
+				Src:     baseURL + file.Name,
+				Sizes:   fmt.Sprintf("%dx%d", file.Size, file.Size),
+				Type:    "image/png",
+				Purpose: "any maskable",
+			})
+		}
+	}
+	return json.MarshalIndent(manifest, "", "  ")
+}
\ No newline at end of file
@@ -0,0 +1,94 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestIcons(t *testing.T) {
+	// A wide image with a transparent half, to check squaring and backgrounds
+	src := image.NewNRGBA(image.Rect(0, 0, 200, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			src.Set(x, y, color.NRGBA{R: 0xFF, A: 0xFF})
+		}
+	}
+	var input bytes.Buffer
+	if err := png.Encode(&input, src); err != nil {
+		t.Fatal(err)
+	}
+	
+	files, err := NewTransformer().Icons(&input, IconOptions{ICNS: true})
+	if err != nil {
+		t.Fatalf("Icons() error = %v", err)
+	}
+	byName := make(map[string]IconFile)
+	for _, file := range files {
+		byName[file.Name] = file
+	}
+	for _, name := range []string{"icon-16x16.png", "icon-512x512.png", "apple-touch-icon.png", "favicon.ico", "icon.icns"} {
+		if _, ok := byName[name]; !ok {
+			t.Errorf("Icons() did not make %s", name)
+		}
+	}
+	
+	decode := func(name string) image.Image {
+		img, err := png.Decode(bytes.NewReader(byName[name].Data))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		return img
+	}
+	icon := decode("icon-192x192.png")
+	if size := icon.Bounds().Size(); size != image.Pt(192, 192) {
+		t.Errorf("icon-192x192.png size = %v", size)
+	}
+	// The top of the squared image is transparent and the left half is red
+	if _, _, _, a := icon.At(96, 5).RGBA(); a != 0 {
+		t.Errorf("padding alpha = %d, want 0", a)
+	}
+	if r, _, _, a := icon.At(20, 96).RGBA(); r>>8 != 0xFF || a>>8 != 0xFF {
+		t.Errorf("artwork = %d/%d, want opaque red", r>>8, a>>8)
+	}
+	// The Apple touch icon is flattened onto white
+	if r, g, b, a := decode("apple-touch-icon.png").At(90, 5).RGBA(); r>>8 != 0xFF || g>>8 != 0xFF || b>>8 != 0xFF || a>>8 != 0xFF {
+		t.Errorf("apple-touch-icon background = %d,%d,%d,%d, want white", r>>8, g>>8, b>>8, a>>8)
+	}
+	
+	var manifest struct {
+		Icons []struct {
+			Src, Sizes, Type, Purpose string
+		}
+	}
+	data, err := IconManifest(files, "/static/icons/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Icons) != 8 {
+		t.Fatalf("manifest has %d icons, want 8", len(manifest.Icons))
+	}
+	if first := manifest.Icons[0]; first.Src != "/static/icons/icon-72x72.png" || first.Sizes != "72x72" || first.Purpose != "any maskable" {
+		t.Errorf("first manifest icon = %+v", first)
+	}
+}
+
+func TestRenderIconPadding(t *testing.T) {
+	icon := renderIcon(solidImage(10, 10, color.Black), 100, 0.1, color.White)
+	if c := color.NRGBAModel.Convert(icon.At(5, 50)).(color.NRGBA); c != (color.NRGBA{0xFF, 0xFF, 0xFF, 0xFF}) {
+		t.Errorf("padding = %v, want white", c)
+	}
+	if c := color.NRGBAModel.Convert(icon.At(50, 50)).(color.NRGBA); c != (color.NRGBA{0, 0, 0, 0xFF}) {
+		t.Errorf("center = %v, want black", c)
+	}
+	
+	if err := ValidateIconOptions(IconOptions{Padding: 0.5}); err == nil {
+		t.Error("ValidateIconOptions() accepted 50% padding")
+	}
+}
\ No newline at end of file
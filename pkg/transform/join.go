@@ -0,0 +1,283 @@
+package transform
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+	"strings"
+)
+
+// JoinDirection selects how joined images are arranged
+type JoinDirection string
+
+const (
+	JoinHorizontal JoinDirection = "horizontal" // One row, left to right
+	JoinVertical   JoinDirection = "vertical"   // One column, top to bottom
+	JoinGrid       JoinDirection = "grid"       // Rows of Columns images
+)
+
+// ParseJoinDirection parses a join direction name
+func ParseJoinDirection(s string) (JoinDirection, error) {
+	switch JoinDirection(strings.ToLower(strings.TrimSpace(s))) {
+	case JoinHorizontal, "h", "":
+		return JoinHorizontal, nil
+	case JoinVertical, "v":
+		return JoinVertical, nil
+	case JoinGrid:
+		return JoinGrid, nil
+	default:
+		return "", fmt.Errorf("unsupported direction: %s (use horizontal, vertical or grid)", s)
+	}
+}
+
+// JoinAlign places an image within a row or column that is larger than the image
+type JoinAlign string
+
+const (
+	AlignStart  JoinAlign = "start"  // Top or left
+	AlignCenter JoinAlign = "center" // Middle
+	AlignEnd    JoinAlign = "end"    // Bottom or right
+)
+
+// ParseJoinAlign parses an alignment, accepting top, left, bottom and right as well
+func ParseJoinAlign(s string) (JoinAlign, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "start", "top", "left":
+		return AlignStart, nil
+	case "center", "middle", "":
+		return AlignCenter, nil
+	case "end", "bottom", "right":
+		return AlignEnd, nil
+	default:
+		return "", fmt.Errorf("unsupported alignment: %s (use start, center or end)", s)
+	}
+}
+
+// offset returns where content of the given size starts in space of a larger size
+func (a JoinAlign) offset(space, size int) int {
+	switch a {
+	case AlignStart:
+		return 0
+	case AlignEnd:
+		return space - size
+	default:
+		return (space - size) / 2
+	}
+}
+
+// JoinScale selects the common size images are scaled to before joining: the height
+// for horizontal joins and the width for vertical and grid joins
+type JoinScale string
+
+const (
+	JoinScaleNone     JoinScale = "none"     // Keep the original sizes
+	JoinScaleSmallest JoinScale = "smallest" // Shrink to the smallest image
+	JoinScaleLargest  JoinScale = "largest"  // Enlarge to the largest image
+)
+
+// ParseJoinScale parses a scale mode name
+func ParseJoinScale(s string) (JoinScale, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "none", "":
+		return JoinScaleNone, nil
+	case "smallest", "min":
+		return JoinScaleSmallest, nil
+	case "largest", "max":
+		return JoinScaleLargest, nil
+	default:
+		return "", fmt.Errorf("unsupported scale: %s (use none, smallest, largest or a pixel size)", s)
+	}
+}
+
+// JoinOptions contains options for stitching images together
+type JoinOptions struct {
+	Direction  JoinDirection
+	Columns    int            // Images per row for grid joins (0 = a roughly square grid)
+	Gap        DimensionValue // Pixels or length between images
+	Align      JoinAlign
+	Scale      JoinScale
+	ScaleSize  int         // Common height or width in pixels (overrides Scale)
+	Background color.Color // Color of the gaps and of the space around smaller images (nil = white)
+	Resolution Resolution  // Output resolution (zero = the first image's)
+	Quality    int
+	PNG        PNGOptions
+	JPEG       JPEGOptions
+}
+
+// ValidateJoinOptions checks join options
+func ValidateJoinOptions(options JoinOptions) error {
+	if _, err := ParseJoinDirection(string(options.Direction)); err != nil {
+		return err
+	}
+	if _, err := ParseJoinAlign(string(options.Align)); err != nil {
+		return err
+	}
+	if _, err := ParseJoinScale(string(options.Scale)); err != nil {
+		return err
+	}
+	if options.Columns < 0 || options.ScaleSize < 0 {
+		return fmt.Errorf("columns and scale size cannot be negative")
+	}
+	gap := options.Gap
+	if gap.IsMultiplier || gap.Constraint != ConstraintNone || gap.Value < 0 || gap.Length < 0 {
+		return fmt.Errorf("gap must be a length or pixel count: %s", gap)
+	}
+	return nil
+}
+
+// JoinColumns returns the number of columns used to join count images
+func JoinColumns(count int, options JoinOptions) int {
+	switch {
+	case options.Direction == JoinVertical:
+		return 1
+	case options.Direction != JoinGrid:
+		return max(count, 1)
+	case options.Columns > 0:
+		return min(options.Columns, max(count, 1))
+	default:
+		return max(int(math.Ceil(math.Sqrt(float64(count)))), 1)
+	}
+}
+
+// joinLayout places images of the given sizes in rows of columns. Each column is as
+// wide as its widest image and each row as tall as its tallest, and smaller images
+// are aligned within their cell.
+func joinLayout(sizes []image.Point, columns int, gap image.Point, align JoinAlign) ([]image.Rectangle, image.Point) {
+	rows := (len(sizes) + columns - 1) / columns
+	widths := make([]int, columns)
+	heights := make([]int, rows)
+	for i, size := range sizes {
+		widths[i%columns] = max(widths[i%columns], size.X)
+		heights[i/columns] = max(heights[i/columns], size.Y)
+	}
+	
+	// Cell corners along each axis, and the canvas size after the last cell
+	xs := make([]int, columns)
+	ys := make([]int, rows)
+	var x, y int
+	for i, w := range widths {
+		xs[i] = x
+		x += w + gap.X
+	}
+	for i, h := range heights {
+		ys[i] = y
+		y += h + gap.Y
+	}
+	canvas := image.Pt(x-gap.X, y-gap.Y)
+	
+	rects := make([]image.Rectangle, len(sizes))
+	for i, size := range sizes {
+		column, row := i%columns, i/columns
+		corner := image.Pt(
+			xs[column]+align.offset(widths[column], size.X),
+			ys[row]+align.offset(heights[row], size.Y),
+		)
+		rects[i] = image.Rectangle{Min: corner, Max: corner.Add(size)}
+	}
+	return rects, canvas
+}
+
+// joinScaleSize returns the common height or width for the scale mode, or 0 when
+// images keep their size
+func joinScaleSize(lengths []int, options JoinOptions) int {
+	if options.ScaleSize > 0 {
+		return options.ScaleSize
+	}
+	size := 0
+	for i, length := range lengths {
+		switch {
+		case options.Scale == JoinScaleSmallest && (i == 0 || length < size):
+			size = length
+		case options.Scale == JoinScaleLargest && length > size:
+			size = length
+		}
+	}
+	return size
+}
+
+// Join stitches images into one next to each other, below each other or in a grid,
+// and returns the size of the result
+func (t *Transformer) Join(inputs []io.Reader, output io.Writer, format ImageFormat, options JoinOptions) (image.Point, error) {
+	if len(inputs) == 0 {
+		return image.Point{}, fmt.Errorf("no images to join")
+	}
+	if err := ValidateJoinOptions(options); err != nil {
+		return image.Point{}, err
+	}
+	if err := ValidateOutputFormat(format); err != nil {
+		return image.Point{}, err
+	}
+	options.Direction, _ = ParseJoinDirection(string(options.Direction))
+	options.Align, _ = ParseJoinAlign(string(options.Align))
+	options.Scale, _ = ParseJoinScale(string(options.Scale))
+	
+	images := make([]image.Image, len(inputs))
+	resolution := options.Resolution
+	for i, input := range inputs {
+		data, err := t.readImage(input)
+		if err != nil {
+			return image.Point{}, fmt.Errorf("image %d: %w", i+1, err)
+		}
+		if i == 0 && resolution.IsZero() {
+			resolution = imageResolution(data)
+		}
+		if images[i], _, err = decodeImage(data); err != nil {
+			return image.Point{}, fmt.Errorf("image %d: failed to load image: %w", i+1, err)
+		}
+	}
+	
+	// Scale to a common height in a row or a common width otherwise
+	horizontal := options.Direction == JoinHorizontal
+	lengths := make([]int, len(images))
+	for i, img := range images {
+		lengths[i] = img.Bounds().Dx()
+		if horizontal {
+			lengths[i] = img.Bounds().Dy()
+		}
+	}
+	if size := joinScaleSize(lengths, options); size > 0 {
+		t.logf("scaling images to a common length of %d pixels", size)
+		for i, img := range images {
+			if lengths[i] == size {
+				continue
+			}
+			if horizontal {
+				images[i] = ResizeByHeight(img, size)
+			} else {
+				images[i] = ResizeByWidth(img, size)
+			}
+		}
+	}
+	
+	var gap image.Point
+	if !options.Gap.IsZero() {
+		dpiX, dpiY := resolution.DPI()
+		gap = image.Pt(options.Gap.Resolve(dpiX).Value, options.Gap.Resolve(dpiY).Value)
+	}
+	sizes := make([]image.Point, len(images))
+	for i, img := range images {
+		sizes[i] = img.Bounds().Size()
+	}
+	columns := JoinColumns(len(images), options)
+	rects, size := joinLayout(sizes, columns, gap, options.Align)
+	t.logf("joining %d images in %d columns on a %dx%d canvas", len(images), columns, size.X, size.Y)
+	
+	background := options.Background
+	if background == nil {
+		background = DefaultBackground
+	}
+	canvas := image.NewNRGBA(image.Rectangle{Max: size})
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	for i, img := range images {
+		draw.Draw(canvas, rects[i], img, img.Bounds().Min, draw.Over)
+	}
+	
+	if format != FormatJPEG && format != FormatPNG {
+		resolution = Resolution{}
+	}
+	saveOptions := SaveOptions{Quality: options.Quality, PNG: options.PNG, JPEG: options.JPEG}
+	return size, t.saveWithResolution(output, canvas, format, saveOptions, resolution)
+}
\ No newline at end of file
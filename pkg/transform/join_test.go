@@ -0,0 +1,139 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"testing"
+)
+
+func TestJoinLayout(t *testing.T) {
+	sizes := []image.Point{{100, 50}, {60, 80}, {40, 40}}
+	
+	tests := []struct {
+		name      string
+		columns   int
+		align     JoinAlign
+		wantRects []image.Rectangle
+		wantSize  image.Point
+	}{
+		{
+			name:    "horizontal centered",
+			columns: 3,
+			align:   AlignCenter,
+			wantRects: []image.Rectangle{
+				image.Rect(0, 15, 100, 65),
+				image.Rect(110, 0, 170, 80),
+				image.Rect(180, 20, 220, 60),
+			},
+			wantSize: image.Pt(220, 80),
+		},
+		{
+			name:    "vertical right",
+			columns: 1,
+			align:   AlignEnd,
+			wantRects: []image.Rectangle{
+				image.Rect(0, 0, 100, 50),
+				image.Rect(40, 60, 100, 140),
+				image.Rect(60, 150, 100, 190),
+			},
+			wantSize: image.Pt(100, 190),
+		},
+		{
+			name:    "grid top left",
+			columns: 2,
+			align:   AlignStart,
+			wantRects: []image.Rectangle{
+				image.Rect(0, 0, 100, 50),
+				image.Rect(110, 0, 170, 80),
+				image.Rect(0, 90, 40, 130),
+			},
+			wantSize: image.Pt(170, 130),
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rects, size := joinLayout(sizes, tt.columns, image.Pt(10, 10), tt.align)
+			if size != tt.wantSize {
+				t.Errorf("size = %v, want %v", size, tt.wantSize)
+			}
+			for i, rect := range rects {
+				if rect != tt.wantRects[i] {
+					t.Errorf("rect %d = %v, want %v", i, rect, tt.wantRects[i])
+				}
+			}
+		})
+	}
+}
+
+func TestJoinColumns(t *testing.T) {
+	tests := []struct {
+		options JoinOptions
+		count   int
+		want    int
+	}{
+		{JoinOptions{Direction: JoinHorizontal}, 4, 4},
+		{JoinOptions{Direction: JoinVertical}, 4, 1},
+		{JoinOptions{Direction: JoinGrid}, 5, 3},
+		{JoinOptions{Direction: JoinGrid, Columns: 2}, 5, 2},
+		{JoinOptions{Direction: JoinGrid, Columns: 8}, 5, 5},
+	}
+	for _, tt := range tests {
+		if got := JoinColumns(tt.count, tt.options); got != tt.want {
+			t.Errorf("JoinColumns(%d, %s) = %d, want %d", tt.count, tt.options.Direction, got, tt.want)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	encode := func(width, height int, c color.Color) io.Reader {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, solidImage(width, height, c)); err != nil {
+			t.Fatal(err)
+		}
+		return &buf
+	}
+	red := color.NRGBA{R: 0xFF, A: 0xFF}
+	blue := color.NRGBA{B: 0xFF, A: 0xFF}
+	
+	// The smaller image is scaled up to the common height of 100
+	var output bytes.Buffer
+	options := JoinOptions{
+		Direction:  JoinHorizontal,
+		Gap:        DimensionValue{Value: 10},
+		Scale:      JoinScaleLargest,
+		Background: color.NRGBA{G: 0xFF, A: 0xFF},
+	}
+	inputs := []io.Reader{encode(200, 100, red), encode(50, 50, blue)}
+	size, err := NewTransformer().Join(inputs, &output, FormatPNG, options)
+	if err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+	if size != image.Pt(310, 100) {
+		t.Errorf("Join() size = %v, want 310x100", size)
+	}
+	
+	img, err := png.Decode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		x    int
+		want color.NRGBA
+	}{{100, red}, {205, options.Background.(color.NRGBA)}, {260, blue}} {
+		if c := color.NRGBAModel.Convert(img.At(tt.x, 50)).(color.NRGBA); c != tt.want {
+			t.Errorf("pixel at x=%d = %v, want %v", tt.x, c, tt.want)
+		}
+	}
+	
+	if _, err := NewTransformer().Join(nil, &output, FormatPNG, options); err == nil {
+		t.Error("Join() accepted no images")
+	}
+	options.Gap = DimensionValue{IsMultiplier: true, Multiplier: 2}
+	if _, err := NewTransformer().Join(inputs, &output, FormatPNG, options); err == nil {
+		t.Error("Join() accepted a multiplier gap")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,53 @@
+package transform
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/jpegcodec"
+)
+
+// ParseChromaSubsampling parses a subsampling mode like "4:4:4", "444" or "420"
+func ParseChromaSubsampling(s string) (ChromaSubsampling, error) {
+	switch strings.ReplaceAll(strings.TrimSpace(s), ":", "") {
+	case "444":
+		return Subsampling444, nil
+	case "422":
+		return Subsampling422, nil
+	case "420":
+		return Subsampling420, nil
+	default:
+		return "", fmt.Errorf("unsupported chroma subsampling: %s (use 444, 422 or 420)", s)
+	}
+}
+
+// subsampling returns the effective subsampling mode
+func (o JPEGOptions) subsampling() ChromaSubsampling {
+	if o.Subsampling == "" {
+		return Subsampling420
+	}
+	return o.Subsampling
+}
+
+// encodeJPEG encodes with the coefficient-level encoder, which supports chroma
+// subsampling control, progressive scans and optimized Huffman tables
+func encodeJPEG(w io.Writer, img image.Image, quality int, options JPEGOptions) error {
+	opts := &jpegcodec.Options{
+		Quality:         quality,
+		Progressive:     options.Progressive,
+		OptimizeHuffman: true,
+	}
+	switch options.subsampling() {
+	case Subsampling444:
+		opts.Subsampling = jpegcodec.Subsampling444
+	case Subsampling422:
+		opts.Subsampling = jpegcodec.Subsampling422
+	case Subsampling420:
+		opts.Subsampling = jpegcodec.Subsampling420
+	default:
+		return fmt.Errorf("unsupported chroma subsampling: %s", options.Subsampling)
+	}
+	return jpegcodec.Encode(w, img, opts)
+}
\ No newline at end of file
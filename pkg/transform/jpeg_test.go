@@ -0,0 +1,93 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestParseChromaSubsampling(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ChromaSubsampling
+		wantErr bool
+	}{
+		{input: "444", want: Subsampling444},
+		{input: "4:4:4", want: Subsampling444},
+		{input: "422", want: Subsampling422},
+		{input: "4:2:0", want: Subsampling420},
+		{input: "411", wantErr: true},
+		{input: "", wantErr: true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseChromaSubsampling(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseChromaSubsampling(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseChromaSubsampling(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertJPEGOptions(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x * 4), uint8(y * 5), 90, 255})
+		}
+	}
+	input := &bytes.Buffer{}
+	if err := png.Encode(input, img); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+	
+	tests := []struct {
+		name       string
+		options    JPEGOptions
+		wantMarker byte
+		wantLuma   byte // Luma sampling factors
+	}{
+		{name: "Default", options: JPEGOptions{}, wantMarker: 0xC0, wantLuma: 0x22},
+		{name: "Progressive", options: JPEGOptions{Progressive: true}, wantMarker: 0xC2, wantLuma: 0x22},
+		{name: "4:4:4", options: JPEGOptions{Subsampling: Subsampling444}, wantMarker: 0xC0, wantLuma: 0x11},
+		{name: "Progressive 4:2:2", options: JPEGOptions{Subsampling: Subsampling422, Progressive: true}, wantMarker: 0xC2, wantLuma: 0x21},
+	}
+	
+	transformer := NewTransformer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			options := ConvertOptions{Format: FormatJPEG, Quality: 90, JPEG: tt.options}
+			if err := transformer.Convert(bytes.NewReader(input.Bytes()), output, options); err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			
+			data := output.Bytes()
+			marker, luma := byte(0), byte(0)
+			for i := 2; i+11 < len(data); i += 2 + (int(data[i+2])<<8 | int(data[i+3])) {
+				if data[i+1] >= 0xC0 && data[i+1] <= 0xC2 {
+					marker, luma = data[i+1], data[i+11]
+					break
+				}
+			}
+			if marker != tt.wantMarker || luma != tt.wantLuma {
+				t.Errorf("frame marker = %#x, luma sampling = %#x, want %#x, %#x", marker, luma, tt.wantMarker, tt.wantLuma)
+			}
+			
+			decoded, format, err := LoadImage(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("LoadImage() error = %v", err)
+			}
+			if format != FormatJPEG || decoded.Bounds().Size() != img.Bounds().Size() {
+				t.Errorf("decoded %v %v, want jpeg %v", format, decoded.Bounds().Size(), img.Bounds().Size())
+			}
+		})
+	}
+}
\ No newline at end of file
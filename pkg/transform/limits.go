@@ -0,0 +1,258 @@
+package transform
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/jpegcodec"
+)
+
+// ErrImageTooLarge is returned when an input exceeds the configured Limits.
+// Use errors.Is to detect it; the wrapped message says which limit was hit.
+var ErrImageTooLarge = errors.New("image too large")
+
+// Limits bounds the resources a single input may use. Zero values are unlimited.
+type Limits struct {
+	MaxPixels int64 // Maximum width × height of the decoded image
+	MaxBytes  int64 // Maximum input size in bytes
+	MaxFrames int   // Maximum number of frames in an animated image
+}
+
+// DefaultLimits are used by LoadImage and new transformers. They allow any real photo
+// while rejecting decompression bombs such as a small PNG claiming 60000x60000 pixels.
+var DefaultLimits = Limits{
+	MaxPixels: 200_000_000,
+	MaxBytes:  512 << 20,
+	MaxFrames: 1000,
+}
+
+// CheckSize verifies the input size before it is read
+func (l Limits) CheckSize(size int64) error {
+	if l.MaxBytes > 0 && size > l.MaxBytes {
+		return fmt.Errorf("%w: %s exceeds the %s file size limit", ErrImageTooLarge, formatByteSize(size), formatByteSize(l.MaxBytes))
+	}
+	return nil
+}
+
+// Check inspects the image header and rejects inputs whose decoded size would exceed
+// the limits. JPEG and PNG data whose header can't be parsed is rejected as well, since
+// its size is unknown; other unrecognized data passes, so the decoder reports the real
+// error.
+func (l Limits) Check(data []byte) error {
+	if err := l.CheckSize(int64(len(data))); err != nil {
+		return err
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		switch {
+		case isJPEGData(data):
+			// image/jpeg rejects some files the lossless path reads, so ask jpegcodec
+			header, headerErr := jpegcodec.DecodeHeader(data)
+			if headerErr != nil {
+				return fmt.Errorf("invalid JPEG header: %w", err)
+			}
+			return l.CheckPixels(header.Width, header.Height)
+		case isPNGData(data):
+			return fmt.Errorf("invalid PNG header: %w", err)
+		}
+		return nil
+	}
+	if err := l.CheckPixels(config.Width, config.Height); err != nil {
+		return err
+	}
+	if format == "gif" && l.MaxFrames > 0 {
+		frames, err := countGIFFrames(data)
+		if err != nil {
+			return err
+		}
+		if frames > l.MaxFrames {
+			return fmt.Errorf("%w: %d frames exceeds the %d frame limit", ErrImageTooLarge, frames, l.MaxFrames)
+		}
+	}
+	return nil
+}
+
+// CheckPixels verifies decoded dimensions against MaxPixels
+func (l Limits) CheckPixels(width, height int) error {
+	if pixels := int64(width) * int64(height); l.MaxPixels > 0 && pixels > l.MaxPixels {
+		return fmt.Errorf("%w: %dx%d (%s) exceeds the %s limit",
+			ErrImageTooLarge, width, height, formatPixelCount(pixels), formatPixelCount(l.MaxPixels))
+	}
+	return nil
+}
+
+// isPNGData reports whether the data starts with the PNG signature
+func isPNGData(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n"))
+}
+
+// read reads all image data, refusing inputs larger than MaxBytes without buffering them.
+// Data already in memory is not copied and files are read into a buffer of their size.
+func (l Limits) read(r io.Reader) ([]byte, error) {
+	switch v := r.(type) {
+	case *bytes.Buffer:
+		if err := l.CheckSize(int64(v.Len())); err != nil {
+			return nil, err
+		}
+		return v.Next(v.Len()), nil
+	case *os.File:
+		if info, err := v.Stat(); err == nil && info.Mode().IsRegular() {
+			if err := l.CheckSize(info.Size()); err != nil {
+				return nil, err
+			}
+			buf := bytes.NewBuffer(make([]byte, 0, info.Size()+bytes.MinRead))
+			_, err := buf.ReadFrom(v)
+			return buf.Bytes(), err
+		}
+	}
+	
+	if l.MaxBytes > 0 {
+		r = io.LimitReader(r, l.MaxBytes+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if l.MaxBytes > 0 && int64(len(data)) > l.MaxBytes {
+		return nil, fmt.Errorf("%w: input exceeds the %s file size limit", ErrImageTooLarge, formatByteSize(l.MaxBytes))
+	}
+	return data, nil
+}
+
+// countGIFFrames counts the image descriptors of a GIF without decoding any pixels
+func countGIFFrames(data []byte) (int, error) {
+	errTruncated := errors.New("truncated GIF data")
+	if len(data) < 13 {
+		return 0, errTruncated
+	}
+	pos := 13
+	if flags := data[10]; flags&0x80 != 0 {
+		pos += 3 << (flags&7 + 1) // Global color table
+	}
+	
+	// skipSubBlocks skips a sequence of data sub-blocks ending with a zero length
+	skipSubBlocks := func() bool {
+		for pos < len(data) {
+			size := int(data[pos])
+			pos += 1 + size
+			if size == 0 {
+				return true
+			}
+		}
+		return false
+	}
+	
+	frames := 0
+	for pos < len(data) {
+		switch data[pos] {
+		case 0x21: // Extension
+			pos += 2
+			if !skipSubBlocks() {
+				return frames, errTruncated
+			}
+		case 0x2C: // Image descriptor
+			if pos+10 >= len(data) {
+				return frames, errTruncated
+			}
+			frames++
+			flags := data[pos+9]
+			pos += 10
+			if flags&0x80 != 0 {
+				pos += 3 << (flags&7 + 1) // Local color table
+			}
+			pos++ // LZW minimum code size
+			if !skipSubBlocks() {
+				return frames, errTruncated
+			}
+		case 0x3B: // Trailer
+			return frames, nil
+		default:
+			return frames, fmt.Errorf("invalid GIF block type %#x", data[pos])
+		}
+	}
+	return frames, nil
+}
+
+// ParsePixelCount parses a pixel count like "50000000", "50MP" or "0.5MP"
+func ParsePixelCount(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	upper := strings.ToUpper(s)
+	multiplier := 1.0
+	if strings.HasSuffix(upper, "MP") {
+		upper = strings.TrimSuffix(upper, "MP")
+		multiplier = 1_000_000
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(upper), 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid pixel count: %s", s)
+	}
+	return int64(value * multiplier), nil
+}
+
+// formatPixelCount formats a pixel count in megapixels
+func formatPixelCount(pixels int64) string {
+	return strconv.FormatFloat(float64(pixels)/1_000_000, 'f', -1, 64) + "MP"
+}
+
+// byteUnits are the size suffixes accepted by ParseByteSize, largest first
+var byteUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// ParseByteSize parses a size like "1048576", "512KB", "50MB" or "1GB" (binary units)
+func ParseByteSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	upper := strings.ToUpper(s)
+	multiplier := int64(1)
+	for _, unit := range byteUnits {
+		if strings.HasSuffix(upper, unit.suffix) {
+			upper = strings.TrimSuffix(upper, unit.suffix)
+			multiplier = unit.size
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(upper), 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size: %s", s)
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
+// formatByteSize formats a byte count with the largest whole unit
+func formatByteSize(size int64) string {
+	for _, unit := range byteUnits[:3] {
+		if size >= unit.size {
+			return strconv.FormatFloat(float64(size)/float64(unit.size), 'f', 1, 64) + unit.suffix
+		}
+	}
+	return strconv.FormatInt(size, 10) + "B"
+}
+
+// readImage reads all input data and checks it against the transformer's limits
+func (t *Transformer) readImage(input io.Reader) ([]byte, error) {
+	data, err := t.limits.read(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image data: %w", err)
+	}
+	if err := t.limits.Check(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// loadImage is LoadImage with the transformer's limits
+func (t *Transformer) loadImage(input io.Reader) (image.Image, ImageFormat, error) {
+	return LoadImageWithLimits(input, t.limits)
+}
\ No newline at end of file
@@ -0,0 +1,212 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+)
+
+// bombPNG returns a tiny PNG whose header claims the given size
+func bombPNG(t *testing.T, width, height uint32) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	
+	// IHDR data starts after the signature, length and chunk type
+	binary.BigEndian.PutUint32(data[16:], width)
+	binary.BigEndian.PutUint32(data[20:], height)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+// testGIF returns an animated GIF with the given number of frames
+func testGIF(t *testing.T, frames int) []byte {
+	t.Helper()
+	palette := color.Palette{color.Black, color.White}
+	anim := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		anim.Image = append(anim.Image, image.NewPaletted(image.Rect(0, 0, 4, 4), palette))
+		anim.Delay = append(anim.Delay, 10)
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// bombJPEG returns a JPEG with only a frame header claiming the given size and no JFIF
+// segment, which image.DecodeConfig can't read without a scan
+func bombJPEG(width, height uint16) []byte {
+	data := []byte{0xFF, 0xD8, 0xFF, 0xC0, 0, 17, 8}
+	data = binary.BigEndian.AppendUint16(data, height)
+	data = binary.BigEndian.AppendUint16(data, width)
+	data = append(data, 3, 1, 0x22, 0, 2, 0x11, 1, 3, 0x11, 1)
+	return append(data, 0xFF, 0xD9)
+}
+
+func TestLimitsRejectsDecompressionBomb(t *testing.T) {
+	data := bombPNG(t, 60000, 60000)
+	
+	_, _, err := LoadImage(bytes.NewReader(data))
+	if !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("LoadImage() error = %v, want ErrImageTooLarge", err)
+	}
+	
+	transformer := NewTransformer()
+	var out bytes.Buffer
+	err = transformer.Resize(bytes.NewReader(data), &out, ResizeOptions{Width: 100})
+	if !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("Resize() error = %v, want ErrImageTooLarge", err)
+	}
+	
+	// Without a pixel limit the header passes and the decoder fails on the bad data instead
+	_, _, err = LoadImageWithLimits(bytes.NewReader(data), Limits{})
+	if err == nil || errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("LoadImageWithLimits() error = %v, want a decode error", err)
+	}
+}
+
+func TestLimitsCheck(t *testing.T) {
+	small := bombPNG(t, 100, 100)
+	
+	tests := []struct {
+		name    string
+		limits  Limits
+		data    []byte
+		wantErr bool
+	}{
+		{"Within pixel limit", Limits{MaxPixels: 10000}, small, false},
+		{"Over pixel limit", Limits{MaxPixels: 9999}, small, true},
+		{"Over byte limit", Limits{MaxBytes: int64(len(small) - 1)}, small, true},
+		{"Within frame limit", Limits{MaxFrames: 3}, testGIF(t, 3), false},
+		{"Over frame limit", Limits{MaxFrames: 3}, testGIF(t, 4), true},
+		{"Unlimited", Limits{}, bombPNG(t, 60000, 60000), false},
+		{"JPEG without a scan", DefaultLimits, bombJPEG(65535, 65535), true},
+		{"JPEG without a scan within limit", DefaultLimits, bombJPEG(100, 100), false},
+		{"Unrecognized data passes", DefaultLimits, []byte("not an image"), false},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limits.Check(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrImageTooLarge) {
+				t.Errorf("Check() error = %v, want ErrImageTooLarge", err)
+			}
+		})
+	}
+}
+
+func TestLimitsCheckUnreadableHeader(t *testing.T) {
+	// JPEG and PNG data whose size can't be read is rejected rather than let through
+	png := bombPNG(t, 100, 100)
+	for name, data := range map[string][]byte{
+		"JPEG": {0xFF, 0xD8, 0xFF, 0xC0, 0, 3},
+		"PNG":  png[:20],
+	} {
+		if err := DefaultLimits.Check(data); err == nil {
+			t.Errorf("Check(%s) accepted an unreadable header", name)
+		}
+	}
+}
+
+func TestLimitsLosslessJPEG(t *testing.T) {
+	// The lossless paths must not allocate coefficients for a huge frame
+	data := bombJPEG(65535, 65535)
+	transformer := NewTransformer()
+	
+	if _, err := transformer.cropJPEGLossless(data, &bytes.Buffer{}, EdgeCropOptions{Top: CropValue{Value: 8}}); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("cropJPEGLossless() error = %v, want ErrImageTooLarge", err)
+	}
+	if _, err := transformer.rotateJPEGLossless(data, &bytes.Buffer{}, RotateOptions{Angle: 90}); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("rotateJPEGLossless() error = %v, want ErrImageTooLarge", err)
+	}
+	if _, err := transformer.loadScaledJPEG(data, ResizeOptions{Width: 100}); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("loadScaledJPEG() error = %v, want ErrImageTooLarge", err)
+	}
+}
+
+func TestLimitsRead(t *testing.T) {
+	data := bytes.Repeat([]byte{1}, 100)
+	limits := Limits{MaxBytes: 99}
+	
+	// A plain reader is cut off after MaxBytes+1 bytes
+	if _, err := limits.read(struct{ *bytes.Reader }{bytes.NewReader(data)}); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("read() error = %v, want ErrImageTooLarge", err)
+	}
+	if _, err := limits.read(bytes.NewBuffer(data)); !errors.Is(err, ErrImageTooLarge) {
+		t.Errorf("read() from buffer error = %v, want ErrImageTooLarge", err)
+	}
+	
+	got, err := Limits{MaxBytes: 100}.read(bytes.NewReader(data))
+	if err != nil || !bytes.Equal(got, data) {
+		t.Errorf("read() = %d bytes, %v; want %d bytes", len(got), err, len(data))
+	}
+}
+
+func TestParsePixelCount(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{"50000000", 50_000_000, false},
+		{"50MP", 50_000_000, false},
+		{"0.5mp", 500_000, false},
+		{"0", 0, false},
+		{"abc", 0, true},
+		{"-1MP", 0, true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePixelCount(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePixelCount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePixelCount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{"1048576", 1 << 20, false},
+		{"512KB", 512 << 10, false},
+		{"50MB", 50 << 20, false},
+		{"1gb", 1 << 30, false},
+		{"100B", 100, false},
+		{"MB", 0, true},
+		{"-5MB", 0, true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseByteSize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseByteSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseByteSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
\ No newline at end of file
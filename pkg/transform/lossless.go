@@ -0,0 +1,185 @@
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	
+	"github.com/allieus/imagekit/pkg/container"
+	"github.com/allieus/imagekit/pkg/jpegcodec"
+)
+
+// orientationTransforms maps EXIF orientation values to the transform that displays
+// the image upright
+var orientationTransforms = map[int]jpegcodec.Transform{
+	2: jpegcodec.FlipHorizontal,
+	3: jpegcodec.Rotate180,
+	4: jpegcodec.FlipVertical,
+	5: jpegcodec.Transpose,
+	6: jpegcodec.Rotate90,
+	7: jpegcodec.Transverse,
+	8: jpegcodec.Rotate270,
+}
+
+// isJPEGData reports whether the data starts with a JPEG SOI marker
+func isJPEGData(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0xFF && data[1] == 0xD8
+}
+
+// exifOrientation finds the orientation tag in the EXIF segment. It returns the
+// orientation (1 when absent), the segment index and the offset of the tag value.
+func exifOrientation(segments []jpegcodec.Segment) (int, int, int) {
+	for i, seg := range segments {
+		if seg.Marker != container.MarkerAPP1 {
+			continue
+		}
+		orientation, offset, ok := parseOrientation(seg.Data)
+		if !ok {
+			continue
+		}
+		if offset < 0 {
+			return 1, -1, 0
+		}
+		return orientation, i, offset
+	}
+	return 1, -1, 0
+}
+
+// resetOrientation returns a copy of the segments with the orientation tag set to 1
+func resetOrientation(segments []jpegcodec.Segment, index, offset int) []jpegcodec.Segment {
+	result := append([]jpegcodec.Segment(nil), segments...)
+	data := append([]byte(nil), segments[index].Data...)
+	if ifd, ok := parseExifIFD0(data); ok {
+		ifd.order.PutUint16(data[offset:], 1)
+	}
+	result[index].Data = data
+	return result
+}
+
+// checkJPEGPixels checks the frame size of a JPEG against the pixel limit before its
+// coefficients are allocated. Unreadable headers are left to the decoder.
+func (t *Transformer) checkJPEGPixels(data []byte) error {
+	header, err := jpegcodec.DecodeHeader(data)
+	if err != nil {
+		return nil
+	}
+	return t.limits.CheckPixels(header.Width, header.Height)
+}
+
+// jpegCoefficients decodes the DCT coefficients of a JPEG and applies its EXIF
+// orientation, so the geometry matches what LoadImage returns. Orientations that
+// would trim partial blocks are only applied when trim is set.
+func (t *Transformer) jpegCoefficients(data []byte, trim bool) (*jpegcodec.Coefficients, bool) {
+	c, err := jpegcodec.DecodeBytes(data)
+	if err != nil {
+		t.logf("lossless JPEG path unavailable: %v", err)
+		return nil, false
+	}
+	
+	orientation, index, offset := exifOrientation(c.Segments)
+	if orientation == 1 {
+		return c, true
+	}
+	transform := orientationTransforms[orientation]
+	if !c.Perfect(transform) && !trim {
+		t.logf("EXIF orientation %d cannot be applied without trimming; re-encoding", orientation)
+		return nil, false
+	}
+	oriented, err := c.Apply(transform)
+	if err != nil {
+		t.logf("lossless JPEG path unavailable: %v", err)
+		return nil, false
+	}
+	
+	// Reset the tag so viewers don't apply the orientation a second time
+	oriented.Segments = resetOrientation(c.Segments, index, offset)
+	t.logf("applied EXIF orientation %d losslessly", orientation)
+	return oriented, true
+}
+
+// writeJPEGCoefficients writes coefficients with optimized Huffman tables, keeping
+// progressive files progressive. The file is encoded in memory first so a failure
+// leaves nothing in w.
+func writeJPEGCoefficients(w io.Writer, c *jpegcodec.Coefficients) error {
+	buf := &bytes.Buffer{}
+	err := jpegcodec.Write(buf, c, &jpegcodec.WriteOptions{
+		Progressive:     c.Progressive,
+		OptimizeHuffman: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// cropJPEGLossless crops a JPEG without re-encoding when the crop origin is on the
+// MCU grid, or snaps the origin onto it when options.Lossless is set. It reports
+// false when the caller should fall back to decoding.
+func (t *Transformer) cropJPEGLossless(data []byte, output io.Writer, options EdgeCropOptions) (bool, error) {
+	if err := t.checkJPEGPixels(data); err != nil {
+		return false, err
+	}
+	c, ok := t.jpegCoefficients(data, options.Lossless)
+	if !ok {
+		return false, nil
+	}
+	if err := ValidateCropOptions(options, c.Width, c.Height); err != nil {
+		return false, fmt.Errorf("invalid crop options: %w", err)
+	}
+	
+	rect := options.rect(c.Width, c.Height)
+	mcuWidth, mcuHeight := c.MCUSize()
+	if rect.Min.X%mcuWidth != 0 || rect.Min.Y%mcuHeight != 0 {
+		if !options.Lossless {
+			t.logf("crop origin (%d, %d) is not on the %dx%d JPEG block grid; re-encoding",
+				rect.Min.X, rect.Min.Y, mcuWidth, mcuHeight)
+			return false, nil
+		}
+		rect.Min = image.Pt(rect.Min.X/mcuWidth*mcuWidth, rect.Min.Y/mcuHeight*mcuHeight)
+		t.logf("snapped crop origin to (%d, %d) for lossless cropping", rect.Min.X, rect.Min.Y)
+	}
+	
+	cropped, err := c.Crop(rect)
+	if err != nil {
+		return false, fmt.Errorf("failed to crop image: %w", err)
+	}
+	t.logf("cropped JPEG losslessly to %dx%d", cropped.Width, cropped.Height)
+	return true, writeJPEGCoefficients(output, cropped)
+}
+
+// rotateJPEGLossless rotates and flips a JPEG without re-encoding when the image is a
+// whole number of MCUs, or trims the partial MCUs when options.Lossless is set. It
+// reports false when the caller should fall back to decoding.
+func (t *Transformer) rotateJPEGLossless(data []byte, output io.Writer, options RotateOptions) (bool, error) {
+	if err := t.checkJPEGPixels(data); err != nil {
+		return false, err
+	}
+	c, ok := t.jpegCoefficients(data, options.Lossless)
+	if !ok {
+		return false, nil
+	}
+	
+	transforms := options.jpegTransforms()
+	if !c.Perfect(transforms...) && !options.Lossless {
+		mcuWidth, mcuHeight := c.MCUSize()
+		t.logf("%dx%d is not a whole number of %dx%d JPEG blocks; re-encoding",
+			c.Width, c.Height, mcuWidth, mcuHeight)
+		return false, nil
+	}
+	
+	result := c
+	for _, transform := range transforms {
+		var err error
+		if result, err = result.Apply(transform); err != nil {
+			t.logf("lossless JPEG path unavailable: %v", err)
+			return false, nil
+		}
+	}
+	if result.Width*result.Height != c.Width*c.Height {
+		t.logf("trimmed partial JPEG blocks: %dx%d → %dx%d", c.Width, c.Height, result.Width, result.Height)
+	}
+	t.logf("rotated JPEG losslessly")
+	return true, writeJPEGCoefficients(output, result)
+}
\ No newline at end of file
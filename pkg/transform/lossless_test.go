@@ -0,0 +1,257 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+	
+	"github.com/allieus/imagekit/pkg/jpegcodec"
+)
+
+// losslessTestJPEG encodes a gradient with the coefficient encoder, optionally with
+// an EXIF orientation tag
+func losslessTestJPEG(t *testing.T, width, height, orientation int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 255 / width), uint8(y * 255 / height), uint8(x * y % 256), 255})
+		}
+	}
+	c := jpegcodec.FromImage(img, 85, jpegcodec.Subsampling420)
+	if orientation > 0 {
+		exif := []byte("Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08\x00\x01")
+		exif = append(exif, 0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, byte(orientation), 0x00, 0x00)
+		exif = binary.BigEndian.AppendUint32(exif, 0)
+		c.Segments = append(c.Segments, jpegcodec.Segment{Marker: 0xE1, Data: exif})
+	}
+	buf := &bytes.Buffer{}
+	if err := jpegcodec.Write(buf, c, nil); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+// decodeCoefficients decodes JPEG data into coefficients
+func decodeCoefficients(t *testing.T, data []byte) *jpegcodec.Coefficients {
+	t.Helper()
+	c, err := jpegcodec.DecodeBytes(data)
+	if err != nil {
+		t.Fatalf("DecodeBytes() error = %v", err)
+	}
+	return c
+}
+
+func TestCropEdgesLosslessJPEG(t *testing.T) {
+	data := losslessTestJPEG(t, 100, 80, 0)
+	original := decodeCoefficients(t, data)
+	
+	tests := []struct {
+		name       string
+		opts       EdgeCropOptions
+		wantWidth  int
+		wantHeight int
+		wantBlocks bool // Whether the DCT blocks must be copied unchanged
+	}{
+		{
+			name:       "Aligned crop keeps coefficients",
+			opts:       EdgeCropOptions{Top: CropValue{Value: 16}, Left: CropValue{Value: 32}, Right: CropValue{Value: 7}},
+			wantWidth:  61,
+			wantHeight: 64,
+			wantBlocks: true,
+		},
+		{
+			name:       "Unaligned crop re-encodes",
+			opts:       EdgeCropOptions{Left: CropValue{Value: 5}},
+			wantWidth:  95,
+			wantHeight: 80,
+		},
+		{
+			name:       "Lossless snaps the origin",
+			opts:       EdgeCropOptions{Top: CropValue{Value: 20}, Left: CropValue{Value: 5}, Lossless: true},
+			wantWidth:  100,
+			wantHeight: 64,
+			wantBlocks: true,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			if err := NewTransformer().CropEdges(bytes.NewReader(data), output, tt.opts); err != nil {
+				t.Fatalf("CropEdges() error = %v", err)
+			}
+			
+			cropped := decodeCoefficients(t, output.Bytes())
+			if cropped.Width != tt.wantWidth || cropped.Height != tt.wantHeight {
+				t.Fatalf("size = %dx%d, want %dx%d", cropped.Width, cropped.Height, tt.wantWidth, tt.wantHeight)
+			}
+			
+			same := *cropped.Quant[0] == *original.Quant[0]
+			if same {
+				luma := cropped.Components[0]
+				offsetX := (100 - tt.wantWidth - tt.opts.Right.Value) / 8
+				offsetY := (80 - tt.wantHeight - tt.opts.Bottom.Value) / 8
+				for by := 0; by < tt.wantHeight/8 && same; by++ {
+					for bx := 0; bx < tt.wantWidth/8 && same; bx++ {
+						same = *luma.Block(bx, by) == *original.Components[0].Block(bx+offsetX, by+offsetY)
+					}
+				}
+			}
+			if same != tt.wantBlocks {
+				t.Errorf("DCT blocks unchanged = %v, want %v", same, tt.wantBlocks)
+			}
+		})
+	}
+}
+
+func TestCropEdgesLosslessAppliesOrientation(t *testing.T) {
+	// Orientation 6 means the stored 64x48 image is displayed as 48x64
+	data := losslessTestJPEG(t, 64, 48, 6)
+	output := &bytes.Buffer{}
+	options := EdgeCropOptions{Bottom: CropValue{Value: 16}}
+	if err := NewTransformer().CropEdges(bytes.NewReader(data), output, options); err != nil {
+		t.Fatalf("CropEdges() error = %v", err)
+	}
+	
+	cropped := decodeCoefficients(t, output.Bytes())
+	if cropped.Width != 48 || cropped.Height != 48 {
+		t.Errorf("size = %dx%d, want 48x48", cropped.Width, cropped.Height)
+	}
+	if orientation, _, _ := exifOrientation(cropped.Segments); orientation != 1 {
+		t.Errorf("orientation = %d, want 1 after applying it", orientation)
+	}
+}
+
+func TestCropEdgesErrors(t *testing.T) {
+	data := losslessTestJPEG(t, 64, 48, 1)
+	tests := []struct {
+		name    string
+		data    []byte
+		options EdgeCropOptions
+		want    string
+	}{
+		{"Crop too large", data, EdgeCropOptions{Top: CropValue{Value: 48}}, "invalid crop options"},
+		{"Truncated scan", data[:len(data)/2], EdgeCropOptions{Top: CropValue{Value: 8}}, "failed to load image"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			err := NewTransformer().CropEdges(bytes.NewReader(tt.data), output, tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("CropEdges() error = %v, want %q", err, tt.want)
+			}
+			if output.Len() != 0 {
+				t.Errorf("CropEdges() wrote %d bytes on failure", output.Len())
+			}
+		})
+	}
+}
+
+func TestTransformerRotate(t *testing.T) {
+	tests := []struct {
+		name       string
+		width      int
+		height     int
+		opts       RotateOptions
+		wantWidth  int
+		wantHeight int
+		wantExact  bool // Whether the DCT blocks must be an exact transform of the input
+	}{
+		{name: "Aligned 90", width: 64, height: 48, opts: RotateOptions{Angle: 90}, wantWidth: 48, wantHeight: 64, wantExact: true},
+		{name: "Aligned 180 with flip", width: 64, height: 48, opts: RotateOptions{Angle: 180, Flip: FlipHorizontal}, wantWidth: 64, wantHeight: 48, wantExact: true},
+		{name: "Negative angle", width: 64, height: 48, opts: RotateOptions{Angle: -90}, wantWidth: 48, wantHeight: 64, wantExact: true},
+		{name: "Unaligned re-encodes", width: 70, height: 37, opts: RotateOptions{Angle: 90}, wantWidth: 37, wantHeight: 70},
+		{name: "Unaligned lossless trims", width: 70, height: 37, opts: RotateOptions{Angle: 90, Lossless: true}, wantWidth: 32, wantHeight: 70, wantExact: true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := losslessTestJPEG(t, tt.width, tt.height, 0)
+			output := &bytes.Buffer{}
+			if err := NewTransformer().Rotate(bytes.NewReader(data), output, tt.opts); err != nil {
+				t.Fatalf("Rotate() error = %v", err)
+			}
+			
+			rotated := decodeCoefficients(t, output.Bytes())
+			if rotated.Width != tt.wantWidth || rotated.Height != tt.wantHeight {
+				t.Fatalf("size = %dx%d, want %dx%d", rotated.Width, rotated.Height, tt.wantWidth, tt.wantHeight)
+			}
+			
+			// Undo the transforms in the DCT domain and compare with the input
+			original := decodeCoefficients(t, data)
+			undo := RotateOptions{Angle: -tt.opts.Angle}
+			restored := rotated
+			if tt.opts.Flip != FlipNone {
+				restored = applyTransforms(t, restored, RotateOptions{Flip: tt.opts.Flip}.jpegTransforms())
+			}
+			restored = applyTransforms(t, restored, undo.jpegTransforms())
+			exact := *restored.Quant[0] == *original.Quant[0]
+			luma := restored.Components[0]
+			for by := 0; by < restored.Height/16*2 && exact; by++ {
+				for bx := 0; bx < restored.Width/16*2 && exact; bx++ {
+					exact = *luma.Block(bx, by) == *original.Components[0].Block(bx, by)
+				}
+			}
+			if exact != tt.wantExact {
+				t.Errorf("exact DCT transform = %v, want %v", exact, tt.wantExact)
+			}
+		})
+	}
+}
+
+// applyTransforms applies DCT-domain transforms in order
+func applyTransforms(t *testing.T, c *jpegcodec.Coefficients, transforms []jpegcodec.Transform) *jpegcodec.Coefficients {
+	t.Helper()
+	for _, transform := range transforms {
+		var err error
+		if c, err = c.Apply(transform); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+	}
+	return c
+}
+
+func TestTransformerRotatePNG(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(0, 0, color.NRGBA{255, 0, 0, 255})
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	
+	output := &bytes.Buffer{}
+	if err := NewTransformer().Rotate(buf, output, RotateOptions{Angle: 90}); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	rotated, err := png.Decode(output)
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	if rotated.Bounds().Dx() != 2 || rotated.Bounds().Dy() != 3 {
+		t.Fatalf("size = %v, want 2x3", rotated.Bounds().Size())
+	}
+	// The top-left pixel moves to the top-right corner when rotating clockwise
+	if r, _, _, _ := rotated.At(1, 0).RGBA(); r>>8 != 255 {
+		t.Errorf("pixel (1, 0) = %v, want red", rotated.At(1, 0))
+	}
+}
+
+func TestValidateRotateOptions(t *testing.T) {
+	if err := ValidateRotateOptions(RotateOptions{Angle: 45}); err == nil {
+		t.Error("ValidateRotateOptions() expected an error for 45 degrees")
+	}
+	if err := ValidateRotateOptions(RotateOptions{Angle: 270, Flip: FlipVertical}); err != nil {
+		t.Errorf("ValidateRotateOptions() error = %v", err)
+	}
+	if _, err := ParseFlipMode("diagonal"); err == nil {
+		t.Error("ParseFlipMode() expected an error for an unknown mode")
+	}
+	if mode, _ := ParseFlipMode("h"); mode != FlipHorizontal {
+		t.Errorf("ParseFlipMode(h) = %q, want %q", mode, FlipHorizontal)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,242 @@
+package transform
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/jpegcodec"
+	"github.com/disintegration/imaging"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// MontageOptions contains options for contact sheets of thumbnails
+type MontageOptions struct {
+	Columns    int
+	Rows       int // Rows per page (0 = one page)
+	TileWidth  int
+	TileHeight int
+	Spacing    int         // Pixels around and between tiles
+	Fit        bool        // Show whole images instead of cropping them to fill the tile
+	Label      string      // Label template, e.g. "{name} {width}x{height}" ("" = no labels)
+	Font       []byte      // TrueType or OpenType font data (nil = Go Regular)
+	FontSize   float64     // Label size in pixels (0 = 12)
+	Background color.Color // Page color (nil = white)
+}
+
+// MontageItem is an image placed on a contact sheet
+type MontageItem struct {
+	Name  string // File name shown by the {name} label
+	Input io.Reader
+}
+
+// MontageLayout is the geometry of a contact sheet page
+type MontageLayout struct {
+	PerPage     int // Tiles per page (0 = all)
+	labelHeight int
+	face        font.Face
+	options     MontageOptions
+}
+
+// NewMontageLayout validates the options and loads the label font
+func NewMontageLayout(options MontageOptions) (*MontageLayout, error) {
+	if options.Columns <= 0 {
+		return nil, fmt.Errorf("columns must be positive: %d", options.Columns)
+	}
+	if options.Rows < 0 || options.Spacing < 0 {
+		return nil, fmt.Errorf("rows and spacing cannot be negative")
+	}
+	if options.TileWidth <= 0 || options.TileHeight <= 0 {
+		return nil, fmt.Errorf("tile size must be positive: %dx%d", options.TileWidth, options.TileHeight)
+	}
+	if options.FontSize == 0 {
+		options.FontSize = 12
+	}
+	if options.Background == nil {
+		options.Background = color.White
+	}
+	
+	l := &MontageLayout{PerPage: options.Columns * options.Rows, options: options}
+	if options.Label != "" {
+		face, err := labelFace(options.Font, options.FontSize)
+		if err != nil {
+			return nil, err
+		}
+		metrics := face.Metrics()
+		l.face = face
+		l.labelHeight = (metrics.Ascent + metrics.Descent).Ceil() + 4
+	}
+	return l, nil
+}
+
+// labelFace loads a font, accepting collections by using their first font
+func labelFace(data []byte, size float64) (font.Face, error) {
+	if data == nil {
+		data = goregular.TTF
+	}
+	f, err := opentype.Parse(data)
+	if err != nil {
+		collection, collectionErr := opentype.ParseCollection(data)
+		if collectionErr != nil {
+			return nil, fmt.Errorf("failed to parse font: %w", err)
+		}
+		if f, err = collection.Font(0); err != nil {
+			return nil, fmt.Errorf("failed to parse font: %w", err)
+		}
+	}
+	return opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+}
+
+// Pages returns the number of pages needed for count images
+func (l *MontageLayout) Pages(count int) int {
+	if l.PerPage == 0 {
+		return 1
+	}
+	return (count + l.PerPage - 1) / l.PerPage
+}
+
+// PageSize returns the size of a page holding count images. The last page of a
+// montage only has the rows it needs.
+func (l *MontageLayout) PageSize(count int) image.Point {
+	o := l.options
+	rows := (count + o.Columns - 1) / o.Columns
+	return image.Pt(
+		o.Columns*o.TileWidth+(o.Columns+1)*o.Spacing,
+		rows*(o.TileHeight+l.labelHeight)+(rows+1)*o.Spacing,
+	)
+}
+
+// tile returns the thumbnail area of tile i
+func (l *MontageLayout) tile(i int) image.Rectangle {
+	o := l.options
+	column, row := i%o.Columns, i/o.Columns
+	corner := image.Pt(
+		o.Spacing+column*(o.TileWidth+o.Spacing),
+		o.Spacing+row*(o.TileHeight+l.labelHeight+o.Spacing),
+	)
+	return image.Rectangle{Min: corner, Max: corner.Add(image.Pt(o.TileWidth, o.TileHeight))}
+}
+
+// thumbnail decodes image data at the tile size and describes the original, with its
+// displayed size. JPEGs are decoded at a reduced DCT scale when much larger.
+func (l *MontageLayout) thumbnail(data []byte) (image.Image, ImageInfo, error) {
+	o := l.options
+	info := ImageInfo{Format: FormatJPEG, Resolution: imageResolution(data)}
+	var img image.Image
+	if isJPEGData(data) {
+		if header, err := jpegcodec.DecodeHeader(data); err == nil {
+			orientation := jpegOrientation(data)
+			info.Width, info.Height = header.Width, header.Height
+			if orientation >= 5 {
+				info.Width, info.Height = info.Height, info.Width
+			}
+			if scale := jpegDecodeScale(info.Width, info.Height, o.TileWidth, o.TileHeight); scale > 1 {
+				if scaled, err := jpegcodec.DecodeScaled(data, scale); err == nil {
+					img = orientImage(scaled, orientation)
+				}
+			}
+		}
+	}
+	if img == nil {
+		decoded, format, err := decodeImage(data)
+		if err != nil {
+			return nil, info, fmt.Errorf("failed to load image: %w", err)
+		}
+		img = decoded
+		info.Width, info.Height, info.Format = img.Bounds().Dx(), img.Bounds().Dy(), format
+	}
+	
+	if o.Fit {
+		return imaging.Fit(img, o.TileWidth, o.TileHeight, imaging.Lanczos), info, nil
+	}
+	return Thumbnail(img, o.TileWidth, o.TileHeight), info, nil
+}
+
+// label fills in the label template for an image
+func (l *MontageLayout) label(index int, name string, info ImageInfo, size int) string {
+	return strings.NewReplacer(
+		"{index}", strconv.Itoa(index),
+		"{name}", filepath.Base(name),
+		"{width}", strconv.Itoa(info.Width),
+		"{height}", strconv.Itoa(info.Height),
+		"{size}", formatByteSize(int64(size)),
+		"{format}", string(info.Format),
+		"{dpi}", info.Resolution.String(),
+	).Replace(l.options.Label)
+}
+
+// drawLabel writes text centered below a tile, shortened with an ellipsis to fit
+func (l *MontageLayout) drawLabel(page draw.Image, tile image.Rectangle, text string) {
+	d := &font.Drawer{Dst: page, Src: image.NewUniform(labelColor(l.options.Background)), Face: l.face}
+	limit := fixed.I(tile.Dx())
+	if d.MeasureString(text) > limit {
+		runes := []rune(text)
+		for len(runes) > 0 && d.MeasureString(string(runes)+"…") > limit {
+			runes = runes[:len(runes)-1]
+		}
+		text = string(runes) + "…"
+	}
+	
+	width := d.MeasureString(text)
+	d.Dot = fixed.Point26_6{
+		X: fixed.I(tile.Min.X) + (limit-width)/2,
+		Y: fixed.I(tile.Max.Y+2) + l.face.Metrics().Ascent,
+	}
+	d.DrawString(text)
+}
+
+// labelColor picks black or white text, whichever stands out on the background
+func labelColor(background color.Color) color.Color {
+	gray := color.GrayModel.Convert(background).(color.Gray)
+	if gray.Y < 128 {
+		return color.White
+	}
+	return color.Black
+}
+
+// Montage renders one contact sheet page of up to PerPage thumbnails. Index is the
+// number of the first image, for the {index} label.
+func (t *Transformer) Montage(items []MontageItem, index int, output io.Writer, format ImageFormat, layout *MontageLayout) error {
+	if layout.PerPage > 0 && len(items) > layout.PerPage {
+		return fmt.Errorf("%d images do not fit on a page of %d", len(items), layout.PerPage)
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("no images to place")
+	}
+	if err := ValidateOutputFormat(format); err != nil {
+		return err
+	}
+	
+	size := layout.PageSize(len(items))
+	page := image.NewNRGBA(image.Rectangle{Max: size})
+	draw.Draw(page, page.Bounds(), image.NewUniform(layout.options.Background), image.Point{}, draw.Src)
+	for i, item := range items {
+		data, err := t.readImage(item.Input)
+		if err != nil {
+			return fmt.Errorf("%s: %w", item.Name, err)
+		}
+		thumb, info, err := layout.thumbnail(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", item.Name, err)
+		}
+		
+		// Center the thumbnail, which is smaller than the tile in fit mode
+		tile := layout.tile(i)
+		corner := tile.Min.Add(tile.Size().Sub(thumb.Bounds().Size()).Div(2))
+		draw.Draw(page, image.Rectangle{Min: corner, Max: corner.Add(thumb.Bounds().Size())}, thumb, thumb.Bounds().Min, draw.Over)
+		if layout.face != nil {
+			layout.drawLabel(page, tile, layout.label(index+i, item.Name, info, len(data)))
+		}
+	}
+	t.logf("placed %d thumbnails on a %dx%d page", len(items), size.X, size.Y)
+	
+	return t.save(output, page, format, SaveOptions{Quality: 90})
+}
\ No newline at end of file
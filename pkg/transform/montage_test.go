@@ -0,0 +1,109 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"testing"
+)
+
+func TestMontageLayout(t *testing.T) {
+	layout, err := NewMontageLayout(MontageOptions{Columns: 3, Rows: 2, TileWidth: 100, TileHeight: 80, Spacing: 10})
+	if err != nil {
+		t.Fatalf("NewMontageLayout() error = %v", err)
+	}
+	if layout.PerPage != 6 || layout.Pages(13) != 3 || layout.Pages(12) != 2 {
+		t.Errorf("PerPage = %d, Pages(13) = %d, Pages(12) = %d", layout.PerPage, layout.Pages(13), layout.Pages(12))
+	}
+	// Three columns of 100 with four gaps of 10, and the last page only has one row
+	if size := layout.PageSize(6); size != image.Pt(340, 190) {
+		t.Errorf("PageSize(6) = %v, want (340,190)", size)
+	}
+	if size := layout.PageSize(1); size != image.Pt(340, 100) {
+		t.Errorf("PageSize(1) = %v, want (340,100)", size)
+	}
+	if tile := layout.tile(4); tile != image.Rect(120, 100, 220, 180) {
+		t.Errorf("tile(4) = %v", tile)
+	}
+	
+	unpaged, _ := NewMontageLayout(MontageOptions{Columns: 3, TileWidth: 100, TileHeight: 80})
+	if unpaged.Pages(100) != 1 {
+		t.Errorf("Pages() without rows = %d, want 1", unpaged.Pages(100))
+	}
+	
+	for _, options := range []MontageOptions{
+		{Columns: 0, TileWidth: 100, TileHeight: 100},
+		{Columns: 3, TileWidth: 0, TileHeight: 100},
+		{Columns: 3, Rows: -1, TileWidth: 100, TileHeight: 100},
+		{Columns: 3, TileWidth: 100, TileHeight: 100, Label: "{name}", Font: []byte("not a font")},
+	} {
+		if _, err := NewMontageLayout(options); err == nil {
+			t.Errorf("NewMontageLayout(%+v) should fail", options)
+		}
+	}
+}
+
+func TestMontageLabel(t *testing.T) {
+	layout, err := NewMontageLayout(MontageOptions{Columns: 1, TileWidth: 10, TileHeight: 10, Label: "{index}. {name} {width}x{height} {format} {size}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := layout.label(3, "photos/beach.jpg", ImageInfo{Width: 1200, Height: 800, Format: FormatJPEG}, 2048)
+	if want := "3. beach.jpg 1200x800 jpeg 2.0KB"; got != want {
+		t.Errorf("label() = %q, want %q", got, want)
+	}
+}
+
+func TestMontageRender(t *testing.T) {
+	layout, err := NewMontageLayout(MontageOptions{Columns: 2, Rows: 1, TileWidth: 60, TileHeight: 60, Spacing: 4, Fit: true, Label: "{width}x{height}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	
+	// A wide black image leaves white bands above and below it in fit mode
+	wide := &bytes.Buffer{}
+	if err := png.Encode(wide, image.NewGray(image.Rect(0, 0, 120, 60))); err != nil {
+		t.Fatal(err)
+	}
+	items := []MontageItem{
+		{Name: "a.png", Input: bytes.NewReader(wide.Bytes())},
+		{Name: "b.png", Input: bytes.NewReader(wide.Bytes())},
+	}
+	output := &bytes.Buffer{}
+	if err := NewTransformer().Montage(items, 1, output, FormatPNG, layout); err != nil {
+		t.Fatalf("Montage() error = %v", err)
+	}
+	
+	page, err := png.Decode(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Bounds().Size() != layout.PageSize(2) {
+		t.Errorf("page size = %v, want %v", page.Bounds().Size(), layout.PageSize(2))
+	}
+	tile := layout.tile(1)
+	gray := func(x, y int) uint8 { return color.GrayModel.Convert(page.At(x, y)).(color.Gray).Y }
+	if gray(tile.Min.X+30, tile.Min.Y+5) != 0xFF || gray(tile.Min.X+30, tile.Min.Y+30) != 0 {
+		t.Error("fit thumbnail is not centered in its tile")
+	}
+	
+	// The label band below the tile has dark text pixels
+	var dark int
+	for y := tile.Max.Y; y < tile.Max.Y+layout.labelHeight; y++ {
+		for x := tile.Min.X; x < tile.Max.X; x++ {
+			if gray(x, y) < 0x80 {
+				dark++
+			}
+		}
+	}
+	if dark == 0 {
+		t.Error("label was not drawn")
+	}
+	
+	tooMany := []MontageItem{items[0], items[1], {Name: "c.png", Input: io.MultiReader()}}
+	if err := NewTransformer().Montage(tooMany, 1, io.Discard, FormatPNG, layout); err == nil {
+		t.Error("Montage() should fail with more images than fit on a page")
+	}
+}
\ No newline at end of file
@@ -75,4 +75,23 @@ func TestLoadImageFormats(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	for _, orientation := range []int{1, 6, 8} {
+		data := losslessTestJPEG(t, 16, 8, orientation)
+		if got := jpegOrientation(data); got != orientation {
+			t.Errorf("jpegOrientation() = %d, want %d", got, orientation)
+		}
+		info, err := ReadImageInfo(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := map[bool]int{true: 8, false: 16}[orientation >= 5]; info.Width != want {
+			t.Errorf("ReadImageInfo() width = %d with orientation %d, want %d", info.Width, orientation, want)
+		}
+	}
+	if got := jpegOrientation(losslessTestJPEG(t, 16, 8, 0)); got != 1 {
+		t.Errorf("jpegOrientation() without EXIF = %d, want 1", got)
+	}
+}
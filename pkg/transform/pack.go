@@ -0,0 +1,171 @@
+package transform
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sort"
+	"strings"
+)
+
+// PackingMethod selects how rectangles are arranged on a sheet
+type PackingMethod string
+
+const (
+	PackMaxRects PackingMethod = "maxrects" // Topmost free rectangle with the best short side fit, the tightest
+	PackShelf    PackingMethod = "shelf"    // Rows of rectangles sorted by height, simple and predictable
+)
+
+// ParsePackingMethod parses a packing method name
+func ParsePackingMethod(s string) (PackingMethod, error) {
+	switch PackingMethod(strings.ToLower(strings.TrimSpace(s))) {
+	case PackMaxRects, "":
+		return PackMaxRects, nil
+	case PackShelf:
+		return PackShelf, nil
+	default:
+		return "", fmt.Errorf("unsupported packing method: %s (use maxrects or shelf)", s)
+	}
+}
+
+// PackRects places rectangles of the given sizes without overlap, keeping padding
+// pixels between them. It tries several sheet widths and returns the positions with
+// the smallest sheet.
+func PackRects(sizes []image.Point, padding int, method PackingMethod) ([]image.Point, image.Point, error) {
+	if len(sizes) == 0 {
+		return nil, image.Point{}, fmt.Errorf("no rectangles to pack")
+	}
+	padded := make([]image.Point, len(sizes))
+	var area float64
+	var maxWidth, sumWidth int
+	for i, size := range sizes {
+		if size.X <= 0 || size.Y <= 0 {
+			return nil, image.Point{}, fmt.Errorf("rectangle %d has no area: %v", i, size)
+		}
+		padded[i] = size.Add(image.Pt(padding, padding))
+		area += float64(padded[i].X) * float64(padded[i].Y)
+		maxWidth = max(maxWidth, padded[i].X)
+		sumWidth += padded[i].X
+	}
+	
+	// Larger rectangles first, by height then width, keeping the input order for ties
+	order := make([]int, len(sizes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		pa, pb := padded[order[a]], padded[order[b]]
+		if pa.Y != pb.Y {
+			return pa.Y > pb.Y
+		}
+		return pa.X > pb.X
+	})
+	
+	var best []image.Point
+	var bestSize image.Point
+	for _, factor := range []float64{1, 1.1, 1.25, 1.5, 2} {
+		width := min(max(int(math.Ceil(math.Sqrt(area)*factor)), maxWidth), sumWidth)
+		var positions []image.Point
+		var extent image.Point
+		if method == PackShelf {
+			positions, extent = packShelf(padded, order, width)
+		} else {
+			positions, extent = packMaxRects(padded, order, width)
+		}
+		if best == nil || extent.X*extent.Y < bestSize.X*bestSize.Y {
+			best, bestSize = positions, extent
+		}
+	}
+	// The padding after the last column and row is not needed
+	return best, bestSize.Sub(image.Pt(padding, padding)), nil
+}
+
+// packShelf fills rows left to right, starting a new row when one is full
+func packShelf(sizes []image.Point, order []int, width int) ([]image.Point, image.Point) {
+	positions := make([]image.Point, len(sizes))
+	var x, y, rowHeight int
+	var extent image.Point
+	for _, i := range order {
+		if x > 0 && x+sizes[i].X > width {
+			x, y, rowHeight = 0, y+rowHeight, 0
+		}
+		positions[i] = image.Pt(x, y)
+		x += sizes[i].X
+		rowHeight = max(rowHeight, sizes[i].Y)
+		extent = image.Pt(max(extent.X, x), max(extent.Y, y+sizes[i].Y))
+	}
+	return positions, extent
+}
+
+// packMaxRects keeps a list of maximal free rectangles and puts each rectangle
+// where it leaves the shortest leftover side
+func packMaxRects(sizes []image.Point, order []int, width int) ([]image.Point, image.Point) {
+	height := 0
+	for _, size := range sizes {
+		height += size.Y
+	}
+	free := []image.Rectangle{image.Rect(0, 0, width, height)}
+	positions := make([]image.Point, len(sizes))
+	var extent image.Point
+	
+	for _, i := range order {
+		size := sizes[i]
+		bestIndex, bestShort, bestLong := -1, 0, 0
+		for j, r := range free {
+			if size.X > r.Dx() || size.Y > r.Dy() {
+				continue
+			}
+			dx, dy := r.Dx()-size.X, r.Dy()-size.Y
+			short, long := min(dx, dy), max(dx, dy)
+			// Prefer higher positions so the sheet stays short
+			if bestIndex < 0 || r.Min.Y < free[bestIndex].Min.Y ||
+				r.Min.Y == free[bestIndex].Min.Y && (short < bestShort || short == bestShort && long < bestLong) {
+				bestIndex, bestShort, bestLong = j, short, long
+			}
+		}
+		placed := image.Rectangle{Min: free[bestIndex].Min, Max: free[bestIndex].Min.Add(size)}
+		positions[i] = placed.Min
+		extent = image.Pt(max(extent.X, placed.Max.X), max(extent.Y, placed.Max.Y))
+		free = splitFreeRects(free, placed)
+	}
+	return positions, extent
+}
+
+// splitFreeRects removes a placed rectangle from the free list, keeping the maximal
+// free rectangles around it
+func splitFreeRects(free []image.Rectangle, placed image.Rectangle) []image.Rectangle {
+	var result []image.Rectangle
+	for _, r := range free {
+		if !r.Overlaps(placed) {
+			result = append(result, r)
+			continue
+		}
+		// Literal rectangles, since image.Rect would swap inverted edges
+		for _, part := range []image.Rectangle{
+			{Min: r.Min, Max: image.Pt(placed.Min.X, r.Max.Y)}, // Left
+			{Min: image.Pt(placed.Max.X, r.Min.Y), Max: r.Max}, // Right
+			{Min: r.Min, Max: image.Pt(r.Max.X, placed.Min.Y)}, // Above
+			{Min: image.Pt(r.Min.X, placed.Max.Y), Max: r.Max}, // Below
+		} {
+			if part.Dx() > 0 && part.Dy() > 0 {
+				result = append(result, part)
+			}
+		}
+	}
+	
+	// Drop rectangles contained in another one
+	var pruned []image.Rectangle
+	for i, r := range result {
+		contained := false
+		for j, other := range result {
+			if i != j && r.In(other) && (r != other || j < i) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			pruned = append(pruned, r)
+		}
+	}
+	return pruned
+}
\ No newline at end of file
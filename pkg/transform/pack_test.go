@@ -0,0 +1,69 @@
+package transform
+
+import (
+	"image"
+	"math/rand"
+	"testing"
+)
+
+func TestPackRects(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var sizes []image.Point
+	var area int
+	for i := 0; i < 60; i++ {
+		size := image.Pt(4+rng.Intn(60), 4+rng.Intn(60))
+		sizes = append(sizes, size)
+		area += size.X * size.Y
+	}
+	
+	for _, method := range []PackingMethod{PackMaxRects, PackShelf} {
+		t.Run(string(method), func(t *testing.T) {
+			const padding = 2
+			positions, size, err := PackRects(sizes, padding, method)
+			if err != nil {
+				t.Fatalf("PackRects() error = %v", err)
+			}
+			sheet := image.Rectangle{Max: size}
+			var rects []image.Rectangle
+			for i, p := range positions {
+				r := image.Rectangle{Min: p, Max: p.Add(sizes[i])}
+				if !r.In(sheet) {
+					t.Fatalf("rectangle %v is outside the %v sheet", r, size)
+				}
+				// Padding keeps every pair apart
+				padded := image.Rectangle{Min: r.Min, Max: r.Max.Add(image.Pt(padding, padding))}
+				for _, other := range rects {
+					if padded.Overlaps(other) {
+						t.Fatalf("rectangles %v and %v are closer than the padding", r, other)
+					}
+				}
+				rects = append(rects, r)
+			}
+			if efficiency := float64(area) / float64(size.X*size.Y); efficiency < 0.6 {
+				t.Errorf("sheet %v is only %.0f%% full", size, efficiency*100)
+			}
+		})
+	}
+}
+
+func TestPackRectsSingle(t *testing.T) {
+	positions, size, err := PackRects([]image.Point{{30, 20}}, 5, PackMaxRects)
+	if err != nil || positions[0] != (image.Point{}) || size != image.Pt(30, 20) {
+		t.Errorf("PackRects() = %v, %v, %v, want the rectangle at the origin on a 30x20 sheet", positions, size, err)
+	}
+	if _, _, err := PackRects([]image.Point{{0, 10}}, 0, PackShelf); err == nil {
+		t.Error("PackRects() should reject empty rectangles")
+	}
+}
+
+func TestParsePackingMethod(t *testing.T) {
+	if m, err := ParsePackingMethod("Shelf"); err != nil || m != PackShelf {
+		t.Errorf("ParsePackingMethod(Shelf) = %v, %v", m, err)
+	}
+	if m, err := ParsePackingMethod(""); err != nil || m != PackMaxRects {
+		t.Errorf("ParsePackingMethod() = %v, %v", m, err)
+	}
+	if _, err := ParsePackingMethod("guillotine"); err == nil {
+		t.Error("ParsePackingMethod(guillotine) should fail")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,433 @@
+package transform
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	
+	"github.com/disintegration/imaging"
+)
+
+// PaletteMethod selects how dominant colors are clustered
+type PaletteMethod string
+
+const (
+	PaletteKMeans    PaletteMethod = "kmeans"    // Median cut refined with k-means until stable
+	PaletteMedianCut PaletteMethod = "mediancut" // Median cut boxes only, faster and coarser
+)
+
+// ParsePaletteMethod parses a palette clustering method name
+func ParsePaletteMethod(s string) (PaletteMethod, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "kmeans", "k-means":
+		return PaletteKMeans, nil
+	case "mediancut", "median-cut":
+		return PaletteMedianCut, nil
+	default:
+		return "", fmt.Errorf("unsupported palette method: %s (use kmeans or mediancut)", s)
+	}
+}
+
+// PaletteOptions contains options for extracting a color palette
+type PaletteOptions struct {
+	Colors int           // Number of colors (0 = 6)
+	Method PaletteMethod // Clustering method (empty = k-means)
+}
+
+// PaletteColor is one dominant color and the share of the image it covers
+type PaletteColor struct {
+	Hex     string     `json:"hex"`
+	RGB     [3]uint8   `json:"rgb"`
+	Lab     [3]float64 `json:"lab"`
+	Percent float64    `json:"percent"`
+}
+
+// Color returns the palette color as an opaque color
+func (c PaletteColor) Color() color.NRGBA {
+	return color.NRGBA{R: c.RGB[0], G: c.RGB[1], B: c.RGB[2], A: 0xFF}
+}
+
+const (
+	// paletteSource limits the pixels read when clustering
+	paletteSource = 256
+	// paletteIterations caps the k-means passes
+	paletteIterations = 20
+)
+
+// ValidatePaletteOptions checks palette options
+func ValidatePaletteOptions(options PaletteOptions) error {
+	if options.Colors < 0 || options.Colors > 64 {
+		return fmt.Errorf("colors must be between 1 and 64: %d", options.Colors)
+	}
+	if _, err := ParsePaletteMethod(string(options.Method)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Palette reads an image and extracts its dominant colors
+func (t *Transformer) Palette(input io.Reader, options PaletteOptions) ([]PaletteColor, error) {
+	if err := ValidatePaletteOptions(options); err != nil {
+		return nil, err
+	}
+	data, err := t.readImage(input)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := decodeImage(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	
+	colors, err := ExtractPalette(img, options)
+	if err != nil {
+		return nil, err
+	}
+	t.logf("extracted %d colors from a %dx%d image", len(colors), img.Bounds().Dx(), img.Bounds().Dy())
+	return colors, nil
+}
+
+// labBin accumulates the pixels of one 5-bit RGB histogram cell in Lab space
+type labBin struct {
+	count int
+	lab   [3]float64 // Mean color
+}
+
+// ExtractPalette finds the dominant colors of an image by clustering its pixels in
+// CIE Lab space, where distances follow perceived color differences. Mostly transparent
+// pixels are ignored. Colors are sorted by the share of the image they cover.
+func ExtractPalette(img image.Image, options PaletteOptions) ([]PaletteColor, error) {
+	if err := ValidatePaletteOptions(options); err != nil {
+		return nil, err
+	}
+	n := options.Colors
+	if n == 0 {
+		n = 6
+	}
+	method, _ := ParsePaletteMethod(string(options.Method))
+	
+	bounds := img.Bounds()
+	if bounds.Dx() > paletteSource || bounds.Dy() > paletteSource {
+		img = imaging.Fit(img, paletteSource, paletteSource, imaging.Lanczos)
+	}
+	bins := buildLabHistogram(img)
+	if len(bins) == 0 {
+		return nil, fmt.Errorf("image has no opaque pixels")
+	}
+	
+	centers := medianCutLab(bins, n)
+	if method == PaletteKMeans {
+		centers = kMeansLab(bins, centers)
+	}
+	
+	// Share of pixels nearest to each center
+	counts := make([]int, len(centers))
+	total := 0
+	for _, bin := range bins {
+		counts[nearestLab(centers, bin.lab)] += bin.count
+		total += bin.count
+	}
+	
+	colors := make([]PaletteColor, 0, len(centers))
+	for i, center := range centers {
+		if counts[i] == 0 {
+			continue
+		}
+		rgb := labToRGB(center)
+		colors = append(colors, PaletteColor{
+			Hex:     FormatHexColor(color.NRGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 0xFF}),
+			RGB:     rgb,
+			Lab:     [3]float64{roundTo(center[0], 2), roundTo(center[1], 2), roundTo(center[2], 2)},
+			Percent: roundTo(float64(counts[i])*100/float64(total), 2),
+		})
+	}
+	sort.SliceStable(colors, func(i, j int) bool { return colors[i].Percent > colors[j].Percent })
+	return colors, nil
+}
+
+// buildLabHistogram groups the pixels with at least half opacity into 5-bit RGB cells
+// and returns the cells with their mean Lab color, in a deterministic order
+func buildLabHistogram(img image.Image) []labBin {
+	bounds := img.Bounds()
+	nrgba, ok := img.(*image.NRGBA)
+	if !ok {
+		nrgba = image.NewNRGBA(bounds)
+		draw.Draw(nrgba, bounds, img, bounds.Min, draw.Src)
+	}
+	
+	var counts [1 << 15]int
+	var sums [1 << 15][3]float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := nrgba.Pix[nrgba.PixOffset(bounds.Min.X, y):nrgba.PixOffset(bounds.Max.X, y)]
+		for i := 0; i < len(row); i += 4 {
+			if row[i+3] < 128 {
+				continue
+			}
+			key := int(row[i]>>3)<<10 | int(row[i+1]>>3)<<5 | int(row[i+2]>>3)
+			lab := rgbToLab(row[i], row[i+1], row[i+2])
+			counts[key]++
+			for c := 0; c < 3; c++ {
+				sums[key][c] += lab[c]
+			}
+		}
+	}
+	
+	var bins []labBin
+	for key, count := range counts {
+		if count == 0 {
+			continue
+		}
+		bin := labBin{count: count}
+		for c := 0; c < 3; c++ {
+			bin.lab[c] = sums[key][c] / float64(count)
+		}
+		bins = append(bins, bin)
+	}
+	return bins
+}
+
+// medianCutLab splits the bins into at most n boxes, always cutting the box with the
+// largest population-weighted spread at the median of its widest Lab axis, and
+// returns the box means
+func medianCutLab(bins []labBin, n int) [][3]float64 {
+	type box struct {
+		bins   []labBin
+		axis   int
+		spread float64
+		count  int
+	}
+	measure := func(bins []labBin) box {
+		b := box{bins: bins}
+		lo := [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+		hi := [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+		for _, bin := range bins {
+			b.count += bin.count
+			for c := 0; c < 3; c++ {
+				lo[c] = math.Min(lo[c], bin.lab[c])
+				hi[c] = math.Max(hi[c], bin.lab[c])
+			}
+		}
+		for c := 0; c < 3; c++ {
+			if hi[c]-lo[c] > b.spread {
+				b.axis, b.spread = c, hi[c]-lo[c]
+			}
+		}
+		return b
+	}
+	
+	boxes := []box{measure(bins)}
+	for len(boxes) < n {
+		best, bestScore := -1, 0.0
+		for i, b := range boxes {
+			if len(b.bins) < 2 || b.spread == 0 {
+				continue
+			}
+			if score := b.spread * float64(b.count); best < 0 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			break
+		}
+		
+		b := boxes[best]
+		sort.Slice(b.bins, func(i, j int) bool { return b.bins[i].lab[b.axis] < b.bins[j].lab[b.axis] })
+		acc, split := 0, 1
+		for i, bin := range b.bins[:len(b.bins)-1] {
+			acc += bin.count
+			split = i + 1
+			if acc >= b.count/2 {
+				break
+			}
+		}
+		boxes[best] = measure(b.bins[:split])
+		boxes = append(boxes, measure(b.bins[split:]))
+	}
+	
+	centers := make([][3]float64, len(boxes))
+	for i, b := range boxes {
+		for _, bin := range b.bins {
+			for c := 0; c < 3; c++ {
+				centers[i][c] += bin.lab[c] * float64(bin.count)
+			}
+		}
+		for c := 0; c < 3; c++ {
+			centers[i][c] /= float64(b.count)
+		}
+	}
+	return centers
+}
+
+// kMeansLab moves the centers to the weighted mean of their nearest bins until no bin
+// changes cluster, and drops centers that end up empty
+func kMeansLab(bins []labBin, centers [][3]float64) [][3]float64 {
+	assignment := make([]int, len(bins))
+	for iter := 0; iter < paletteIterations; iter++ {
+		changed := false
+		for i, bin := range bins {
+			nearest := nearestLab(centers, bin.lab)
+			if iter == 0 || nearest != assignment[i] {
+				assignment[i] = nearest
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+		
+		sums := make([][3]float64, len(centers))
+		counts := make([]int, len(centers))
+		for i, bin := range bins {
+			k := assignment[i]
+			counts[k] += bin.count
+			for c := 0; c < 3; c++ {
+				sums[k][c] += bin.lab[c] * float64(bin.count)
+			}
+		}
+		for k := range centers {
+			if counts[k] == 0 {
+				continue
+			}
+			for c := 0; c < 3; c++ {
+				centers[k][c] = sums[k][c] / float64(counts[k])
+			}
+		}
+	}
+	return centers
+}
+
+// nearestLab returns the index of the center closest to lab
+func nearestLab(centers [][3]float64, lab [3]float64) int {
+	nearest, bestDist := 0, math.Inf(1)
+	for i, center := range centers {
+		d := 0.0
+		for c := 0; c < 3; c++ {
+			diff := lab[c] - center[c]
+			d += diff * diff
+		}
+		if d < bestDist {
+			nearest, bestDist = i, d
+		}
+	}
+	return nearest
+}
+
+// D65 reference white
+const (
+	whiteX = 0.95047
+	whiteY = 1.0
+	whiteZ = 1.08883
+)
+
+// rgbToLab converts an sRGB color to CIE Lab under D65
+func rgbToLab(r, g, b uint8) [3]float64 {
+	lr, lg, lb := toLinear(r), toLinear(g), toLinear(b)
+	x := (0.4124564*lr + 0.3575761*lg + 0.1804375*lb) / whiteX
+	y := (0.2126729*lr + 0.7151522*lg + 0.0721750*lb) / whiteY
+	z := (0.0193339*lr + 0.1191920*lg + 0.9503041*lb) / whiteZ
+	fx, fy, fz := labF(x), labF(y), labF(z)
+	return [3]float64{116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)}
+}
+
+// labToRGB converts a CIE Lab color back to sRGB, clipping colors outside the gamut
+func labToRGB(lab [3]float64) [3]uint8 {
+	fy := (lab[0] + 16) / 116
+	fx := fy + lab[1]/500
+	fz := fy - lab[2]/200
+	x, y, z := labFInv(fx)*whiteX, labFInv(fy)*whiteY, labFInv(fz)*whiteZ
+	return [3]uint8{
+		fromLinear(3.2404542*x - 1.5371385*y - 0.4985314*z),
+		fromLinear(-0.9692660*x + 1.8760108*y + 0.0415560*z),
+		fromLinear(0.0556434*x - 0.2040259*y + 1.0572252*z),
+	}
+}
+
+// labF is the CIE Lab companding function
+func labF(t float64) float64 {
+	if t > 216.0/24389 {
+		return math.Cbrt(t)
+	}
+	return (24389.0/27*t + 16) / 116
+}
+
+// labFInv inverts labF
+func labFInv(t float64) float64 {
+	if t*t*t > 216.0/24389 {
+		return t * t * t
+	}
+	return (116*t - 16) * 27 / 24389
+}
+
+// toLinear converts an sRGB channel to linear light in 0-1
+func toLinear(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// fromLinear converts linear light to an sRGB channel
+func fromLinear(c float64) uint8 {
+	if c <= 0.0031308 {
+		c *= 12.92
+	} else {
+		c = 1.055*math.Pow(c, 1/2.4) - 0.055
+	}
+	return clampByte(int(math.Round(c * 255)))
+}
+
+// roundTo rounds v to the given number of decimals
+func roundTo(v float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(v*scale) / scale
+}
+
+// PaletteCSS formats a palette as CSS custom properties, --<prefix>-1 being the most
+// common color
+func PaletteCSS(colors []PaletteColor, prefix string) string {
+	if prefix == "" {
+		prefix = "palette"
+	}
+	var b strings.Builder
+	b.WriteString(":root {\n")
+	for i, c := range colors {
+		fmt.Fprintf(&b, "  --%s-%d: %s; /* %.1f%% */\n", prefix, i+1, c.Hex, c.Percent)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// PaletteSwatch draws the palette as vertical stripes whose widths follow the share of
+// each color
+func PaletteSwatch(colors []PaletteColor, width, height int) (*image.NRGBA, error) {
+	if len(colors) == 0 {
+		return nil, fmt.Errorf("empty palette")
+	}
+	if width < len(colors) || height < 1 {
+		return nil, fmt.Errorf("swatch size too small for %d colors: %dx%d", len(colors), width, height)
+	}
+	
+	total := 0.0
+	for _, c := range colors {
+		total += c.Percent
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	x, acc := 0, 0.0
+	for i, c := range colors {
+		acc += c.Percent
+		right := width
+		if i < len(colors)-1 {
+			// At least one pixel per color, leaving one for each remaining color
+			right = max(x+1, min(int(math.Round(acc/total*float64(width))), width-(len(colors)-1-i)))
+		}
+		draw.Draw(img, image.Rect(x, 0, right, height), image.NewUniform(c.Color()), image.Point{}, draw.Src)
+		x = right
+	}
+	return img, nil
+}
\ No newline at end of file
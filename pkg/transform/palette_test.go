@@ -0,0 +1,118 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestLabRoundTrip(t *testing.T) {
+	tests := []struct {
+		rgb [3]uint8
+		lab [3]float64
+	}{
+		{[3]uint8{255, 255, 255}, [3]float64{100, 0, 0}},
+		{[3]uint8{0, 0, 0}, [3]float64{0, 0, 0}},
+		{[3]uint8{255, 0, 0}, [3]float64{53.24, 80.09, 67.20}},
+	}
+	for _, tt := range tests {
+		lab := rgbToLab(tt.rgb[0], tt.rgb[1], tt.rgb[2])
+		for c := 0; c < 3; c++ {
+			if math.Abs(lab[c]-tt.lab[c]) > 0.05 {
+				t.Errorf("rgbToLab(%v) = %v, want %v", tt.rgb, lab, tt.lab)
+				break
+			}
+		}
+		if got := labToRGB(lab); got != tt.rgb {
+			t.Errorf("labToRGB(%v) = %v, want %v", lab, got, tt.rgb)
+		}
+	}
+}
+
+func TestExtractPalette(t *testing.T) {
+	// Three quarters blue, one quarter orange, and a transparent strip that is ignored
+	img := image.NewNRGBA(image.Rect(0, 0, 100, 120))
+	draw.Draw(img, image.Rect(0, 0, 75, 100), image.NewUniform(color.NRGBA{R: 0x20, G: 0x50, B: 0xC0, A: 0xFF}), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(75, 0, 100, 100), image.NewUniform(color.NRGBA{R: 0xF0, G: 0x90, B: 0x20, A: 0xFF}), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 100, 100, 120), image.NewUniform(color.NRGBA{R: 0xFF, A: 0x10}), image.Point{}, draw.Src)
+	
+	for _, method := range []PaletteMethod{PaletteKMeans, PaletteMedianCut} {
+		colors, err := ExtractPalette(img, PaletteOptions{Colors: 4, Method: method})
+		if err != nil {
+			t.Fatalf("ExtractPalette(%s) error = %v", method, err)
+		}
+		// Only two distinct colors exist
+		if len(colors) != 2 {
+			t.Fatalf("ExtractPalette(%s) = %v, want 2 colors", method, colors)
+		}
+		if colors[0].Hex != "#2050c0" || colors[0].Percent != 75 {
+			t.Errorf("ExtractPalette(%s)[0] = %+v, want #2050c0 at 75%%", method, colors[0])
+		}
+		if colors[1].Hex != "#f09020" || colors[1].Percent != 25 {
+			t.Errorf("ExtractPalette(%s)[1] = %+v, want #f09020 at 25%%", method, colors[1])
+		}
+	}
+	
+	if _, err := ExtractPalette(image.NewNRGBA(image.Rect(0, 0, 4, 4)), PaletteOptions{}); err == nil {
+		t.Error("ExtractPalette() accepted a fully transparent image")
+	}
+	if _, err := ExtractPalette(img, PaletteOptions{Method: "octree"}); err == nil {
+		t.Error("ExtractPalette() accepted an unknown method")
+	}
+}
+
+func TestPalette(t *testing.T) {
+	// A gradient has many colors, so the palette is full
+	img := image.NewNRGBA(image.Rect(0, 0, 300, 40))
+	for x := 0; x < 300; x++ {
+		for y := 0; y < 40; y++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 255 / 299), G: 0x40, B: uint8(255 - x*255/299), A: 0xFF})
+		}
+	}
+	var input bytes.Buffer
+	if err := png.Encode(&input, img); err != nil {
+		t.Fatal(err)
+	}
+	
+	colors, err := NewTransformer().Palette(&input, PaletteOptions{Colors: 5})
+	if err != nil {
+		t.Fatalf("Palette() error = %v", err)
+	}
+	if len(colors) != 5 {
+		t.Fatalf("Palette() returned %d colors, want 5", len(colors))
+	}
+	total := 0.0
+	for i, c := range colors {
+		total += c.Percent
+		if i > 0 && c.Percent > colors[i-1].Percent {
+			t.Errorf("Palette() not sorted by share: %v", colors)
+		}
+	}
+	if math.Abs(total-100) > 0.1 {
+		t.Errorf("Palette() shares add up to %.2f, want 100", total)
+	}
+	
+	css := PaletteCSS(colors, "brand")
+	if !strings.HasPrefix(css, ":root {\n  --brand-1: "+colors[0].Hex+";") {
+		t.Errorf("PaletteCSS() = %q", css)
+	}
+	
+	swatch, err := PaletteSwatch(colors, 500, 50)
+	if err != nil {
+		t.Fatalf("PaletteSwatch() error = %v", err)
+	}
+	if got := FormatHexColor(swatch.At(0, 0)); got != colors[0].Hex {
+		t.Errorf("PaletteSwatch() first stripe = %s, want %s", got, colors[0].Hex)
+	}
+	if got := FormatHexColor(swatch.At(499, 49)); got != colors[4].Hex {
+		t.Errorf("PaletteSwatch() last stripe = %s, want %s", got, colors[4].Hex)
+	}
+	if _, err := PaletteSwatch(colors, 3, 50); err == nil {
+		t.Error("PaletteSwatch() accepted a swatch narrower than the palette")
+	}
+}
\ No newline at end of file
@@ -0,0 +1,163 @@
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	
+	"github.com/allieus/imagekit/pkg/jpegcodec"
+	"github.com/allieus/imagekit/pkg/pdf"
+)
+
+// PDFOptions contains options for writing images as PDF pages
+type PDFOptions struct {
+	Paper      *PaperSize     // Page size; nil = each page matches its image's print size
+	Fit        bool           // Scale images up or down to fill the paper within the margins
+	Margin     DimensionValue // Space kept free at the paper edges
+	Resolution Resolution     // Print resolution (zero = each image's own)
+}
+
+// ValidatePDFOptions checks that the options describe a page layout
+func ValidatePDFOptions(options PDFOptions) error {
+	if !options.Margin.IsZero() && !options.Margin.IsPhysical() {
+		return fmt.Errorf("margin must be a length like 10mm: %s", options.Margin)
+	}
+	if options.Paper == nil && (options.Fit || !options.Margin.IsZero()) {
+		return fmt.Errorf("fit and margin need a page size")
+	}
+	if options.Paper != nil && !options.Margin.IsZero() {
+		margin := options.Margin.Length * options.Margin.Unit.inches() * 25.4
+		if 2*margin >= math.Min(options.Paper.Width, options.Paper.Height) {
+			return fmt.Errorf("%s margins leave no room on %s paper", options.Margin, options.Paper.Name)
+		}
+	}
+	return nil
+}
+
+// PDFWriter writes images to a PDF document, one page per image
+type PDFWriter struct {
+	t       *Transformer
+	w       *pdf.Writer
+	options PDFOptions
+	pages   int
+}
+
+// NewPDFWriter starts a PDF document on output
+func (t *Transformer) NewPDFWriter(output io.Writer, options PDFOptions) (*PDFWriter, error) {
+	if err := ValidatePDFOptions(options); err != nil {
+		return nil, err
+	}
+	return &PDFWriter{t: t, w: pdf.NewWriter(output), options: options}, nil
+}
+
+// AddImage adds a page showing the image. JPEG data is embedded as is, other formats
+// are stored losslessly.
+func (pw *PDFWriter) AddImage(input io.Reader) error {
+	data, err := pw.t.readImage(input)
+	if err != nil {
+		return err
+	}
+	resolution := pw.options.Resolution
+	if resolution.IsZero() {
+		resolution = imageResolution(data)
+	}
+	
+	img, orientation, err := pw.pdfImage(data)
+	if err != nil {
+		return err
+	}
+	
+	// The displayed size swaps the stored width and height for rotated orientations
+	dpiX, dpiY := resolution.DPI()
+	width, height := img.Width, img.Height
+	if orientation >= 5 {
+		width, height = height, width
+		dpiX, dpiY = dpiY, dpiX
+	}
+	imageWidth := float64(width) / dpiX * pdf.PointsPerInch
+	imageHeight := float64(height) / dpiY * pdf.PointsPerInch
+	
+	page := pdf.Page{Image: img, Orientation: orientation}
+	page.Width, page.Height, page.Rect = pw.pageLayout(imageWidth, imageHeight)
+	if err := pw.w.AddPage(page); err != nil {
+		return fmt.Errorf("failed to write PDF page: %w", err)
+	}
+	pw.pages++
+	pw.t.logf("page %d: %dx%d %s image at %s on a %.1fx%.1fmm page", pw.pages, width, height,
+		img.Filter, resolution, page.Width/pdf.PointsPerInch*25.4, page.Height/pdf.PointsPerInch*25.4)
+	return nil
+}
+
+// pdfImage prepares the image data for embedding. JPEGs keep their bytes and EXIF
+// orientation; other images are decoded upright.
+func (pw *PDFWriter) pdfImage(data []byte) (*pdf.Image, int, error) {
+	if isJPEGData(data) {
+		if header, err := jpegcodec.DecodeHeader(data); err == nil {
+			return pdf.JPEGImage(data, header.Width, header.Height, len(header.Components), hasAdobeSegment(header.Segments)), jpegOrientation(data), nil
+		}
+		pw.t.logf("JPEG cannot be embedded directly, storing decoded pixels")
+	}
+	
+	img, _, err := decodeImage(data)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load image: %w", err)
+	}
+	result, err := pdf.FlateImage(img)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to compress image: %w", err)
+	}
+	return result, 1, nil
+}
+
+// hasAdobeSegment reports whether an Adobe APP14 segment is present, which marks
+// CMYK data as inverted
+func hasAdobeSegment(segments []jpegcodec.Segment) bool {
+	for _, seg := range segments {
+		if seg.Marker == 0xEE && bytes.HasPrefix(seg.Data, []byte("Adobe")) {
+			return true
+		}
+	}
+	return false
+}
+
+// pageLayout returns the page size and image placement in points. Without a paper
+// size the page is the image; otherwise the paper is turned to match the image, which
+// is centered and shrunk to fit the margins, or scaled to fill them with Fit.
+func (pw *PDFWriter) pageLayout(imageWidth, imageHeight float64) (float64, float64, pdf.Rect) {
+	if pw.options.Paper == nil {
+		return imageWidth, imageHeight, pdf.Rect{Width: imageWidth, Height: imageHeight}
+	}
+	
+	pageWidth := pw.options.Paper.Width / 25.4 * pdf.PointsPerInch
+	pageHeight := pw.options.Paper.Height / 25.4 * pdf.PointsPerInch
+	if imageWidth > imageHeight {
+		pageWidth, pageHeight = pageHeight, pageWidth
+	}
+	var margin float64
+	if !pw.options.Margin.IsZero() {
+		margin = pw.options.Margin.Length * pw.options.Margin.Unit.inches() * pdf.PointsPerInch
+	}
+	
+	scale := math.Min((pageWidth-2*margin)/imageWidth, (pageHeight-2*margin)/imageHeight)
+	if !pw.options.Fit {
+		scale = math.Min(scale, 1)
+	}
+	width, height := imageWidth*scale, imageHeight*scale
+	return pageWidth, pageHeight, pdf.Rect{
+		X:      (pageWidth - width) / 2,
+		Y:      (pageHeight - height) / 2,
+		Width:  width,
+		Height: height,
+	}
+}
+
+// Pages returns the number of pages written so far
+func (pw *PDFWriter) Pages() int {
+	return pw.pages
+}
+
+// Close finishes the document. It does not close the output.
+func (pw *PDFWriter) Close() error {
+	return pw.w.Close()
+}
\ No newline at end of file
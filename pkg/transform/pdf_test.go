@@ -0,0 +1,113 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestPDFWriterPageSizes(t *testing.T) {
+	photo := gradientImage(600, 300)
+	jpegData := &bytes.Buffer{}
+	if err := jpeg.Encode(jpegData, photo, nil); err != nil {
+		t.Fatal(err)
+	}
+	highDPI, err := SetJPEGResolution(jpegData.Bytes(), SquareDPI(300))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pngData := &bytes.Buffer{}
+	if err := png.Encode(pngData, image.NewGray(image.Rect(0, 0, 96, 192))); err != nil {
+		t.Fatal(err)
+	}
+	a4 := paperSizes["a4"]
+	
+	tests := []struct {
+		name    string
+		options PDFOptions
+		input   []byte
+		want    []string
+	}{
+		// 600x300 pixels at 300 DPI is 2x1 inches
+		{"JPEG at its DPI", PDFOptions{}, highDPI, []string{"/MediaBox [0 0 144 72]", "/Filter /DCTDecode", "q 144 0 0 72 0 0 cm"}},
+		// No resolution in a PNG means 96 DPI, so 96x192 pixels is 1x2 inches
+		{"PNG at 96 DPI", PDFOptions{}, pngData.Bytes(), []string{"/MediaBox [0 0 72 144]", "/ColorSpace /DeviceGray", "/Filter /FlateDecode"}},
+		{"Resolution override", PDFOptions{Resolution: SquareDPI(150)}, highDPI, []string{"/MediaBox [0 0 288 144]"}},
+		// A landscape image turns the paper and keeps its size
+		{"A4 centered", PDFOptions{Paper: &a4}, highDPI, []string{"/MediaBox [0 0 841.8898 595.2756]", "q 144 0 0 72 348.9449 261.6378 cm"}},
+		// Fit fills the 10mm margins: 841.89 - 2*28.35 points wide
+		{"A4 fit", PDFOptions{Paper: &a4, Fit: true, Margin: DimensionValue{Length: 10, Unit: LengthMillimeter}}, highDPI, []string{"q 785.1969 0 0 392.5984 28.3465 101.3386 cm"}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			w, err := NewTransformer().NewPDFWriter(output, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := w.AddImage(bytes.NewReader(tt.input)); err != nil {
+				t.Fatalf("AddImage() error = %v", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(output.String(), want) {
+					t.Errorf("PDF does not contain %q", want)
+				}
+			}
+			if bytes.HasPrefix(tt.input, []byte{0xFF, 0xD8}) && !bytes.Contains(output.Bytes(), tt.input) {
+				t.Error("JPEG data was not embedded as is")
+			}
+		})
+	}
+}
+
+func TestPDFWriterOrientation(t *testing.T) {
+	// Orientation 6 displays the 200x100 photo as 100x200
+	rotated := losslessTestJPEG(t, 200, 100, 6)
+	
+	output := &bytes.Buffer{}
+	w, err := NewTransformer().NewPDFWriter(output, PDFOptions{Resolution: SquareDPI(72)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddImage(bytes.NewReader(rotated)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"/MediaBox [0 0 100 200]", "/Width 200 /Height 100", "q 0 -200 100 0 0 200 cm"} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("PDF does not contain %q", want)
+		}
+	}
+}
+
+func TestValidatePDFOptions(t *testing.T) {
+	a6 := paperSizes["a6"]
+	tests := []struct {
+		name    string
+		options PDFOptions
+		wantErr bool
+	}{
+		{"Image size", PDFOptions{}, false},
+		{"Paper with margin", PDFOptions{Paper: &a6, Margin: DimensionValue{Length: 1, Unit: LengthCentimeter}}, false},
+		{"Fit without paper", PDFOptions{Fit: true}, true},
+		{"Pixel margin", PDFOptions{Paper: &a6, Margin: DimensionValue{Value: 20}}, true},
+		{"Margin too large", PDFOptions{Paper: &a6, Margin: DimensionValue{Length: 6, Unit: LengthCentimeter}}, true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidatePDFOptions(tt.options); (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePDFOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,218 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"io"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/placeholder"
+	"github.com/disintegration/imaging"
+)
+
+// PlaceholderType selects a kind of image placeholder
+type PlaceholderType string
+
+const (
+	PlaceholderBlurHash  PlaceholderType = "blurhash"  // Short text hash of a blurred image
+	PlaceholderThumbHash PlaceholderType = "thumbhash" // Binary hash keeping aspect ratio and alpha, base64 encoded
+	PlaceholderLQIP      PlaceholderType = "lqip"      // Tiny JPEG or PNG as a data URI
+)
+
+// ParsePlaceholderType parses a placeholder type name
+func ParsePlaceholderType(s string) (PlaceholderType, error) {
+	switch PlaceholderType(strings.ToLower(strings.TrimSpace(s))) {
+	case PlaceholderBlurHash:
+		return PlaceholderBlurHash, nil
+	case PlaceholderThumbHash:
+		return PlaceholderThumbHash, nil
+	case PlaceholderLQIP:
+		return PlaceholderLQIP, nil
+	default:
+		return "", fmt.Errorf("unsupported placeholder type: %s (use blurhash, thumbhash or lqip)", s)
+	}
+}
+
+// ParsePlaceholderTypes parses a comma separated list of placeholder types
+func ParsePlaceholderTypes(s string) ([]PlaceholderType, error) {
+	var types []PlaceholderType
+	seen := make(map[PlaceholderType]bool)
+	for _, part := range strings.Split(s, ",") {
+		kind, err := ParsePlaceholderType(part)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[kind] {
+			seen[kind] = true
+			types = append(types, kind)
+		}
+	}
+	return types, nil
+}
+
+// PlaceholderOptions contains options for computing image placeholders
+type PlaceholderOptions struct {
+	Types       []PlaceholderType
+	ComponentsX int // BlurHash components across (0 = 4 for landscape, 3 for portrait)
+	ComponentsY int // BlurHash components down (0 = 3 for landscape, 4 for portrait)
+	LQIPSize    int // Longest side of the data URI preview (0 = 16)
+	Quality     int // JPEG quality of the data URI preview (0 = 60)
+}
+
+// Placeholder holds the placeholders of one image, with its displayed size
+type Placeholder struct {
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	BlurHash  string `json:"blurhash,omitempty"`
+	ThumbHash string `json:"thumbhash,omitempty"`
+	LQIP      string `json:"lqip,omitempty"`
+}
+
+// blurHashSource and thumbHashSource limit the pixels the hash encoders read
+const (
+	blurHashSource  = 64
+	thumbHashSource = 100
+)
+
+// ValidatePlaceholderOptions checks placeholder options
+func ValidatePlaceholderOptions(options PlaceholderOptions) error {
+	if len(options.Types) == 0 {
+		return fmt.Errorf("no placeholder types")
+	}
+	if options.ComponentsX < 0 || options.ComponentsX > 9 || options.ComponentsY < 0 || options.ComponentsY > 9 {
+		return fmt.Errorf("blurhash components must be 1-9: %dx%d", options.ComponentsX, options.ComponentsY)
+	}
+	if options.LQIPSize < 0 || options.LQIPSize > 256 {
+		return fmt.Errorf("preview size must be at most 256 pixels: %d", options.LQIPSize)
+	}
+	if options.Quality < 0 || options.Quality > 100 {
+		return fmt.Errorf("quality must be between 1 and 100: %d", options.Quality)
+	}
+	return nil
+}
+
+// Placeholder computes the requested placeholders of an image
+func (t *Transformer) Placeholder(input io.Reader, options PlaceholderOptions) (Placeholder, error) {
+	if err := ValidatePlaceholderOptions(options); err != nil {
+		return Placeholder{}, err
+	}
+	data, err := t.readImage(input)
+	if err != nil {
+		return Placeholder{}, err
+	}
+	img, _, err := decodeImage(data)
+	if err != nil {
+		return Placeholder{}, fmt.Errorf("failed to decode image: %w", err)
+	}
+	
+	bounds := img.Bounds()
+	result := Placeholder{Width: bounds.Dx(), Height: bounds.Dy()}
+	for _, kind := range options.Types {
+		switch kind {
+		case PlaceholderBlurHash:
+			x, y := options.ComponentsX, options.ComponentsY
+			if x == 0 || y == 0 {
+				x, y = 4, 3
+				if result.Height > result.Width {
+					x, y = 3, 4
+				}
+			}
+			result.BlurHash, err = placeholder.EncodeBlurHash(imaging.Fit(img, blurHashSource, blurHashSource, imaging.Lanczos), x, y)
+		case PlaceholderThumbHash:
+			var hash []byte
+			hash, err = placeholder.EncodeThumbHash(imaging.Fit(img, thumbHashSource, thumbHashSource, imaging.Lanczos))
+			result.ThumbHash = base64.StdEncoding.EncodeToString(hash)
+		case PlaceholderLQIP:
+			result.LQIP, err = t.lqip(img, options)
+		default:
+			err = fmt.Errorf("unsupported placeholder type: %s", kind)
+		}
+		if err != nil {
+			return Placeholder{}, fmt.Errorf("%s: %w", kind, err)
+		}
+	}
+	t.logf("computed %d placeholders for a %dx%d image", len(options.Types), result.Width, result.Height)
+	return result, nil
+}
+
+// lqip shrinks an image to a few pixels and returns it as a data URI, as JPEG unless
+// it is transparent
+func (t *Transformer) lqip(img image.Image, options PlaceholderOptions) (string, error) {
+	size := options.LQIPSize
+	if size == 0 {
+		size = 16
+	}
+	quality := options.Quality
+	if quality == 0 {
+		quality = 60
+	}
+	small := imaging.Fit(img, size, size, imaging.Lanczos)
+	format := FormatJPEG
+	if HasAlpha(small) {
+		format = FormatPNG
+	}
+	
+	var buf bytes.Buffer
+	if err := SaveImageWithOptions(&buf, small, format, SaveOptions{Quality: quality, PNG: PNGOptions{Optimize: format == FormatPNG}}); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("data:image/%s;base64,%s", format, base64.StdEncoding.EncodeToString(buf.Bytes())), nil
+}
+
+// DecodePlaceholder renders a placeholder for previewing. A zero width or height keeps
+// the aspect ratio, and both zero use the placeholder's own size (32x32 for BlurHash,
+// which does not store one).
+func DecodePlaceholder(kind PlaceholderType, value string, width, height int) (image.Image, error) {
+	if width < 0 || height < 0 {
+		return nil, fmt.Errorf("invalid size: %dx%d", width, height)
+	}
+	value = strings.TrimSpace(value)
+	
+	var img image.Image
+	switch kind {
+	case PlaceholderBlurHash:
+		switch {
+		case width == 0 && height == 0:
+			width, height = 32, 32
+		case width == 0:
+			width = height
+		case height == 0:
+			height = width
+		}
+		return placeholder.DecodeBlurHash(value, width, height, 1)
+	case PlaceholderThumbHash:
+		hash, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			if hash, err = base64.RawStdEncoding.DecodeString(value); err != nil {
+				return nil, fmt.Errorf("invalid thumbhash: %w", err)
+			}
+		}
+		if img, err = placeholder.DecodeThumbHash(hash); err != nil {
+			return nil, err
+		}
+	case PlaceholderLQIP:
+		_, encoded, found := strings.Cut(value, ";base64,")
+		if !found || !strings.HasPrefix(value, "data:") {
+			return nil, fmt.Errorf("invalid data URI: expected data:<type>;base64,<data>")
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data URI: %w", err)
+		}
+		if err := DefaultLimits.Check(data); err != nil {
+			return nil, err
+		}
+		if img, _, err = decodeImage(data); err != nil {
+			return nil, fmt.Errorf("failed to decode image: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported placeholder type: %s", kind)
+	}
+	
+	if width == 0 && height == 0 {
+		return img, nil
+	}
+	return imaging.Resize(img, width, height, imaging.Linear), nil
+}
\ No newline at end of file
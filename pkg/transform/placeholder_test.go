@@ -0,0 +1,82 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestParsePlaceholderTypes(t *testing.T) {
+	types, err := ParsePlaceholderTypes("blurhash, LQIP,blurhash")
+	if err != nil || len(types) != 2 || types[0] != PlaceholderBlurHash || types[1] != PlaceholderLQIP {
+		t.Errorf("ParsePlaceholderTypes() = %v, %v", types, err)
+	}
+	if _, err := ParsePlaceholderTypes("blurhash,webp"); err == nil {
+		t.Error("ParsePlaceholderTypes() accepted an unknown type")
+	}
+}
+
+func TestPlaceholder(t *testing.T) {
+	want := color.NRGBA{R: 0x30, G: 0x90, B: 0xD0, A: 0xFF}
+	var input bytes.Buffer
+	if err := png.Encode(&input, solidImage(300, 150, want)); err != nil {
+		t.Fatal(err)
+	}
+	
+	options := PlaceholderOptions{Types: []PlaceholderType{PlaceholderBlurHash, PlaceholderThumbHash, PlaceholderLQIP}}
+	result, err := NewTransformer().Placeholder(&input, options)
+	if err != nil {
+		t.Fatalf("Placeholder() error = %v", err)
+	}
+	if result.Width != 300 || result.Height != 150 {
+		t.Errorf("Placeholder() size = %dx%d, want 300x150", result.Width, result.Height)
+	}
+	// Landscape images get 4x3 BlurHash components
+	if len(result.BlurHash) != 4+2*4*3 {
+		t.Errorf("BlurHash = %q, want 28 characters", result.BlurHash)
+	}
+	if !strings.HasPrefix(result.LQIP, "data:image/jpeg;base64,") {
+		t.Errorf("LQIP = %q, want a JPEG data URI", result.LQIP)
+	}
+	
+	// Every placeholder decodes back to about the source color
+	tests := []struct {
+		kind     PlaceholderType
+		value    string
+		wantSize image.Point
+	}{
+		{PlaceholderBlurHash, result.BlurHash, image.Pt(40, 40)},
+		{PlaceholderThumbHash, result.ThumbHash, image.Pt(40, 23)}, // ThumbHash stores 2:1 as 7:4
+		{PlaceholderLQIP, result.LQIP, image.Pt(40, 20)},
+	}
+	for _, tt := range tests {
+		img, err := DecodePlaceholder(tt.kind, tt.value, 40, 0)
+		if err != nil {
+			t.Errorf("DecodePlaceholder(%s) error = %v", tt.kind, err)
+			continue
+		}
+		if size := img.Bounds().Size(); size != tt.wantSize {
+			t.Errorf("DecodePlaceholder(%s) size = %v, want %v", tt.kind, size, tt.wantSize)
+		}
+		c := color.NRGBAModel.Convert(img.At(10, 10)).(color.NRGBA)
+		if diff := int(c.R) - int(want.R) + int(c.G) - int(want.G) + int(c.B) - int(want.B); diff < -30 || diff > 30 {
+			t.Errorf("DecodePlaceholder(%s) color = %v, want about %v", tt.kind, c, want)
+		}
+	}
+	
+	for _, tt := range []struct {
+		kind  PlaceholderType
+		value string
+	}{
+		{PlaceholderBlurHash, "LEHV6nWB2yk8"},
+		{PlaceholderThumbHash, "not base64!"},
+		{PlaceholderLQIP, "image/jpeg;base64,AAAA"},
+	} {
+		if _, err := DecodePlaceholder(tt.kind, tt.value, 0, 0); err == nil {
+			t.Errorf("DecodePlaceholder(%s, %q) accepted an invalid value", tt.kind, tt.value)
+		}
+	}
+}
\ No newline at end of file
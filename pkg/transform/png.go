@@ -0,0 +1,539 @@
+package transform
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+	
+	"github.com/allieus/imagekit/pkg/container"
+)
+
+// PNGFilterStrategy selects how PNG row filters are chosen
+type PNGFilterStrategy int
+
+const (
+	// PNGFilterNone uses filter type 0 for every row
+	PNGFilterNone PNGFilterStrategy = iota
+	// PNGFilterSub uses filter type 1 (Sub) for every row
+	PNGFilterSub
+	// PNGFilterUp uses filter type 2 (Up) for every row
+	PNGFilterUp
+	// PNGFilterAverage uses filter type 3 (Average) for every row
+	PNGFilterAverage
+	// PNGFilterPaeth uses filter type 4 (Paeth) for every row
+	PNGFilterPaeth
+	// PNGFilterAdaptive picks the filter with the minimum sum of absolute differences per row
+	PNGFilterAdaptive
+)
+
+// String returns the name of the filter strategy
+func (s PNGFilterStrategy) String() string {
+	switch s {
+	case PNGFilterNone:
+		return "none"
+	case PNGFilterSub:
+		return "sub"
+	case PNGFilterUp:
+		return "up"
+	case PNGFilterAverage:
+		return "average"
+	case PNGFilterPaeth:
+		return "paeth"
+	case PNGFilterAdaptive:
+		return "adaptive"
+	default:
+		return fmt.Sprintf("PNGFilterStrategy(%d)", int(s))
+	}
+}
+
+// pngCompressionLevels are the zlib levels tried for the final encode
+var pngCompressionLevels = []int{flate.BestCompression, flate.HuffmanOnly}
+
+// pngFilterTrialPixels is the largest image for which every filter strategy is tried;
+// larger images only use the adaptive heuristic
+const pngFilterTrialPixels = 16_000_000
+
+// PNG color types
+const (
+	pngColorGray      = 0
+	pngColorRGB       = 2
+	pngColorPalette   = 3
+	pngColorGrayAlpha = 4
+	pngColorRGBA      = 6
+)
+
+// PNGOptimizeResult describes the encoding chosen by the optimizer
+type PNGOptimizeResult struct {
+	Size         int               // Size of the optimized PNG in bytes
+	BaselineSize int               // Size produced by the standard encoder
+	ColorType    string            // Chosen color representation
+	BitDepth     int               // Bits per sample
+	Filter       PNGFilterStrategy // Chosen filter strategy
+	Level        int               // Chosen zlib compression level
+	Colors       int               // Number of palette colors (0 if not paletted)
+}
+
+// BytesSaved returns how many bytes the optimizer saved compared to the standard encoder
+func (r *PNGOptimizeResult) BytesSaved() int {
+	return r.BaselineSize - r.Size
+}
+
+// pngRaster is a candidate representation of an image as raw PNG scanlines
+type pngRaster struct {
+	width, height int
+	colorType     byte
+	bitDepth      int
+	palette       []color.NRGBA
+	rows          [][]byte // Unfiltered scanlines
+}
+
+// name returns a human readable name for the raster's color type
+func (r *pngRaster) name() string {
+	switch r.colorType {
+	case pngColorGray:
+		return "grayscale"
+	case pngColorGrayAlpha:
+		return "grayscale+alpha"
+	case pngColorRGB:
+		return "rgb"
+	case pngColorRGBA:
+		return "rgba"
+	default:
+		return "palette"
+	}
+}
+
+// bytesPerPixel returns the filter distance in bytes (at least 1)
+func (r *pngRaster) bytesPerPixel() int {
+	channels := map[byte]int{
+		pngColorGray:      1,
+		pngColorRGB:       3,
+		pngColorPalette:   1,
+		pngColorGrayAlpha: 2,
+		pngColorRGBA:      4,
+	}[r.colorType]
+	return max(1, channels*r.bitDepth/8)
+}
+
+// OptimizePNG encodes an image as small as possible by trying color type and
+// bit depth reductions, row filter strategies and zlib compression levels.
+// Candidates are ranked in parallel at a fast zlib level and only the winner is
+// compressed at the slow levels.
+// When options.Colors is set the image is first quantized to a palette with
+// Floyd-Steinberg dithering, which is lossy.
+func OptimizePNG(w io.Writer, img image.Image, options PNGOptions) (*PNGOptimizeResult, error) {
+	if img.Bounds().Empty() {
+		return nil, fmt.Errorf("cannot optimize empty image")
+	}
+	
+	baseline := &countingWriter{}
+	if err := png.Encode(baseline, img); err != nil {
+		return nil, err
+	}
+	
+	if options.Colors > 0 {
+		if options.Colors < 2 || options.Colors > 256 {
+			return nil, fmt.Errorf("colors must be between 2 and 256: %d", options.Colors)
+		}
+		img = QuantizeImage(img, options.Colors, true)
+	}
+	
+	// 16-bit images would lose precision, let the standard encoder handle them
+	if is16Bit(img) {
+		buf := &bytes.Buffer{}
+		enc := &png.Encoder{CompressionLevel: png.BestCompression}
+		if err := enc.Encode(buf, img); err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return nil, err
+		}
+		return &PNGOptimizeResult{
+			Size:         buf.Len(),
+			BaselineSize: baseline.n,
+			ColorType:    "16-bit",
+			BitDepth:     16,
+			Filter:       PNGFilterAdaptive,
+			Level:        flate.BestCompression,
+		}, nil
+	}
+	
+	// Rank every color type and filter strategy by a fast compression, then spend the
+	// slow levels on the winner only
+	var trials []pngTrial
+	for _, raster := range pngCandidateRasters(img) {
+		strategies := []PNGFilterStrategy{PNGFilterNone, PNGFilterSub, PNGFilterUp, PNGFilterAverage, PNGFilterPaeth, PNGFilterAdaptive}
+		if raster.bitDepth < 8 {
+			// Filters work on bytes, which rarely helps packed pixels
+			strategies = []PNGFilterStrategy{PNGFilterNone, PNGFilterAdaptive}
+		}
+		if raster.width*raster.height > pngFilterTrialPixels {
+			strategies = []PNGFilterStrategy{PNGFilterAdaptive}
+		}
+		for _, strategy := range strategies {
+			trials = append(trials, pngTrial{raster: raster, filter: strategy})
+		}
+	}
+	err := parallel(len(trials), func(i int) error {
+		encoded, err := trials[i].raster.encode(trials[i].raster.filter(trials[i].filter), flate.BestSpeed)
+		trials[i].size = len(encoded)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	chosen := trials[0]
+	for _, trial := range trials[1:] {
+		if trial.size < chosen.size {
+			chosen = trial
+		}
+	}
+	
+	raster := chosen.raster
+	filtered := raster.filter(chosen.filter)
+	encoded := make([][]byte, len(pngCompressionLevels))
+	err = parallel(len(pngCompressionLevels), func(i int) error {
+		var err error
+		encoded[i], err = raster.encode(filtered, pngCompressionLevels[i])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	best, level := encoded[0], pngCompressionLevels[0]
+	for i, data := range encoded[1:] {
+		if len(data) < len(best) {
+			best, level = data, pngCompressionLevels[i+1]
+		}
+	}
+	result := &PNGOptimizeResult{
+		BaselineSize: baseline.n,
+		ColorType:    raster.name(),
+		BitDepth:     raster.bitDepth,
+		Filter:       chosen.filter,
+		Level:        level,
+		Colors:       len(raster.palette),
+	}
+	
+	if _, err := w.Write(best); err != nil {
+		return nil, err
+	}
+	result.Size = len(best)
+	return result, nil
+}
+
+// is16Bit reports whether the image stores 16 bits per channel
+func is16Bit(img image.Image) bool {
+	switch img.(type) {
+	case *image.RGBA64, *image.NRGBA64, *image.Gray16:
+		return true
+	}
+	return false
+}
+
+// pngCandidateRasters returns the lossless representations worth trying for an image
+func pngCandidateRasters(img image.Image) []*pngRaster {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	
+	nrgba := image.NewNRGBA(image.Rect(0, 0, width, height))
+	if p, ok := img.(*image.Paletted); ok {
+		// Keep the exact palette colors instead of going through premultiplied RGBA
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				c := color.NRGBAModel.Convert(p.Palette[p.ColorIndexAt(bounds.Min.X+x, bounds.Min.Y+y)]).(color.NRGBA)
+				nrgba.SetNRGBA(x, y, c)
+			}
+		}
+	} else {
+		draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+	}
+	
+	// Analyze the pixels
+	gray, opaque := true, true
+	colors := make(map[color.NRGBA]int)
+	for i := 0; i < len(nrgba.Pix); i += 4 {
+		c := color.NRGBA{R: nrgba.Pix[i], G: nrgba.Pix[i+1], B: nrgba.Pix[i+2], A: nrgba.Pix[i+3]}
+		if c.A == 0 {
+			// Fully transparent pixels all look the same
+			c = color.NRGBA{}
+			nrgba.Pix[i], nrgba.Pix[i+1], nrgba.Pix[i+2] = 0, 0, 0
+		}
+		if c.A != 0xFF {
+			opaque = false
+		}
+		if c.R != c.G || c.G != c.B {
+			gray = false
+		}
+		if len(colors) <= 256 {
+			if _, ok := colors[c]; !ok {
+				colors[c] = len(colors)
+			}
+		}
+	}
+	
+	var candidates []*pngRaster
+	if len(colors) <= 256 {
+		candidates = append(candidates, newPalettedRaster(nrgba, colors))
+	}
+	
+	switch {
+	case gray && opaque:
+		candidates = append(candidates, newTrueColorRaster(nrgba, pngColorGray))
+	case gray:
+		candidates = append(candidates, newTrueColorRaster(nrgba, pngColorGrayAlpha))
+	case opaque:
+		candidates = append(candidates, newTrueColorRaster(nrgba, pngColorRGB))
+	default:
+		candidates = append(candidates, newTrueColorRaster(nrgba, pngColorRGBA))
+	}
+	
+	return candidates
+}
+
+// newTrueColorRaster builds a non-paletted raster with 8 bits per sample
+func newTrueColorRaster(img *image.NRGBA, colorType byte) *pngRaster {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	raster := &pngRaster{width: width, height: height, colorType: colorType, bitDepth: 8}
+	bpp := raster.bytesPerPixel()
+	
+	raster.rows = make([][]byte, height)
+	for y := 0; y < height; y++ {
+		row := make([]byte, width*bpp)
+		src := img.Pix[y*img.Stride : y*img.Stride+width*4]
+		for x := 0; x < width; x++ {
+			p := src[x*4 : x*4+4]
+			switch colorType {
+			case pngColorGray:
+				row[x] = p[0]
+			case pngColorGrayAlpha:
+				row[x*2], row[x*2+1] = p[0], p[3]
+			case pngColorRGB:
+				copy(row[x*3:x*3+3], p[:3])
+			default:
+				copy(row[x*4:x*4+4], p)
+			}
+		}
+		raster.rows[y] = row
+	}
+	return raster
+}
+
+// newPalettedRaster builds a paletted raster with the smallest possible bit depth
+func newPalettedRaster(img *image.NRGBA, colors map[color.NRGBA]int) *pngRaster {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	
+	// Order the palette so that translucent entries come first, which keeps tRNS short,
+	// and otherwise by first appearance so the output is deterministic
+	palette := make([]color.NRGBA, 0, len(colors))
+	for c := range colors {
+		palette = append(palette, c)
+	}
+	sort.Slice(palette, func(i, j int) bool {
+		ti, tj := palette[i].A != 0xFF, palette[j].A != 0xFF
+		if ti != tj {
+			return ti
+		}
+		return colors[palette[i]] < colors[palette[j]]
+	})
+	index := make(map[color.NRGBA]int, len(palette))
+	for i, c := range palette {
+		index[c] = i
+	}
+	
+	bitDepth := 8
+	switch {
+	case len(palette) <= 2:
+		bitDepth = 1
+	case len(palette) <= 4:
+		bitDepth = 2
+	case len(palette) <= 16:
+		bitDepth = 4
+	}
+	
+	raster := &pngRaster{width: width, height: height, colorType: pngColorPalette, bitDepth: bitDepth, palette: palette}
+	raster.rows = make([][]byte, height)
+	pixelsPerByte := 8 / bitDepth
+	for y := 0; y < height; y++ {
+		row := make([]byte, (width*bitDepth+7)/8)
+		src := img.Pix[y*img.Stride : y*img.Stride+width*4]
+		for x := 0; x < width; x++ {
+			p := src[x*4 : x*4+4]
+			i := index[color.NRGBA{R: p[0], G: p[1], B: p[2], A: p[3]}]
+			shift := uint(8 - bitDepth*(x%pixelsPerByte+1))
+			row[x/pixelsPerByte] |= byte(i) << shift
+		}
+		raster.rows[y] = row
+	}
+	return raster
+}
+
+// filter applies the filter strategy and returns the filtered scanlines,
+// each prefixed with its filter type byte
+func (r *pngRaster) filter(strategy PNGFilterStrategy) []byte {
+	bpp := r.bytesPerPixel()
+	rowLen := len(r.rows[0])
+	out := make([]byte, 0, r.height*(rowLen+1))
+	
+	prev := make([]byte, rowLen)
+	candidates := make([][]byte, 5)
+	for i := range candidates {
+		candidates[i] = make([]byte, rowLen)
+	}
+	
+	for _, row := range r.rows {
+		filterType := int(strategy)
+		if strategy == PNGFilterAdaptive {
+			// Minimum sum of absolute differences heuristic
+			bestSum := -1
+			for ft := 0; ft < 5; ft++ {
+				pngFilterRow(candidates[ft], row, prev, ft, bpp)
+				sum := 0
+				for _, b := range candidates[ft] {
+					sum += abs(int(int8(b)))
+				}
+				if bestSum < 0 || sum < bestSum {
+					bestSum, filterType = sum, ft
+				}
+			}
+		} else {
+			pngFilterRow(candidates[filterType], row, prev, filterType, bpp)
+		}
+		
+		out = append(out, byte(filterType))
+		out = append(out, candidates[filterType]...)
+		prev = row
+	}
+	return out
+}
+
+// pngFilterRow filters a single scanline with the given filter type
+func pngFilterRow(dst, row, prev []byte, filterType, bpp int) {
+	for i := range row {
+		var a, b, c byte
+		if i >= bpp {
+			a = row[i-bpp]
+			c = prev[i-bpp]
+		}
+		b = prev[i]
+		
+		switch filterType {
+		case 0:
+			dst[i] = row[i]
+		case 1:
+			dst[i] = row[i] - a
+		case 2:
+			dst[i] = row[i] - b
+		case 3:
+			dst[i] = row[i] - byte((int(a)+int(b))/2)
+		default:
+			dst[i] = row[i] - pngPaeth(a, b, c)
+		}
+	}
+}
+
+// pngPaeth implements the Paeth predictor
+func pngPaeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa := abs(p - int(a))
+	pb := abs(p - int(b))
+	pc := abs(p - int(c))
+	if pa <= pb && pa <= pc {
+		return a
+	}
+	if pb <= pc {
+		return b
+	}
+	return c
+}
+
+// encode writes a complete PNG file with the filtered data compressed at the given level
+func (r *pngRaster) encode(filtered []byte, level int) ([]byte, error) {
+	compressed := &bytes.Buffer{}
+	zw, err := zlib.NewWriterLevel(compressed, level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(filtered); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	
+	out := &bytes.Buffer{}
+	out.Write([]byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'})
+	
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:4], uint32(r.width))
+	binary.BigEndian.PutUint32(ihdr[4:8], uint32(r.height))
+	ihdr[8] = byte(r.bitDepth)
+	ihdr[9] = r.colorType
+	container.WriteChunk(out, "IHDR", ihdr)
+	
+	if r.colorType == pngColorPalette {
+		plte := make([]byte, 0, len(r.palette)*3)
+		var trns []byte
+		for _, c := range r.palette {
+			plte = append(plte, c.R, c.G, c.B)
+			if c.A != 0xFF {
+				trns = append(trns, c.A)
+			}
+		}
+		container.WriteChunk(out, "PLTE", plte)
+		if len(trns) > 0 {
+			container.WriteChunk(out, "tRNS", trns)
+		}
+	}
+	
+	container.WriteChunk(out, "IDAT", compressed.Bytes())
+	container.WriteChunk(out, "IEND", nil)
+	return out.Bytes(), nil
+}
+
+// countingWriter counts the bytes written to it
+type countingWriter struct {
+	n int
+}
+
+// Write implements io.Writer
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += len(p)
+	return len(p), nil
+}
+
+// pngTrial is a raster and filter strategy ranked by the optimizer
+type pngTrial struct {
+	raster *pngRaster
+	filter PNGFilterStrategy
+	size   int // File size at the fast compression level
+}
+
+// parallel runs fn for 0..n-1 on up to GOMAXPROCS goroutines and returns the first error
+func parallel(n int, fn func(i int) error) error {
+	errs := make([]error, n)
+	limit := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		limit <- struct{}{}
+		go func() {
+			defer wg.Done()
+			errs[i] = fn(i)
+			<-limit
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
\ No newline at end of file
@@ -0,0 +1,163 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"testing"
+)
+
+func TestOptimizePNGRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	
+	gradient := image.NewNRGBA(image.Rect(0, 0, 64, 48))
+	gray := image.NewNRGBA(image.Rect(0, 0, 50, 20))
+	fewColors := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	noise := image.NewNRGBA(image.Rect(0, 0, 17, 9))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			gradient.SetNRGBA(x, y, color.NRGBA{uint8(x * 4), uint8(y * 5), 128, 255})
+		}
+	}
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 50; x++ {
+			v := uint8(x * 5)
+			gray.SetNRGBA(x, y, color.NRGBA{v, v, v, 255})
+		}
+	}
+	palette := []color.NRGBA{{255, 0, 0, 255}, {0, 0, 255, 128}, {0, 0, 0, 0}}
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			fewColors.SetNRGBA(x, y, palette[rng.Intn(len(palette))])
+		}
+	}
+	for i := range noise.Pix {
+		noise.Pix[i] = uint8(rng.Intn(256))
+	}
+	
+	tests := []struct {
+		name      string
+		img       *image.NRGBA
+		colorType string
+	}{
+		{name: "Gradient", img: gradient},
+		{name: "Grayscale", img: gray, colorType: "grayscale"},
+		{name: "Few colors with alpha", img: fewColors, colorType: "palette"},
+		{name: "Noise with alpha", img: noise},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			result, err := OptimizePNG(buf, tt.img, PNGOptions{Optimize: true})
+			if err != nil {
+				t.Fatalf("OptimizePNG() error = %v", err)
+			}
+			if result.Size != buf.Len() {
+				t.Errorf("result.Size = %d, want %d", result.Size, buf.Len())
+			}
+			if result.Size > result.BaselineSize {
+				t.Errorf("optimized size %d is larger than baseline %d", result.Size, result.BaselineSize)
+			}
+			if tt.colorType != "" && result.ColorType != tt.colorType {
+				t.Errorf("color type = %s, want %s", result.ColorType, tt.colorType)
+			}
+			
+			decoded, err := png.Decode(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("png.Decode() error = %v", err)
+			}
+			bounds := tt.img.Bounds()
+			if decoded.Bounds() != bounds {
+				t.Fatalf("decoded bounds = %v, want %v", decoded.Bounds(), bounds)
+			}
+			for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+				for x := bounds.Min.X; x < bounds.Max.X; x++ {
+					want := tt.img.NRGBAAt(x, y)
+					got := color.NRGBAModel.Convert(decoded.At(x, y)).(color.NRGBA)
+					if want.A == 0 && got.A == 0 {
+						continue
+					}
+					if got != want {
+						t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestOptimizePNGColors(t *testing.T) {
+	// A noisy photo-like image, which quantization shrinks considerably
+	rng := rand.New(rand.NewSource(3))
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x*4 + rng.Intn(8)), uint8(y*4 + rng.Intn(8)), uint8(rng.Intn(8)), 255})
+		}
+	}
+	
+	buf := &bytes.Buffer{}
+	result, err := OptimizePNG(buf, img, PNGOptions{Colors: 16})
+	if err != nil {
+		t.Fatalf("OptimizePNG() error = %v", err)
+	}
+	if result.ColorType != "palette" {
+		t.Errorf("color type = %s, want palette", result.ColorType)
+	}
+	if result.BitDepth > 4 {
+		t.Errorf("bit depth = %d, want at most 4 for 16 colors", result.BitDepth)
+	}
+	if result.BytesSaved() <= 0 {
+		t.Errorf("BytesSaved() = %d, want a positive value", result.BytesSaved())
+	}
+	
+	decoded, err := png.Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	paletted, ok := decoded.(*image.Paletted)
+	if !ok {
+		t.Fatalf("decoded image is %T, want *image.Paletted", decoded)
+	}
+	if len(paletted.Palette) > 16 {
+		t.Errorf("palette has %d colors, want at most 16", len(paletted.Palette))
+	}
+}
+
+func TestOptimizePNGInvalidColors(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for _, n := range []int{1, 257} {
+		if _, err := OptimizePNG(&bytes.Buffer{}, img, PNGOptions{Colors: n}); err == nil {
+			t.Errorf("OptimizePNG() with %d colors succeeded, want error", n)
+		}
+	}
+}
+
+func TestConvertPNGOptimize(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 80, 60))
+	for y := 0; y < 60; y++ {
+		for x := 0; x < 80; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x / 10 * 30), uint8(y / 10 * 40), 0, 255})
+		}
+	}
+	input := &bytes.Buffer{}
+	if err := png.Encode(input, img); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+	
+	output := &bytes.Buffer{}
+	transformer := NewTransformer()
+	options := ConvertOptions{PNG: PNGOptions{Optimize: true}}
+	if err := transformer.Convert(bytes.NewReader(input.Bytes()), output, options); err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if output.Len() >= input.Len() {
+		t.Errorf("optimized size %d is not smaller than %d", output.Len(), input.Len())
+	}
+	if _, format, err := LoadImage(output); err != nil || format != FormatPNG {
+		t.Errorf("LoadImage() = %v, %v, want png", format, err)
+	}
+}
\ No newline at end of file
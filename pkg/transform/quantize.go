@@ -0,0 +1,264 @@
+package transform
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"sort"
+)
+
+// kMeansIterations is the number of refinement passes after median cut
+const kMeansIterations = 4
+
+// colorBin accumulates all pixels that fall into one cell of the color histogram
+type colorBin struct {
+	count      int
+	r, g, b, a int // Channel sums
+}
+
+// mean returns the average color of the bin
+func (b *colorBin) mean() [4]int {
+	return [4]int{b.r / b.count, b.g / b.count, b.b / b.count, b.a / b.count}
+}
+
+// colorBox is a set of histogram bins that median cut may split further
+type colorBox struct {
+	bins    []*colorBin
+	count   int
+	channel int // Channel with the widest spread
+	spread  int
+}
+
+// newColorBox creates a box and measures its widest channel
+func newColorBox(bins []*colorBin) *colorBox {
+	box := &colorBox{bins: bins, count: totalCount(bins), spread: -1}
+	lo := [4]int{255, 255, 255, 255}
+	hi := [4]int{}
+	for _, bin := range bins {
+		m := bin.mean()
+		for c := 0; c < 4; c++ {
+			lo[c] = min(lo[c], m[c])
+			hi[c] = max(hi[c], m[c])
+		}
+	}
+	for c := 0; c < 4; c++ {
+		if hi[c]-lo[c] > box.spread {
+			box.channel, box.spread = c, hi[c]-lo[c]
+		}
+	}
+	return box
+}
+
+// MedianCutQuantizer implements draw.Quantizer using median cut with k-means refinement
+type MedianCutQuantizer struct{}
+
+// Quantize appends up to cap(p) - len(p) colors to p
+func (MedianCutQuantizer) Quantize(p color.Palette, m image.Image) color.Palette {
+	n := cap(p) - len(p)
+	if n <= 0 {
+		return p
+	}
+	return append(p, MedianCutPalette(m, n)...)
+}
+
+// QuantizeImage reduces an image to a palette of at most n colors.
+// With dither enabled, quantization errors are diffused using Floyd-Steinberg.
+func QuantizeImage(img image.Image, n int, dither bool) *image.Paletted {
+	palette := MedianCutPalette(img, n)
+	bounds := img.Bounds()
+	result := image.NewPaletted(bounds, palette)
+	if dither {
+		draw.FloydSteinberg.Draw(result, bounds, img, bounds.Min)
+	} else {
+		draw.Draw(result, bounds, img, bounds.Min, draw.Src)
+	}
+	return result
+}
+
+// MedianCutPalette computes a palette of at most n colors that represents the image.
+// Colors are first split with median cut over a 5-bit-per-channel histogram,
+// then refined with a few k-means iterations.
+func MedianCutPalette(img image.Image, n int) color.Palette {
+	if n < 1 {
+		n = 1
+	}
+	
+	bins := buildColorHistogram(img)
+	if len(bins) == 0 {
+		return color.Palette{color.NRGBA{}}
+	}
+	
+	// Few enough distinct colors to use them directly
+	if len(bins) <= n {
+		palette := make(color.Palette, 0, len(bins))
+		for _, bin := range bins {
+			m := bin.mean()
+			palette = append(palette, color.NRGBA{R: uint8(m[0]), G: uint8(m[1]), B: uint8(m[2]), A: uint8(m[3])})
+		}
+		return palette
+	}
+	
+	boxes := []*colorBox{newColorBox(bins)}
+	for len(boxes) < n {
+		// Split the box with the largest population-weighted spread
+		best, bestScore := -1, 0.0
+		for i, box := range boxes {
+			if len(box.bins) < 2 {
+				continue
+			}
+			if score := float64(box.spread) * float64(box.count); best < 0 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			break
+		}
+		
+		left, right := splitColorBox(boxes[best])
+		boxes[best] = left
+		boxes = append(boxes, right)
+	}
+	
+	centers := make([][4]float64, len(boxes))
+	for i, box := range boxes {
+		var sum [4]float64
+		for _, bin := range box.bins {
+			sum[0] += float64(bin.r)
+			sum[1] += float64(bin.g)
+			sum[2] += float64(bin.b)
+			sum[3] += float64(bin.a)
+		}
+		for c := 0; c < 4; c++ {
+			centers[i][c] = sum[c] / float64(box.count)
+		}
+	}
+	
+	refineColorCenters(bins, centers)
+	
+	palette := make(color.Palette, len(centers))
+	for i, center := range centers {
+		palette[i] = color.NRGBA{
+			R: clampByte(int(center[0] + 0.5)),
+			G: clampByte(int(center[1] + 0.5)),
+			B: clampByte(int(center[2] + 0.5)),
+			A: clampByte(int(center[3] + 0.5)),
+		}
+	}
+	return palette
+}
+
+// buildColorHistogram groups the image pixels into 5-bit-per-channel bins
+func buildColorHistogram(img image.Image) []*colorBin {
+	bounds := img.Bounds()
+	nrgba, ok := img.(*image.NRGBA)
+	if !ok {
+		nrgba = image.NewNRGBA(bounds)
+		draw.Draw(nrgba, bounds, img, bounds.Min, draw.Src)
+	}
+	
+	histogram := make(map[uint32]*colorBin)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := nrgba.Pix[nrgba.PixOffset(bounds.Min.X, y):nrgba.PixOffset(bounds.Max.X, y)]
+		for i := 0; i < len(row); i += 4 {
+			r, g, b, a := int(row[i]), int(row[i+1]), int(row[i+2]), int(row[i+3])
+			if a == 0 {
+				r, g, b = 0, 0, 0
+			}
+			key := uint32(r>>3)<<15 | uint32(g>>3)<<10 | uint32(b>>3)<<5 | uint32(a>>3)
+			bin := histogram[key]
+			if bin == nil {
+				bin = &colorBin{}
+				histogram[key] = bin
+			}
+			bin.count++
+			bin.r += r
+			bin.g += g
+			bin.b += b
+			bin.a += a
+		}
+	}
+	
+	bins := make([]*colorBin, 0, len(histogram))
+	for _, bin := range histogram {
+		bins = append(bins, bin)
+	}
+	// Map iteration order is random, sort for deterministic output
+	sort.Slice(bins, func(i, j int) bool {
+		mi, mj := bins[i].mean(), bins[j].mean()
+		for c := 0; c < 4; c++ {
+			if mi[c] != mj[c] {
+				return mi[c] < mj[c]
+			}
+		}
+		return bins[i].count < bins[j].count
+	})
+	return bins
+}
+
+// splitColorBox splits a box at the population median of its widest channel
+func splitColorBox(box *colorBox) (*colorBox, *colorBox) {
+	channel := box.channel
+	sort.Slice(box.bins, func(i, j int) bool {
+		return box.bins[i].mean()[channel] < box.bins[j].mean()[channel]
+	})
+	
+	half, acc, split := box.count/2, 0, 1
+	for i, bin := range box.bins[:len(box.bins)-1] {
+		acc += bin.count
+		split = i + 1
+		if acc >= half {
+			break
+		}
+	}
+	
+	return newColorBox(box.bins[:split]), newColorBox(box.bins[split:])
+}
+
+// refineColorCenters moves the centers to the mean of the bins closest to them
+func refineColorCenters(bins []*colorBin, centers [][4]float64) {
+	iterations := kMeansIterations
+	if len(bins)*len(centers) > 50_000_000 {
+		iterations = 1
+	}
+	
+	for iter := 0; iter < iterations; iter++ {
+		sums := make([][4]float64, len(centers))
+		counts := make([]float64, len(centers))
+		for _, bin := range bins {
+			m := bin.mean()
+			nearest, bestDist := 0, -1.0
+			for i, center := range centers {
+				d := 0.0
+				for c := 0; c < 4; c++ {
+					diff := float64(m[c]) - center[c]
+					d += diff * diff
+				}
+				if bestDist < 0 || d < bestDist {
+					nearest, bestDist = i, d
+				}
+			}
+			sums[nearest][0] += float64(bin.r)
+			sums[nearest][1] += float64(bin.g)
+			sums[nearest][2] += float64(bin.b)
+			sums[nearest][3] += float64(bin.a)
+			counts[nearest] += float64(bin.count)
+		}
+		for i := range centers {
+			if counts[i] == 0 {
+				continue
+			}
+			for c := 0; c < 4; c++ {
+				centers[i][c] = sums[i][c] / counts[i]
+			}
+		}
+	}
+}
+
+// totalCount returns the number of pixels in the bins
+func totalCount(bins []*colorBin) int {
+	total := 0
+	for _, bin := range bins {
+		total += bin.count
+	}
+	return total
+}
\ No newline at end of file
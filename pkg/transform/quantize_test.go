@@ -0,0 +1,92 @@
+package transform
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestMedianCutPalette(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 100, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x * 2), uint8(y * 2), uint8(x + y), 255})
+		}
+	}
+	
+	tests := []struct {
+		name string
+		n    int
+	}{
+		{name: "2 colors", n: 2},
+		{name: "16 colors", n: 16},
+		{name: "256 colors", n: 256},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			palette := MedianCutPalette(img, tt.n)
+			if len(palette) == 0 || len(palette) > tt.n {
+				t.Errorf("palette size = %d, want 1..%d", len(palette), tt.n)
+			}
+		})
+	}
+}
+
+func TestMedianCutPaletteFewColors(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	colors := []color.NRGBA{{255, 0, 0, 255}, {0, 255, 0, 255}, {0, 0, 255, 255}}
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			img.SetNRGBA(x, y, colors[(x+y)%3])
+		}
+	}
+	
+	palette := MedianCutPalette(img, 256)
+	if len(palette) != 3 {
+		t.Fatalf("palette size = %d, want 3", len(palette))
+	}
+	for _, want := range colors {
+		found := false
+		for _, c := range palette {
+			if color.NRGBAModel.Convert(c) == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("palette %v is missing %v", palette, want)
+		}
+	}
+}
+
+func TestQuantizeImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 32))
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 64; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{uint8(x * 4), uint8(y * 8), 64, 255})
+		}
+	}
+	
+	for _, dither := range []bool{false, true} {
+		result := QuantizeImage(img, 8, dither)
+		if result.Bounds() != img.Bounds() {
+			t.Errorf("dither=%v: bounds = %v, want %v", dither, result.Bounds(), img.Bounds())
+		}
+		if len(result.Palette) > 8 {
+			t.Errorf("dither=%v: palette size = %d, want at most 8", dither, len(result.Palette))
+		}
+		
+		// The quantized image should stay close to the original on average
+		var total int
+		for y := 0; y < 32; y++ {
+			for x := 0; x < 64; x++ {
+				want := img.NRGBAAt(x, y)
+				got := color.NRGBAModel.Convert(result.At(x, y)).(color.NRGBA)
+				total += absDiff(int(got.R), int(want.R)) + absDiff(int(got.G), int(want.G)) + absDiff(int(got.B), int(want.B))
+			}
+		}
+		if avg := total / (64 * 32 * 3); avg > 24 {
+			t.Errorf("dither=%v: average channel error = %d, want at most 24", dither, avg)
+		}
+	}
+}
\ No newline at end of file
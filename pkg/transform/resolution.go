@@ -0,0 +1,138 @@
+package transform
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ResolutionUnit is the physical unit of a Resolution
+type ResolutionUnit string
+
+const (
+	// UnitInch measures resolution in dots per inch
+	UnitInch ResolutionUnit = "dpi"
+	// UnitCentimeter measures resolution in dots per centimeter
+	UnitCentimeter ResolutionUnit = "dpcm"
+)
+
+// Resolution is the physical pixel density of an image. The horizontal and vertical
+// densities may differ, as with fax images or non-square printer pixels.
+type Resolution struct {
+	X, Y float64        // Pixels per unit
+	Unit ResolutionUnit // UnitInch when empty
+}
+
+// SquareDPI returns a resolution with the same DPI in both directions
+func SquareDPI(dpi int) Resolution {
+	return Resolution{X: float64(dpi), Y: float64(dpi), Unit: UnitInch}
+}
+
+// IsZero reports whether the resolution is unset
+func (r Resolution) IsZero() bool {
+	return r.X <= 0 || r.Y <= 0
+}
+
+// DPI returns the horizontal and vertical resolution in dots per inch
+func (r Resolution) DPI() (float64, float64) {
+	if r.Unit == UnitCentimeter {
+		return r.X * 2.54, r.Y * 2.54
+	}
+	return r.X, r.Y
+}
+
+// PrintSize returns the printed width and height in inches of an image with the given
+// pixel size
+func (r Resolution) PrintSize(width, height int) (float64, float64) {
+	x, y := r.DPI()
+	return float64(width) / x, float64(height) / y
+}
+
+// pixelsPerMeter returns the resolution as stored in a PNG pHYs chunk
+func (r Resolution) pixelsPerMeter() (uint32, uint32) {
+	x, y := r.DPI()
+	return clampUint32(x / inchesToMeters), clampUint32(y / inchesToMeters)
+}
+
+// String formats the resolution like "300 DPI", "300x150 DPI" or "118 dpcm"
+func (r Resolution) String() string {
+	unit := "DPI"
+	if r.Unit == UnitCentimeter {
+		unit = "dpcm"
+	}
+	x, y := formatDensity(r.X), formatDensity(r.Y)
+	if x == y {
+		return x + " " + unit
+	}
+	return x + "x" + y + " " + unit
+}
+
+// formatDensity formats a density with at most one decimal place, which hides the
+// rounding of PNG's pixels-per-meter values
+func formatDensity(v float64) string {
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+}
+
+// ParseResolution parses a resolution like "300", "300x150", "300dpi" or "118dpcm"
+func ParseResolution(s string) (Resolution, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	r := Resolution{Unit: UnitInch}
+	if strings.HasSuffix(value, string(UnitCentimeter)) {
+		value = strings.TrimSuffix(value, string(UnitCentimeter))
+		r.Unit = UnitCentimeter
+	} else {
+		value = strings.TrimSuffix(value, string(UnitInch))
+	}
+	
+	xs, ys, found := strings.Cut(value, "x")
+	if !found {
+		ys = xs
+	}
+	var err error
+	if r.X, err = parseDensity(xs); err != nil {
+		return Resolution{}, fmt.Errorf("invalid resolution %q: %w", s, err)
+	}
+	if r.Y, err = parseDensity(ys); err != nil {
+		return Resolution{}, fmt.Errorf("invalid resolution %q: %w", s, err)
+	}
+	return r, nil
+}
+
+// parseDensity parses a density of at least 1 that fits the 16-bit JFIF fields
+func parseDensity(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("not a number: %q", s)
+	}
+	// Densities below 1 round to 0 in the integer fields; the negated form also rejects NaN
+	if !(v >= 1 && v <= math.MaxUint16) {
+		return 0, fmt.Errorf("must be between 1 and %d", math.MaxUint16)
+	}
+	return v, nil
+}
+
+// resolutionFromPixelsPerMeter converts a PNG pHYs resolution. PNG only stores meters,
+// so values are reported in DPI unless they are whole dots per centimeter that no
+// whole DPI value would have produced.
+func resolutionFromPixelsPerMeter(x, y uint32) Resolution {
+	if x%100 == 0 && y%100 == 0 && !(wholeDPI(x) && wholeDPI(y)) {
+		return Resolution{X: float64(x) / 100, Y: float64(y) / 100, Unit: UnitCentimeter}
+	}
+	return Resolution{X: float64(x) * inchesToMeters, Y: float64(y) * inchesToMeters, Unit: UnitInch}
+}
+
+// wholeDPI reports whether a pixels per meter value is what a whole DPI value rounds to
+func wholeDPI(ppm uint32) bool {
+	return clampUint32(math.Round(float64(ppm)*inchesToMeters)/inchesToMeters) == ppm
+}
+
+// clampUint32 rounds a value to the nearest uint32
+func clampUint32(v float64) uint32 {
+	return uint32(min(max(v+0.5, 0), math.MaxUint32))
+}
+
+// clampUint16 rounds a value to the nearest uint16
+func clampUint16(v float64) uint16 {
+	return uint16(min(max(v+0.5, 0), math.MaxUint16))
+}
\ No newline at end of file
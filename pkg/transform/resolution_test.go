@@ -0,0 +1,76 @@
+package transform
+
+import "testing"
+
+func TestParseResolution(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Resolution
+		wantErr bool
+	}{
+		{"300", Resolution{X: 300, Y: 300, Unit: UnitInch}, false},
+		{"300dpi", Resolution{X: 300, Y: 300, Unit: UnitInch}, false},
+		{"300x150", Resolution{X: 300, Y: 150, Unit: UnitInch}, false},
+		{"118dpcm", Resolution{X: 118, Y: 118, Unit: UnitCentimeter}, false},
+		{"118x59DPCM", Resolution{X: 118, Y: 59, Unit: UnitCentimeter}, false},
+		{"72.5", Resolution{X: 72.5, Y: 72.5, Unit: UnitInch}, false},
+		{"", Resolution{}, true},
+		{"0", Resolution{}, true},
+		{"0.5", Resolution{}, true},
+		{"300x0.5", Resolution{}, true},
+		{"NaN", Resolution{}, true},
+		{"300x", Resolution{}, true},
+		{"70000", Resolution{}, true},
+		{"abc", Resolution{}, true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseResolution(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseResolution() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseResolution() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolutionString(t *testing.T) {
+	tests := []struct {
+		resolution Resolution
+		want       string
+	}{
+		{SquareDPI(300), "300 DPI"},
+		{Resolution{X: 300, Y: 150}, "300x150 DPI"},
+		{Resolution{X: 118, Y: 118, Unit: UnitCentimeter}, "118 dpcm"},
+		{Resolution{X: 299.9994, Y: 299.9994, Unit: UnitInch}, "300 DPI"},
+		{Resolution{X: 72.5, Y: 72.5, Unit: UnitInch}, "72.5 DPI"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.resolution.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolutionDPI(t *testing.T) {
+	x, y := Resolution{X: 100, Y: 50, Unit: UnitCentimeter}.DPI()
+	if x != 254 || y != 127 {
+		t.Errorf("DPI() = %v, %v; want 254, 127", x, y)
+	}
+	if got := resolutionFromPixelsPerMeter(11811, 11811); got.String() != "300 DPI" {
+		t.Errorf("resolutionFromPixelsPerMeter(11811) = %v, want 300 DPI", got)
+	}
+	if got := resolutionFromPixelsPerMeter(11800, 5900); got.String() != "118x59 dpcm" {
+		t.Errorf("resolutionFromPixelsPerMeter(11800, 5900) = %v, want 118x59 dpcm", got)
+	}
+	// 254 DPI is exactly 100 dpcm; inches win when both are whole
+	if got := resolutionFromPixelsPerMeter(10000, 10000); got.String() != "254 DPI" {
+		t.Errorf("resolutionFromPixelsPerMeter(10000) = %v, want 254 DPI", got)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,98 @@
+package transform
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	
+	"github.com/allieus/imagekit/pkg/jpegcodec"
+	"github.com/disintegration/imaging"
+)
+
+// FlipMode selects a mirror operation
+type FlipMode string
+
+const (
+	FlipNone       FlipMode = ""
+	FlipHorizontal FlipMode = "horizontal" // Mirror left and right
+	FlipVertical   FlipMode = "vertical"   // Mirror top and bottom
+)
+
+// RotateOptions contains options for rotating and flipping an image
+type RotateOptions struct {
+	Angle    int      // Clockwise rotation in degrees, a multiple of 90
+	Flip     FlipMode // Mirror applied after the rotation
+	Lossless bool     // For JPEG, trim partial edge blocks instead of re-encoding
+}
+
+// ParseFlipMode parses a flip mode like "horizontal", "h", "vertical" or "v"
+func ParseFlipMode(s string) (FlipMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return FlipNone, nil
+	case "h", "horizontal":
+		return FlipHorizontal, nil
+	case "v", "vertical":
+		return FlipVertical, nil
+	default:
+		return FlipNone, fmt.Errorf("unsupported flip mode: %s (use horizontal or vertical)", s)
+	}
+}
+
+// ValidateRotateOptions checks that the angle is a multiple of 90 degrees
+func ValidateRotateOptions(options RotateOptions) error {
+	if options.Angle%90 != 0 {
+		return fmt.Errorf("rotation angle must be a multiple of 90: %d", options.Angle)
+	}
+	switch options.Flip {
+	case FlipNone, FlipHorizontal, FlipVertical:
+		return nil
+	default:
+		return fmt.Errorf("unsupported flip mode: %s", options.Flip)
+	}
+}
+
+// normalizedAngle returns the clockwise rotation in the range 0-270
+func (o RotateOptions) normalizedAngle() int {
+	return (o.Angle%360 + 360) % 360
+}
+
+// jpegTransforms returns the equivalent DCT-domain transforms, in order
+func (o RotateOptions) jpegTransforms() []jpegcodec.Transform {
+	var transforms []jpegcodec.Transform
+	switch o.normalizedAngle() {
+	case 90:
+		transforms = append(transforms, jpegcodec.Rotate90)
+	case 180:
+		transforms = append(transforms, jpegcodec.Rotate180)
+	case 270:
+		transforms = append(transforms, jpegcodec.Rotate270)
+	}
+	switch o.Flip {
+	case FlipHorizontal:
+		transforms = append(transforms, jpegcodec.FlipHorizontal)
+	case FlipVertical:
+		transforms = append(transforms, jpegcodec.FlipVertical)
+	}
+	return transforms
+}
+
+// RotateImage rotates an image clockwise and then applies the flip
+func RotateImage(img image.Image, options RotateOptions) image.Image {
+	// imaging rotates counter-clockwise
+	switch options.normalizedAngle() {
+	case 90:
+		img = imaging.Rotate270(img)
+	case 180:
+		img = imaging.Rotate180(img)
+	case 270:
+		img = imaging.Rotate90(img)
+	}
+	switch options.Flip {
+	case FlipHorizontal:
+		img = imaging.FlipH(img)
+	case FlipVertical:
+		img = imaging.FlipV(img)
+	}
+	return img
+}
\ No newline at end of file
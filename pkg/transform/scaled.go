@@ -0,0 +1,160 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	
+	"github.com/allieus/imagekit/pkg/jpegcodec"
+	"github.com/disintegration/imaging"
+)
+
+// jpegDecodeScale returns the largest JPEG decode scale (1, 2, 4 or 8) that still leaves
+// at least twice the target size, so the final resize filter has enough pixels to work
+// with. A target dimension of 0 keeps the source size.
+func jpegDecodeScale(srcWidth, srcHeight, targetWidth, targetHeight int) int {
+	if targetWidth <= 0 {
+		targetWidth = srcWidth
+	}
+	if targetHeight <= 0 {
+		targetHeight = srcHeight
+	}
+	for _, scale := range []int{8, 4, 2} {
+		if srcWidth/scale >= 2*targetWidth && srcHeight/scale >= 2*targetHeight {
+			return scale
+		}
+	}
+	return 1
+}
+
+// orientImage applies an EXIF orientation so the image is displayed upright
+func orientImage(img image.Image, orientation int) image.Image {
+	switch orientation {
+	case 2:
+		return imaging.FlipH(img)
+	case 3:
+		return imaging.Rotate180(img)
+	case 4:
+		return imaging.FlipV(img)
+	case 5:
+		return imaging.Transpose(img)
+	case 6:
+		return imaging.Rotate270(img)
+	case 7:
+		return imaging.Transverse(img)
+	case 8:
+		return imaging.Rotate90(img)
+	default:
+		return img
+	}
+}
+
+// fitSize returns the size resizeImage produces in ResizeFit mode, so a scaled decode
+// whose dimensions were rounded still gives the same output size as a full decode
+func fitSize(srcWidth, srcHeight, width, height int) (int, int) {
+	if width > 0 && height > 0 {
+		if float64(srcWidth)/float64(srcHeight) > float64(width)/float64(height) {
+			height = 0
+		} else {
+			width = 0
+		}
+	}
+	if height <= 0 {
+		return width, int(math.Max(1, math.Floor(float64(width)*float64(srcHeight)/float64(srcWidth)+0.5)))
+	}
+	return int(math.Max(1, math.Floor(float64(height)*float64(srcWidth)/float64(srcHeight)+0.5))), height
+}
+
+// loadAndResize loads an image and resizes it with target dimensions computed from its
+// original size. JPEGs much larger than the target are decoded at 1/2, 1/4 or 1/8 scale
+// in the DCT domain, which is far faster and smaller than a full decode; the resize
+// filter then produces the exact size.
+func (t *Transformer) loadAndResize(input io.Reader, options ResizeOptions) (image.Image, ImageFormat, error) {
+	data, err := t.readImage(input)
+	if err != nil {
+		return nil, "", err
+	}
+	return t.resizeData(data, options)
+}
+
+// resizeData decodes and resizes image data that was already read and checked.
+// Physical sizes are resolved against options.Resolution or the image's own.
+func (t *Transformer) resizeData(data []byte, options ResizeOptions) (image.Image, ImageFormat, error) {
+	if options.HasPhysicalSize() {
+		resolution := options.Resolution
+		if resolution.IsZero() {
+			resolution = imageResolution(data)
+		}
+		options = options.resolvePhysicalSize(resolution)
+		t.logf("physical size resolved at %s to %s x %s pixels", resolution, options.WidthDim, options.HeightDim)
+	}
+	
+	if isJPEGData(data) {
+		if img, err := t.loadScaledJPEG(data, options); err == nil && img != nil {
+			return img, FormatJPEG, nil
+		} else if errors.Is(err, ErrImageTooLarge) {
+			return nil, "", err
+		} else if err != nil {
+			t.logf("scaled JPEG decoding unavailable: %v", err)
+		}
+	}
+	
+	img, format, err := decodeImage(data)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load image: %w", err)
+	}
+	info := GetImageInfo(img, format)
+	targetWidth, targetHeight := CalculateDimensions(info.Width, info.Height, options)
+	if targetWidth == info.Width && targetHeight == info.Height {
+		t.logf("%dx%d image already matches the target size, skipping resize", info.Width, info.Height)
+		return img, format, nil
+	}
+	resized, err := resizeImage(img, targetWidth, targetHeight, options.Mode)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resize image: %w", err)
+	}
+	return resized, format, nil
+}
+
+// loadScaledJPEG decodes and resizes a JPEG using a reduced decode scale. It returns
+// nil without an error when the target is too close to the source size.
+func (t *Transformer) loadScaledJPEG(data []byte, options ResizeOptions) (image.Image, error) {
+	header, err := jpegcodec.DecodeHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.limits.CheckPixels(header.Width, header.Height); err != nil {
+		return nil, err
+	}
+	
+	// Compute the target from the displayed size
+	orientation := jpegOrientation(data)
+	width, height := header.Width, header.Height
+	if orientation >= 5 {
+		width, height = height, width
+	}
+	targetWidth, targetHeight := CalculateDimensions(width, height, options)
+	scale := jpegDecodeScale(width, height, targetWidth, targetHeight)
+	if scale == 1 {
+		return nil, nil
+	}
+	
+	img, err := jpegcodec.DecodeScaled(data, scale)
+	if err != nil {
+		return nil, err
+	}
+	t.logf("decoded %dx%d JPEG at 1/%d scale for a %dx%d target", width, height, scale, targetWidth, targetHeight)
+	
+	mode := options.Mode
+	if mode == ResizeFit {
+		targetWidth, targetHeight = fitSize(width, height, targetWidth, targetHeight)
+		mode = ResizeExact
+	}
+	resized, err := resizeImage(orientImage(img, orientation), targetWidth, targetHeight, mode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resize image: %w", err)
+	}
+	return resized, nil
+}
\ No newline at end of file
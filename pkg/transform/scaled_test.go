@@ -0,0 +1,171 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestJPEGDecodeScale(t *testing.T) {
+	tests := []struct {
+		name                      string
+		srcWidth, srcHeight       int
+		targetWidth, targetHeight int
+		want                      int
+	}{
+		{"Tenth of the size", 8000, 6000, 800, 600, 4},
+		{"Tiny thumbnail", 8000, 6000, 160, 120, 8},
+		{"Half size", 4000, 3000, 2000, 1500, 1},
+		{"Quarter size", 4000, 3000, 1000, 750, 2},
+		{"Enlargement", 400, 300, 800, 600, 1},
+		{"Zero keeps the source size", 8000, 6000, 200, 0, 1},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jpegDecodeScale(tt.srcWidth, tt.srcHeight, tt.targetWidth, tt.targetHeight); got != tt.want {
+				t.Errorf("jpegDecodeScale() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// photoJPEG encodes a smooth synthetic photo of the given size
+func photoJPEG(tb testing.TB, width, height int) []byte {
+	tb.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			fx, fy := float64(x)/float64(width), float64(y)/float64(height)
+			img.SetRGBA(x, y, color.RGBA{
+				uint8(127 + 127*math.Sin(fx*9)),
+				uint8(127 + 127*math.Cos(fy*7)),
+				uint8(255 * fx * fy),
+				255,
+			})
+		}
+	}
+	buf := &bytes.Buffer{}
+	if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: 90}); err != nil {
+		tb.Fatalf("jpeg.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestLoadAndResizeScaledMatchesFullDecode(t *testing.T) {
+	data := photoJPEG(t, 1203, 803)
+	
+	for _, options := range []ResizeOptions{
+		{WidthDim: DimensionValue{IsMultiplier: true, Multiplier: 0.1}, Mode: ResizeFit},
+		{Width: 150, Height: 150, Mode: ResizeFit},
+		{Width: 100, Height: 100, Mode: ResizeFill},
+	} {
+		scaled, _, err := NewTransformer().loadAndResize(bytes.NewReader(data), options)
+		if err != nil {
+			t.Fatalf("loadAndResize() error = %v", err)
+		}
+		
+		full, format, err := LoadImage(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("LoadImage() error = %v", err)
+		}
+		info := GetImageInfo(full, format)
+		width, height := CalculateDimensions(info.Width, info.Height, options)
+		want, err := resizeImage(full, width, height, options.Mode)
+		if err != nil {
+			t.Fatalf("resizeImage() error = %v", err)
+		}
+		
+		if scaled.Bounds().Size() != want.Bounds().Size() {
+			t.Fatalf("%+v: size = %v, want %v", options, scaled.Bounds().Size(), want.Bounds().Size())
+		}
+		if p := imagePSNR(want, scaled); p < 35 {
+			t.Errorf("%+v: PSNR against a full decode = %.2f dB, want at least 35", options, p)
+		}
+	}
+}
+
+func TestLoadAndResizeScaledAppliesOrientation(t *testing.T) {
+	// Orientation 6 means the stored 640x320 image is displayed as 320x640
+	data := losslessTestJPEG(t, 640, 320, 6)
+	logged := false
+	transformer := NewTransformer()
+	transformer.SetLogger(func(format string, args ...interface{}) { logged = true })
+	
+	img, _, err := transformer.loadAndResize(bytes.NewReader(data), ResizeOptions{Width: 40, Mode: ResizeFit})
+	if err != nil {
+		t.Fatalf("loadAndResize() error = %v", err)
+	}
+	if img.Bounds().Dx() != 40 || img.Bounds().Dy() != 80 {
+		t.Errorf("size = %v, want 40x80", img.Bounds().Size())
+	}
+	if !logged {
+		t.Error("expected the scaled decode to be logged")
+	}
+}
+
+// imagePSNR returns the peak signal-to-noise ratio between two images in dB
+func imagePSNR(a, b image.Image) float64 {
+	var sum float64
+	n := 0
+	bounds := a.Bounds()
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			r1, g1, b1, _ := a.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			r2, g2, b2, _ := b.At(b.Bounds().Min.X+x, b.Bounds().Min.Y+y).RGBA()
+			for _, d := range []float64{
+				float64(r1>>8) - float64(r2>>8),
+				float64(g1>>8) - float64(g2>>8),
+				float64(b1>>8) - float64(b2>>8),
+			} {
+				sum += d * d
+				n++
+			}
+		}
+	}
+	if sum == 0 {
+		return math.Inf(1)
+	}
+	return 10 * math.Log10(255*255/(sum/float64(n)))
+}
+
+var (
+	benchmarkPhotoOnce sync.Once
+	benchmarkPhoto     []byte
+)
+
+// BenchmarkThumbnail compares a full decode with a DCT-scaled decode when making a
+// 10% thumbnail of a 24 MP photo. Run with -benchmem to see the memory difference.
+func BenchmarkThumbnail(b *testing.B) {
+	benchmarkPhotoOnce.Do(func() { benchmarkPhoto = photoJPEG(b, 6000, 4000) })
+	options := ResizeOptions{WidthDim: DimensionValue{IsMultiplier: true, Multiplier: 0.1}, Mode: ResizeFit}
+	
+	b.Run("FullDecode", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			img, format, err := LoadImage(bytes.NewReader(benchmarkPhoto))
+			if err != nil {
+				b.Fatal(err)
+			}
+			info := GetImageInfo(img, format)
+			width, height := CalculateDimensions(info.Width, info.Height, options)
+			if _, err := resizeImage(img, width, height, options.Mode); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	
+	b.Run("ScaledDecode", func(b *testing.B) {
+		b.ReportAllocs()
+		transformer := NewTransformer()
+		for i := 0; i < b.N; i++ {
+			if _, _, err := transformer.loadAndResize(bytes.NewReader(benchmarkPhoto), options); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
\ No newline at end of file
@@ -0,0 +1,243 @@
+package transform
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"sort"
+	"strings"
+	
+	"github.com/disintegration/imaging"
+)
+
+// PaperSize is a sheet size in millimeters, portrait
+type PaperSize struct {
+	Name          string
+	Width, Height float64
+}
+
+// paperSizes are the named sheet sizes accepted by ParsePaperSize
+var paperSizes = map[string]PaperSize{
+	"a3":     {"A3", 297, 420},
+	"a4":     {"A4", 210, 297},
+	"a5":     {"A5", 148, 210},
+	"a6":     {"A6", 105, 148},
+	"b5":     {"B5", 182, 257}, // JIS B5, the common Korean and Japanese size
+	"letter": {"Letter", 215.9, 279.4},
+	"legal":  {"Legal", 215.9, 355.6},
+}
+
+// cropMarkGap separates sheet crop marks from the photos, in inches
+const cropMarkGap = 2 / 25.4
+
+// ParsePaperSize parses a paper name like "A4" or "Letter", or a size like "210x297mm"
+func ParsePaperSize(s string) (PaperSize, error) {
+	if paper, ok := paperSizes[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return paper, nil
+	}
+	width, height, err := ParsePrintSize(s)
+	if err != nil || !width.IsPhysical() || !height.IsPhysical() {
+		return PaperSize{}, fmt.Errorf("unsupported paper size: %s (use A3, A4, A5, A6, B5, Letter, Legal or a size like 210x297mm)", s)
+	}
+	return PaperSize{
+		Name:   strings.TrimSpace(s),
+		Width:  width.Length * width.Unit.inches() * 25.4,
+		Height: height.Length * height.Unit.inches() * 25.4,
+	}, nil
+}
+
+// ParsePrintSize parses a width and height like "9x13cm", "3.5x5in" or "600x400"
+// pixels. The unit suffix applies to both values.
+func ParsePrintSize(s string) (DimensionValue, DimensionValue, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	var unit string
+	for _, u := range []LengthUnit{LengthCentimeter, LengthMillimeter, LengthInch} {
+		if rest, ok := strings.CutSuffix(value, string(u)); ok {
+			value, unit = rest, string(u)
+			break
+		}
+	}
+	
+	ws, hs, found := strings.Cut(value, "x")
+	if !found {
+		return DimensionValue{}, DimensionValue{}, fmt.Errorf("invalid size: %s (use WIDTHxHEIGHT like 9x13cm)", s)
+	}
+	width, err := ParseDimension(strings.TrimSpace(ws) + unit)
+	if err != nil {
+		return DimensionValue{}, DimensionValue{}, fmt.Errorf("invalid size %s: %w", s, err)
+	}
+	height, err := ParseDimension(strings.TrimSpace(hs) + unit)
+	if err != nil {
+		return DimensionValue{}, DimensionValue{}, fmt.Errorf("invalid size %s: %w", s, err)
+	}
+	if width.IsZero() || height.IsZero() || width.IsMultiplier || height.IsMultiplier {
+		return DimensionValue{}, DimensionValue{}, fmt.Errorf("invalid size: %s", s)
+	}
+	return width, height, nil
+}
+
+// SheetOptions contains options for laying out photos on printed sheets
+type SheetOptions struct {
+	Paper      PaperSize
+	Landscape  bool           // Use the paper sideways
+	Resolution Resolution     // Output resolution (zero = 300 DPI)
+	CellWidth  DimensionValue // Printed photo width
+	CellHeight DimensionValue // Printed photo height
+	Margin     DimensionValue // Space kept free at the paper edges
+	Gutter     DimensionValue // Space between photos
+	CropMarks  bool           // Draw cut lines in the margins in line with the photo edges
+	AutoRotate bool           // Turn the grid and the photos to fit more and crop less
+	Background color.Color    // Paper color (nil = white)
+}
+
+// SheetLayout is the pixel geometry of an N-up sheet
+type SheetLayout struct {
+	Size       image.Point       // Page size in pixels
+	Cells      []image.Rectangle // Photo positions, row by row
+	Columns    int
+	Rows       int
+	Rotated    bool // The cells are turned 90 degrees from the requested size
+	Resolution Resolution
+	options    SheetOptions
+}
+
+// NewSheetLayout places as many cells of the requested physical size on the page as
+// fit within the margins, centering the grid
+func NewSheetLayout(options SheetOptions) (*SheetLayout, error) {
+	if options.Paper.Width <= 0 || options.Paper.Height <= 0 {
+		return nil, fmt.Errorf("paper size must be specified")
+	}
+	for _, d := range []DimensionValue{options.CellWidth, options.CellHeight, options.Margin, options.Gutter} {
+		if d.IsMultiplier || d.Constraint != ConstraintNone {
+			return nil, fmt.Errorf("sheet sizes must be a length or pixel count: %s", d)
+		}
+	}
+	if options.CellWidth.IsZero() || options.CellHeight.IsZero() {
+		return nil, fmt.Errorf("photo size must be specified")
+	}
+	if options.Resolution.IsZero() {
+		options.Resolution = SquareDPI(300)
+	}
+	dpiX, dpiY := options.Resolution.DPI()
+	
+	paperWidth, paperHeight := options.Paper.Width, options.Paper.Height
+	if options.Landscape {
+		paperWidth, paperHeight = paperHeight, paperWidth
+	}
+	size := image.Pt(
+		DimensionValue{Length: paperWidth, Unit: LengthMillimeter}.Resolve(dpiX).Value,
+		DimensionValue{Length: paperHeight, Unit: LengthMillimeter}.Resolve(dpiY).Value,
+	)
+	marginX, marginY := options.Margin.Resolve(dpiX).Value, options.Margin.Resolve(dpiY).Value
+	gutterX, gutterY := options.Gutter.Resolve(dpiX).Value, options.Gutter.Resolve(dpiY).Value
+	availWidth, availHeight := size.X-2*marginX, size.Y-2*marginY
+	
+	// Cell sizes in the requested orientation and turned sideways. A turned cell swaps
+	// its physical width and height, so each is resolved at the other axis' DPI.
+	cell := image.Pt(options.CellWidth.Resolve(dpiX).Value, options.CellHeight.Resolve(dpiY).Value)
+	turned := image.Pt(options.CellHeight.Resolve(dpiX).Value, options.CellWidth.Resolve(dpiY).Value)
+	
+	l := &SheetLayout{Size: size, Resolution: options.Resolution, options: options}
+	l.Columns, l.Rows = gridCount(availWidth, cell.X, gutterX), gridCount(availHeight, cell.Y, gutterY)
+	if options.AutoRotate {
+		columns, rows := gridCount(availWidth, turned.X, gutterX), gridCount(availHeight, turned.Y, gutterY)
+		if columns*rows > l.Columns*l.Rows {
+			l.Columns, l.Rows, l.Rotated = columns, rows, true
+			cell = turned
+		}
+	}
+	if l.Columns*l.Rows == 0 {
+		return nil, fmt.Errorf("a %sx%s photo does not fit on %s paper with %s margins",
+			options.CellWidth, options.CellHeight, options.Paper.Name, options.Margin)
+	}
+	
+	gridWidth := l.Columns*cell.X + (l.Columns-1)*gutterX
+	gridHeight := l.Rows*cell.Y + (l.Rows-1)*gutterY
+	origin := image.Pt((size.X-gridWidth)/2, (size.Y-gridHeight)/2)
+	for row := 0; row < l.Rows; row++ {
+		for column := 0; column < l.Columns; column++ {
+			corner := origin.Add(image.Pt(column*(cell.X+gutterX), row*(cell.Y+gutterY)))
+			l.Cells = append(l.Cells, image.Rectangle{Min: corner, Max: corner.Add(cell)})
+		}
+	}
+	return l, nil
+}
+
+// gridCount returns how many cells with gutters between them fit in the space
+func gridCount(space, cell, gutter int) int {
+	if cell <= 0 || cell > space {
+		return 0
+	}
+	return 1 + (space-cell)/(cell+gutter)
+}
+
+// Pages returns the number of sheets needed for count photos
+func (l *SheetLayout) Pages(count int) int {
+	return (count + len(l.Cells) - 1) / len(l.Cells)
+}
+
+// NewPage returns a blank page
+func (l *SheetLayout) NewPage() *image.NRGBA {
+	page := image.NewNRGBA(image.Rectangle{Max: l.Size})
+	background := l.options.Background
+	if background == nil {
+		background = color.White
+	}
+	draw.Draw(page, page.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	return page
+}
+
+// Place scales and center-crops img to fill cell i of the page. With AutoRotate, a
+// landscape photo in a portrait cell, or the reverse, is turned first.
+func (l *SheetLayout) Place(page *image.NRGBA, i int, img image.Image) {
+	cell := l.Cells[i]
+	bounds := img.Bounds()
+	if l.options.AutoRotate && isLandscape(bounds.Dx(), bounds.Dy()) != isLandscape(cell.Dx(), cell.Dy()) &&
+		bounds.Dx() != bounds.Dy() && cell.Dx() != cell.Dy() {
+		img = imaging.Rotate90(img)
+	}
+	fitted := imaging.Fill(img, cell.Dx(), cell.Dy(), imaging.Center, imaging.Lanczos)
+	draw.Draw(page, cell, fitted, image.Point{}, draw.Over)
+}
+
+// isLandscape reports whether a size is wider than it is tall
+func isLandscape(width, height int) bool {
+	return width > height
+}
+
+// DrawCropMarks draws cut lines in the margins in line with every photo edge
+func (l *SheetLayout) DrawCropMarks(page *image.NRGBA) {
+	dpiX, dpiY := l.Resolution.DPI()
+	lengthX, lengthY := inchesToPixels(cropMarkLength, dpiX), inchesToPixels(cropMarkLength, dpiY)
+	gapX, gapY := inchesToPixels(cropMarkGap, dpiX), inchesToPixels(cropMarkGap, dpiY)
+	lineX, lineY := inchesToPixels(cropMarkThickness, dpiX), inchesToPixels(cropMarkThickness, dpiY)
+	
+	grid := l.Cells[0].Union(l.Cells[len(l.Cells)-1])
+	mark := image.NewUniform(cropMarkColor)
+	for _, x := range cellEdges(l.Cells, func(r image.Rectangle) (int, int) { return r.Min.X, r.Max.X - lineX }) {
+		draw.Draw(page, image.Rect(x, grid.Min.Y-gapY-lengthY, x+lineX, grid.Min.Y-gapY), mark, image.Point{}, draw.Src)
+		draw.Draw(page, image.Rect(x, grid.Max.Y+gapY, x+lineX, grid.Max.Y+gapY+lengthY), mark, image.Point{}, draw.Src)
+	}
+	for _, y := range cellEdges(l.Cells, func(r image.Rectangle) (int, int) { return r.Min.Y, r.Max.Y - lineY }) {
+		draw.Draw(page, image.Rect(grid.Min.X-gapX-lengthX, y, grid.Min.X-gapX, y+lineY), mark, image.Point{}, draw.Src)
+		draw.Draw(page, image.Rect(grid.Max.X+gapX, y, grid.Max.X+gapX+lengthX, y+lineY), mark, image.Point{}, draw.Src)
+	}
+}
+
+// cellEdges returns the distinct edge positions of the cells along one axis
+func cellEdges(cells []image.Rectangle, edges func(image.Rectangle) (int, int)) []int {
+	seen := make(map[int]bool)
+	var positions []int
+	for _, cell := range cells {
+		a, b := edges(cell)
+		for _, p := range []int{a, b} {
+			if !seen[p] {
+				seen[p] = true
+				positions = append(positions, p)
+			}
+		}
+	}
+	sort.Ints(positions)
+	return positions
+}
\ No newline at end of file
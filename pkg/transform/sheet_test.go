@@ -0,0 +1,144 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestParsePrintSize(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantWidth  DimensionValue
+		wantHeight DimensionValue
+		wantErr    bool
+	}{
+		{"9x13cm", DimensionValue{Length: 9, Unit: LengthCentimeter}, DimensionValue{Length: 13, Unit: LengthCentimeter}, false},
+		{"3.5 x 5in", DimensionValue{Length: 3.5, Unit: LengthInch}, DimensionValue{Length: 5, Unit: LengthInch}, false},
+		{"600x400", DimensionValue{Value: 600}, DimensionValue{Value: 400}, false},
+		{"9cm", DimensionValue{}, DimensionValue{}, true},
+		{"0x13cm", DimensionValue{}, DimensionValue{}, true},
+		{"9x13px", DimensionValue{}, DimensionValue{}, true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			width, height, err := ParsePrintSize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePrintSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if width != tt.wantWidth || height != tt.wantHeight {
+				t.Errorf("ParsePrintSize() = %v, %v, want %v, %v", width, height, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
+
+func TestParsePaperSize(t *testing.T) {
+	if paper, err := ParsePaperSize("a4"); err != nil || paper.Width != 210 || paper.Height != 297 {
+		t.Errorf("ParsePaperSize(a4) = %+v, %v", paper, err)
+	}
+	if paper, err := ParsePaperSize("4x6in"); err != nil || math.Round(paper.Width*10) != 1016 || math.Round(paper.Height*10) != 1524 {
+		t.Errorf("ParsePaperSize(4x6in) = %+v, %v", paper, err)
+	}
+	if _, err := ParsePaperSize("A0"); err == nil {
+		t.Error("ParsePaperSize(A0) should fail")
+	}
+}
+
+func TestNewSheetLayout(t *testing.T) {
+	a4 := paperSizes["a4"]
+	mm := func(v float64) DimensionValue { return DimensionValue{Length: v, Unit: LengthMillimeter} }
+	
+	tests := []struct {
+		name        string
+		options     SheetOptions
+		wantCells   int
+		wantRotated bool
+	}{
+		{"9x13cm on A4", SheetOptions{Paper: a4, CellWidth: mm(90), CellHeight: mm(130), Margin: mm(5), Gutter: mm(2)}, 4, false},
+		{"10x15cm on A4", SheetOptions{Paper: a4, CellWidth: mm(100), CellHeight: mm(150), Margin: mm(5), Gutter: mm(2)}, 1, false},
+		{"10x15cm on A4 turned", SheetOptions{Paper: a4, CellWidth: mm(100), CellHeight: mm(150), Margin: mm(5), Gutter: mm(2), AutoRotate: true}, 2, true},
+		{"Landscape paper", SheetOptions{Paper: a4, Landscape: true, CellWidth: mm(100), CellHeight: mm(150), Margin: mm(5)}, 2, false},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := NewSheetLayout(tt.options)
+			if err != nil {
+				t.Fatalf("NewSheetLayout() error = %v", err)
+			}
+			if len(layout.Cells) != tt.wantCells || layout.Rotated != tt.wantRotated {
+				t.Errorf("NewSheetLayout() = %d cells, rotated %v, want %d, %v",
+					len(layout.Cells), layout.Rotated, tt.wantCells, tt.wantRotated)
+			}
+			page := image.Rectangle{Max: layout.Size}
+			for _, cell := range layout.Cells {
+				if !cell.In(page) {
+					t.Errorf("cell %v is outside the %v page", cell, page)
+				}
+			}
+		})
+	}
+	
+	_, err := NewSheetLayout(SheetOptions{Paper: a4, CellWidth: mm(300), CellHeight: mm(400)})
+	if err == nil {
+		t.Error("NewSheetLayout() should fail when the photo is larger than the paper")
+	}
+}
+
+func TestSheetExactPhysicalSize(t *testing.T) {
+	layout, err := NewSheetLayout(SheetOptions{
+		Paper:      paperSizes["a6"],
+		Resolution: SquareDPI(300),
+		CellWidth:  DimensionValue{Length: 4, Unit: LengthCentimeter},
+		CellHeight: DimensionValue{Length: 4, Unit: LengthCentimeter},
+		Margin:     DimensionValue{Length: 5, Unit: LengthMillimeter},
+		Gutter:     DimensionValue{Length: 2, Unit: LengthMillimeter},
+		CropMarks:  true,
+	})
+	if err != nil {
+		t.Fatalf("NewSheetLayout() error = %v", err)
+	}
+	// A6 is 1240x1748 pixels at 300 DPI and fits 2x3 photos of 4cm, 472 pixels square
+	if layout.Size != image.Pt(1240, 1748) || len(layout.Cells) != 6 || layout.Cells[0].Size() != image.Pt(472, 472) {
+		t.Fatalf("layout = %v page, %d cells of %v", layout.Size, len(layout.Cells), layout.Cells[0])
+	}
+	
+	// A black landscape photo
+	photo := image.NewGray(image.Rect(0, 0, 200, 100))
+	buf := &bytes.Buffer{}
+	if err := jpeg.Encode(buf, photo, nil); err != nil {
+		t.Fatal(err)
+	}
+	
+	output := &bytes.Buffer{}
+	inputs := []io.Reader{bytes.NewReader(buf.Bytes()), bytes.NewReader(buf.Bytes())}
+	if err := NewTransformer().Sheet(inputs, output, FormatJPEG, layout); err != nil {
+		t.Fatalf("Sheet() error = %v", err)
+	}
+	
+	resolution, err := GetImageResolution(bytes.NewReader(output.Bytes()), FormatJPEG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolution.String() != "300 DPI" {
+		t.Errorf("Sheet() resolution = %s, want 300 DPI", resolution)
+	}
+	
+	page, err := jpeg.Decode(bytes.NewReader(output.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cell := layout.Cells[1]
+	if r, _, _, _ := page.At(cell.Min.X+10, cell.Min.Y+10).RGBA(); r > 0x1000 {
+		t.Errorf("second photo is missing from its cell")
+	}
+	if c := color.GrayModel.Convert(page.At(layout.Cells[2].Min.X+10, layout.Cells[2].Min.Y+10)).(color.Gray); c.Y < 0xF0 {
+		t.Errorf("empty cell is not paper colored: %v", c)
+	}
+}
\ No newline at end of file
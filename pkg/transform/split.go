@@ -0,0 +1,185 @@
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// SplitOptions contains options for cutting an image into a grid of tiles. Either
+// Columns and Rows or TileWidth and TileHeight are set.
+type SplitOptions struct {
+	Columns, Rows         int            // Split into this many tiles
+	TileWidth, TileHeight DimensionValue // Or cut tiles of this size, in pixels or a length like "210mm"
+	Overlap               DimensionValue // Pixels or length shared by neighbouring tiles
+	Pad                   bool           // Extend the last row and column to the full tile size
+	Background            color.Color    // Padding color (nil = white)
+	Resolution            Resolution     // Resolution for physical lengths (zero = the image's own)
+	KeepResolution        bool           // Write the resolution into each JPEG or PNG tile
+	Quality               int
+}
+
+// SplitTile is one tile of a split image. Row and Column count from 1.
+type SplitTile struct {
+	Row, Column   int
+	Rows, Columns int
+	Bounds        image.Rectangle // Area of the source image
+}
+
+// SplitWriter stores the encoded data of one tile
+type SplitWriter func(tile SplitTile, data []byte) error
+
+// ParseGrid parses a grid like "3x2" as columns and rows
+func ParseGrid(s string) (int, int, error) {
+	cs, rs, found := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "x")
+	columns, err1 := strconv.Atoi(strings.TrimSpace(cs))
+	rows, err2 := strconv.Atoi(strings.TrimSpace(rs))
+	if !found || err1 != nil || err2 != nil || columns < 1 || rows < 1 {
+		return 0, 0, fmt.Errorf("invalid grid: %s (use COLUMNSxROWS like 3x1)", s)
+	}
+	return columns, rows, nil
+}
+
+// ValidateSplitOptions checks that exactly one of a grid or a tile size is given
+func ValidateSplitOptions(options SplitOptions) error {
+	grid := options.Columns > 0 || options.Rows > 0
+	size := !options.TileWidth.IsZero() || !options.TileHeight.IsZero()
+	if grid == size {
+		return fmt.Errorf("either a grid or a tile size must be specified")
+	}
+	if grid && (options.Columns < 1 || options.Rows < 1) {
+		return fmt.Errorf("invalid grid: %dx%d", options.Columns, options.Rows)
+	}
+	for _, d := range []DimensionValue{options.TileWidth, options.TileHeight, options.Overlap} {
+		if d.IsMultiplier || d.Constraint != ConstraintNone || d.Value < 0 || d.Length < 0 {
+			return fmt.Errorf("tile size and overlap must be a length or pixel count: %s", d)
+		}
+	}
+	if size && (options.TileWidth.IsZero() || options.TileHeight.IsZero()) {
+		return fmt.Errorf("tile width and height must both be specified")
+	}
+	return nil
+}
+
+// SplitGrid returns the tiles of a width x height image. Tiles advance by their size
+// minus the overlap, and the last row and column are cut at the image edge.
+func SplitGrid(width, height int, options SplitOptions, resolution Resolution) ([]SplitTile, image.Point, error) {
+	if err := ValidateSplitOptions(options); err != nil {
+		return nil, image.Point{}, err
+	}
+	if resolution.IsZero() {
+		resolution = SquareDPI(96)
+	}
+	dpiX, dpiY := resolution.DPI()
+	var overlapX, overlapY int
+	if !options.Overlap.IsZero() {
+		overlapX = options.Overlap.Resolve(dpiX).Value
+		overlapY = options.Overlap.Resolve(dpiY).Value
+	}
+	
+	// Tile size and count along one axis
+	axis := func(length, count int, tile DimensionValue, overlap int, dpi float64) (int, int, error) {
+		if count > length {
+			return 0, 0, fmt.Errorf("cannot split %d pixels into %d tiles", length, count)
+		}
+		size := tile.Resolve(dpi).Value
+		if count > 0 {
+			// The tiles together cover the length plus every overlap
+			size = (length + (count-1)*overlap + count - 1) / count
+		}
+		step := size - overlap
+		if step <= 0 {
+			return 0, 0, fmt.Errorf("overlap of %d pixels leaves no room in %d pixel tiles", overlap, size)
+		}
+		if count == 0 {
+			count = 1 + max(0, (length-size+step-1)/step)
+		}
+		return size, count, nil
+	}
+	tileWidth, columns, err := axis(width, options.Columns, options.TileWidth, overlapX, dpiX)
+	if err != nil {
+		return nil, image.Point{}, err
+	}
+	tileHeight, rows, err := axis(height, options.Rows, options.TileHeight, overlapY, dpiY)
+	if err != nil {
+		return nil, image.Point{}, err
+	}
+	
+	var tiles []SplitTile
+	for row := 0; row < rows; row++ {
+		for col := 0; col < columns; col++ {
+			x := col * (tileWidth - overlapX)
+			y := row * (tileHeight - overlapY)
+			tiles = append(tiles, SplitTile{
+				Row:     row + 1,
+				Column:  col + 1,
+				Rows:    rows,
+				Columns: columns,
+				Bounds:  image.Rect(x, y, min(x+tileWidth, width), min(y+tileHeight, height)),
+			})
+		}
+	}
+	return tiles, image.Pt(tileWidth, tileHeight), nil
+}
+
+// Split cuts an image into tiles and writes each one in the given format ("" = the
+// input format)
+func (t *Transformer) Split(input io.Reader, format ImageFormat, options SplitOptions, write SplitWriter) ([]SplitTile, error) {
+	data, err := t.readImage(input)
+	if err != nil {
+		return nil, err
+	}
+	resolution := options.Resolution
+	if resolution.IsZero() {
+		resolution = imageResolution(data)
+	}
+	img, inputFormat, err := decodeImage(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load image: %w", err)
+	}
+	if format == "" {
+		format = inputFormat
+	}
+	if err := ValidateOutputFormat(format); err != nil {
+		return nil, err
+	}
+	
+	bounds := img.Bounds()
+	tiles, size, err := SplitGrid(bounds.Dx(), bounds.Dy(), options, resolution)
+	if err != nil {
+		return nil, err
+	}
+	t.logf("splitting %dx%d into %dx%d tiles of %dx%d", bounds.Dx(), bounds.Dy(), tiles[0].Columns, tiles[0].Rows, size.X, size.Y)
+	
+	var saveResolution Resolution
+	if options.KeepResolution && (format == FormatJPEG || format == FormatPNG) {
+		saveResolution = resolution
+	}
+	background := options.Background
+	if background == nil {
+		background = DefaultBackground
+	}
+	
+	for _, tile := range tiles {
+		piece := image.NewNRGBA(image.Rectangle{Max: tile.Bounds.Size()})
+		if options.Pad {
+			piece = image.NewNRGBA(image.Rectangle{Max: size})
+			draw.Draw(piece, piece.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+		}
+		draw.Draw(piece, image.Rectangle{Max: tile.Bounds.Size()}, img, bounds.Min.Add(tile.Bounds.Min), draw.Src)
+		
+		var buf bytes.Buffer
+		if err := t.saveWithResolution(&buf, piece, format, SaveOptions{Quality: options.Quality}, saveResolution); err != nil {
+			return nil, fmt.Errorf("failed to encode tile r%dc%d: %w", tile.Row, tile.Column, err)
+		}
+		if err := write(tile, buf.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	return tiles, nil
+}
\ No newline at end of file
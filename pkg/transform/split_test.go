@@ -0,0 +1,138 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestParseGrid(t *testing.T) {
+	if columns, rows, err := ParseGrid("3x1"); err != nil || columns != 3 || rows != 1 {
+		t.Errorf("ParseGrid(3x1) = %d, %d, %v", columns, rows, err)
+	}
+	for _, s := range []string{"3", "0x2", "ax1", "2x-1"} {
+		if _, _, err := ParseGrid(s); err == nil {
+			t.Errorf("ParseGrid(%q) accepted an invalid grid", s)
+		}
+	}
+}
+
+func TestSplitGrid(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     image.Point
+		options    SplitOptions
+		resolution Resolution
+		wantSize   image.Point
+		wantGrid   image.Point
+		wantLast   image.Rectangle
+	}{
+		{
+			name:     "carousel",
+			source:   image.Pt(3240, 1080),
+			options:  SplitOptions{Columns: 3, Rows: 1},
+			wantSize: image.Pt(1080, 1080),
+			wantGrid: image.Pt(3, 1),
+			wantLast: image.Rect(2160, 0, 3240, 1080),
+		},
+		{
+			name:     "grid with overlap",
+			source:   image.Pt(100, 50),
+			options:  SplitOptions{Columns: 2, Rows: 1, Overlap: DimensionValue{Value: 10}},
+			wantSize: image.Pt(55, 50),
+			wantGrid: image.Pt(2, 1),
+			wantLast: image.Rect(45, 0, 100, 50),
+		},
+		{
+			name:     "tile size",
+			source:   image.Pt(5000, 4000),
+			options:  SplitOptions{TileWidth: DimensionValue{Value: 2480}, TileHeight: DimensionValue{Value: 3508}},
+			wantSize: image.Pt(2480, 3508),
+			wantGrid: image.Pt(3, 2),
+			wantLast: image.Rect(4960, 3508, 5000, 4000),
+		},
+		{
+			// 10mm at 254 DPI is 100 pixels and 5mm overlap is 50
+			name:       "physical tile size",
+			source:     image.Pt(250, 100),
+			options:    SplitOptions{TileWidth: DimensionValue{Length: 10, Unit: LengthMillimeter}, TileHeight: DimensionValue{Length: 10, Unit: LengthMillimeter}, Overlap: DimensionValue{Length: 5, Unit: LengthMillimeter}},
+			resolution: SquareDPI(254),
+			wantSize:   image.Pt(100, 100),
+			wantGrid:   image.Pt(4, 1),
+			wantLast:   image.Rect(150, 0, 250, 100),
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tiles, size, err := SplitGrid(tt.source.X, tt.source.Y, tt.options, tt.resolution)
+			if err != nil {
+				t.Fatalf("SplitGrid() error = %v", err)
+			}
+			last := tiles[len(tiles)-1]
+			if size != tt.wantSize || image.Pt(last.Columns, last.Rows) != tt.wantGrid || last.Bounds != tt.wantLast {
+				t.Errorf("SplitGrid() = size %v, grid %dx%d, last %v; want %v, %v, %v",
+					size, last.Columns, last.Rows, last.Bounds, tt.wantSize, tt.wantGrid, tt.wantLast)
+			}
+			if last.Row != last.Rows || last.Column != last.Columns {
+				t.Errorf("last tile is r%dc%d of %dx%d", last.Row, last.Column, last.Columns, last.Rows)
+			}
+		})
+	}
+	
+	invalid := []SplitOptions{
+		{},
+		{Columns: 2, Rows: 1, TileWidth: DimensionValue{Value: 10}, TileHeight: DimensionValue{Value: 10}},
+		{TileWidth: DimensionValue{Value: 10}},
+		{TileWidth: DimensionValue{Value: 10}, TileHeight: DimensionValue{Value: 10}, Overlap: DimensionValue{Value: 10}},
+		{Columns: 200, Rows: 1},
+	}
+	for _, options := range invalid {
+		if _, _, err := SplitGrid(100, 100, options, Resolution{}); err == nil {
+			t.Errorf("SplitGrid(%+v) accepted invalid options", options)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	var input bytes.Buffer
+	if err := png.Encode(&input, solidImage(250, 100, color.NRGBA{R: 0xFF, A: 0xFF})); err != nil {
+		t.Fatal(err)
+	}
+	
+	sizes := make(map[image.Point]image.Point)
+	write := func(tile SplitTile, data []byte) error {
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		sizes[image.Pt(tile.Column, tile.Row)] = img.Bounds().Size()
+		// Padding is filled with the background
+		if tile.Column == 3 {
+			if c := color.NRGBAModel.Convert(img.At(99, 0)).(color.NRGBA); c != (color.NRGBA{0, 0, 0xFF, 0xFF}) {
+				t.Errorf("padding = %v, want blue", c)
+			}
+		}
+		return nil
+	}
+	options := SplitOptions{
+		TileWidth:  DimensionValue{Value: 100},
+		TileHeight: DimensionValue{Value: 100},
+		Pad:        true,
+		Background: color.NRGBA{B: 0xFF, A: 0xFF},
+	}
+	tiles, err := NewTransformer().Split(&input, "", options, write)
+	if err != nil {
+		t.Fatalf("Split() error = %v", err)
+	}
+	if len(tiles) != 3 || len(sizes) != 3 {
+		t.Fatalf("Split() wrote %d tiles, want 3", len(sizes))
+	}
+	for pos, size := range sizes {
+		if size != image.Pt(100, 100) {
+			t.Errorf("tile %v size = %v, want 100x100", pos, size)
+		}
+	}
+}
\ No newline at end of file
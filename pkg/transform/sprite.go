@@ -0,0 +1,150 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"strings"
+	
+	"github.com/disintegration/imaging"
+)
+
+// SpriteOptions contains options for packing images into a sprite sheet
+type SpriteOptions struct {
+	Packing PackingMethod
+	Padding int  // Transparent pixels between sprites, at 1x
+	Retina  bool // Treat the images as @2x and also make a half size sheet
+}
+
+// Sprite is the position of one image on a sprite sheet, in 1x pixels
+type Sprite struct {
+	Name   string `json:"-"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// SpriteSheet is a packed sprite image with its coordinate map. With Retina, Image
+// is the 1x sheet and Retina the @2x sheet with doubled coordinates.
+type SpriteSheet struct {
+	Width, Height int
+	Sprites       []Sprite
+	Image         *image.NRGBA
+	Retina        *image.NRGBA
+}
+
+// NewSpriteSheet packs named images into a sprite sheet
+func NewSpriteSheet(names []string, images []image.Image, options SpriteOptions) (*SpriteSheet, error) {
+	if len(names) != len(images) {
+		return nil, fmt.Errorf("%d names for %d images", len(names), len(images))
+	}
+	if options.Padding < 0 {
+		return nil, fmt.Errorf("padding cannot be negative: %d", options.Padding)
+	}
+	// Names must stay distinct as CSS classes too, e.g. "Arrow" and "arrow" would not
+	seen := make(map[string]string)
+	for _, name := range names {
+		ident := cssIdent(name)
+		if other, ok := seen[ident]; ok {
+			if other == name {
+				return nil, fmt.Errorf("duplicate sprite name: %s", name)
+			}
+			return nil, fmt.Errorf("sprite names %q and %q have the same CSS class: %s", other, name, ident)
+		}
+		seen[ident] = name
+	}
+	
+	// @2x images are packed with even sizes and padding, so every position halves exactly
+	scale, padding := 1, options.Padding
+	if options.Retina {
+		scale, padding = 2, 2*options.Padding
+	}
+	sizes := make([]image.Point, len(images))
+	for i, img := range images {
+		size := img.Bounds().Size()
+		sizes[i] = image.Pt((size.X+scale-1)/scale*scale, (size.Y+scale-1)/scale*scale)
+	}
+	positions, size, err := PackRects(sizes, padding, options.Packing)
+	if err != nil {
+		return nil, err
+	}
+	
+	sheet := &SpriteSheet{Width: size.X / scale, Height: size.Y / scale}
+	sheet.Image = image.NewNRGBA(image.Rect(0, 0, sheet.Width, sheet.Height))
+	if options.Retina {
+		sheet.Retina = image.NewNRGBA(image.Rectangle{Max: size})
+	}
+	for i, img := range images {
+		sprite := Sprite{
+			Name:   names[i],
+			X:      positions[i].X / scale,
+			Y:      positions[i].Y / scale,
+			Width:  sizes[i].X / scale,
+			Height: sizes[i].Y / scale,
+		}
+		sheet.Sprites = append(sheet.Sprites, sprite)
+		
+		full := img
+		if options.Retina {
+			draw.Draw(sheet.Retina, img.Bounds().Sub(img.Bounds().Min).Add(positions[i]), img, img.Bounds().Min, draw.Src)
+			full = imaging.Resize(img, sprite.Width, sprite.Height, imaging.Lanczos)
+		}
+		target := image.Rect(sprite.X, sprite.Y, sprite.X+full.Bounds().Dx(), sprite.Y+full.Bounds().Dy())
+		draw.Draw(sheet.Image, target, full, full.Bounds().Min, draw.Src)
+	}
+	return sheet, nil
+}
+
+// CSS returns rules that show each sprite as an element with class prefix-name.
+// The URLs are written as given; retinaURL is used when Retina is set.
+func (s *SpriteSheet) CSS(imageURL, retinaURL, prefix string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, ".%s {\n  display: inline-block;\n  background-image: url(%q);\n  background-repeat: no-repeat;\n}\n", prefix, imageURL)
+	for _, sprite := range s.Sprites {
+		fmt.Fprintf(&b, "\n.%s-%s {\n  width: %dpx;\n  height: %dpx;\n  background-position: %s %s;\n}\n",
+			prefix, cssIdent(sprite.Name), sprite.Width, sprite.Height, cssOffset(sprite.X), cssOffset(sprite.Y))
+	}
+	if s.Retina != nil {
+		fmt.Fprintf(&b, "\n@media (-webkit-min-device-pixel-ratio: 2), (min-resolution: 192dpi) {\n  .%s {\n    background-image: url(%q);\n    background-size: %dpx %dpx;\n  }\n}\n",
+			prefix, retinaURL, s.Width, s.Height)
+	}
+	return b.String()
+}
+
+// cssOffset formats a background position that moves a sprite to the origin
+func cssOffset(v int) string {
+	if v == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("-%dpx", v)
+}
+
+// cssIdent turns a file name into a class name, replacing unsafe characters with '-'
+func cssIdent(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(name))
+}
+
+// JSON returns the coordinate map as {"image", "width", "height", "sprites": {name: {x, y, width, height}}}
+func (s *SpriteSheet) JSON(imageURL, retinaURL string) ([]byte, error) {
+	manifest := struct {
+		Image   string            `json:"image"`
+		Retina  string            `json:"retina,omitempty"`
+		Width   int               `json:"width"`
+		Height  int               `json:"height"`
+		Sprites map[string]Sprite `json:"sprites"`
+	}{Image: imageURL, Width: s.Width, Height: s.Height, Sprites: make(map[string]Sprite)}
+	if s.Retina != nil {
+		manifest.Retina = retinaURL
+	}
+	for _, sprite := range s.Sprites {
+		manifest.Sprites[sprite.Name] = sprite
+	}
+	return json.MarshalIndent(manifest, "", "  ")
+}
\ No newline at end of file
@@ -0,0 +1,100 @@
+package transform
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+// solidImage returns an opaque image of one color
+func solidImage(width, height int, c color.Color) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestNewSpriteSheet(t *testing.T) {
+	red, blue := color.NRGBA{255, 0, 0, 255}, color.NRGBA{0, 0, 255, 255}
+	sheet, err := NewSpriteSheet([]string{"red", "blue"}, []image.Image{solidImage(16, 16, red), solidImage(32, 8, blue)}, SpriteOptions{Padding: 2})
+	if err != nil {
+		t.Fatalf("NewSpriteSheet() error = %v", err)
+	}
+	if sheet.Retina != nil || sheet.Image.Bounds().Size() != image.Pt(sheet.Width, sheet.Height) {
+		t.Fatalf("sheet is %v, want %dx%d without a retina image", sheet.Image.Bounds(), sheet.Width, sheet.Height)
+	}
+	for i, want := range []color.NRGBA{red, blue} {
+		sprite := sheet.Sprites[i]
+		if got := sheet.Image.NRGBAAt(sprite.X+sprite.Width-1, sprite.Y+sprite.Height-1); got != want {
+			t.Errorf("sprite %s corner = %v, want %v", sprite.Name, got, want)
+		}
+	}
+	
+	for _, names := range [][]string{{"a", "a"}, {"Arrow", "arrow"}, {"a b", "a-b"}} {
+		if _, err := NewSpriteSheet(names, []image.Image{solidImage(1, 1, red), solidImage(1, 1, red)}, SpriteOptions{}); err == nil {
+			t.Errorf("NewSpriteSheet(%q) should reject names with the same CSS class", names)
+		}
+	}
+}
+
+func TestNewSpriteSheetRetina(t *testing.T) {
+	images := []image.Image{solidImage(32, 32, color.Black), solidImage(25, 13, color.White), solidImage(40, 20, color.Black)}
+	sheet, err := NewSpriteSheet([]string{"a", "b", "c"}, images, SpriteOptions{Padding: 1, Retina: true})
+	if err != nil {
+		t.Fatalf("NewSpriteSheet() error = %v", err)
+	}
+	if sheet.Retina.Bounds().Size() != image.Pt(2*sheet.Width, 2*sheet.Height) {
+		t.Errorf("retina sheet %v is not twice the %dx%d sheet", sheet.Retina.Bounds(), sheet.Width, sheet.Height)
+	}
+	// Odd @2x sizes round up, so the 25x13 image is a 13x7 sprite
+	if b := sheet.Sprites[1]; b.Width != 13 || b.Height != 7 {
+		t.Errorf("sprite b is %dx%d, want 13x7", b.Width, b.Height)
+	}
+	for _, sprite := range sheet.Sprites {
+		if got := sheet.Retina.NRGBAAt(2*sprite.X, 2*sprite.Y); got.A != 0xFF {
+			t.Errorf("retina sprite %s is not at twice its 1x position", sprite.Name)
+		}
+	}
+}
+
+func TestSpriteSheetMaps(t *testing.T) {
+	sheet, err := NewSpriteSheet([]string{"Arrow Left", "home"}, []image.Image{solidImage(10, 10, color.Black), solidImage(20, 10, color.Black)}, SpriteOptions{Retina: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	css := sheet.CSS("sprite.png", "sprite@2x.png", "icon")
+	for _, want := range []string{
+		`.icon {`,
+		`background-image: url("sprite.png");`,
+		`.icon-arrow-left {`,
+		`width: 10px;`,
+		`background-position: 0 0;`,
+		`background-image: url("sprite@2x.png");`,
+		`background-size: 15px 5px;`,
+	} {
+		if !strings.Contains(css, want) {
+			t.Errorf("CSS does not contain %q:\n%s", want, css)
+		}
+	}
+	
+	data, err := sheet.JSON("sprite.png", "sprite@2x.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest struct {
+		Image   string
+		Retina  string
+		Sprites map[string]Sprite
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Retina != "sprite@2x.png" || manifest.Sprites["home"].Width != 10 {
+		t.Errorf("JSON = %s", data)
+	}
+}
\ No newline at end of file
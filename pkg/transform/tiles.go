@@ -0,0 +1,257 @@
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	
+	"github.com/disintegration/imaging"
+)
+
+// TileLayout selects the directory layout and descriptor of a tile pyramid
+type TileLayout string
+
+const (
+	TileDZI     TileLayout = "dzi"     // Deep Zoom: name.dzi and name_files/level/col_row.jpg, levels down to 1x1
+	TileZoomify TileLayout = "zoomify" // ImageProperties.xml and TileGroupN/z-x-y.jpg
+	TileXYZ     TileLayout = "xyz"     // z/x/y.jpg with full size edge tiles, zoom 0 fits in one tile
+)
+
+// ParseTileLayout parses a tile layout name
+func ParseTileLayout(s string) (TileLayout, error) {
+	switch TileLayout(strings.ToLower(strings.TrimSpace(s))) {
+	case TileDZI, "":
+		return TileDZI, nil
+	case TileZoomify:
+		return TileZoomify, nil
+	case TileXYZ:
+		return TileXYZ, nil
+	default:
+		return "", fmt.Errorf("unsupported tile layout: %s (use dzi, zoomify or xyz)", s)
+	}
+}
+
+// TileOptions contains options for tile pyramid export
+type TileOptions struct {
+	Layout   TileLayout
+	TileSize int
+	Overlap  int // Pixels shared with neighbouring tiles (DZI only)
+	Format   ImageFormat
+	Quality  int
+	Workers  int // Tiles encoded in parallel (0 = number of CPUs)
+}
+
+// TileWriter stores one file of a tile pyramid under a slash separated path.
+// It is called from several goroutines at once.
+type TileWriter func(name string, data []byte) error
+
+// TilePyramid describes an exported pyramid
+type TilePyramid struct {
+	Width, Height int
+	Levels        int
+	Tiles         int
+}
+
+// ValidateTileOptions checks tile options
+func ValidateTileOptions(options TileOptions) error {
+	if _, err := ParseTileLayout(string(options.Layout)); err != nil {
+		return err
+	}
+	if options.TileSize < 16 || options.TileSize > 4096 {
+		return fmt.Errorf("tile size must be between 16 and 4096: %d", options.TileSize)
+	}
+	if options.Overlap < 0 || options.Overlap*2 >= options.TileSize {
+		return fmt.Errorf("invalid overlap %d for %d pixel tiles", options.Overlap, options.TileSize)
+	}
+	if options.Overlap > 0 && options.Layout != TileDZI {
+		return fmt.Errorf("overlap is only supported by the dzi layout")
+	}
+	if options.Layout == TileZoomify && options.Format != FormatJPEG {
+		return fmt.Errorf("zoomify tiles must be JPEG")
+	}
+	return ValidateOutputFormat(options.Format)
+}
+
+// tileLevel is one level of a pyramid, numbered as in its layout
+type tileLevel struct {
+	number        int
+	width, height int
+	columns, rows int
+}
+
+// tileLevels returns the levels from full size down by successive halving. DZI goes
+// down to 1x1 and the other layouts stop at the first level that fits in one tile.
+func tileLevels(width, height int, options TileOptions) []tileLevel {
+	var levels []tileLevel
+	for {
+		levels = append(levels, tileLevel{
+			width:   width,
+			height:  height,
+			columns: (width + options.TileSize - 1) / options.TileSize,
+			rows:    (height + options.TileSize - 1) / options.TileSize,
+		})
+		if options.Layout == TileDZI && width == 1 && height == 1 ||
+			options.Layout != TileDZI && width <= options.TileSize && height <= options.TileSize {
+			break
+		}
+		width, height = (width+1)/2, (height+1)/2
+	}
+	for i := range levels {
+		levels[i].number = len(levels) - 1 - i
+	}
+	return levels
+}
+
+// Tiles exports an image as a zoomable tile pyramid named name. Only the current level
+// and the next smaller one are held in memory, and each level's tiles are encoded in
+// parallel.
+func (t *Transformer) Tiles(input io.Reader, name string, options TileOptions, write TileWriter) (*TilePyramid, error) {
+	if err := ValidateTileOptions(options); err != nil {
+		return nil, err
+	}
+	level, err := t.loadTileSource(input)
+	if err != nil {
+		return nil, err
+	}
+	
+	bounds := level.Bounds()
+	levels := tileLevels(bounds.Dx(), bounds.Dy(), options)
+	pyramid := &TilePyramid{Width: bounds.Dx(), Height: bounds.Dy(), Levels: len(levels)}
+	
+	// Zoomify numbers tiles from the smallest level up and puts 256 in each group
+	groupStart := make([]int, len(levels))
+	for i, count := len(levels)-1, 0; i >= 0; i-- {
+		groupStart[i] = count
+		count += levels[i].columns * levels[i].rows
+	}
+	
+	for i, info := range levels {
+		if i > 0 {
+			level = imaging.Resize(level, info.width, info.height, imaging.Box)
+		}
+		err := t.writeTileLevel(level, name, info, groupStart[i], options, write)
+		if err != nil {
+			return nil, err
+		}
+		pyramid.Tiles += info.columns * info.rows
+		t.logf("level %d: %dx%d, %d tiles", info.number, info.width, info.height, info.columns*info.rows)
+	}
+	
+	// Descriptor
+	ext := strings.TrimPrefix(options.Format.Extension(), ".")
+	switch options.Layout {
+	case TileDZI:
+		dzi := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<Image xmlns="http://schemas.microsoft.com/deepzoom/2008" Format="%s" Overlap="%d" TileSize="%d">
+  <Size Width="%d" Height="%d"/>
+</Image>
+`, ext, options.Overlap, options.TileSize, pyramid.Width, pyramid.Height)
+		err = write(name+".dzi", []byte(dzi))
+	case TileZoomify:
+		properties := fmt.Sprintf(`<IMAGE_PROPERTIES WIDTH="%d" HEIGHT="%d" NUMTILES="%d" NUMIMAGES="1" VERSION="1.8" TILESIZE="%d" />
+`, pyramid.Width, pyramid.Height, pyramid.Tiles, options.TileSize)
+		err = write("ImageProperties.xml", []byte(properties))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pyramid, nil
+}
+
+// loadTileSource decodes the full size level, so the encoded data can be freed early
+func (t *Transformer) loadTileSource(input io.Reader) (*image.NRGBA, error) {
+	data, err := t.readImage(input)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := decodeImage(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	if nrgba, ok := img.(*image.NRGBA); ok && nrgba.Bounds().Min == (image.Point{}) {
+		return nrgba, nil
+	}
+	return imaging.Clone(img), nil
+}
+
+// writeTileLevel encodes and writes the tiles of one level in parallel
+func (t *Transformer) writeTileLevel(level *image.NRGBA, name string, info tileLevel, groupStart int, options TileOptions, write TileWriter) error {
+	workers := options.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	
+	jobs := make(chan image.Point)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for tile := range jobs {
+				err := t.writeTile(level, name, info, tile, groupStart, options, write)
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+tiles:
+	for row := 0; row < info.rows; row++ {
+		for col := 0; col < info.columns; col++ {
+			mu.Lock()
+			failed := firstErr != nil
+			mu.Unlock()
+			if failed {
+				break tiles
+			}
+			jobs <- image.Pt(col, row)
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return firstErr
+}
+
+// writeTile crops, encodes and writes one tile
+func (t *Transformer) writeTile(level *image.NRGBA, name string, info tileLevel, tile image.Point, groupStart int, options TileOptions, write TileWriter) error {
+	size := options.TileSize
+	rect := image.Rect(tile.X*size, tile.Y*size, (tile.X+1)*size, (tile.Y+1)*size)
+	rect = image.Rect(rect.Min.X-options.Overlap, rect.Min.Y-options.Overlap, rect.Max.X+options.Overlap, rect.Max.Y+options.Overlap)
+	var img image.Image = level.SubImage(rect.Intersect(level.Bounds()))
+	
+	var path string
+	ext := options.Format.Extension()
+	switch options.Layout {
+	case TileDZI:
+		path = fmt.Sprintf("%s_files/%d/%d_%d%s", name, info.number, tile.X, tile.Y, ext)
+	case TileZoomify:
+		group := (groupStart + tile.Y*info.columns + tile.X) / 256
+		path = fmt.Sprintf("TileGroup%d/%d-%d-%d%s", group, info.number, tile.X, tile.Y, ext)
+	case TileXYZ:
+		// Map viewers stretch every tile to the full size, so edge tiles are padded
+		if img.Bounds().Size() != image.Pt(size, size) {
+			padded := image.NewNRGBA(image.Rect(0, 0, size, size))
+			draw.Draw(padded, img.Bounds().Sub(img.Bounds().Min), img, img.Bounds().Min, draw.Src)
+			img = padded
+		}
+		path = fmt.Sprintf("%d/%d/%d%s", info.number, tile.X, tile.Y, ext)
+	}
+	
+	var buf bytes.Buffer
+	if err := SaveImageWithOptions(&buf, img, options.Format, SaveOptions{Quality: options.Quality, Background: t.background}); err != nil {
+		return fmt.Errorf("failed to encode tile %s: %w", path, err)
+	}
+	return write(path, buf.Bytes())
+}
\ No newline at end of file
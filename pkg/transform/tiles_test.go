@@ -0,0 +1,137 @@
+package transform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// collectTiles returns a TileWriter that keeps the files in a map
+func collectTiles() (map[string][]byte, TileWriter) {
+	files := make(map[string][]byte)
+	var mu sync.Mutex
+	return files, func(name string, data []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		files[name] = data
+		return nil
+	}
+}
+
+// testTileSource encodes a 600x300 PNG
+func testTileSource(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, solidImage(600, 300, color.NRGBA{R: 0x40, G: 0x80, B: 0xC0, A: 0xFF})); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestTileLevels(t *testing.T) {
+	levels := tileLevels(600, 300, TileOptions{Layout: TileDZI, TileSize: 256})
+	// ceil(log2(600)) = 10, so levels 10 (600x300) down to 0 (1x1)
+	if len(levels) != 11 {
+		t.Fatalf("DZI levels = %d, want 11", len(levels))
+	}
+	if first := levels[0]; first.number != 10 || first.columns != 3 || first.rows != 2 {
+		t.Errorf("DZI level 10 = %+v", first)
+	}
+	if second := levels[1]; second.width != 300 || second.height != 150 {
+		t.Errorf("DZI level 9 = %dx%d, want 300x150", second.width, second.height)
+	}
+	if last := levels[10]; last.width != 1 || last.height != 1 || last.number != 0 {
+		t.Errorf("DZI level 0 = %+v", last)
+	}
+	
+	levels = tileLevels(600, 300, TileOptions{Layout: TileXYZ, TileSize: 256})
+	if len(levels) != 3 || levels[2].width != 150 || levels[2].number != 0 {
+		t.Errorf("XYZ levels = %+v", levels)
+	}
+}
+
+func TestTilesDZI(t *testing.T) {
+	files, write := collectTiles()
+	options := TileOptions{Layout: TileDZI, TileSize: 256, Overlap: 1, Format: FormatPNG, Workers: 3}
+	pyramid, err := NewTransformer().Tiles(testTileSource(t), "poster", options, write)
+	if err != nil {
+		t.Fatalf("Tiles() error = %v", err)
+	}
+	if pyramid.Levels != 11 || pyramid.Tiles != len(files)-1 {
+		t.Errorf("pyramid = %+v with %d files", pyramid, len(files))
+	}
+	dzi := string(files["poster.dzi"])
+	for _, want := range []string{`Format="png"`, `Overlap="1"`, `TileSize="256"`, `Width="600" Height="300"`} {
+		if !strings.Contains(dzi, want) {
+			t.Errorf("poster.dzi missing %s:\n%s", want, dzi)
+		}
+	}
+	
+	// Inner tiles have overlap on both sides, edge tiles only inside
+	sizes := map[string]image.Point{
+		"poster_files/10/0_0.png": image.Pt(257, 257),
+		"poster_files/10/1_0.png": image.Pt(258, 257),
+		"poster_files/10/2_1.png": image.Pt(89, 45),
+		"poster_files/0/0_0.png":  image.Pt(1, 1),
+	}
+	for name, want := range sizes {
+		config, err := png.DecodeConfig(bytes.NewReader(files[name]))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if got := image.Pt(config.Width, config.Height); got != want {
+			t.Errorf("%s size = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestTilesXYZAndZoomify(t *testing.T) {
+	files, write := collectTiles()
+	_, err := NewTransformer().Tiles(testTileSource(t), "poster", TileOptions{Layout: TileXYZ, TileSize: 256, Format: FormatPNG}, write)
+	if err != nil {
+		t.Fatalf("Tiles() error = %v", err)
+	}
+	// Edge tiles are padded to the full tile size
+	config, err := png.DecodeConfig(bytes.NewReader(files["2/2/1.png"]))
+	if err != nil || config.Width != 256 || config.Height != 256 {
+		t.Errorf("2/2/1.png = %+v, %v, want 256x256", config, err)
+	}
+	if _, ok := files["0/0/0.png"]; !ok {
+		t.Error("missing zoom 0 tile")
+	}
+	
+	files, write = collectTiles()
+	pyramid, err := NewTransformer().Tiles(testTileSource(t), "poster", TileOptions{Layout: TileZoomify, TileSize: 256, Format: FormatJPEG}, write)
+	if err != nil {
+		t.Fatalf("Tiles() error = %v", err)
+	}
+	if pyramid.Tiles != 1+2+6 {
+		t.Errorf("zoomify tiles = %d, want 9", pyramid.Tiles)
+	}
+	if !strings.Contains(string(files["ImageProperties.xml"]), `NUMTILES="9"`) {
+		t.Errorf("ImageProperties.xml = %s", files["ImageProperties.xml"])
+	}
+	if _, ok := files["TileGroup0/2-2-1.jpg"]; !ok {
+		t.Error("missing TileGroup0/2-2-1.jpg")
+	}
+}
+
+func TestValidateTileOptions(t *testing.T) {
+	tests := []TileOptions{
+		{Layout: TileDZI, TileSize: 8, Format: FormatJPEG},
+		{Layout: TileDZI, TileSize: 256, Overlap: 128, Format: FormatJPEG},
+		{Layout: TileXYZ, TileSize: 256, Overlap: 1, Format: FormatJPEG},
+		{Layout: TileZoomify, TileSize: 256, Format: FormatPNG},
+		{Layout: TileDZI, TileSize: 256, Format: FormatTIFF},
+	}
+	for _, options := range tests {
+		if err := ValidateTileOptions(options); err == nil {
+			t.Errorf("ValidateTileOptions(%+v) accepted invalid options", options)
+		}
+	}
+}
\ No newline at end of file
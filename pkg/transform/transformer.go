@@ -3,49 +3,109 @@ package transform
 import (
 	"bytes"
 	"fmt"
+	"image"
 	"io"
 )
 
 // Resize implements image resizing functionality
 func (t *Transformer) Resize(input io.Reader, output io.Writer, options ResizeOptions) error {
-	// Load the image
-	img, format, err := LoadImage(input)
+	// Load and resize the image, decoding at a reduced scale when it is much larger
+	// than the target
+	resizedImg, format, err := t.loadAndResize(input, options)
 	if err != nil {
-		return fmt.Errorf("failed to load image: %w", err)
+		return err
 	}
 	
-	// Get original dimensions
-	info := GetImageInfo(img, format)
+	// Save the resized image
+	quality := options.Quality
+	if quality <= 0 {
+		quality = 95 // Default high quality
+	}
 	
-	// Calculate target dimensions
-	targetWidth, targetHeight := CalculateDimensions(info.Width, info.Height, options)
+	return t.save(output, resizedImg, format, SaveOptions{Quality: quality})
+}
+
+// Convert resizes, changes the format and sets the DPI of an image in a single
+// decode/encode pass. Options that are not set leave that aspect unchanged.
+func (t *Transformer) Convert(input io.Reader, output io.Writer, options ConvertOptions) error {
+	var img image.Image
+	var format ImageFormat
+	quality := options.Quality
+	resolution := options.Resolution
+	if resolution.IsZero() && options.DPI > 0 {
+		resolution = SquareDPI(options.DPI)
+	}
 	
-	// Resize the image
-	resizedImg, err := resizeImage(img, targetWidth, targetHeight, options.Mode)
+	data, err := t.readImage(input)
 	if err != nil {
-		return fmt.Errorf("failed to resize image: %w", err)
+		return err
 	}
 	
-	// Save the resized image
-	quality := options.Quality
+	// Load and resize if requested
+	var sourceResolution Resolution
+	if options.Resize != nil {
+		resize := *options.Resize
+		if resize.HasPhysicalSize() && resize.Resolution.IsZero() {
+			// Size the image for the target resolution, or the image's own
+			resize.Resolution = resolution
+			if resolution.IsZero() {
+				sourceResolution = imageResolution(data)
+				resize.Resolution = sourceResolution
+			}
+		}
+		img, format, err = t.resizeData(data, resize)
+		if err != nil {
+			return err
+		}
+		if quality <= 0 {
+			quality = options.Resize.Quality
+		}
+	} else {
+		img, format, err = decodeImage(data)
+		if err != nil {
+			return fmt.Errorf("failed to load image: %w", err)
+		}
+	}
 	if quality <= 0 {
 		quality = 95 // Default high quality
 	}
 	
-	return SaveImage(output, resizedImg, format, quality)
+	// Determine the output format
+	outputFormat := format
+	if options.Format != "" {
+		outputFormat = options.Format
+	}
+	if err := ValidateOutputFormat(outputFormat); err != nil {
+		return err
+	}
+	if outputFormat != format {
+		t.logf("converting %s to %s", format, outputFormat)
+	}
+	
+	// Keep the resolution used for a physical size so the print size holds
+	if resolution.IsZero() && !sourceResolution.IsZero() && (outputFormat == FormatJPEG || outputFormat == FormatPNG) {
+		resolution = sourceResolution
+	}
+	
+	saveOptions := SaveOptions{
+		Quality: quality,
+		PNG:     options.PNG,
+		JPEG:    options.JPEG,
+	}
+	return t.saveWithResolution(output, img, outputFormat, saveOptions, resolution)
 }
 
 // SetDPI implements DPI metadata setting functionality
 func (t *Transformer) SetDPI(input io.Reader, output io.Writer, dpi int) error {
 	// First detect the format
-	img, format, err := LoadImage(input)
+	img, format, err := t.loadImage(input)
 	if err != nil {
 		return fmt.Errorf("failed to load image: %w", err)
 	}
 	
 	// Save the image back to get raw data
 	buf := &bytes.Buffer{}
-	if err := SaveImage(buf, img, format, 95); err != nil {
+	if err := t.save(buf, img, format, SaveOptions{Quality: 95}); err != nil {
 		return fmt.Errorf("failed to encode image: %w", err)
 	}
 	
@@ -56,8 +116,21 @@ func (t *Transformer) SetDPI(input io.Reader, output io.Writer, dpi int) error {
 
 // CropEdges implements edge cropping functionality
 func (t *Transformer) CropEdges(input io.Reader, output io.Writer, options EdgeCropOptions) error {
+	data, err := t.readImage(input)
+	if err != nil {
+		return err
+	}
+	
+	// JPEGs cropped on the block grid keep their original DCT coefficients
+	if isJPEGData(data) {
+		done, err := t.cropJPEGLossless(data, output, options)
+		if done || err != nil {
+			return err
+		}
+	}
+	
 	// Load the image
-	img, format, err := LoadImage(input)
+	img, format, err := decodeImage(data)
 	if err != nil {
 		return fmt.Errorf("failed to load image: %w", err)
 	}
@@ -75,5 +148,155 @@ func (t *Transformer) CropEdges(input io.Reader, output io.Writer, options EdgeC
 	}
 	
 	// Save the cropped image
-	return SaveImage(output, croppedImg, format, 95)
+	return t.save(output, croppedImg, format, SaveOptions{Quality: 95})
+}
+
+// Rotate rotates an image clockwise and optionally flips it. JPEGs that are a whole
+// number of blocks are transformed without re-encoding.
+func (t *Transformer) Rotate(input io.Reader, output io.Writer, options RotateOptions) error {
+	if err := ValidateRotateOptions(options); err != nil {
+		return err
+	}
+	data, err := t.readImage(input)
+	if err != nil {
+		return err
+	}
+	
+	if isJPEGData(data) {
+		done, err := t.rotateJPEGLossless(data, output, options)
+		if done || err != nil {
+			return err
+		}
+	}
+	
+	// Load the image
+	img, format, err := decodeImage(data)
+	if err != nil {
+		return fmt.Errorf("failed to load image: %w", err)
+	}
+	
+	return t.save(output, RotateImage(img, options), format, SaveOptions{Quality: 95})
+}
+
+// Bleed extends an image with print bleed. Physical lengths use options.Resolution or
+// the image's own, and JPEG and PNG output keep that resolution so the trim size holds.
+func (t *Transformer) Bleed(input io.Reader, output io.Writer, options BleedOptions) error {
+	if err := ValidateBleedOptions(options); err != nil {
+		return err
+	}
+	data, err := t.readImage(input)
+	if err != nil {
+		return err
+	}
+	if options.Resolution.IsZero() {
+		options.Resolution = imageResolution(data)
+	}
+	
+	// Load the image
+	img, format, err := decodeImage(data)
+	if err != nil {
+		return fmt.Errorf("failed to load image: %w", err)
+	}
+	
+	result, err := AddBleed(img, options)
+	if err != nil {
+		return fmt.Errorf("failed to add bleed: %w", err)
+	}
+	t.logf("added %s bleed at %s: %dx%d → %dx%d", options.Size, options.Resolution,
+		img.Bounds().Dx(), img.Bounds().Dy(), result.Bounds().Dx(), result.Bounds().Dy())
+	
+	var resolution Resolution
+	if format == FormatJPEG || format == FormatPNG {
+		resolution = options.Resolution
+	}
+	return t.saveWithResolution(output, result, format, SaveOptions{Quality: 95}, resolution)
+}
+
+// Sheet renders one page of an N-up sheet from up to len(layout.Cells) images and
+// saves it with the layout's resolution
+func (t *Transformer) Sheet(inputs []io.Reader, output io.Writer, format ImageFormat, layout *SheetLayout) error {
+	if len(inputs) > len(layout.Cells) {
+		return fmt.Errorf("%d images do not fit on a sheet of %d", len(inputs), len(layout.Cells))
+	}
+	if err := ValidateOutputFormat(format); err != nil {
+		return err
+	}
+	
+	page := layout.NewPage()
+	for i, input := range inputs {
+		img, _, err := t.loadImage(input)
+		if err != nil {
+			return fmt.Errorf("failed to load image %d: %w", i+1, err)
+		}
+		layout.Place(page, i, img)
+	}
+	if layout.options.CropMarks {
+		layout.DrawCropMarks(page)
+	}
+	t.logf("placed %d of %d photos on a %dx%d page at %s", len(inputs), len(layout.Cells),
+		layout.Size.X, layout.Size.Y, layout.Resolution)
+	
+	var resolution Resolution
+	if format == FormatJPEG || format == FormatPNG {
+		resolution = layout.Resolution
+	}
+	return t.saveWithResolution(output, page, format, SaveOptions{Quality: 95}, resolution)
+}
+
+// saveWithResolution encodes an image and patches the resolution metadata into the
+// encoded bytes. A zero resolution leaves the encoder's default.
+func (t *Transformer) saveWithResolution(output io.Writer, img image.Image, format ImageFormat, options SaveOptions, resolution Resolution) error {
+	if resolution.IsZero() {
+		return t.save(output, img, format, options)
+	}
+	
+	buf := &bytes.Buffer{}
+	if err := t.save(buf, img, format, options); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+	
+	var data []byte
+	var err error
+	switch format {
+	case FormatJPEG:
+		data, err = SetJPEGResolution(buf.Bytes(), resolution)
+	case FormatPNG:
+		data, err = SetPNGResolution(buf.Bytes(), resolution)
+	default:
+		return fmt.Errorf("DPI metadata is not supported for %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set DPI: %w", err)
+	}
+	
+	_, err = output.Write(data)
+	return err
+}
+
+// save encodes an image, flattening transparency onto the background when needed
+func (t *Transformer) save(output io.Writer, img image.Image, format ImageFormat, options SaveOptions) error {
+	img, flattened := FlattenIfNeeded(img, format, t.background)
+	if flattened {
+		t.logf("flattened transparency onto %s for %s output", FormatHexColor(t.background), format)
+	}
+	options.Background = t.background
+	
+	if format == FormatPNG && options.PNG.Enabled() {
+		result, err := OptimizePNG(output, img, options.PNG)
+		if err != nil {
+			return fmt.Errorf("failed to optimize PNG: %w", err)
+		}
+		t.logf("optimized PNG: %d → %d bytes (%s, %d-bit, filter %s, zlib level %d)",
+			result.BaselineSize, result.Size, result.ColorType, result.BitDepth, result.Filter, result.Level)
+		return nil
+	}
+	if format == FormatJPEG && options.JPEG.Enabled() {
+		mode := "baseline"
+		if options.JPEG.Progressive {
+			mode = "progressive"
+		}
+		t.logf("encoding %s JPEG (%s, optimized Huffman tables)", mode, options.JPEG.subsampling())
+	}
+	
+	return SaveImageWithOptions(output, img, format, options)
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package transform
 
 import (
+	"image/color"
 	"io"
 )
 
@@ -17,10 +18,75 @@ type ImageTransformer interface {
 type ResizeOptions struct {
 	Width  int     // Target width in pixels (0 = auto) - deprecated, use WidthDim
 	Height int     // Target height in pixels (0 = auto) - deprecated, use HeightDim
-	WidthDim  DimensionValue // Target width (can be pixels or multiplier)
-	HeightDim DimensionValue // Target height (can be pixels or multiplier)
+	WidthDim  DimensionValue // Target width (can be pixels, multiplier or physical length)
+	HeightDim DimensionValue // Target height (can be pixels, multiplier or physical length)
 	Mode   ResizeMode // Resize mode
 	Quality int    // JPEG quality (1-100)
+	Resolution Resolution // Resolution for physical sizes like "10cm" (zero = the image's own)
+	MaxPixels int64       // Cap on output width x height (0 = no cap)
+}
+
+// HasPhysicalSize reports whether the width or height is a physical length
+func (o ResizeOptions) HasPhysicalSize() bool {
+	return o.WidthDim.IsPhysical() || o.HeightDim.IsPhysical()
+}
+
+// resolvePhysicalSize converts physical lengths to pixels at the given resolution
+func (o ResizeOptions) resolvePhysicalSize(r Resolution) ResizeOptions {
+	x, y := r.DPI()
+	o.WidthDim = o.WidthDim.Resolve(x)
+	o.HeightDim = o.HeightDim.Resolve(y)
+	return o
+}
+
+// SaveOptions contains options for encoding an image
+type SaveOptions struct {
+	Quality    int         // JPEG quality (1-100)
+	Background color.Color // Background for flattening transparency (nil = white)
+	PNG        PNGOptions  // PNG size optimization
+	JPEG       JPEGOptions // JPEG encoder options
+}
+
+// PNGOptions contains options for PNG size optimization
+type PNGOptions struct {
+	Optimize bool // Try color types, row filters and compression levels and keep the smallest
+	Colors   int  // Quantize to at most this many colors (0 = lossless, 2-256)
+}
+
+// Enabled reports whether any PNG optimization was requested
+func (o PNGOptions) Enabled() bool {
+	return o.Optimize || o.Colors > 0
+}
+
+// ChromaSubsampling selects how much color resolution JPEG output keeps
+type ChromaSubsampling string
+
+const (
+	Subsampling444 ChromaSubsampling = "4:4:4" // Full color resolution, best for text and graphics
+	Subsampling422 ChromaSubsampling = "4:2:2" // Half horizontal color resolution
+	Subsampling420 ChromaSubsampling = "4:2:0" // Half color resolution in both directions
+)
+
+// JPEGOptions contains options for the JPEG encoder
+type JPEGOptions struct {
+	Subsampling ChromaSubsampling // Chroma subsampling (empty = 4:2:0)
+	Progressive bool              // Write progressive scans
+}
+
+// Enabled reports whether any JPEG encoder option was requested
+func (o JPEGOptions) Enabled() bool {
+	return o.Subsampling != "" || o.Progressive
+}
+
+// ConvertOptions contains options for converting an image in a single pass
+type ConvertOptions struct {
+	Resize     *ResizeOptions // Resize options (nil = keep original size)
+	Format     ImageFormat    // Output format (empty = same as input)
+	Quality    int            // JPEG quality (1-100)
+	DPI        int            // Target DPI (0 = unchanged) - deprecated, use Resolution
+	Resolution Resolution     // Target resolution (zero = unchanged)
+	PNG        PNGOptions     // PNG size optimization
+	JPEG       JPEGOptions    // JPEG encoder options
 }
 
 // ResizeMode defines how the image should be resized
@@ -49,25 +115,59 @@ type ImageFormat string
 const (
 	FormatJPEG ImageFormat = "jpeg"
 	FormatPNG  ImageFormat = "png"
+	FormatWebP ImageFormat = "webp"
+	FormatGIF  ImageFormat = "gif"
+	FormatTIFF ImageFormat = "tiff" // Read only
 )
 
 // ImageInfo contains metadata about an image
 type ImageInfo struct {
-	Width  int
-	Height int
-	Format ImageFormat
-	DPI    int
+	Width      int
+	Height     int
+	Format     ImageFormat
+	DPI        int
+	Resolution Resolution // Horizontal and vertical resolution; DPI is the horizontal DPI
 }
 
 // Transformer implements the ImageTransformer interface
 type Transformer struct {
 	// preserveMetadata indicates whether to preserve EXIF data
 	preserveMetadata bool
+	// background is used to flatten transparency for formats without alpha
+	background color.Color
+	// logger receives verbose messages about implicit processing steps
+	logger func(format string, args ...interface{})
+	// limits bounds the size of inputs before they are decoded
+	limits Limits
 }
 
 // NewTransformer creates a new image transformer
 func NewTransformer() *Transformer {
 	return &Transformer{
 		preserveMetadata: false,
+		background:       DefaultBackground,
+		limits:           DefaultLimits,
+	}
+}
+
+// SetLimits sets the resource limits enforced before decoding
+func (t *Transformer) SetLimits(limits Limits) {
+	t.limits = limits
+}
+
+// SetBackground sets the color used to flatten transparent images
+func (t *Transformer) SetBackground(c color.Color) {
+	t.background = c
+}
+
+// SetLogger sets a function that receives verbose messages
+func (t *Transformer) SetLogger(logger func(format string, args ...interface{})) {
+	t.logger = logger
+}
+
+// logf sends a message to the logger if one is set
+func (t *Transformer) logf(format string, args ...interface{}) {
+	if t.logger != nil {
+		t.logger(format, args...)
 	}
 }
\ No newline at end of file
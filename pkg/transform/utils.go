@@ -4,23 +4,40 @@ import (
 	"bytes"
 	"fmt"
 	"image"
+	"image/gif"
 	"image/jpeg"
 	"image/png"
 	"io"
+	"math"
 	
 	"github.com/disintegration/imaging"
+	_ "golang.org/x/image/tiff" // Register the TIFF decoder
+	_ "golang.org/x/image/webp" // Register the WebP decoder
 )
 
-// LoadImage loads an image from a reader and automatically corrects EXIF orientation
+// LoadImage loads an image from a reader and automatically corrects EXIF orientation.
+// Inputs exceeding DefaultLimits are rejected with ErrImageTooLarge before decoding.
 func LoadImage(r io.Reader) (image.Image, ImageFormat, error) {
-	// Read all data into buffer for format detection
-	buf := &bytes.Buffer{}
-	if _, err := io.Copy(buf, r); err != nil {
+	return LoadImageWithLimits(r, DefaultLimits)
+}
+
+// LoadImageWithLimits is like LoadImage with explicit resource limits
+func LoadImageWithLimits(r io.Reader, limits Limits) (image.Image, ImageFormat, error) {
+	// Read all data for format detection
+	data, err := limits.read(r)
+	if err != nil {
 		return nil, "", fmt.Errorf("failed to read image data: %w", err)
 	}
-	
-	// Create a new reader from buffer
-	reader := bytes.NewReader(buf.Bytes())
+	if err := limits.Check(data); err != nil {
+		return nil, "", err
+	}
+	return decodeImage(data)
+}
+
+// decodeImage decodes image data that already passed the limits check
+func decodeImage(data []byte) (image.Image, ImageFormat, error) {
+	// Create a new reader from the data
+	reader := bytes.NewReader(data)
 	
 	// Try to decode the image with EXIF orientation support
 	// The imaging library's Open function handles EXIF orientation automatically,
@@ -35,14 +52,9 @@ func LoadImage(r io.Reader) (image.Image, ImageFormat, error) {
 		}
 		
 		// Convert format string to our ImageFormat type
-		var imgFormat ImageFormat
-		switch format {
-		case "jpeg", "jpg":
-			imgFormat = FormatJPEG
-		case "png":
-			imgFormat = FormatPNG
-		default:
-			return nil, "", fmt.Errorf("unsupported image format: %s", format)
+		imgFormat, err := ParseImageFormat(format)
+		if err != nil {
+			return nil, "", err
 		}
 		
 		return standardImg, imgFormat, nil
@@ -56,14 +68,9 @@ func LoadImage(r io.Reader) (image.Image, ImageFormat, error) {
 	}
 	
 	// Convert format string to our ImageFormat type
-	var imgFormat ImageFormat
-	switch format {
-	case "jpeg", "jpg":
-		imgFormat = FormatJPEG
-	case "png":
-		imgFormat = FormatPNG
-	default:
-		return nil, "", fmt.Errorf("unsupported image format: %s", format)
+	imgFormat, err := ParseImageFormat(format)
+	if err != nil {
+		return nil, "", err
 	}
 	
 	return img, imgFormat, nil
@@ -71,17 +78,37 @@ func LoadImage(r io.Reader) (image.Image, ImageFormat, error) {
 
 // SaveImage saves an image to a writer
 func SaveImage(w io.Writer, img image.Image, format ImageFormat, quality int) error {
+	return SaveImageWithOptions(w, img, format, SaveOptions{Quality: quality})
+}
+
+// SaveImageWithOptions saves an image to a writer using the given encoding options.
+// Transparent images are flattened onto the background when the format has no alpha channel.
+// WebP output is always lossless, so options.Quality doesn't apply to it.
+func SaveImageWithOptions(w io.Writer, img image.Image, format ImageFormat, options SaveOptions) error {
+	img, _ = FlattenIfNeeded(img, format, options.Background)
+	
 	switch format {
 	case FormatJPEG:
 		opts := &jpeg.Options{
-			Quality: quality,
+			Quality: options.Quality,
 		}
-		if quality <= 0 {
+		if options.Quality <= 0 {
 			opts.Quality = 95 // Default high quality
 		}
+		if options.JPEG.Enabled() {
+			return encodeJPEG(w, img, opts.Quality, options.JPEG)
+		}
 		return jpeg.Encode(w, img, opts)
 	case FormatPNG:
+		if options.PNG.Enabled() {
+			_, err := OptimizePNG(w, img, options.PNG)
+			return err
+		}
 		return png.Encode(w, img)
+	case FormatWebP:
+		return encodeWebP(w, img)
+	case FormatGIF:
+		return gif.Encode(w, img, &gif.Options{NumColors: 256, Quantizer: MedianCutQuantizer{}})
 	default:
 		return fmt.Errorf("unsupported format: %s", format)
 	}
@@ -91,15 +118,57 @@ func SaveImage(w io.Writer, img image.Image, format ImageFormat, quality int) er
 func GetImageInfo(img image.Image, format ImageFormat) ImageInfo {
 	bounds := img.Bounds()
 	return ImageInfo{
-		Width:  bounds.Max.X - bounds.Min.X,
-		Height: bounds.Max.Y - bounds.Min.Y,
-		Format: format,
-		DPI:    96, // Default DPI
+		Width:      bounds.Max.X - bounds.Min.X,
+		Height:     bounds.Max.Y - bounds.Min.Y,
+		Format:     format,
+		DPI:        96, // Default DPI
+		Resolution: SquareDPI(96),
 	}
 }
 
-// CalculateDimensions calculates target dimensions based on resize options
+// ReadImageInfo reads the displayed size, format and resolution of an image from its
+// headers without decoding the pixels. JPEG EXIF rotations swap the width and height.
+func ReadImageInfo(r io.Reader) (ImageInfo, error) {
+	data, err := DefaultLimits.read(r)
+	if err != nil {
+		return ImageInfo{}, fmt.Errorf("failed to read image data: %w", err)
+	}
+	config, name, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ImageInfo{}, fmt.Errorf("failed to read image header: %w", err)
+	}
+	format, err := ParseImageFormat(name)
+	if err != nil {
+		return ImageInfo{}, err
+	}
+	
+	resolution := imageResolution(data)
+	dpi, _ := resolution.DPI()
+	info := ImageInfo{Width: config.Width, Height: config.Height, Format: format, DPI: int(math.Round(dpi)), Resolution: resolution}
+	if isJPEGData(data) && jpegOrientation(data) >= 5 {
+		info.Width, info.Height = info.Height, info.Width
+	}
+	return info, nil
+}
+
+// CalculateDimensions calculates target dimensions based on resize options, scaling
+// the result down proportionally when it exceeds opts.MaxPixels
 func CalculateDimensions(srcWidth, srcHeight int, opts ResizeOptions) (int, int) {
+	width, height := calculateDimensions(srcWidth, srcHeight, opts)
+	return capPixels(width, height, opts.MaxPixels)
+}
+
+// capPixels scales width and height down so that their product stays within maxPixels
+func capPixels(width, height int, maxPixels int64) (int, int) {
+	if maxPixels <= 0 || width <= 0 || height <= 0 || int64(width)*int64(height) <= maxPixels {
+		return width, height
+	}
+	scale := math.Sqrt(float64(maxPixels) / (float64(width) * float64(height)))
+	return max(int(float64(width)*scale), 1), max(int(float64(height)*scale), 1)
+}
+
+// calculateDimensions calculates target dimensions without the pixel cap
+func calculateDimensions(srcWidth, srcHeight int, opts ResizeOptions) (int, int) {
 	// Calculate actual pixel values from DimensionValue
 	var targetWidth, targetHeight int
 	
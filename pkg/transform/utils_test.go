@@ -115,6 +115,29 @@ func TestGetImageInfo(t *testing.T) {
 	}
 }
 
+func TestReadImageInfo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 100, 50))); err != nil {
+		t.Fatal(err)
+	}
+	info, err := ReadImageInfo(&buf)
+	if err != nil {
+		t.Fatalf("ReadImageInfo() error = %v", err)
+	}
+	if info.Width != 100 || info.Height != 50 || info.Format != FormatPNG {
+		t.Errorf("ReadImageInfo() = %dx%d %v, want 100x50 png", info.Width, info.Height, info.Format)
+	}
+	
+	// EXIF orientation 6 shows the image rotated, swapping the sides
+	info, err = ReadImageInfo(bytes.NewReader(losslessTestJPEG(t, 64, 32, 6)))
+	if err != nil {
+		t.Fatalf("ReadImageInfo() error = %v", err)
+	}
+	if info.Width != 32 || info.Height != 64 {
+		t.Errorf("ReadImageInfo() rotated = %dx%d, want 32x64", info.Width, info.Height)
+	}
+}
+
 func TestValidateRectangle(t *testing.T) {
 	tests := []struct {
 		name      string
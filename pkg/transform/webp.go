@@ -0,0 +1,734 @@
+package transform
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+)
+
+// This file implements a lossless WebP (VP8L) encoder.
+// The bitstream uses the subtract-green and predictor transforms followed by
+// LZ77 backward references and a single group of canonical prefix codes.
+
+const (
+	webpMaxDimension  = 16384
+	webpPredictorBits = 4 // Predictor tiles are 16x16 pixels
+	
+	webpNumLiteralCodes  = 256
+	webpNumLengthCodes   = 24
+	webpNumDistanceCodes = 40
+	webpMaxCodeLength    = 15
+	webpMaxCLCodeLength  = 7
+	
+	webpMinMatch    = 3
+	webpMaxMatch    = 4096
+	webpMaxDistance = 1<<20 - 121
+	webpHashBits    = 16
+	webpMaxChain    = 32
+	
+	// Distance codes below this value refer to the 2D neighborhood table
+	webpDistanceMapSize = 120
+)
+
+// webpCodeLengthCodeOrder is the order in which code length code lengths are stored
+var webpCodeLengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// encodeWebP writes an image as a lossless WebP file
+func encodeWebP(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 1 || height < 1 {
+		return fmt.Errorf("webp: empty image")
+	}
+	if width > webpMaxDimension || height > webpMaxDimension {
+		return fmt.Errorf("webp: image too large (%dx%d, max %d)", width, height, webpMaxDimension)
+	}
+	
+	// VP8L stores non-premultiplied ARGB
+	nrgba := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+	
+	argb := make([]uint32, width*height)
+	hasAlpha := false
+	for i := range argb {
+		p := nrgba.Pix[i*4 : i*4+4]
+		if p[3] == 0 {
+			// RGB of invisible pixels is irrelevant, zero it for better compression
+			hasAlpha = true
+			continue
+		}
+		if p[3] != 0xFF {
+			hasAlpha = true
+		}
+		argb[i] = uint32(p[3])<<24 | uint32(p[0])<<16 | uint32(p[1])<<8 | uint32(p[2])
+	}
+	
+	bw := &webpBitWriter{}
+	bw.writeBits(0x2F, 8) // VP8L signature
+	bw.writeBits(uint32(width-1), 14)
+	bw.writeBits(uint32(height-1), 14)
+	if hasAlpha {
+		bw.writeBits(1, 1)
+	} else {
+		bw.writeBits(0, 1)
+	}
+	bw.writeBits(0, 3) // Version
+	
+	// Subtract green transform
+	webpSubtractGreen(argb)
+	bw.writeBits(1, 1)
+	bw.writeBits(2, 2)
+	
+	// Predictor transform
+	modes, residuals := webpPredict(argb, width, height, webpPredictorBits)
+	bw.writeBits(1, 1)
+	bw.writeBits(0, 2)
+	bw.writeBits(webpPredictorBits-2, 3)
+	tilesX := webpTiles(width, webpPredictorBits)
+	tilesY := webpTiles(height, webpPredictorBits)
+	webpWriteImageData(bw, modes, tilesX, tilesY, false)
+	
+	// No more transforms
+	bw.writeBits(0, 1)
+	
+	webpWriteImageData(bw, residuals, width, height, true)
+	data := bw.bytes()
+	
+	// RIFF container
+	chunkSize := len(data)
+	padded := chunkSize + chunkSize&1
+	header := make([]byte, 20)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(4+8+padded))
+	copy(header[8:12], "WEBP")
+	copy(header[12:16], "VP8L")
+	binary.LittleEndian.PutUint32(header[16:20], uint32(chunkSize))
+	
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if chunkSize&1 == 1 {
+		if _, err := w.Write([]byte{0}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// webpTiles returns the number of tiles needed to cover size pixels
+func webpTiles(size, bits int) int {
+	return (size + 1<<bits - 1) >> bits
+}
+
+// webpSubtractGreen subtracts the green channel from red and blue
+func webpSubtractGreen(argb []uint32) {
+	for i, p := range argb {
+		g := (p >> 8) & 0xFF
+		r := ((p >> 16) - g) & 0xFF
+		b := (p - g) & 0xFF
+		argb[i] = p&0xFF00FF00 | r<<16 | b
+	}
+}
+
+// webpPredict chooses a predictor mode per tile and returns the mode sub-image
+// together with the residual image
+func webpPredict(argb []uint32, width, height, bits int) ([]uint32, []uint32) {
+	tilesX := webpTiles(width, bits)
+	tilesY := webpTiles(height, bits)
+	modes := make([]uint32, tilesX*tilesY)
+	residuals := make([]uint32, len(argb))
+	tileSize := 1 << bits
+	
+	for ty := 0; ty < tilesY; ty++ {
+		for tx := 0; tx < tilesX; tx++ {
+			x0, y0 := tx*tileSize, ty*tileSize
+			x1, y1 := min(x0+tileSize, width), min(y0+tileSize, height)
+			
+			// Pick the mode with the smallest total residual magnitude
+			bestMode, bestCost := 0, -1
+			for mode := 0; mode < 14; mode++ {
+				cost := 0
+				for y := y0; y < y1; y++ {
+					for x := x0; x < x1; x++ {
+						cost += webpResidualCost(argb[y*width+x], webpPredictPixel(argb, width, x, y, mode))
+					}
+				}
+				if bestCost < 0 || cost < bestCost {
+					bestMode, bestCost = mode, cost
+				}
+			}
+			
+			modes[ty*tilesX+tx] = 0xFF000000 | uint32(bestMode)<<8
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					i := y*width + x
+					residuals[i] = webpSubPixels(argb[i], webpPredictPixel(argb, width, x, y, bestMode))
+				}
+			}
+		}
+	}
+	
+	return modes, residuals
+}
+
+// webpPredictPixel returns the prediction for the pixel at (x, y) using the given mode
+func webpPredictPixel(argb []uint32, width, x, y, mode int) uint32 {
+	// The first row and column use fixed predictors regardless of the tile mode
+	if y == 0 {
+		if x == 0 {
+			return 0xFF000000
+		}
+		return argb[x-1]
+	}
+	i := y*width + x
+	if x == 0 {
+		return argb[i-width]
+	}
+	
+	// For the rightmost column, TR is the leftmost pixel of the current row,
+	// which follows naturally from the row-major memory layout
+	L, T, TL, TR := argb[i-1], argb[i-width], argb[i-width-1], argb[i-width+1]
+	
+	switch mode {
+	case 0:
+		return 0xFF000000
+	case 1:
+		return L
+	case 2:
+		return T
+	case 3:
+		return TR
+	case 4:
+		return TL
+	case 5:
+		return webpAverage2(webpAverage2(L, TR), T)
+	case 6:
+		return webpAverage2(L, TL)
+	case 7:
+		return webpAverage2(L, T)
+	case 8:
+		return webpAverage2(TL, T)
+	case 9:
+		return webpAverage2(T, TR)
+	case 10:
+		return webpAverage2(webpAverage2(L, TL), webpAverage2(T, TR))
+	case 11:
+		return webpSelect(L, T, TL)
+	case 12:
+		return webpClampAddSubtractFull(L, T, TL)
+	default:
+		return webpClampAddSubtractHalf(webpAverage2(L, T), TL)
+	}
+}
+
+// webpAverage2 averages two ARGB pixels per channel
+func webpAverage2(a, b uint32) uint32 {
+	return (((a ^ b) & 0xFEFEFEFE) >> 1) + (a & b)
+}
+
+// webpSelect returns whichever of L or T is closer to the gradient estimate L+T-TL
+func webpSelect(L, T, TL uint32) uint32 {
+	pL, pT := 0, 0
+	for shift := 0; shift < 32; shift += 8 {
+		l := int(L >> shift & 0xFF)
+		t := int(T >> shift & 0xFF)
+		tl := int(TL >> shift & 0xFF)
+		pL += abs(t - tl)
+		pT += abs(l - tl)
+	}
+	if pL < pT {
+		return L
+	}
+	return T
+}
+
+// webpClampAddSubtractFull returns clamp(a + b - c) per channel
+func webpClampAddSubtractFull(a, b, c uint32) uint32 {
+	var result uint32
+	for shift := 0; shift < 32; shift += 8 {
+		v := int(a>>shift&0xFF) + int(b>>shift&0xFF) - int(c>>shift&0xFF)
+		result |= uint32(clampByte(v)) << shift
+	}
+	return result
+}
+
+// webpClampAddSubtractHalf returns clamp(a + (a - b) / 2) per channel
+func webpClampAddSubtractHalf(a, b uint32) uint32 {
+	var result uint32
+	for shift := 0; shift < 32; shift += 8 {
+		av := int(a >> shift & 0xFF)
+		bv := int(b >> shift & 0xFF)
+		result |= uint32(clampByte(av+(av-bv)/2)) << shift
+	}
+	return result
+}
+
+// webpSubPixels subtracts two ARGB pixels per channel modulo 256
+func webpSubPixels(a, b uint32) uint32 {
+	alphaGreen := 0x00FF00FF + (a & 0xFF00FF00) - (b & 0xFF00FF00)
+	redBlue := 0xFF00FF00 + (a & 0x00FF00FF) - (b & 0x00FF00FF)
+	return alphaGreen&0xFF00FF00 | redBlue&0x00FF00FF
+}
+
+// webpResidualCost estimates how expensive a residual is to encode
+func webpResidualCost(actual, predicted uint32) int {
+	diff := webpSubPixels(actual, predicted)
+	cost := 0
+	for shift := 0; shift < 32; shift += 8 {
+		cost += abs(int(int8(diff >> shift)))
+	}
+	return cost
+}
+
+// webpToken is either a literal pixel or an LZ77 backward reference
+type webpToken struct {
+	pixel    uint32
+	length   int // 0 for literals
+	distCode int
+}
+
+// webpWriteImageData writes an entropy-coded image
+func webpWriteImageData(bw *webpBitWriter, argb []uint32, width, height int, topLevel bool) {
+	tokens := webpBackwardReferences(argb, width)
+	
+	// Histograms for green+length, red, blue, alpha and distance
+	histograms := [5][]int{
+		make([]int, webpNumLiteralCodes+webpNumLengthCodes),
+		make([]int, webpNumLiteralCodes),
+		make([]int, webpNumLiteralCodes),
+		make([]int, webpNumLiteralCodes),
+		make([]int, webpNumDistanceCodes),
+	}
+	for _, tok := range tokens {
+		if tok.length == 0 {
+			histograms[0][tok.pixel>>8&0xFF]++
+			histograms[1][tok.pixel>>16&0xFF]++
+			histograms[2][tok.pixel&0xFF]++
+			histograms[3][tok.pixel>>24]++
+			continue
+		}
+		lengthPrefix, _, _ := webpPrefixEncode(tok.length)
+		histograms[0][webpNumLiteralCodes+lengthPrefix]++
+		distPrefix, _, _ := webpPrefixEncode(tok.distCode)
+		histograms[4][distPrefix]++
+	}
+	
+	bw.writeBits(0, 1) // No color cache
+	if topLevel {
+		bw.writeBits(0, 1) // No meta prefix codes
+	}
+	
+	var codes [5]webpPrefixCode
+	for i, histogram := range histograms {
+		codes[i] = webpWritePrefixCode(bw, histogram)
+	}
+	
+	for _, tok := range tokens {
+		if tok.length == 0 {
+			codes[0].write(bw, int(tok.pixel>>8&0xFF))
+			codes[1].write(bw, int(tok.pixel>>16&0xFF))
+			codes[2].write(bw, int(tok.pixel&0xFF))
+			codes[3].write(bw, int(tok.pixel>>24))
+			continue
+		}
+		prefix, extraBits, extra := webpPrefixEncode(tok.length)
+		codes[0].write(bw, webpNumLiteralCodes+prefix)
+		bw.writeBits(uint32(extra), uint(extraBits))
+		prefix, extraBits, extra = webpPrefixEncode(tok.distCode)
+		codes[4].write(bw, prefix)
+		bw.writeBits(uint32(extra), uint(extraBits))
+	}
+}
+
+// webpBackwardReferences finds LZ77 matches using hash chains
+func webpBackwardReferences(argb []uint32, width int) []webpToken {
+	n := len(argb)
+	tokens := make([]webpToken, 0, n/2)
+	head := make([]int32, 1<<webpHashBits)
+	for i := range head {
+		head[i] = -1
+	}
+	prev := make([]int32, n)
+	
+	hash := func(i int) uint32 {
+		h := argb[i]*0x1E35A7BD ^ argb[i+1]*0x9E3779B1
+		return h >> (32 - webpHashBits)
+	}
+	insert := func(i int) {
+		if i+1 < n {
+			h := hash(i)
+			prev[i] = head[h]
+			head[h] = int32(i)
+		}
+	}
+	matchLength := func(i, j, limit int) int {
+		l := 0
+		for l < limit && argb[i+l] == argb[j+l] {
+			l++
+		}
+		return l
+	}
+	
+	for i := 0; i < n; {
+		limit := min(webpMaxMatch, n-i)
+		bestLen, bestDist := 0, 0
+		
+		if limit >= webpMinMatch {
+			// Try the pixel to the left and the pixel above first, they are the most common matches
+			for _, dist := range [2]int{1, width} {
+				if dist <= i {
+					if l := matchLength(i, i-dist, limit); l > bestLen {
+						bestLen, bestDist = l, dist
+					}
+				}
+			}
+			
+			for j, chain := int(head[hash(i)]), 0; j >= 0 && chain < webpMaxChain && bestLen < limit; j, chain = int(prev[j]), chain+1 {
+				dist := i - j
+				if dist > webpMaxDistance {
+					break
+				}
+				if l := matchLength(i, j, limit); l > bestLen {
+					bestLen, bestDist = l, dist
+				}
+			}
+		}
+		
+		if bestLen >= webpMinMatch {
+			tokens = append(tokens, webpToken{length: bestLen, distCode: webpDistanceCode(bestDist, width)})
+			for k := 0; k < bestLen; k++ {
+				insert(i + k)
+			}
+			i += bestLen
+			continue
+		}
+		
+		tokens = append(tokens, webpToken{pixel: argb[i]})
+		insert(i)
+		i++
+	}
+	
+	return tokens
+}
+
+// webpDistanceCode maps a linear distance to a distance code, using the short
+// codes of the 2D neighborhood table for the pixel above and to the left
+func webpDistanceCode(dist, width int) int {
+	switch dist {
+	case width:
+		return 1
+	case 1:
+		return 2
+	}
+	return dist + webpDistanceMapSize
+}
+
+// webpPrefixEncode splits a length or distance value into prefix symbol and extra bits
+func webpPrefixEncode(value int) (prefix, extraBits, extra int) {
+	d := value - 1
+	if d < 4 {
+		return d, 0, 0
+	}
+	highBit := 0
+	for (d >> (highBit + 1)) != 0 {
+		highBit++
+	}
+	second := (d >> (highBit - 1)) & 1
+	extraBits = highBit - 1
+	return 2*highBit + second, extraBits, d & (1<<extraBits - 1)
+}
+
+// webpPrefixCode holds canonical prefix codes, bit-reversed for LSB-first output
+type webpPrefixCode struct {
+	lengths []uint8
+	codes   []uint16
+}
+
+// write emits the code for symbol
+func (c *webpPrefixCode) write(bw *webpBitWriter, symbol int) {
+	bw.writeBits(uint32(c.codes[symbol]), uint(c.lengths[symbol]))
+}
+
+// webpWritePrefixCode builds a prefix code from a histogram and writes its description
+func webpWritePrefixCode(bw *webpBitWriter, histogram []int) webpPrefixCode {
+	var used []int
+	for symbol, count := range histogram {
+		if count > 0 {
+			used = append(used, symbol)
+		}
+	}
+	
+	// Simple codes cover up to two symbols below 256 and need no lengths at all
+	if len(used) <= 2 && (len(used) == 0 || used[len(used)-1] < 256) {
+		code := webpPrefixCode{lengths: make([]uint8, len(histogram)), codes: make([]uint16, len(histogram))}
+		bw.writeBits(1, 1)
+		if len(used) == 0 {
+			used = []int{0}
+		}
+		bw.writeBits(uint32(len(used)-1), 1)
+		if used[0] < 2 {
+			bw.writeBits(0, 1)
+			bw.writeBits(uint32(used[0]), 1)
+		} else {
+			bw.writeBits(1, 1)
+			bw.writeBits(uint32(used[0]), 8)
+		}
+		if len(used) == 2 {
+			bw.writeBits(uint32(used[1]), 8)
+			code.lengths[used[0]], code.lengths[used[1]] = 1, 1
+			code.codes[used[1]] = 1
+		}
+		return code
+	}
+	
+	lengths := webpHuffmanLengths(histogram, webpMaxCodeLength)
+	bw.writeBits(0, 1)
+	
+	// Run-length encode the code lengths with symbols 16 (repeat previous), 17 and 18 (repeat zero)
+	type clToken struct{ symbol, extra, extraBits int }
+	var clTokens []clToken
+	for i := 0; i < len(lengths); {
+		value := int(lengths[i])
+		run := 1
+		for i+run < len(lengths) && int(lengths[i+run]) == value {
+			run++
+		}
+		i += run
+		
+		if value == 0 {
+			for run >= 11 {
+				r := min(run, 138)
+				clTokens = append(clTokens, clToken{18, r - 11, 7})
+				run -= r
+			}
+			if run >= 3 {
+				clTokens = append(clTokens, clToken{17, run - 3, 3})
+				run = 0
+			}
+		} else {
+			clTokens = append(clTokens, clToken{value, 0, 0})
+			run--
+			for run >= 3 {
+				r := min(run, 6)
+				clTokens = append(clTokens, clToken{16, r - 3, 2})
+				run -= r
+			}
+		}
+		for ; run > 0; run-- {
+			clTokens = append(clTokens, clToken{value, 0, 0})
+		}
+	}
+	
+	clHistogram := make([]int, len(webpCodeLengthCodeOrder))
+	for _, tok := range clTokens {
+		clHistogram[tok.symbol]++
+	}
+	clLengths := webpHuffmanLengths(clHistogram, webpMaxCLCodeLength)
+	
+	numCodes := 4
+	for i, symbol := range webpCodeLengthCodeOrder {
+		if clLengths[symbol] != 0 && i+1 > numCodes {
+			numCodes = i + 1
+		}
+	}
+	bw.writeBits(uint32(numCodes-4), 4)
+	for i := 0; i < numCodes; i++ {
+		bw.writeBits(uint32(clLengths[webpCodeLengthCodeOrder[i]]), 3)
+	}
+	
+	bw.writeBits(0, 1) // Code lengths cover the whole alphabet
+	
+	clCode := webpCanonicalCode(clLengths)
+	for _, tok := range clTokens {
+		clCode.write(bw, tok.symbol)
+		if tok.extraBits > 0 {
+			bw.writeBits(uint32(tok.extra), uint(tok.extraBits))
+		}
+	}
+	
+	return webpCanonicalCode(lengths)
+}
+
+// webpCanonicalCode assigns canonical codes to the given lengths
+func webpCanonicalCode(lengths []uint8) webpPrefixCode {
+	var count [webpMaxCodeLength + 1]int
+	for _, l := range lengths {
+		count[l]++
+	}
+	count[0] = 0
+	
+	var next [webpMaxCodeLength + 1]int
+	code := 0
+	for bits := 1; bits <= webpMaxCodeLength; bits++ {
+		code = (code + count[bits-1]) << 1
+		next[bits] = code
+	}
+	
+	codes := make([]uint16, len(lengths))
+	for symbol, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		c := next[l]
+		next[l]++
+		
+		// Reverse the bits since the stream is read LSB first
+		reversed := 0
+		for b := 0; b < int(l); b++ {
+			reversed = reversed<<1 | (c>>b)&1
+		}
+		codes[symbol] = uint16(reversed)
+	}
+	
+	return webpPrefixCode{lengths: lengths, codes: codes}
+}
+
+// webpHuffmanLengths computes Huffman code lengths limited to maxLength bits.
+// At least two symbols always get a code so that the tree is complete.
+func webpHuffmanLengths(histogram []int, maxLength int) []uint8 {
+	freqs := make([]int, len(histogram))
+	copy(freqs, histogram)
+	
+	used := 0
+	for _, f := range freqs {
+		if f > 0 {
+			used++
+		}
+	}
+	for i := 0; used < 2 && i < len(freqs); i++ {
+		if freqs[i] == 0 {
+			freqs[i] = 1
+			used++
+		}
+	}
+	
+	for {
+		lengths := huffmanCodeLengths(freqs)
+		longest := 0
+		for _, l := range lengths {
+			longest = max(longest, int(l))
+		}
+		if longest <= maxLength {
+			return lengths
+		}
+		
+		// Flatten the distribution and try again
+		for i, f := range freqs {
+			if f > 0 {
+				freqs[i] = (f + 1) / 2
+			}
+		}
+	}
+}
+
+// huffmanNode is a node of the tree built by huffmanCodeLengths
+type huffmanNode struct {
+	weight      int
+	symbol      int // -1 for internal nodes
+	left, right *huffmanNode
+}
+
+// huffmanHeap is a min-heap of nodes ordered by weight
+type huffmanHeap []*huffmanNode
+
+func (h huffmanHeap) Len() int            { return len(h) }
+func (h huffmanHeap) Less(i, j int) bool  { return h[i].weight < h[j].weight }
+func (h huffmanHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *huffmanHeap) Push(x interface{}) { *h = append(*h, x.(*huffmanNode)) }
+func (h *huffmanHeap) Pop() interface{} {
+	old := *h
+	node := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return node
+}
+
+// huffmanCodeLengths returns unlimited Huffman code lengths for the given frequencies
+func huffmanCodeLengths(freqs []int) []uint8 {
+	lengths := make([]uint8, len(freqs))
+	h := &huffmanHeap{}
+	for symbol, f := range freqs {
+		if f > 0 {
+			*h = append(*h, &huffmanNode{weight: f, symbol: symbol})
+		}
+	}
+	if h.Len() == 1 {
+		lengths[(*h)[0].symbol] = 1
+		return lengths
+	}
+	
+	heap.Init(h)
+	for h.Len() > 1 {
+		a := heap.Pop(h).(*huffmanNode)
+		b := heap.Pop(h).(*huffmanNode)
+		heap.Push(h, &huffmanNode{weight: a.weight + b.weight, symbol: -1, left: a, right: b})
+	}
+	
+	var walk func(node *huffmanNode, depth int)
+	walk = func(node *huffmanNode, depth int) {
+		if node == nil {
+			return
+		}
+		if node.symbol >= 0 {
+			lengths[node.symbol] = uint8(depth)
+			return
+		}
+		walk(node.left, depth+1)
+		walk(node.right, depth+1)
+	}
+	if h.Len() == 1 {
+		walk((*h)[0], 0)
+	}
+	return lengths
+}
+
+// webpBitWriter writes bits least significant bit first
+type webpBitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits uint
+}
+
+// writeBits appends the low n bits of v
+func (b *webpBitWriter) writeBits(v uint32, n uint) {
+	b.acc |= uint64(v) << b.nbits
+	b.nbits += n
+	for b.nbits >= 8 {
+		b.buf = append(b.buf, byte(b.acc))
+		b.acc >>= 8
+		b.nbits -= 8
+	}
+}
+
+// bytes flushes any pending bits and returns the encoded data
+func (b *webpBitWriter) bytes() []byte {
+	if b.nbits > 0 {
+		b.buf = append(b.buf, byte(b.acc))
+		b.acc, b.nbits = 0, 0
+	}
+	return b.buf
+}
+
+// clampByte clamps an integer to the 0-255 range
+func clampByte(v int) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
+
+// abs returns the absolute value of an integer
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
\ No newline at end of file